@@ -20,15 +20,19 @@ import (
 	"bytes"
 	"context"
 	"encoding/base64"
+	goerrors "errors"
 	"flag"
 	"fmt"
 	"html"
 	"io"
 	"os"
+	"reflect"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
-	"github.com/antchfx/xmlquery"
 	semver "github.com/blang/semver/v4"
 	backoff "github.com/cenkalti/backoff/v4"
 	"github.com/dsnet/compress/bzip2"
@@ -40,11 +44,13 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/version"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/flowcontrol"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
@@ -64,6 +70,10 @@ const (
 	configMapCompressed            = "openscap-scan-result/compressed"
 	apiserverOperatorName          = "openshift-apiserver"
 	tailoredProfileSuffix          = "-tp"
+	// The file the termination message of the aggregator is read from
+	aggregatorTerminationLog = "/dev/termination-log"
+	// Kubernetes truncates longer termination messages
+	maxTerminationMessageLength = 4096
 )
 
 var AggregatorCmd = &cobra.Command{
@@ -78,9 +88,17 @@ func init() {
 }
 
 type aggregatorConfig struct {
-	Content   string
-	ScanName  string
-	Namespace string
+	Content     string
+	ScanName    string
+	Namespace   string
+	Concurrency int
+	QPS         int
+	Burst       int
+	// The HTML report of the scan is stored by the result server, if set
+	ResultServerURI string
+	Cert            string
+	Key             string
+	CA              string
 }
 
 type aggregatorCrClient interface {
@@ -112,6 +130,13 @@ func defineAggregatorFlags(cmd *cobra.Command) {
 	cmd.Flags().String("content", "", "The path to the OpenScap content")
 	cmd.Flags().String("scan", "", "The compliance scan that owns the configMap objects.")
 	cmd.Flags().String("namespace", "openshift-compliance", "Running pod namespace.")
+	cmd.Flags().Int("concurrency", 10, "The number of result objects to create or update at the same time.")
+	cmd.Flags().Int("qps", 20, "The maximum number of requests per second to send to the API server.")
+	cmd.Flags().Int("burst", 30, "The maximum number of requests to send at once on top of the QPS.")
+	cmd.Flags().String("resultserveruri", "", "The resultserver URI to store the HTML report of the scan with.")
+	cmd.Flags().String("tls-client-cert", "", "The path to the client certificate of the resultserver.")
+	cmd.Flags().String("tls-client-key", "", "The path to the client key of the resultserver.")
+	cmd.Flags().String("tls-ca", "", "The path to the CA certificate of the resultserver.")
 
 	flags := cmd.Flags()
 
@@ -125,6 +150,15 @@ func parseAggregatorConfig(cmd *cobra.Command) *aggregatorConfig {
 	conf.Content = getValidStringArg(cmd, "content")
 	conf.ScanName = getValidStringArg(cmd, "scan")
 	conf.Namespace = getValidStringArg(cmd, "namespace")
+	conf.Concurrency, _ = cmd.Flags().GetInt("concurrency")
+	conf.QPS, _ = cmd.Flags().GetInt("qps")
+	conf.Burst, _ = cmd.Flags().GetInt("burst")
+	conf.ResultServerURI, _ = cmd.Flags().GetString("resultserveruri")
+	if conf.ResultServerURI != "" {
+		conf.Cert = getValidStringArg(cmd, "tls-client-cert")
+		conf.Key = getValidStringArg(cmd, "tls-client-key")
+		conf.CA = getValidStringArg(cmd, "tls-ca")
+	}
 
 	logf.SetLogger(zap.New())
 
@@ -167,23 +201,18 @@ func readCompressedData(compressed string) (*bzip2.Reader, error) {
 	return bzip2.NewReader(compressedReader, &bzip2.ReaderConfig{})
 }
 
-// parseResultRemediations parses scan results from a configMap with the help of DS provided in the
-// content parameter.
-// Returns a triple of (array-of-ParseResults, source, error) where source identifies the entity whose
-// scan produced this configMap -- typically a nodeName for node scans. For platform scans, the source
-// is empty. The source is used later when reconciling inconsistent results
-func parseResultRemediations(client runtimeclient.Client, scheme *runtime.Scheme, scanName, namespace string, content *xmlquery.Node, cm *v1.ConfigMap) ([]*utils.ParseResult, string, error) {
+// parseResultRemediations parses scan results from a configMap with the help of the lookup tables of the
+// DS provided in the tables parameter, and adds them to prCtx one result at a time so that the results of
+// the configMap are never all held in memory.
+// Returns whether any of the parsed results passed. The results are added in a batch whose source
+// identifies the entity whose scan produced this configMap -- typically a nodeName for node scans. For
+// platform scans, the source is empty. The source is used later when reconciling inconsistent results
+func parseResultRemediations(client runtimeclient.Client, scheme *runtime.Scheme, scanName, namespace string, tables *utils.DataStreamTables, cm *v1.ConfigMap, prCtx *utils.ParseResultContext) (bool, error) {
 	var scanReader io.Reader
 
-	_, ok := cm.Annotations[configMapRemediationsProcessed]
-	if ok {
-		cmdLog.Info("ConfigMap already processed", "ConfigMap.Name", cm.Name)
-		return nil, "", nil
-	}
-
 	cmScanResult, ok := cm.Data["results"]
 	if !ok {
-		return nil, "", fmt.Errorf("no results in configmap %s", cm.Name)
+		return false, fmt.Errorf("no results in configmap %s", cm.Name)
 	}
 
 	_, ok = cm.Annotations[configMapCompressed]
@@ -191,7 +220,7 @@ func parseResultRemediations(client runtimeclient.Client, scheme *runtime.Scheme
 		cmdLog.Info("Results are compressed\n")
 		scanResult, err := readCompressedData(cmScanResult)
 		if err != nil {
-			return nil, "", err
+			return false, err
 		}
 		defer scanResult.Close()
 		scanReader = scanResult
@@ -200,7 +229,7 @@ func parseResultRemediations(client runtimeclient.Client, scheme *runtime.Scheme
 	}
 
 	// This would return an empty string for a platform check that is handled later explicitly
-	nodeName := cm.Annotations["openscap-scan-result/node"]
+	nodeName := configMapSource(cm)
 	manualRules := []string{}
 
 	//get all manual rules from tailored profile
@@ -209,7 +238,7 @@ func parseResultRemediations(client runtimeclient.Client, scheme *runtime.Scheme
 	err := client.Get(context.TODO(), types.NamespacedName{Name: scanName, Namespace: namespace}, scan)
 	if err != nil {
 		errStr := "ErrorGettingTailoredProfileScan." + err.Error() + "\n"
-		return nil, nodeName, fmt.Errorf(errStr)
+		return false, fmt.Errorf(errStr)
 	}
 	// scan has tailored profile CM
 	if scan.Spec.TailoringConfigMap != nil {
@@ -222,8 +251,50 @@ func parseResultRemediations(client runtimeclient.Client, scheme *runtime.Scheme
 		manualRules = xccdf.GetManualRules(tp)
 	}
 
-	table, err := utils.ParseResultsFromContentAndXccdf(scheme, scanName, namespace, content, scanReader, manualRules)
-	return table, nodeName, nil
+	gotPass := false
+	nResults := 0
+	batch := prCtx.NewBatch(nodeName)
+	defer batch.Close()
+	err = utils.StreamResultsFromContentAndXccdf(scheme, scanName, namespace, tables, scanReader, manualRules,
+		func(pr *utils.ParseResult) error {
+			if pr.CheckResult != nil && pr.CheckResult.Status == compv1alpha1.CheckResultPass {
+				gotPass = true
+			}
+			nResults++
+			batch.Add(pr)
+			return nil
+		})
+	if err != nil {
+		// The results parsed so far are kept, as they used to be when a remediation couldn't be rendered
+		cmdLog.Error(err, "Error parsing the results", "ConfigMap.Name", cm.Name)
+	}
+
+	// A platform scan of a tailored profile also checks its custom rules
+	customPass, nCustomResults := addCustomRuleResults(scanName, namespace, cm, batch)
+	gotPass = gotPass || customPass
+	nResults += nCustomResults
+	cmdLog.Info("ConfigMap contained parsed results", "ConfigMap.Name", cm.Name, "results", nResults)
+	return gotPass, nil
+}
+
+// addCustomRuleResults adds the results of the custom rules the scan
+// checked to the batch. It returns whether any of them passed and how many
+// there were.
+func addCustomRuleResults(scanName, namespace string, cm *v1.ConfigMap, batch *utils.ParseResultBatch) (bool, int) {
+	customResults, err := utils.ParseCustomRuleResults(scanName, namespace, cm.Data[utils.CustomRulesResultKey])
+	if err != nil {
+		cmdLog.Error(err, "Error parsing the custom rule results", "ConfigMap.Name", cm.Name)
+		return false, 0
+	}
+
+	gotPass := false
+	for _, pr := range customResults {
+		if pr.CheckResult.Status == compv1alpha1.CheckResultPass {
+			gotPass = true
+		}
+		batch.Add(pr)
+	}
+	return gotPass, len(customResults)
 }
 
 func getScanResult(cm *v1.ConfigMap) (compv1alpha1.ComplianceScanStatusResult, string) {
@@ -245,24 +316,12 @@ func getScanResult(cm *v1.ConfigMap) (compv1alpha1.ComplianceScanStatusResult, s
 	return compv1alpha1.ResultError, fmt.Sprintf("The ConfigMap '%s' was missing 'exit-code'", cm.Name)
 }
 
-func annotateCMWithScanResult(cm *v1.ConfigMap, cmParsedResults []*utils.ParseResult) *v1.ConfigMap {
+func annotateCMWithScanResult(cm *v1.ConfigMap, gotPass bool) *v1.ConfigMap {
 	scanResult, errMsg := getScanResult(cm)
 	if scanResult == compv1alpha1.ResultCompliant {
 		// Special case: If the OS didn't match at all and SCAP skipped all the tests,
 		// then we would have gotten COMPLIANT. Let's make sure that at least one
 		// rule passed in this case
-		gotPass := false
-		for i := range cmParsedResults {
-			if cmParsedResults[i] == nil || cmParsedResults[i].CheckResult == nil {
-				continue
-			}
-
-			if cmParsedResults[i].CheckResult.Status == compv1alpha1.CheckResultPass {
-				gotPass = true
-				break
-			}
-		}
-
 		if gotPass == false {
 			scanResult = compv1alpha1.ResultNotApplicable
 			errMsg = "The scan did not produce any results, maybe an OS/platform mismatch?"
@@ -279,6 +338,145 @@ func annotateCMWithScanResult(cm *v1.ConfigMap, cmParsedResults []*utils.ParseRe
 	return cm.DeepCopy()
 }
 
+// getPendingConfigMaps returns the configMaps that weren't marked as processed yet
+func getPendingConfigMaps(configMaps []v1.ConfigMap) []*v1.ConfigMap {
+	var pending []*v1.ConfigMap
+	for i := range configMaps {
+		if _, ok := configMaps[i].Annotations[configMapRemediationsProcessed]; ok {
+			cmdLog.Info("ConfigMap already processed", "ConfigMap.Name", configMaps[i].Name)
+			continue
+		}
+		pending = append(pending, &configMaps[i])
+	}
+	return pending
+}
+
+// configMapSource returns the node whose results a configMap holds, or an
+// empty string for the configMap of a platform scan
+func configMapSource(cm *v1.ConfigMap) string {
+	return cm.Annotations["openscap-scan-result/node"]
+}
+
+// getPendingResults returns the results that any of the pending configMaps
+// contributed to. The results of the configMaps that were already
+// checkpointed were persisted before.
+func getPendingResults(results []*utils.ParseResultContextItem, pending []*v1.ConfigMap) []*utils.ParseResultContextItem {
+	pendingSources := make(map[string]bool)
+	for _, cm := range pending {
+		pendingSources[configMapSource(cm)] = true
+	}
+
+	var pendingResults []*utils.ParseResultContextItem
+	for _, pr := range results {
+		for _, src := range pr.Sources() {
+			if pendingSources[src] {
+				pendingResults = append(pendingResults, pr)
+				break
+			}
+		}
+	}
+	return pendingResults
+}
+
+// configMapCheckpointer marks each of the pending configMaps as processed as
+// soon as all the results it contributed to are persisted. An aggregator
+// that stops halfway thus only creates the results of the configMaps that
+// weren't marked again. A configMap with a result that couldn't be persisted
+// isn't marked.
+type configMapCheckpointer struct {
+	crClient aggregatorCrClient
+
+	mu        sync.Mutex
+	bySource  map[string][]*v1.ConfigMap
+	remaining map[string]int
+	failed    map[string]bool
+	errs      []error
+}
+
+func newConfigMapCheckpointer(crClient aggregatorCrClient, pending []*v1.ConfigMap, results []*utils.ParseResultContextItem) *configMapCheckpointer {
+	c := &configMapCheckpointer{
+		crClient:  crClient,
+		bySource:  make(map[string][]*v1.ConfigMap),
+		remaining: make(map[string]int),
+		failed:    make(map[string]bool),
+	}
+	for _, cm := range pending {
+		src := configMapSource(cm)
+		c.bySource[src] = append(c.bySource[src], cm)
+	}
+	for _, pr := range results {
+		for _, src := range pr.Sources() {
+			if _, ok := c.bySource[src]; ok {
+				c.remaining[src]++
+			}
+		}
+	}
+	return c
+}
+
+// resultDone records that the result was handled, and checkpoints the
+// configMaps whose results were all persisted
+func (c *configMapCheckpointer) resultDone(pr *utils.ParseResultContextItem, resultErr error) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, src := range pr.Sources() {
+		if _, ok := c.bySource[src]; !ok {
+			continue
+		}
+		if resultErr != nil {
+			c.failed[src] = true
+			continue
+		}
+		c.remaining[src]--
+		if c.remaining[src] == 0 && !c.failed[src] {
+			c.checkpoint(src)
+		}
+	}
+}
+
+// finish checkpoints the configMaps that didn't contribute to any result and
+// returns the errors marking any of the configMaps
+func (c *configMapCheckpointer) finish() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for src := range c.bySource {
+		if c.remaining[src] == 0 && !c.failed[src] {
+			c.checkpoint(src)
+		}
+	}
+	return utilerrors.NewAggregate(c.errs)
+}
+
+// checkpoint marks the configMaps of the source as processed. Must be called
+// with the lock held.
+func (c *configMapCheckpointer) checkpoint(src string) {
+	for _, cm := range c.bySource[src] {
+		if err := markConfigMapAsProcessed(c.crClient, cm); err != nil {
+			c.errs = append(c.errs, fmt.Errorf("cannot mark ConfigMap %s as processed: %w", cm.Name, err))
+		}
+	}
+	delete(c.bySource, src)
+}
+
+// writeAggregationWarnings writes the errors creating the results to the
+// termination message of the aggregator, for the scan to report them
+func writeAggregationWarnings(path string, resultErr error) error {
+	if resultErr == nil {
+		return nil
+	}
+	msg := "Some results couldn't be created or updated: " + resultErr.Error()
+	if len(msg) > maxTerminationMessageLength {
+		const ellipsis = "..."
+		msg = msg[:maxTerminationMessageLength-len(ellipsis)] + ellipsis
+	}
+	return os.WriteFile(path, []byte(msg), 0600)
+}
+
 func markConfigMapAsProcessed(crClient aggregatorCrClient, cm *v1.ConfigMap) error {
 	cmCopy := cm.DeepCopy()
 
@@ -536,8 +734,168 @@ func getCheckResultAnnotations(cr *compv1alpha1.ComplianceCheckResult, resultAnn
 	return annotations
 }
 
-func createResults(crClient aggregatorCrClient, scan *compv1alpha1.ComplianceScan, consistentResults []*utils.ParseResultContextItem) error {
-	cmdLog.Info("Will create result objects", "objects", len(consistentResults))
+// getActiveAnswers returns the answers to the manual checks of the scan that
+// are in effect, keyed by the DNS-friendly name of the answered rule. When
+// several answers cover the same rule, the newest one wins.
+func getActiveAnswers(crClient aggregatorCrClient, scan *compv1alpha1.ComplianceScan, now time.Time) (map[string]*compv1alpha1.ComplianceCheckAnswer, error) {
+	answerList := &compv1alpha1.ComplianceCheckAnswerList{}
+	if err := crClient.getClient().List(context.TODO(), answerList, client.InNamespace(scan.Namespace)); err != nil {
+		return nil, err
+	}
+
+	answers := make(map[string]*compv1alpha1.ComplianceCheckAnswer)
+	for i := range answerList.Items {
+		answer := &answerList.Items[i]
+		if !answer.AppliesToScan(scan.Name) {
+			continue
+		}
+		if !answer.Spec.ExpiresAt.Time.After(now) {
+			cmdLog.Info("Skipping expired answer", "ComplianceCheckAnswer.Name", answer.Name)
+			continue
+		}
+		if !answer.HasEvidence() {
+			why := fmt.Sprintf("Ignoring the answer %s, it has neither evidence nor a reference to it", answer.Name)
+			crClient.getRecorder().Event(scan, v1.EventTypeWarning, "IgnoringAnswer", why)
+			continue
+		}
+		ruleID, err := getRuleID(crClient, answer.Spec.Rule, answer.Namespace)
+		if err != nil {
+			why := fmt.Sprintf("Ignoring the answer %s: %s", answer.Name, err)
+			crClient.getRecorder().Event(scan, v1.EventTypeWarning, "IgnoringAnswer", why)
+			continue
+		}
+		if other, ok := answers[ruleID]; ok && !isNewerAnswer(answer, other) {
+			continue
+		}
+		answers[ruleID] = answer
+	}
+	return answers, nil
+}
+
+// getRuleID returns the DNS-friendly name of the rule, as used in the rule
+// annotation of the ComplianceCheckResults
+func getRuleID(crClient aggregatorCrClient, name, namespace string) (string, error) {
+	rule := &compv1alpha1.Rule{}
+	key := types.NamespacedName{Name: name, Namespace: namespace}
+	if err := crClient.getClient().Get(context.TODO(), key, rule); err != nil {
+		return "", fmt.Errorf("cannot get rule %s: %w", name, err)
+	}
+
+	ruleID, ok := rule.Annotations[compv1alpha1.RuleIDAnnotationKey]
+	if !ok || ruleID == "" {
+		return "", fmt.Errorf("rule %s has no %s annotation", rule.Name, compv1alpha1.RuleIDAnnotationKey)
+	}
+	return ruleID, nil
+}
+
+func isNewerAnswer(answer, other *compv1alpha1.ComplianceCheckAnswer) bool {
+	if !answer.CreationTimestamp.Equal(&other.CreationTimestamp) {
+		return other.CreationTimestamp.Before(&answer.CreationTimestamp)
+	}
+	return answer.Name > other.Name
+}
+
+// applyAnswers sets the status of the manual results that were answered to
+// their answer. It returns whether any of the answers failed a check.
+func applyAnswers(answers map[string]*compv1alpha1.ComplianceCheckAnswer, results []*utils.ParseResultContextItem) bool {
+	answeredFail := false
+	for _, pr := range results {
+		if pr == nil || pr.CheckResult == nil || pr.CheckResult.Status != compv1alpha1.CheckResultManual {
+			continue
+		}
+		answer, ok := answers[utils.IDToDNSFriendlyName(pr.CheckResult.ID)]
+		if !ok {
+			continue
+		}
+
+		cmdLog.Info("Answering manual check", "ComplianceCheckResult.Name", pr.CheckResult.Name,
+			"ComplianceCheckAnswer.Name", answer.Name, "answer", answer.Spec.Answer)
+		pr.CheckResult.Status = answer.Spec.Answer
+		if pr.Annotations == nil {
+			pr.Annotations = make(map[string]string)
+		}
+		pr.Annotations[compv1alpha1.ComplianceCheckResultAnswerAnnotation] = answer.Name
+		if answer.Spec.Answer == compv1alpha1.CheckResultFail {
+			answeredFail = true
+		}
+	}
+	return answeredFail
+}
+
+// getActiveExceptions returns the exceptions that are in effect for the
+// failed checks of the scan, keyed by the DNS-friendly name of the excepted
+// rule. When several exceptions cover the same rule, the oldest one wins.
+func getActiveExceptions(crClient aggregatorCrClient, scan *compv1alpha1.ComplianceScan, now time.Time) (map[string]*compv1alpha1.ComplianceException, error) {
+	excList := &compv1alpha1.ComplianceExceptionList{}
+	if err := crClient.getClient().List(context.TODO(), excList, client.InNamespace(scan.Namespace)); err != nil {
+		return nil, err
+	}
+
+	role := utils.GetFirstNodeRole(scan.Spec.NodeSelector)
+	exceptions := make(map[string]*compv1alpha1.ComplianceException)
+	for i := range excList.Items {
+		exc := &excList.Items[i]
+		if !exc.DeletionTimestamp.IsZero() || !exc.Spec.ExpiresAt.Time.After(now) || !exc.AppliesToNodeRole(role) {
+			continue
+		}
+		ruleID, err := getRuleID(crClient, exc.Spec.Rule, exc.Namespace)
+		if err != nil {
+			cmdLog.Info("Skipping exception", "ComplianceException.Name", exc.Name, "reason", err.Error())
+			continue
+		}
+		if other, ok := exceptions[ruleID]; ok && !isOlderException(exc, other) {
+			continue
+		}
+		exceptions[ruleID] = exc
+	}
+	return exceptions, nil
+}
+
+func isOlderException(exc, other *compv1alpha1.ComplianceException) bool {
+	if !exc.CreationTimestamp.Equal(&other.CreationTimestamp) {
+		return exc.CreationTimestamp.Before(&other.CreationTimestamp)
+	}
+	return exc.Name < other.Name
+}
+
+// applyExceptions marks the failed results that are excepted as such, so
+// that re-creating them doesn't report the failures again
+func applyExceptions(exceptions map[string]*compv1alpha1.ComplianceException, results []*utils.ParseResultContextItem) {
+	for _, pr := range results {
+		if pr == nil || pr.CheckResult == nil || pr.CheckResult.Status != compv1alpha1.CheckResultFail {
+			continue
+		}
+		exc, ok := exceptions[utils.IDToDNSFriendlyName(pr.CheckResult.ID)]
+		if !ok {
+			continue
+		}
+
+		cmdLog.Info("Excepting failed check", "ComplianceCheckResult.Name", pr.CheckResult.Name,
+			"ComplianceException.Name", exc.Name)
+		if pr.Annotations == nil {
+			pr.Annotations = make(map[string]string)
+		}
+		pr.Annotations[compv1alpha1.ComplianceCheckResultExceptionAnnotation] = exc.GetReference()
+		pr.Annotations[compv1alpha1.ComplianceCheckResultExceptedStatusAnnotation] = string(pr.CheckResult.Status)
+		pr.CheckResult.Status = compv1alpha1.CheckResultExcepted
+	}
+}
+
+// annotateCMsWithFailedAnswers marks the configMaps of compliant scans as
+// non-compliant, as one of the manual checks of the scan was answered to fail
+func annotateCMsWithFailedAnswers(configMaps []*v1.ConfigMap) {
+	for _, cm := range configMaps {
+		if cm.Annotations[compv1alpha1.CmScanResultAnnotation] == string(compv1alpha1.ResultCompliant) {
+			cm.Annotations[compv1alpha1.CmScanResultAnnotation] = string(compv1alpha1.ResultNonCompliant)
+		}
+	}
+}
+
+// createResults creates or updates the result objects along with their
+// remediations. The checkpointer, if any, is told about every result that
+// was handled.
+func createResults(crClient aggregatorCrClient, scan *compv1alpha1.ComplianceScan, consistentResults []*utils.ParseResultContextItem, concurrency int, checkpointer *configMapCheckpointer) error {
+	cmdLog.Info("Will create result objects", "objects", len(consistentResults), "concurrency", concurrency)
 	if len(consistentResults) == 0 {
 		cmdLog.Info("Nothing to create")
 		return nil
@@ -557,84 +915,178 @@ func createResults(crClient aggregatorCrClient, scan *compv1alpha1.ComplianceSca
 	// aggregator need to know it's using gRPC under the hood, probably
 	// not).
 
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	// A pool of workers creates the results along with their remediations.
+	// A result that can't be created doesn't keep the others from being
+	// created, the errors are returned together once all were handled.
+	var (
+		wg        sync.WaitGroup
+		errsMu    sync.Mutex
+		errs      []error
+		unchanged int32
+	)
+	work := make(chan *utils.ParseResultContextItem)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for pr := range work {
+				updated, err := createResult(crClient, scan, f, pr)
+				checkpointer.resultDone(pr, err)
+				if err != nil {
+					cmdLog.Error(err, "Could not create result objects", "ParseResult.Id", pr.Id)
+					errsMu.Lock()
+					errs = append(errs, err)
+					errsMu.Unlock()
+				} else if !updated {
+					atomic.AddInt32(&unchanged, 1)
+				}
+			}
+		}()
+	}
+
 	for _, pr := range consistentResults {
-		if pr == nil || pr.CheckResult == nil {
-			cmdLog.Info("nil result or result.check, this shouldn't happen")
-			continue
-		}
-		_, hasHiddenAnnotation := pr.CheckResult.Annotations[compv1alpha1.RuleHideTagAnnotationKey]
+		work <- pr
+	}
+	close(work)
+	wg.Wait()
 
-		if hasHiddenAnnotation {
-			cmdLog.Info("Skipping result as has hidden annotation", "result", pr.CheckResult)
-			continue
-		}
+	cmdLog.Info("Done creating result objects", "skipped", atomic.LoadInt32(&unchanged), "failed", len(errs))
 
-		checkResultLabels := getCheckResultLabels(&pr.ParseResult, pr.Labels, scan)
-		checkResultAnnotations := getCheckResultAnnotations(pr.CheckResult, pr.Annotations)
-
-		crkey := getObjKey(pr.CheckResult.GetName(), pr.CheckResult.GetNamespace())
-		foundCheckResult := &compv1alpha1.ComplianceCheckResult{}
-		// Copy type metadata so dynamic client copies data correctly
-		foundCheckResult.TypeMeta = pr.CheckResult.TypeMeta
-		cmdLog.Info("Getting ComplianceCheckResult", "ComplianceCheckResult.Name", crkey.Name,
-			"ComplianceCheckResult.Namespace", crkey.Namespace)
-		checkResultExists := getObjectIfFound(crClient, crkey, foundCheckResult)
-		if checkResultExists {
-			// Copy resource version and other metadata needed for update
-			foundCheckResult.ObjectMeta.DeepCopyInto(&pr.CheckResult.ObjectMeta)
-		} else if !scan.Spec.ShowNotApplicable && pr.CheckResult.Status == compv1alpha1.CheckResultNotApplicable {
-			// If the result is not applicable we skip creation
-			// Note that updating a not-applicable result should still
-			// work in order to get older deployments to keep working.
-			continue
-		}
+	return utilerrors.NewAggregate(errs)
+}
+
+// createResult creates or updates the check result and the remediations of
+// a parsed result. It returns false if the check result was skipped or was
+// already up to date.
+func createResult(crClient aggregatorCrClient, scan *compv1alpha1.ComplianceScan, f compliancescan.Forwarder, pr *utils.ParseResultContextItem) (bool, error) {
+	if pr == nil || pr.CheckResult == nil {
+		cmdLog.Info("nil result or result.check, this shouldn't happen")
+		return false, nil
+	}
+	_, hasHiddenAnnotation := pr.CheckResult.Annotations[compv1alpha1.RuleHideTagAnnotationKey]
+
+	if hasHiddenAnnotation {
+		cmdLog.Info("Skipping result as has hidden annotation", "result", pr.CheckResult)
+		return false, nil
+	}
+
+	checkResultLabels := getCheckResultLabels(&pr.ParseResult, pr.Labels, scan)
+	checkResultAnnotations := getCheckResultAnnotations(pr.CheckResult, pr.Annotations)
+
+	crkey := getObjKey(pr.CheckResult.GetName(), pr.CheckResult.GetNamespace())
+	foundCheckResult := &compv1alpha1.ComplianceCheckResult{}
+	// Copy type metadata so dynamic client copies data correctly
+	foundCheckResult.TypeMeta = pr.CheckResult.TypeMeta
+	cmdLog.Info("Getting ComplianceCheckResult", "ComplianceCheckResult.Name", crkey.Name,
+		"ComplianceCheckResult.Namespace", crkey.Namespace)
+	checkResultExists := getObjectIfFound(crClient, crkey, foundCheckResult)
+	if checkResultExists {
+		// Copy resource version and other metadata needed for update
+		foundCheckResult.ObjectMeta.DeepCopyInto(&pr.CheckResult.ObjectMeta)
+	} else if !scan.Spec.ShowNotApplicable && pr.CheckResult.Status == compv1alpha1.CheckResultNotApplicable {
+		// If the result is not applicable we skip creation
+		// Note that updating a not-applicable result should still
+		// work in order to get older deployments to keep working.
+		return false, nil
+	}
+	// Node scans can list the result of each node
+	if scan.Spec.PerNodeResults {
+		pr.CheckResult.NodeResults = pr.NodeResults()
+	}
+
+	updated := true
+	if checkResultExists && checkResultUnchanged(crClient, scan, foundCheckResult, pr.CheckResult, checkResultLabels, checkResultAnnotations) {
+		cmdLog.Info("ComplianceCheckResult is up to date", "ComplianceCheckResult.Name", crkey.Name)
+		updated = false
+	} else if err := createOrUpdateOneResult(crClient, scan, checkResultLabels, checkResultAnnotations, checkResultExists, pr.CheckResult); err != nil {
 		// check is owned by the scan
-		if err := createOrUpdateOneResult(crClient, scan, checkResultLabels, checkResultAnnotations, checkResultExists, pr.CheckResult); err != nil {
-			return fmt.Errorf("cannot create or update checkResult %s: %v", pr.CheckResult.Name, err)
-		}
+		return false, fmt.Errorf("cannot create or update checkResult %s: %v", pr.CheckResult.Name, err)
+	}
+	// Handle forwarding.
+	f.SendComplianceCheckResult(pr.CheckResult)
+
+	if pr.Remediations == nil ||
+		(pr.CheckResult.Status != compv1alpha1.CheckResultFail &&
+			pr.CheckResult.Status != compv1alpha1.CheckResultInfo &&
+			pr.CheckResult.Status != compv1alpha1.CheckResultPass && /* even passing remediations might need to be updated */
+			pr.CheckResult.Status != compv1alpha1.CheckResultExcepted &&
+			pr.CheckResult.Status != compv1alpha1.CheckResultInconsistent) {
+		return updated, nil
+	}
+	for _, r := range pr.Remediations {
 		// Handle forwarding.
-		f.SendComplianceCheckResult(pr.CheckResult)
+		f.SendComplianceRemediation(r)
+	}
 
-		if pr.Remediations == nil ||
-			(pr.CheckResult.Status != compv1alpha1.CheckResultFail &&
-				pr.CheckResult.Status != compv1alpha1.CheckResultInfo &&
-				pr.CheckResult.Status != compv1alpha1.CheckResultPass && /* even passing remediations might need to be updated */
-				pr.CheckResult.Status != compv1alpha1.CheckResultInconsistent) {
-			continue
+	// The remediations of a fix with several objects form a group
+	groupName := ""
+	if len(pr.Remediations) > 1 {
+		groupName = pr.Remediations[0].Name
+	}
+	var members []*compv1alpha1.ComplianceRemediation
+	for idx := range pr.Remediations {
+		rem := pr.Remediations[idx]
+		handled, remErr := handleRemediation(crClient, rem, pr.CheckResult, scan, groupName)
+		if remErr != nil {
+			return updated, remErr
 		}
-		for _, r := range pr.Remediations {
-			// Handle forwarding.
-			f.SendComplianceRemediation(r)
+		if handled {
+			members = append(members, rem)
 		}
-
-		for idx := range pr.Remediations {
-			rem := pr.Remediations[idx]
-			if remErr := handleRemediation(crClient, rem, pr.CheckResult, scan); remErr != nil {
-				return remErr
-			}
+	}
+	if groupName != "" && len(members) > 0 {
+		if groupErr := handleRemediationGroup(crClient, groupName, members, pr.CheckResult, scan); groupErr != nil {
+			return updated, groupErr
 		}
 	}
+	return updated, nil
+}
 
-	return nil
+// checkResultUnchanged returns whether updating the check result found in
+// the cluster with the parsed one would be a no-op
+func checkResultUnchanged(crClient aggregatorCrClient, owner metav1.Object, found, parsed *compv1alpha1.ComplianceCheckResult,
+	labels map[string]string, annotations map[string]string) bool {
+	// The parsed check result as createOrUpdateOneResult would update it
+	desired := parsed.DeepCopy()
+	if err := controllerutil.SetControllerReference(owner, desired, crClient.getScheme()); err != nil {
+		return false
+	}
+	desired.SetLabels(labels)
+	if annotations != nil {
+		desired.SetAnnotations(annotations)
+	}
+	// The type metadata of typed objects isn't always filled in on reads
+	desired.TypeMeta = found.TypeMeta
+	return utils.DiffChecks(found, desired)
 }
 
-func handleRemediation(crClient aggregatorCrClient, rem *compv1alpha1.ComplianceRemediation, cr *compv1alpha1.ComplianceCheckResult, scan *compv1alpha1.ComplianceScan) error {
+// handleRemediation creates or updates the remediation and returns whether
+// it exists in the cluster
+func handleRemediation(crClient aggregatorCrClient, rem *compv1alpha1.ComplianceRemediation, cr *compv1alpha1.ComplianceCheckResult,
+	scan *compv1alpha1.ComplianceScan, groupName string) (bool, error) {
 	crkey := getObjKey(cr.GetName(), cr.GetNamespace())
 	remTargetObj := rem.Spec.Current.Object
 	// Skipping is harmless
 	if skip, why := shouldSkipRemediation(scan, rem, crClient); skip {
 		cmdLog.Info(why, "Remediation", crkey.Name)
-		return nil
+		return false, nil
 	}
 
 	// this is a validation and should warn the user
 	if canCreate, why := canCreateRemediationObject(scan, remTargetObj); !canCreate {
 		cmdLog.Info(why, "Remediation", crkey.Name)
 		crClient.getRecorder().Event(scan, v1.EventTypeWarning, "CannotRemediate", why+" Remediation:"+crkey.Name)
-		return nil
+		return false, nil
 	}
 
 	remLabels := getRemediationLabels(scan, remTargetObj)
+	if groupName != "" {
+		remLabels[compv1alpha1.RemediationGroupLabel] = groupName
+	}
 
 	// The state even if set in the object would have been overwritten by the call to
 	// spec update, so we keep the state separately in a variable
@@ -648,6 +1100,18 @@ func handleRemediation(crClient aggregatorCrClient, rem *compv1alpha1.Compliance
 		"ComplianceRemediation.Namespace", crkey.Namespace)
 	remExists := getObjectIfFound(crClient, remkey, foundRemediation)
 	if remExists {
+		// A fix that took values from the remediation values ConfigMaps is
+		// rendered with them again, so that it doesn't differ from the
+		// remediation in the cluster
+		if sourced, ok := foundRemediation.GetAnnotations()[compv1alpha1.RemediationSourcedValueAnnotation]; ok {
+			if err := resolveSourcedValues(crClient, rem, sourced); err != nil {
+				return false, err
+			}
+		}
+
+		// The enforcement mode is chosen by the admin, not the content
+		rem.Spec.EnforcementMode = foundRemediation.Spec.EnforcementMode
+
 		// If the remediation is already applied and the status of the check is compliant, only update
 		// the remediation if the payload differs. Let's not create remediations for checks that are passing
 		// needlessly and let's not trigger the remediation controller needlessly
@@ -655,7 +1119,7 @@ func handleRemediation(crClient aggregatorCrClient, rem *compv1alpha1.Compliance
 			foundRemediation.Status.ApplicationState == compv1alpha1.RemediationOutdated {
 			if !foundRemediation.RemediationPayloadDiffers(rem) {
 				cmdLog.Info("Not updating passing remediation that was the same between runs", "ComplianceRemediation.Name", foundRemediation.Name)
-				return nil
+				return true, nil
 			}
 
 			// Applied remediation that differs must be updated, let's set the appropriate state
@@ -675,25 +1139,73 @@ func handleRemediation(crClient aggregatorCrClient, rem *compv1alpha1.Compliance
 			remLabels[compv1alpha1.OutdatedRemediationLabel] = ""
 		}
 
-		// Copy resource version and other metadata needed for update
+		// Copy resource version and other metadata needed for update, but
+		// keep the disruption and severity of the current fix
+		parsedAnnotations := rem.GetAnnotations()
 		foundRemediation.ObjectMeta.DeepCopyInto(&rem.ObjectMeta)
+		for _, ann := range []string{compv1alpha1.RemediationDisruptionAnnotation, compv1alpha1.RemediationSeverityAnnotation} {
+			if value, ok := parsedAnnotations[ann]; ok {
+				if rem.Annotations == nil {
+					rem.Annotations = make(map[string]string)
+				}
+				rem.Annotations[ann] = value
+			}
+		}
 	} else if cr.Status == compv1alpha1.CheckResultPass {
 		// If the remediation was not created earlier (e.g. the check was always passing), don't bother
 		// creating it now
-		return nil
+		return false, nil
 	}
 
 	// remediation is owned by the check
 	if err := createOrUpdateOneResult(crClient, cr, remLabels, nil, remExists, rem); err != nil {
-		return fmt.Errorf("cannot create or update remediation %s: %v", rem.Name, err)
+		return false, fmt.Errorf("cannot create or update remediation %s: %v", rem.Name, err)
 	}
 
 	// Update the status as needed
 	if remExists {
 		if err := updateRemediationStatus(crClient, rem, stateUpdate); err != nil {
-			return err
+			return false, err
 		}
 	}
+	return true, nil
+}
+
+// handleRemediationGroup creates or updates the group of the remediations
+// of a fix. Like the remediations, it's owned by the check.
+func handleRemediationGroup(crClient aggregatorCrClient, groupName string, members []*compv1alpha1.ComplianceRemediation,
+	cr *compv1alpha1.ComplianceCheckResult, scan *compv1alpha1.ComplianceScan) error {
+	group := &compv1alpha1.ComplianceRemediationGroup{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ComplianceRemediationGroup",
+			APIVersion: compv1alpha1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      groupName,
+			Namespace: cr.Namespace,
+		},
+	}
+	for _, rem := range members {
+		order, _ := strconv.ParseInt(rem.GetAnnotations()[compv1alpha1.RemediationGroupOrderAnnotation], 10, 32)
+		group.Spec.Members = append(group.Spec.Members, compv1alpha1.RemediationGroupMember{
+			Name:  rem.Name,
+			Order: int32(order),
+		})
+	}
+
+	foundGroup := &compv1alpha1.ComplianceRemediationGroup{}
+	groupExists := getObjectIfFound(crClient, getObjKey(group.Name, group.Namespace), foundGroup)
+	if groupExists {
+		if reflect.DeepEqual(foundGroup.Spec, group.Spec) {
+			return nil
+		}
+		foundGroup.ObjectMeta.DeepCopyInto(&group.ObjectMeta)
+	}
+
+	groupLabels := getRemediationLabels(scan, group)
+	if err := createOrUpdateOneResult(crClient, cr, groupLabels, nil, groupExists, group); err != nil {
+		return fmt.Errorf("cannot create or update remediation group %s: %v", group.Name, err)
+	}
 	return nil
 }
 
@@ -724,6 +1236,27 @@ func getObjKey(name, ns string) types.NamespacedName {
 }
 
 // Returns whether or not an object exists, and updates the data in the obj.
+func resolveSourcedValues(crClient aggregatorCrClient, rem *compv1alpha1.ComplianceRemediation, sourced string) error {
+	values, err := utils.GetRemediationValues(context.TODO(), crClient.getClient(), rem.GetNamespace())
+	if err != nil {
+		return err
+	}
+	if rem.Annotations == nil {
+		rem.Annotations = make(map[string]string)
+	}
+	rem.Annotations[compv1alpha1.RemediationSourcedValueAnnotation] = sourced
+	_, err = utils.ResolveRemediationValues(rem, values)
+	var invalidErr *utils.InvalidRemediationValuesError
+	if goerrors.As(err, &invalidErr) {
+		// The remediation is created as the scan rendered it, and the
+		// remediation controller reports the rejected values
+		cmdLog.Info("Not rendering remediation with invalid values", "ComplianceRemediation.Name", rem.Name,
+			"Reasons", invalidErr.Reasons)
+		return nil
+	}
+	return err
+}
+
 func getObjectIfFound(crClient aggregatorCrClient, key types.NamespacedName, obj runtimeclient.Object) bool {
 	var found bool
 	err := backoff.Retry(func() error {
@@ -752,6 +1285,9 @@ func aggregator(cmd *cobra.Command, args []string) {
 		cmdLog.Error(err, "")
 		os.Exit(1)
 	}
+	// All the clients share a single rate limiter, so that the workers
+	// creating the result objects together stay within the limits
+	cfg.RateLimiter = flowcontrol.NewTokenBucketRateLimiter(float32(aggregatorConf.QPS), aggregatorConf.Burst)
 
 	crclient, err := createAggregatorCrClient(cfg)
 	if err != nil {
@@ -798,47 +1334,99 @@ func aggregator(cmd *cobra.Command, args []string) {
 		cmdLog.Error(err, "Cannot parse the content")
 		os.Exit(1)
 	}
+	// The lookup tables of the content are built once and used to parse the results of every node
+	contentTables := utils.NewDataStreamTables(contentDom)
+
+	// Only the ConfigMaps that weren't checkpointed yet are aggregated
+	pending := getPendingConfigMaps(configMaps)
+	if len(pending) == 0 {
+		// The results were all stored, but the aggregation may have stopped
+		// before the exports and the report were
+		cmdLog.Info("All the ConfigMaps were already processed")
+		if err := storeExports(crclient, scan); err != nil {
+			cmdLog.Error(err, "Could not export the results")
+		}
+		storeReport(crclient, aggregatorConf, scan)
+		return
+	}
+
+	if len(pending) < len(configMaps) {
+		cmdLog.Info("Resuming the aggregation", "pending", len(pending))
+	}
+
+	isPending := make(map[*v1.ConfigMap]bool)
+	for _, cm := range pending {
+		isPending[cm] = true
+	}
 
 	prCtx := utils.NewParseResultContext()
 
-	// For each configmap, create a list of remediations
+	// For each configmap, add its results to the parse result context. The
+	// configMaps that were already checkpointed are parsed as well, as the
+	// results are only consistent if they're compared across all the nodes.
 	for i := range configMaps {
 		cm := &configMaps[i]
 		cmdLog.Info("processing ConfigMap", "ConfigMap.Name", cm.Name)
 
-		cmParsedResults, source, err := parseResultRemediations(crclient.getClient(), crclient.getScheme(), aggregatorConf.ScanName, aggregatorConf.Namespace, contentDom, cm)
+		gotPass, err := parseResultRemediations(crclient.getClient(), crclient.getScheme(), aggregatorConf.ScanName, aggregatorConf.Namespace, contentTables, cm, prCtx)
 		if err != nil {
 			cmdLog.Error(err, "Cannot parse ConfigMap into remediations", "ConfigMap.Name", cm.Name)
-		} else if cmParsedResults == nil {
-			cmdLog.Info("Either no parsed results found in result or result already processed")
-			continue
 		}
-		cmdLog.Info("ConfigMap contained parsed results", "ConfigMap.Name", cm.Name, "results", len(cmParsedResults))
 
-		prCtx.AddResults(source, cmParsedResults)
 		// If the CM was processed, annotate it with the result
-		annotateCMWithScanResult(&configMaps[i], cmParsedResults)
+		if isPending[cm] {
+			annotateCMWithScanResult(cm, gotPass)
+		}
 	}
 
 	// Once we gathered all results, try to reconcile those that are inconsistent
 	consistentParsedResults := prCtx.GetConsistentResults()
 
-	// At this point either scanRemediations is nil or contains a list
-	// of remediations for this scan
-	// Create the remediations
+	// The manual checks that were answered take the status of their answer
+	answers, err := getActiveAnswers(crclient, scan, time.Now())
+	if err != nil {
+		cmdLog.Error(err, "Cannot get the answers to the manual checks")
+	} else if applyAnswers(answers, consistentParsedResults) {
+		annotateCMsWithFailedAnswers(pending)
+	}
+
+	// The failures that are excepted stay excepted
+	exceptions, err := getActiveExceptions(crclient, scan, time.Now())
+	if err != nil {
+		cmdLog.Error(err, "Cannot get the exceptions of the failed checks")
+	} else {
+		applyExceptions(exceptions, consistentParsedResults)
+	}
+
+	// Only the results of the configMaps that weren't checkpointed need to
+	// be created. Each configMap is checkpointed as soon as its results are
+	// persisted, so we don't need to re-create them.
+	pendingResults := getPendingResults(consistentParsedResults, pending)
+	checkpointer := newConfigMapCheckpointer(crclient, pending, pendingResults)
+
 	cmdLog.Info("Creating result objects")
-	if err := createResults(crclient, scan, consistentParsedResults); err != nil {
-		cmdLog.Error(err, "Could not create remediation objects")
+	resultErr := createResults(crclient, scan, pendingResults, aggregatorConf.Concurrency, checkpointer)
+	if resultErr != nil {
+		// The results that couldn't be created are reported in the scan
+		// instead of failing the whole aggregation
+		cmdLog.Error(resultErr, "Could not create some of the result objects")
+	}
+
+	if err := checkpointer.finish(); err != nil {
+		cmdLog.Error(err, "Cannot annotate the ConfigMaps")
 		os.Exit(1)
 	}
 
-	// Annotate configMaps, so we don't need to re-parse them
-	cmdLog.Info("Annotating ConfigMaps")
-	for idx := range configMaps {
-		err = markConfigMapAsProcessed(crclient, &configMaps[idx])
-		if err != nil {
-			cmdLog.Error(err, "Cannot annotate the ConfigMap")
-			os.Exit(1)
-		}
+	// The results of the configMaps that were checkpointed before are
+	// exported as well
+	exportErr := storeExports(crclient, scan)
+	if exportErr != nil {
+		cmdLog.Error(exportErr, "Could not export the results")
 	}
+
+	if err := writeAggregationWarnings(aggregatorTerminationLog, utilerrors.NewAggregate([]error{resultErr, exportErr})); err != nil {
+		cmdLog.Error(err, "Cannot report the results that couldn't be created")
+	}
+
+	storeReport(crclient, aggregatorConf, scan)
 }
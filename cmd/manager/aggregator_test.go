@@ -3,11 +3,16 @@ package manager
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	backoff "github.com/cenkalti/backoff/v4"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	ocpcfgv1 "github.com/openshift/api/config/v1"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -21,6 +26,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/utils"
 )
 
 type aggregatorCrClientFake struct {
@@ -296,4 +302,660 @@ var _ = Describe("Aggregator Tests", func() {
 			})
 		})
 	})
+
+	Context("Creating result objects", func() {
+		const nResults = 30
+		var scan *compv1alpha1.ComplianceScan
+		var crClient *aggregatorCrClientFake
+		var ctx context.Context
+
+		parsedResults := func(failing string) []*utils.ParseResultContextItem {
+			var list []*utils.ParseResult
+			for i := 0; i < nResults; i++ {
+				id := fmt.Sprintf("xccdf_org.ssgproject.content_rule_check_%d", i)
+				status := compv1alpha1.CheckResultPass
+				if id == failing {
+					status = compv1alpha1.CheckResultFail
+				}
+				list = append(list, &utils.ParseResult{
+					Id: id,
+					CheckResult: &compv1alpha1.ComplianceCheckResult{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      fmt.Sprintf("foo-check-%d", i),
+							Namespace: "bar",
+						},
+						ID:       id,
+						Status:   status,
+						Severity: compv1alpha1.CheckResultSeverityMedium,
+					},
+				})
+			}
+			prCtx := utils.NewParseResultContext()
+			prCtx.AddResults("", list)
+			return prCtx.GetConsistentResults()
+		}
+
+		resourceVersions := func() map[string]string {
+			results := &compv1alpha1.ComplianceCheckResultList{}
+			Expect(crClient.client.List(ctx, results)).To(Succeed())
+			versions := make(map[string]string)
+			for _, result := range results.Items {
+				versions[result.Name] = result.ResourceVersion
+			}
+			return versions
+		}
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			scheme := getScheme()
+
+			scan = &compv1alpha1.ComplianceScan{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo",
+					Namespace: "bar",
+					UID:       "foo-uid",
+				},
+			}
+			client := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithRuntimeObjects(scan).
+				Build()
+			crClient = &aggregatorCrClientFake{
+				scheme:      scheme,
+				client:      client,
+				recorder:    fakerec.NewFakeRecorder(nResults),
+				fakevgetter: &fakeversionget{},
+			}
+			Expect(createResults(crClient, scan, parsedResults(""), 5, nil)).To(Succeed())
+		})
+
+		It("Creates all the results with several workers", func() {
+			Expect(resourceVersions()).To(HaveLen(nResults))
+		})
+
+		It("Skips updating results that didn't change", func() {
+			before := resourceVersions()
+			Expect(createResults(crClient, scan, parsedResults(""), 5, nil)).To(Succeed())
+			Expect(resourceVersions()).To(Equal(before))
+		})
+
+		It("Only updates the results that changed", func() {
+			before := resourceVersions()
+			Expect(createResults(crClient, scan, parsedResults("xccdf_org.ssgproject.content_rule_check_3"), 5, nil)).To(Succeed())
+			after := resourceVersions()
+			for name, version := range after {
+				if name == "foo-check-3" {
+					Expect(version).ToNot(Equal(before[name]))
+				} else {
+					Expect(version).To(Equal(before[name]))
+				}
+			}
+
+			updated := &compv1alpha1.ComplianceCheckResult{}
+			Expect(crClient.client.Get(ctx, getObjKey("foo-check-3", "bar"), updated)).To(Succeed())
+			Expect(updated.Status).To(Equal(compv1alpha1.CheckResultFail))
+		})
+
+		It("Creates the other results when some can't be created", func() {
+			results := parsedResults("")
+			for _, pr := range results {
+				if pr.Id == "xccdf_org.ssgproject.content_rule_check_3" || pr.Id == "xccdf_org.ssgproject.content_rule_check_7" {
+					// The scan can't own a result in another namespace
+					pr.CheckResult.Name = pr.CheckResult.Name + "-misplaced"
+					pr.CheckResult.Namespace = "baz"
+				}
+			}
+			err := createResults(crClient, scan, results, 5, nil)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("foo-check-3-misplaced"))
+			Expect(err.Error()).To(ContainSubstring("foo-check-7-misplaced"))
+			Expect(resourceVersions()).To(HaveLen(nResults))
+		})
+
+		It("Checkpoints the ConfigMap once its results are created", func() {
+			cm := &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo-platform",
+					Namespace: "bar",
+				},
+			}
+			Expect(crClient.client.Create(ctx, cm)).To(Succeed())
+			results := parsedResults("")
+			checkpointer := newConfigMapCheckpointer(crClient, []*v1.ConfigMap{cm}, results)
+			Expect(createResults(crClient, scan, results, 5, checkpointer)).To(Succeed())
+			Expect(checkpointer.finish()).To(Succeed())
+
+			Expect(crClient.client.Get(ctx, getObjKey("foo-platform", "bar"), cm)).To(Succeed())
+			Expect(cm.Annotations).To(HaveKey(configMapRemediationsProcessed))
+		})
+
+		It("Doesn't checkpoint the ConfigMap when some of its results can't be created", func() {
+			cm := &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo-platform",
+					Namespace: "bar",
+				},
+			}
+			Expect(crClient.client.Create(ctx, cm)).To(Succeed())
+			results := parsedResults("")
+			results[0].CheckResult.Name = results[0].CheckResult.Name + "-misplaced"
+			results[0].CheckResult.Namespace = "baz"
+			checkpointer := newConfigMapCheckpointer(crClient, []*v1.ConfigMap{cm}, results)
+			Expect(createResults(crClient, scan, results, 5, checkpointer)).ToNot(Succeed())
+			Expect(checkpointer.finish()).To(Succeed())
+
+			Expect(crClient.client.Get(ctx, getObjKey("foo-platform", "bar"), cm)).To(Succeed())
+			Expect(cm.Annotations).ToNot(HaveKey(configMapRemediationsProcessed))
+		})
+
+		It("Stores the results in the export formats of the scan", func() {
+			rule := &compv1alpha1.Rule{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ocp4-check-3",
+					Namespace: "bar",
+					Annotations: map[string]string{
+						compv1alpha1.RuleIDAnnotationKey:                         "check-3",
+						compv1alpha1.RuleControlAnnotationPrefix + "NIST-800-53": "AC-2",
+					},
+				},
+			}
+			Expect(crClient.client.Create(ctx, rule)).To(Succeed())
+			scan.Spec.ExportFormats = []compv1alpha1.ExportFormat{
+				compv1alpha1.ExportFormatSARIF,
+				compv1alpha1.ExportFormatJUnit,
+			}
+
+			// The exports are updated by later aggregations
+			for _, failing := range []string{"", "xccdf_org.ssgproject.content_rule_check_3"} {
+				Expect(createResults(crClient, scan, parsedResults(failing), 5, nil)).To(Succeed())
+				Expect(storeExports(crClient, scan)).To(Succeed())
+			}
+
+			sarif := &v1.ConfigMap{}
+			Expect(crClient.client.Get(ctx, getObjKey("foo-sarif-export", "bar"), sarif)).To(Succeed())
+			Expect(sarif.Labels).To(HaveKeyWithValue(compv1alpha1.ComplianceScanLabel, "foo"))
+			Expect(sarif.Labels).To(HaveKeyWithValue(exportFormatLabel, "sarif"))
+			Expect(sarif.OwnerReferences).To(HaveLen(1))
+			Expect(sarif.OwnerReferences[0].Name).To(Equal("foo"))
+			Expect(sarif.Data["results.sarif"]).To(ContainSubstring(`"NIST-800-53:AC-2"`))
+			Expect(sarif.Data["results.sarif"]).To(ContainSubstring(`"kind": "fail"`))
+
+			junit := &v1.ConfigMap{}
+			Expect(crClient.client.Get(ctx, getObjKey("foo-junit-export", "bar"), junit)).To(Succeed())
+			Expect(junit.Data["results.junit.xml"]).To(ContainSubstring(`<testsuites name="foo" tests="30" failures="1"`))
+		})
+
+		It("Exports the results stored by an earlier aggregation when resuming", func() {
+			scan.Spec.ExportFormats = []compv1alpha1.ExportFormat{compv1alpha1.ExportFormatJUnit}
+
+			results := parsedResults("xccdf_org.ssgproject.content_rule_check_3")
+			Expect(createResults(crClient, scan, results[:10], 5, nil)).To(Succeed())
+			Expect(createResults(crClient, scan, results[10:], 5, nil)).To(Succeed())
+			Expect(storeExports(crClient, scan)).To(Succeed())
+
+			junit := &v1.ConfigMap{}
+			Expect(crClient.client.Get(ctx, getObjKey("foo-junit-export", "bar"), junit)).To(Succeed())
+			Expect(junit.Data["results.junit.xml"]).To(ContainSubstring(`<testsuites name="foo" tests="30" failures="1"`))
+		})
+	})
+
+	Context("Parsing the results of custom rules", func() {
+		It("adds the results of the custom rules to the batch", func() {
+			cm := &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo-api-checks-pod",
+					Namespace: "bar",
+				},
+				Data: map[string]string{
+					utils.CustomRulesResultKey: `[{"id":"custom_rule_token-age","status":"FAIL","severity":"high"},` +
+						`{"id":"custom_rule_audit","status":"PASS","severity":"medium"}]`,
+				},
+			}
+
+			prCtx := utils.NewParseResultContext()
+			batch := prCtx.NewBatch("")
+			gotPass, nResults := addCustomRuleResults("foo", "bar", cm, batch)
+			batch.Close()
+			Expect(gotPass).To(BeTrue())
+			Expect(nResults).To(Equal(2))
+
+			results := prCtx.GetConsistentResults()
+			Expect(results).To(HaveLen(2))
+			names := []string{results[0].CheckResult.Name, results[1].CheckResult.Name}
+			Expect(names).To(ConsistOf("foo-custom-rule-token-age", "foo-custom-rule-audit"))
+			for _, pr := range results {
+				Expect(pr.CheckResult.Namespace).To(Equal("bar"))
+				if pr.Id == "custom_rule_token-age" {
+					Expect(pr.CheckResult.Status).To(Equal(compv1alpha1.CheckResultFail))
+					Expect(pr.CheckResult.Severity).To(Equal(compv1alpha1.CheckResultSeverityHigh))
+				}
+			}
+		})
+
+		It("adds nothing if the scan checked no custom rules", func() {
+			cm := &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo-api-checks-pod",
+					Namespace: "bar",
+				},
+				Data: map[string]string{"results": "<xml/>"},
+			}
+
+			prCtx := utils.NewParseResultContext()
+			batch := prCtx.NewBatch("")
+			gotPass, nResults := addCustomRuleResults("foo", "bar", cm, batch)
+			batch.Close()
+			Expect(gotPass).To(BeFalse())
+			Expect(nResults).To(BeZero())
+			Expect(prCtx.GetConsistentResults()).To(BeEmpty())
+		})
+
+		It("marks the probes that differ between nodes as inconsistent", func() {
+			nodeCM := func(node, status string) *v1.ConfigMap {
+				return &v1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "foo-" + node + "-pod",
+						Namespace: "bar",
+					},
+					Data: map[string]string{
+						utils.CustomRulesResultKey: `[{"id":"custom_rule_kubelet-enabled","status":"` + status + `","severity":"medium"}]`,
+					},
+				}
+			}
+
+			prCtx := utils.NewParseResultContext()
+			for _, node := range []struct{ name, status string }{{"node-1", "PASS"}, {"node-2", "FAIL"}} {
+				batch := prCtx.NewBatch(node.name)
+				addCustomRuleResults("foo", "bar", nodeCM(node.name, node.status), batch)
+				batch.Close()
+			}
+
+			results := prCtx.GetConsistentResults()
+			Expect(results).To(HaveLen(1))
+			Expect(results[0].CheckResult.Name).To(Equal("foo-custom-rule-kubelet-enabled"))
+			Expect(results[0].CheckResult.Status).To(Equal(compv1alpha1.CheckResultInconsistent))
+			Expect(results[0].Labels).To(HaveKey(compv1alpha1.ComplianceCheckInconsistentLabel))
+		})
+	})
+
+	Context("Answering manual checks", func() {
+		var scan *compv1alpha1.ComplianceScan
+		var crClient *aggregatorCrClientFake
+		var recorder *fakerec.FakeRecorder
+
+		rule := func(name, id string) *compv1alpha1.Rule {
+			return &compv1alpha1.Rule{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        name,
+					Namespace:   "bar",
+					Annotations: map[string]string{compv1alpha1.RuleIDAnnotationKey: id},
+				},
+			}
+		}
+
+		answer := func(name, rule string, value compv1alpha1.ComplianceCheckStatus, expiresIn time.Duration) *compv1alpha1.ComplianceCheckAnswer {
+			return &compv1alpha1.ComplianceCheckAnswer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: "bar",
+				},
+				Spec: compv1alpha1.ComplianceCheckAnswerSpec{
+					Rule:      rule,
+					Answer:    value,
+					Evidence:  "checked by hand",
+					Author:    "auditor",
+					ExpiresAt: metav1.NewTime(time.Now().Add(expiresIn)),
+				},
+			}
+		}
+
+		manualResults := func(ids ...string) []*utils.ParseResultContextItem {
+			var list []*utils.ParseResult
+			for _, id := range ids {
+				list = append(list, &utils.ParseResult{
+					Id: "xccdf_org.ssgproject.content_rule_" + id,
+					CheckResult: &compv1alpha1.ComplianceCheckResult{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "foo-" + strings.ReplaceAll(id, "_", "-"),
+							Namespace: "bar",
+						},
+						ID:     "xccdf_org.ssgproject.content_rule_" + id,
+						Status: compv1alpha1.CheckResultManual,
+					},
+				})
+			}
+			prCtx := utils.NewParseResultContext()
+			prCtx.AddResults("", list)
+			return prCtx.GetConsistentResults()
+		}
+
+		BeforeEach(func() {
+			scheme := getScheme()
+			scan = &compv1alpha1.ComplianceScan{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo",
+					Namespace: "bar",
+				},
+			}
+
+			withoutEvidence := answer("no-evidence", "ocp4-no-evidence", compv1alpha1.CheckResultPass, time.Hour)
+			withoutEvidence.Spec.Evidence = ""
+			otherScan := answer("other-scan", "ocp4-other-scan", compv1alpha1.CheckResultPass, time.Hour)
+			otherScan.Spec.Scans = []string{"baz"}
+			withRef := answer("with-ref", "ocp4-with-ref", compv1alpha1.CheckResultFail, time.Hour)
+			withRef.Spec.Evidence = ""
+			withRef.Spec.EvidenceRef = "https://example.com/audit.pdf"
+			older := answer("older", "ocp4-answered", compv1alpha1.CheckResultFail, time.Hour)
+			older.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Hour))
+			newer := answer("newer", "ocp4-answered", compv1alpha1.CheckResultPass, time.Hour)
+			newer.CreationTimestamp = metav1.NewTime(time.Now())
+
+			client := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithRuntimeObjects(
+					scan,
+					rule("ocp4-answered", "answered"),
+					rule("ocp4-expired", "expired"),
+					rule("ocp4-no-evidence", "no-evidence"),
+					rule("ocp4-other-scan", "other-scan"),
+					rule("ocp4-with-ref", "with-ref"),
+					older, newer,
+					answer("expired", "ocp4-expired", compv1alpha1.CheckResultPass, -time.Hour),
+					withoutEvidence, otherScan, withRef,
+					answer("missing-rule", "ocp4-missing", compv1alpha1.CheckResultPass, time.Hour),
+				).
+				Build()
+			recorder = fakerec.NewFakeRecorder(10)
+			crClient = &aggregatorCrClientFake{
+				scheme:      scheme,
+				client:      client,
+				recorder:    recorder,
+				fakevgetter: &fakeversionget{},
+			}
+		})
+
+		It("Only returns the answers in effect for the scan", func() {
+			answers, err := getActiveAnswers(crClient, scan, time.Now())
+			Expect(err).To(BeNil())
+			Expect(answers).To(HaveLen(2))
+			Expect(answers).To(HaveKey("answered"))
+			Expect(answers["answered"].Name).To(Equal("newer"))
+			Expect(answers).To(HaveKey("with-ref"))
+
+			// The invalid answers are reported
+			Expect(recorder.Events).To(HaveLen(2))
+			Expect(recorder.Events).To(Receive(ContainSubstring("missing-rule")))
+			Expect(recorder.Events).To(Receive(ContainSubstring("no-evidence")))
+		})
+
+		It("Sets the status of the answered manual checks", func() {
+			answers, err := getActiveAnswers(crClient, scan, time.Now())
+			Expect(err).To(BeNil())
+			results := manualResults("answered", "expired", "with_ref")
+			Expect(applyAnswers(answers, results)).To(BeTrue())
+
+			statuses := map[string]compv1alpha1.ComplianceCheckStatus{}
+			for _, pr := range results {
+				statuses[pr.CheckResult.Name] = pr.CheckResult.Status
+				if pr.CheckResult.Status != compv1alpha1.CheckResultManual {
+					Expect(pr.Annotations).To(HaveKey(compv1alpha1.ComplianceCheckResultAnswerAnnotation))
+				} else {
+					Expect(pr.Annotations).ToNot(HaveKey(compv1alpha1.ComplianceCheckResultAnswerAnnotation))
+				}
+			}
+			Expect(statuses).To(Equal(map[string]compv1alpha1.ComplianceCheckStatus{
+				"foo-answered": compv1alpha1.CheckResultPass,
+				"foo-expired":  compv1alpha1.CheckResultManual,
+				"foo-with-ref": compv1alpha1.CheckResultFail,
+			}))
+		})
+
+		It("Doesn't answer the checks that aren't manual", func() {
+			answers, err := getActiveAnswers(crClient, scan, time.Now())
+			Expect(err).To(BeNil())
+			results := manualResults("with_ref")
+			results[0].CheckResult.Status = compv1alpha1.CheckResultPass
+			Expect(applyAnswers(answers, results)).To(BeFalse())
+			Expect(results[0].CheckResult.Status).To(Equal(compv1alpha1.CheckResultPass))
+		})
+
+		It("Marks compliant scans as non-compliant when a check was answered to fail", func() {
+			compliant := &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{compv1alpha1.CmScanResultAnnotation: string(compv1alpha1.ResultCompliant)},
+			}}
+			errored := &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{compv1alpha1.CmScanResultAnnotation: string(compv1alpha1.ResultError)},
+			}}
+			annotateCMsWithFailedAnswers([]*v1.ConfigMap{compliant, errored})
+			Expect(compliant.Annotations[compv1alpha1.CmScanResultAnnotation]).To(Equal(string(compv1alpha1.ResultNonCompliant)))
+			Expect(errored.Annotations[compv1alpha1.CmScanResultAnnotation]).To(Equal(string(compv1alpha1.ResultError)))
+		})
+	})
+
+	Context("Excepting failed checks", func() {
+		var scan *compv1alpha1.ComplianceScan
+		var crClient *aggregatorCrClientFake
+
+		exception := func(name, rule string, expiresIn time.Duration, roles ...string) *compv1alpha1.ComplianceException {
+			return &compv1alpha1.ComplianceException{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: "bar",
+				},
+				Spec: compv1alpha1.ComplianceExceptionSpec{
+					Rule:          rule,
+					Scope:         compv1alpha1.ExceptionScope{NodeRoles: roles},
+					Justification: "accepted",
+					ExpiresAt:     metav1.NewTime(time.Now().Add(expiresIn)),
+				},
+			}
+		}
+
+		failedResult := func(id string) *utils.ParseResultContextItem {
+			prCtx := utils.NewParseResultContext()
+			prCtx.AddResults("", []*utils.ParseResult{{
+				Id: "xccdf_org.ssgproject.content_rule_" + id,
+				CheckResult: &compv1alpha1.ComplianceCheckResult{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "foo-" + strings.ReplaceAll(id, "_", "-"),
+						Namespace: "bar",
+					},
+					ID:     "xccdf_org.ssgproject.content_rule_" + id,
+					Status: compv1alpha1.CheckResultFail,
+				},
+			}})
+			return prCtx.GetConsistentResults()[0]
+		}
+
+		BeforeEach(func() {
+			scheme := getScheme()
+			scan = &compv1alpha1.ComplianceScan{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo",
+					Namespace: "bar",
+				},
+				Spec: compv1alpha1.ComplianceScanSpec{
+					NodeSelector: map[string]string{"node-role.kubernetes.io/worker": ""},
+				},
+			}
+
+			rules := []runtime.Object{}
+			for _, id := range []string{"excepted", "expired", "master_only"} {
+				rules = append(rules, &compv1alpha1.Rule{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        "ocp4-" + strings.ReplaceAll(id, "_", "-"),
+						Namespace:   "bar",
+						Annotations: map[string]string{compv1alpha1.RuleIDAnnotationKey: strings.ReplaceAll(id, "_", "-")},
+					},
+				})
+			}
+			older := exception("older", "ocp4-excepted", time.Hour)
+			older.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Hour))
+			newer := exception("newer", "ocp4-excepted", time.Hour, "worker")
+			newer.CreationTimestamp = metav1.NewTime(time.Now())
+
+			client := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithRuntimeObjects(rules...).
+				WithRuntimeObjects(
+					scan, older, newer,
+					exception("expired", "ocp4-expired", -time.Hour),
+					exception("master-only", "ocp4-master-only", time.Hour, "master"),
+					exception("missing-rule", "ocp4-missing", time.Hour),
+				).
+				Build()
+			crClient = &aggregatorCrClientFake{
+				scheme:      scheme,
+				client:      client,
+				recorder:    fakerec.NewFakeRecorder(10),
+				fakevgetter: &fakeversionget{},
+			}
+		})
+
+		It("Only returns the exceptions in effect for the scan", func() {
+			exceptions, err := getActiveExceptions(crClient, scan, time.Now())
+			Expect(err).To(BeNil())
+			Expect(exceptions).To(HaveLen(1))
+			Expect(exceptions).To(HaveKey("excepted"))
+			Expect(exceptions["excepted"].Name).To(Equal("older"))
+		})
+
+		It("Keeps the failures of the excepted checks excepted", func() {
+			exceptions, err := getActiveExceptions(crClient, scan, time.Now())
+			Expect(err).To(BeNil())
+			excepted := failedResult("excepted")
+			expired := failedResult("expired")
+			applyExceptions(exceptions, []*utils.ParseResultContextItem{excepted, expired})
+
+			Expect(excepted.CheckResult.Status).To(Equal(compv1alpha1.CheckResultExcepted))
+			Expect(excepted.Annotations).To(HaveKeyWithValue(compv1alpha1.ComplianceCheckResultExceptionAnnotation, "bar/older"))
+			Expect(excepted.Annotations).To(HaveKeyWithValue(compv1alpha1.ComplianceCheckResultExceptedStatusAnnotation, "FAIL"))
+			labels := getCheckResultLabels(&excepted.ParseResult, excepted.Labels, scan)
+			Expect(labels).To(HaveKeyWithValue(compv1alpha1.ComplianceCheckResultStatusLabel, "EXCEPTED"))
+
+			Expect(expired.CheckResult.Status).To(Equal(compv1alpha1.CheckResultFail))
+			Expect(expired.Annotations).ToNot(HaveKey(compv1alpha1.ComplianceCheckResultExceptionAnnotation))
+		})
+
+		It("Doesn't except the checks that passed", func() {
+			exceptions, err := getActiveExceptions(crClient, scan, time.Now())
+			Expect(err).To(BeNil())
+			passed := failedResult("excepted")
+			passed.CheckResult.Status = compv1alpha1.CheckResultPass
+			applyExceptions(exceptions, []*utils.ParseResultContextItem{passed})
+			Expect(passed.CheckResult.Status).To(Equal(compv1alpha1.CheckResultPass))
+		})
+	})
+
+	Context("Resuming the aggregation", func() {
+		var crClient *aggregatorCrClientFake
+		var configMaps []v1.ConfigMap
+
+		listConfigMaps := func() []v1.ConfigMap {
+			cmList := &v1.ConfigMapList{}
+			Expect(crClient.client.List(context.TODO(), cmList)).To(Succeed())
+			return cmList.Items
+		}
+
+		BeforeEach(func() {
+			scheme := getScheme()
+			processed := &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo-node-1",
+					Namespace: "bar",
+					Annotations: map[string]string{
+						configMapRemediationsProcessed: "",
+						"openscap-scan-result/node":    "node-1",
+					},
+				},
+			}
+			unprocessed := &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "foo-node-2",
+					Namespace:   "bar",
+					Annotations: map[string]string{"openscap-scan-result/node": "node-2"},
+				},
+			}
+			client := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithRuntimeObjects(processed, unprocessed).
+				Build()
+			crClient = &aggregatorCrClientFake{
+				scheme:      scheme,
+				client:      client,
+				recorder:    fakerec.NewFakeRecorder(10),
+				fakevgetter: &fakeversionget{},
+			}
+			configMaps = listConfigMaps()
+		})
+
+		It("Only returns the ConfigMaps that weren't processed", func() {
+			pending := getPendingConfigMaps(configMaps)
+			Expect(pending).To(HaveLen(1))
+			Expect(pending[0].Name).To(Equal("foo-node-2"))
+		})
+
+		It("Doesn't return anything once the ConfigMaps are checkpointed", func() {
+			checkpointer := newConfigMapCheckpointer(crClient, getPendingConfigMaps(configMaps), nil)
+			Expect(checkpointer.finish()).To(Succeed())
+			Expect(getPendingConfigMaps(listConfigMaps())).To(BeEmpty())
+		})
+
+		It("Only returns the results the pending ConfigMaps contributed to", func() {
+			result := func(id string) *utils.ParseResult {
+				return &utils.ParseResult{
+					Id: id,
+					CheckResult: &compv1alpha1.ComplianceCheckResult{
+						ID:     id,
+						Status: compv1alpha1.CheckResultPass,
+					},
+				}
+			}
+			prCtx := utils.NewParseResultContext()
+			prCtx.AddResults("node-1", []*utils.ParseResult{result("shared"), result("node-1-only")})
+			prCtx.AddResults("node-2", []*utils.ParseResult{result("shared")})
+
+			pendingResults := getPendingResults(prCtx.GetConsistentResults(), getPendingConfigMaps(configMaps))
+			Expect(pendingResults).To(HaveLen(1))
+			Expect(pendingResults[0].Id).To(Equal("shared"))
+		})
+	})
+
+	Context("Reporting the results that couldn't be created", func() {
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = os.MkdirTemp("", "aggregator")
+			Expect(err).To(BeNil())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		It("Doesn't write anything without errors", func() {
+			path := filepath.Join(dir, "termination-log")
+			Expect(writeAggregationWarnings(path, nil)).To(Succeed())
+			_, err := os.Stat(path)
+			Expect(os.IsNotExist(err)).To(BeTrue())
+		})
+
+		It("Writes the errors to the termination message", func() {
+			path := filepath.Join(dir, "termination-log")
+			Expect(writeAggregationWarnings(path, fmt.Errorf("cannot create foo-check-3"))).To(Succeed())
+			msg, err := os.ReadFile(path)
+			Expect(err).To(BeNil())
+			Expect(string(msg)).To(Equal("Some results couldn't be created or updated: cannot create foo-check-3"))
+		})
+
+		It("Truncates the errors that don't fit in a termination message", func() {
+			path := filepath.Join(dir, "termination-log")
+			Expect(writeAggregationWarnings(path, fmt.Errorf("%s", strings.Repeat("x", 2*maxTerminationMessageLength)))).To(Succeed())
+			msg, err := os.ReadFile(path)
+			Expect(err).To(BeNil())
+			Expect(msg).To(HaveLen(maxTerminationMessageLength))
+			Expect(string(msg)).To(HaveSuffix("..."))
+		})
+	})
 })
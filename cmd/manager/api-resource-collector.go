@@ -16,6 +16,7 @@ limitations under the License.
 package manager
 
 import (
+	"context"
 	"flag"
 
 	"k8s.io/apimachinery/pkg/runtime"
@@ -63,6 +64,11 @@ type fetcherConfig struct {
 	Profile            string
 	ExitCodeFile       string
 	WarningsOutputFile string
+
+	// The CustomRules of the tailored profile, checked after the resources
+	// are fetched
+	CustomRules           string
+	CustomRulesOutputFile string
 }
 
 func defineAPIResourceCollectorFlags(cmd *cobra.Command) {
@@ -73,6 +79,8 @@ func defineAPIResourceCollectorFlags(cmd *cobra.Command) {
 	cmd.Flags().String("warnings-output-file", "", "A file containing the warnings output.")
 	cmd.Flags().Bool("debug", false, "Print debug messages.")
 	cmd.Flags().String("platform", "", "The platform flag used by CPE detection.")
+	cmd.Flags().String("custom-rules", "", "The path to the custom rules of the tailored profile.")
+	cmd.Flags().String("custom-rules-output-file", "", "A file to write the results of the custom rules to.")
 
 	flags := cmd.Flags()
 
@@ -89,6 +97,11 @@ func parseAPIResourceCollectorConfig(cmd *cobra.Command) *fetcherConfig {
 	conf.WarningsOutputFile = getValidStringArg(cmd, "warnings-output-file")
 	debugLog, _ = cmd.Flags().GetBool("debug")
 	conf.Tailoring, _ = cmd.Flags().GetString("tailoring")
+	conf.CustomRules, _ = cmd.Flags().GetString("custom-rules")
+	conf.CustomRulesOutputFile, _ = cmd.Flags().GetString("custom-rules-output-file")
+	if conf.CustomRules != "" && conf.CustomRulesOutputFile == "" {
+		FATAL("The custom-rules-output-file flag is required with custom-rules")
+	}
 	return &conf
 }
 
@@ -149,4 +162,24 @@ func runAPIResourceCollector(cmd *cobra.Command, args []string) {
 	if err := fetcher.SaveResources(fetcherConf.ResultDir); err != nil {
 		FATAL("Error saving resources: %v", err)
 	}
+
+	if fetcherConf.CustomRules == "" {
+		return
+	}
+	rules, err := loadCustomRules(fetcherConf.CustomRules)
+	if err != nil {
+		FATAL("Error loading custom rules: %v", err)
+	}
+	if len(rules) == 0 {
+		return
+	}
+	rfClients := resourceFetcherClients{
+		client:    client,
+		clientset: kubeClientSet,
+		scheme:    scheme,
+	}
+	results := checkCustomRules(context.TODO(), getStreamerFn, rfClients, rules)
+	if err := saveCustomRuleResults(results, fetcherConf.CustomRulesOutputFile); err != nil {
+		FATAL("Error saving custom rule results: %v", err)
+	}
 }
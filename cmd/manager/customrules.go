@@ -0,0 +1,126 @@
+/*
+Copyright © 2020 Red Hat Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/utils"
+)
+
+// loadCustomRules reads the CustomRules the tailoring ConfigMap of the scan
+// holds. A tailored profile without custom rules has none.
+func loadCustomRules(path string) ([]compv1alpha1.CustomRule, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var rules []compv1alpha1.CustomRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("cannot parse the custom rules: %w", err)
+	}
+	return rules, nil
+}
+
+// fetchCustomRuleInput returns the decoded resource at the path, or nil if
+// the resource doesn't exist
+func fetchCustomRuleInput(ctx context.Context, streamDispatcher streamerDispatcherFn,
+	rfClients resourceFetcherClients, path string) (interface{}, error) {
+	stream, err := streamDispatcher(path).Stream(ctx, rfClients)
+	if kerrors.IsNotFound(err) {
+		DBG("The input %s of a custom rule doesn't exist", path)
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("could not fetch %s: %w", path, err)
+	}
+	defer stream.Close()
+
+	var resource interface{}
+	if err := json.NewDecoder(stream).Decode(&resource); err != nil {
+		return nil, fmt.Errorf("could not decode %s: %w", path, err)
+	}
+	return resource, nil
+}
+
+// checkCustomRules evaluates the rules against the resources of their
+// inputs. A rule whose inputs can't be fetched is an ERROR. Each resource is
+// only fetched once, even if several rules check it. The probes of node
+// checks are left to the probe runner.
+func checkCustomRules(ctx context.Context, streamDispatcher streamerDispatcherFn,
+	rfClients resourceFetcherClients, rules []compv1alpha1.CustomRule) []*compv1alpha1.ComplianceCheckResult {
+	type fetched struct {
+		resource interface{}
+		err      error
+	}
+	cache := make(map[string]fetched)
+
+	results := make([]*compv1alpha1.ComplianceCheckResult, 0, len(rules))
+	for i := range rules {
+		rule := &rules[i]
+		if rule.CheckType() != compv1alpha1.CheckTypePlatform {
+			continue
+		}
+
+		resources := make(map[string]interface{}, len(rule.Spec.Inputs))
+		var fetchErr error
+		for _, input := range rule.Spec.Inputs {
+			f, ok := cache[input.Path]
+			if !ok {
+				f.resource, f.err = fetchCustomRuleInput(ctx, streamDispatcher, rfClients, input.Path)
+				cache[input.Path] = f
+			}
+			if f.err != nil {
+				fetchErr = f.err
+				break
+			}
+			resources[input.Name] = f.resource
+		}
+		if fetchErr != nil {
+			LOG("Custom rule %s: %s", rule.Name, fetchErr)
+			results = append(results, utils.NewCustomRuleCheckResult(rule, compv1alpha1.CheckResultError, fetchErr.Error()))
+			continue
+		}
+
+		status, err := utils.EvaluateCustomRule(rule, resources)
+		warning := ""
+		if err != nil {
+			warning = err.Error()
+		}
+		LOG("Custom rule %s: %s", rule.Name, status)
+		results = append(results, utils.NewCustomRuleCheckResult(rule, status, warning))
+	}
+	return results
+}
+
+// saveCustomRuleResults writes the results of the custom rules for the log
+// collector to upload
+func saveCustomRuleResults(results []*compv1alpha1.ComplianceCheckResult, to string) error {
+	data, err := json.Marshal(results)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Clean(to), data, 0600)
+}
@@ -0,0 +1,143 @@
+package manager
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+)
+
+// jsonFetcher streams a fixed JSON document, counting how often it's fetched
+type jsonFetcher struct {
+	data    string
+	fetched *int
+}
+
+func (jf *jsonFetcher) Stream(_ context.Context, _ resourceFetcherClients) (io.ReadCloser, error) {
+	*jf.fetched++
+	return io.NopCloser(strings.NewReader(jf.data)), nil
+}
+
+// errorFetcher fails to stream with a fixed error
+type errorFetcher struct {
+	err error
+}
+
+func (ef *errorFetcher) Stream(_ context.Context, _ resourceFetcherClients) (io.ReadCloser, error) {
+	return nil, ef.err
+}
+
+var _ = Describe("Testing custom rules", func() {
+	const (
+		oauthPath     = "/apis/config.openshift.io/v1/oauths/cluster"
+		apiServerPath = "/apis/config.openshift.io/v1/apiservers/cluster"
+		missingPath   = "/apis/config.openshift.io/v1/proxies/cluster"
+		forbiddenPath = "/api/v1/secrets"
+	)
+
+	var (
+		fetched    map[string]*int
+		dispatcher streamerDispatcherFn
+	)
+
+	newRule := func(name, expression string, inputs ...compv1alpha1.CustomRuleInput) compv1alpha1.CustomRule {
+		rule := compv1alpha1.CustomRule{
+			Spec: compv1alpha1.CustomRuleSpec{
+				Title:          "Rule " + name,
+				Severity:       compv1alpha1.CheckResultSeverityHigh,
+				Inputs:         inputs,
+				Expression:     expression,
+				FailureMessage: "Rule " + name + " failed",
+			},
+		}
+		rule.Name = name
+		return rule
+	}
+
+	BeforeEach(func() {
+		fetched = map[string]*int{oauthPath: new(int), apiServerPath: new(int)}
+		documents := map[string]string{
+			oauthPath:     `{"spec":{"tokenConfig":{"accessTokenMaxAgeSeconds":3600}}}`,
+			apiServerPath: `{"spec":{"audit":{"profile":"None"}}}`,
+		}
+		dispatcher = func(uri string) resourceStreamer {
+			switch uri {
+			case missingPath:
+				return &notFoundFetcher{}
+			case forbiddenPath:
+				return &errorFetcher{err: errors.NewForbidden(schema.GroupResource{Resource: "secrets"}, "", nil)}
+			}
+			return &jsonFetcher{data: documents[uri], fetched: fetched[uri]}
+		}
+	})
+
+	It("checks the rules against the fetched resources", func() {
+		rules := []compv1alpha1.CustomRule{
+			newRule("token-age", "oauth.spec.tokenConfig.accessTokenMaxAgeSeconds <= 86400",
+				compv1alpha1.CustomRuleInput{Name: "oauth", Path: oauthPath}),
+			newRule("audit", "apiserver.spec.audit.profile != 'None'",
+				compv1alpha1.CustomRuleInput{Name: "apiserver", Path: apiServerPath}),
+			newRule("both", "oauth != null && apiserver != null",
+				compv1alpha1.CustomRuleInput{Name: "oauth", Path: oauthPath},
+				compv1alpha1.CustomRuleInput{Name: "apiserver", Path: apiServerPath}),
+		}
+
+		results := checkCustomRules(context.TODO(), dispatcher, resourceFetcherClients{}, rules)
+		Expect(results).To(HaveLen(3))
+		Expect(results[0].ID).To(Equal("custom_rule_token-age"))
+		Expect(results[0].Status).To(Equal(compv1alpha1.CheckResultPass))
+		Expect(results[0].Severity).To(Equal(compv1alpha1.CheckResultSeverityHigh))
+		Expect(results[0].Warnings).To(BeEmpty())
+		Expect(results[1].ID).To(Equal("custom_rule_audit"))
+		Expect(results[1].Status).To(Equal(compv1alpha1.CheckResultFail))
+		Expect(results[1].Warnings).To(ConsistOf("Rule audit failed"))
+		Expect(results[2].Status).To(Equal(compv1alpha1.CheckResultPass))
+
+		By("fetching each resource once")
+		Expect(*fetched[oauthPath]).To(Equal(1))
+		Expect(*fetched[apiServerPath]).To(Equal(1))
+	})
+
+	It("makes a resource that doesn't exist null", func() {
+		rules := []compv1alpha1.CustomRule{
+			newRule("no-proxy", "proxy == null",
+				compv1alpha1.CustomRuleInput{Name: "proxy", Path: missingPath}),
+		}
+
+		results := checkCustomRules(context.TODO(), dispatcher, resourceFetcherClients{}, rules)
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].Status).To(Equal(compv1alpha1.CheckResultPass))
+	})
+
+	It("reports an error if a resource can't be fetched", func() {
+		rules := []compv1alpha1.CustomRule{
+			newRule("secrets", "size(secrets.items) > 0",
+				compv1alpha1.CustomRuleInput{Name: "secrets", Path: forbiddenPath}),
+		}
+
+		results := checkCustomRules(context.TODO(), dispatcher, resourceFetcherClients{}, rules)
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].Status).To(Equal(compv1alpha1.CheckResultError))
+		Expect(results[0].Warnings).To(HaveLen(1))
+		Expect(results[0].Warnings[0]).To(ContainSubstring("could not fetch " + forbiddenPath))
+	})
+
+	It("reports an error if the expression can't be evaluated", func() {
+		rules := []compv1alpha1.CustomRule{
+			newRule("no-such-field", "oauth.spec.identityProviders.size() > 0",
+				compv1alpha1.CustomRuleInput{Name: "oauth", Path: oauthPath}),
+		}
+
+		results := checkCustomRules(context.TODO(), dispatcher, resourceFetcherClients{}, rules)
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].Status).To(Equal(compv1alpha1.CheckResultError))
+		Expect(results[0].Warnings).To(HaveLen(1))
+		Expect(results[0].Warnings[0]).To(ContainSubstring("cannot evaluate the expression"))
+	})
+})
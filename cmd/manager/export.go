@@ -0,0 +1,252 @@
+/*
+Copyright © 2020 Red Hat Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package manager
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	goerrors "errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/compliancescan"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/export"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/utils"
+)
+
+// The label of the ConfigMaps the exported results of a scan are stored in
+const exportFormatLabel = "compliance.openshift.io/export-format"
+
+var ExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Exports the results of a scan to SARIF or JUnit.",
+	Long: "Exports the ComplianceCheckResults of a scan, or the results of a raw ARF " +
+		"report, to SARIF or JUnit.",
+	Run: exportMain,
+}
+
+func init() {
+	defineExportFlags(ExportCmd)
+}
+
+type exportConfig struct {
+	Format    compv1alpha1.ExportFormat
+	Output    string
+	ScanName  string
+	Namespace string
+	ArfFile   string
+	Content   string
+}
+
+func defineExportFlags(cmd *cobra.Command) {
+	cmd.Flags().String("format", "", "The format to export to, sarif or junit.")
+	cmd.Flags().String("output", "-", "The file to write to, or - for the standard output.")
+	cmd.Flags().String("scan", "", "The scan whose results are exported.")
+	cmd.Flags().String("namespace", "openshift-compliance", "The namespace of the scan.")
+	cmd.Flags().String("arf-file", "", "A raw ARF report to export instead of the results of the scan.")
+	cmd.Flags().String("content", "", "The data stream the ARF report was scanned with.")
+}
+
+func parseExportConfig(cmd *cobra.Command) *exportConfig {
+	var conf exportConfig
+	var err error
+	conf.Format, err = export.ParseFormat(getValidStringArg(cmd, "format"))
+	if err != nil {
+		FATAL("%v", err)
+	}
+	conf.Output, _ = cmd.Flags().GetString("output")
+	conf.ScanName, _ = cmd.Flags().GetString("scan")
+	conf.Namespace, _ = cmd.Flags().GetString("namespace")
+	conf.ArfFile, _ = cmd.Flags().GetString("arf-file")
+	conf.Content, _ = cmd.Flags().GetString("content")
+	if conf.ArfFile == "" && conf.ScanName == "" {
+		FATAL("Either the scan or the arf-file flag is required")
+	}
+	if conf.ArfFile != "" && conf.Content == "" {
+		FATAL("The content flag is required with arf-file")
+	}
+	return &conf
+}
+
+func exportMain(cmd *cobra.Command, args []string) {
+	conf := parseExportConfig(cmd)
+
+	var results *export.Results
+	var err error
+	if conf.ArfFile != "" {
+		results, err = getArfResults(conf)
+	} else {
+		var crclient *complianceCrClient
+		crclient, err = createCrClient(getConfig())
+		if err != nil {
+			FATAL("Cannot create kube client for compliance-operator types: %v", err)
+		}
+		results, err = export.GetScanResults(context.TODO(), crclient.getClient(), conf.ScanName, conf.Namespace)
+	}
+	if err != nil {
+		FATAL("Cannot get the results to export: %v", err)
+	}
+
+	out := os.Stdout
+	if conf.Output != "-" {
+		out, err = os.Create(filepath.Clean(conf.Output))
+		if err != nil {
+			FATAL("Cannot create the output file: %v", err)
+		}
+		// #nosec
+		defer out.Close()
+	}
+	if err := export.Render(conf.Format, results, out); err != nil {
+		FATAL("Cannot export the results: %v", err)
+	}
+}
+
+// getArfResults returns the results of the ARF report of the configuration,
+// parsed with its data stream. The controls of the rules aren't known
+// without the Rule objects.
+func getArfResults(conf *exportConfig) (*export.Results, error) {
+	contentFile, err := readContent(conf.Content)
+	if err != nil {
+		return nil, err
+	}
+	// #nosec
+	defer contentFile.Close()
+	contentDom, err := utils.ParseContent(bufio.NewReader(contentFile))
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse the content: %w", err)
+	}
+
+	arfReader, err := openArfFile(conf.ArfFile)
+	if err != nil {
+		return nil, err
+	}
+
+	name := conf.ScanName
+	if name == "" {
+		name = strings.SplitN(filepath.Base(conf.ArfFile), ".", 2)[0]
+	}
+	// The results are still parsed when their remediations can't be
+	// rendered, e.g. because of unset variables. Only the results are
+	// exported.
+	parsed, parseErr := utils.ParseResultsFromContentAndXccdf(getScheme(), name, conf.Namespace, contentDom, arfReader, nil)
+
+	results := &export.Results{Name: name}
+	for _, pr := range parsed {
+		if pr.CheckResult != nil {
+			results.Checks = append(results.Checks, *pr.CheckResult)
+		}
+	}
+	if len(results.Checks) == 0 {
+		if parseErr != nil {
+			return nil, fmt.Errorf("cannot parse the ARF report: %w", parseErr)
+		}
+		return nil, goerrors.New("the ARF report has no results")
+	}
+	if parseErr != nil {
+		// The export might go to the standard output
+		fmt.Fprintf(os.Stderr, "Some results of the ARF report couldn't be fully parsed: %v\n", parseErr)
+	}
+	return results, nil
+}
+
+// openArfFile reads an ARF report, as stored by the result server: reports
+// with the .bzip2 extension were compressed by the result collector
+func openArfFile(path string) (io.Reader, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasSuffix(path, ".bzip2") {
+		return readCompressedData(string(data))
+	}
+	return bytes.NewReader(data), nil
+}
+
+// getExportConfigMapName returns the name of the ConfigMap the results of
+// the scan exported in the format are stored in
+func getExportConfigMapName(scanName string, format compv1alpha1.ExportFormat) string {
+	return fmt.Sprintf("%s-%s-export", scanName, format)
+}
+
+// storeExports stores the exports of the scan, each format in a ConfigMap
+// owned by the scan, if its forwarder exports the results
+func storeExports(crClient aggregatorCrClient, scan *compv1alpha1.ComplianceScan) error {
+	f, ok := compliancescan.NewForwarder(scan).(compliancescan.ExportingForwarder)
+	if !ok {
+		return nil
+	}
+	exports, err := f.Export(context.TODO(), crClient.getClient())
+	if err != nil {
+		return fmt.Errorf("cannot export the results: %w", err)
+	}
+
+	for _, format := range scan.Spec.ExportFormats {
+		if err := storeExport(crClient, scan, format, exports[format]); err != nil {
+			return fmt.Errorf("cannot store the %s export: %w", format, err)
+		}
+		cmdLog.Info("Stored the exported results", "format", format)
+	}
+	return nil
+}
+
+func storeExport(crClient aggregatorCrClient, scan *compv1alpha1.ComplianceScan, format compv1alpha1.ExportFormat, data []byte) error {
+	cm := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getExportConfigMapName(scan.Name, format),
+			Namespace: scan.Namespace,
+			Labels: map[string]string{
+				compv1alpha1.ComplianceScanLabel: scan.Name,
+				exportFormatLabel:                string(format),
+			},
+		},
+		Data: map[string]string{},
+	}
+	// Large exports are compressed like the results of the scan
+	cmData, compressed, err := utils.GetConfigMapData(data)
+	if err != nil {
+		return err
+	}
+	if compressed {
+		cm.Annotations = map[string]string{configMapCompressed: ""}
+	}
+	cm.Data[export.FileName(format)] = cmData
+	if err := controllerutil.SetControllerReference(scan, cm, crClient.getScheme()); err != nil {
+		return err
+	}
+
+	found := &v1.ConfigMap{}
+	err = crClient.getClient().Get(context.TODO(), getObjKey(cm.Name, cm.Namespace), found)
+	if errors.IsNotFound(err) {
+		return crClient.getClient().Create(context.TODO(), cm)
+	} else if err != nil {
+		return err
+	}
+	found.Labels = cm.Labels
+	found.Annotations = cm.Annotations
+	found.Data = cm.Data
+	return crClient.getClient().Update(context.TODO(), found)
+}
@@ -0,0 +1,42 @@
+package manager
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+)
+
+var _ = Describe("Testing the export of results", func() {
+	It("compresses the exports that are too large for a ConfigMap", func() {
+		scan := &compv1alpha1.ComplianceScan{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"},
+		}
+		crClient := &aggregatorCrClientFake{
+			scheme: getScheme(),
+			client: fake.NewClientBuilder().WithScheme(getScheme()).Build(),
+		}
+		data := []byte(strings.Repeat("<testcase/>\n", 100000))
+		Expect(storeExport(crClient, scan, compv1alpha1.ExportFormatJUnit, data)).To(Succeed())
+
+		cm := &v1.ConfigMap{}
+		Expect(crClient.client.Get(context.TODO(), getObjKey("foo-junit-export", "bar"), cm)).To(Succeed())
+		Expect(cm.Annotations).To(HaveKey(configMapCompressed))
+		reader, err := readCompressedData(cm.Data["results.junit.xml"])
+		Expect(err).To(BeNil())
+		decompressed, err := io.ReadAll(reader)
+		Expect(err).To(BeNil())
+		Expect(decompressed).To(Equal(data))
+	})
+
+	It("names the ConfigMaps of the exports after the scan and the format", func() {
+		Expect(getExportConfigMapName("ocp4-cis", compv1alpha1.ExportFormatJUnit)).To(Equal("ocp4-cis-junit-export"))
+	})
+})
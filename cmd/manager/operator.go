@@ -24,6 +24,7 @@ import (
 	monitoring "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	monclientv1 "github.com/prometheus-operator/prometheus-operator/pkg/client/versioned/typed/monitoring/v1"
 	"github.com/spf13/cobra"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
@@ -50,6 +51,7 @@ import (
 	"github.com/ComplianceAsCode/compliance-operator/pkg/controller"
 	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/common"
 	ctrlMetrics "github.com/ComplianceAsCode/compliance-operator/pkg/controller/metrics"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/requester"
 	"github.com/ComplianceAsCode/compliance-operator/pkg/utils"
 	"github.com/ComplianceAsCode/compliance-operator/version"
 )
@@ -92,6 +94,9 @@ var (
 	metricsHost                      = "0.0.0.0"
 	metricsServiceName               = "metrics"
 	metricsPort                int32 = 8383
+	webhookPort                int32 = 9443
+	servingCertDir                   = "/var/run/secrets/serving-cert"
+	requesterWebhookName             = "compliance-operator-requester"
 	defaultProductsPerPlatform       = map[PlatformType][]string{
 		PlatformOpenShift: {
 			"rhcos4",
@@ -228,11 +233,18 @@ func RunOperator(cmd *cobra.Command, args []string) {
 	kubeClient := kubernetes.NewForConfigOrDie(cfg)
 	monitoringClient := monclientv1.NewForConfigOrDie(cfg)
 
+	webhookServer := webhook.NewServer(webhook.Options{
+		Port:     int(webhookPort),
+		CertDir:  servingCertDir,
+		CertName: "tls.crt",
+		KeyName:  "tls.key",
+	})
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Cache:                  c,
 		Scheme:                 operatorScheme,
 		Metrics:                metricsserver.Options{BindAddress: fmt.Sprintf("%s:%d", metricsHost, metricsPort)},
-		WebhookServer:          webhook.NewServer(webhook.Options{Port: 9443}),
+		WebhookServer:          webhookServer,
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "81473831.openshift.io", // operator-sdk generated this for us
@@ -311,6 +323,11 @@ func RunOperator(cmd *cobra.Command, args []string) {
 		// Add the Metrics Service
 		addMetrics(ctx, cfg, kubeClient, monitoringClient)
 	}
+	// The webhook is served with the certificate of the metrics service,
+	// which only OpenShift provides
+	if platform == PlatformOpenShift {
+		addRequesterWebhook(ctx, mgr, kubeClient)
+	}
 
 	if err := ensureDefaultProfileBundles(ctx, mgr.GetClient(), namespaceList, platform); err != nil {
 		setupLog.Error(err, "Error creating default ProfileBundles.")
@@ -398,6 +415,12 @@ func operatorMetricService(ns string) *v1.Service {
 					TargetPort: intstr.FromInt(ctrlMetrics.ControllerMetricsPort),
 					Protocol:   v1.ProtocolTCP,
 				},
+				{
+					Name:       "webhook",
+					Port:       webhookPort,
+					TargetPort: intstr.FromInt(int(webhookPort)),
+					Protocol:   v1.ProtocolTCP,
+				},
 			},
 			Selector: map[string]string{
 				"name": "compliance-operator",
@@ -456,6 +479,99 @@ func ensureMetricsServiceAndSecret(ctx context.Context, kClient *kubernetes.Clie
 	return returnService, nil
 }
 
+// addRequesterWebhook serves the webhook that records who approved, reviewed
+// or answered something in the compliance objects, and registers it with the
+// API server
+func addRequesterWebhook(ctx context.Context, mgr manager.Manager, kClient *kubernetes.Clientset) {
+	operatorNs := common.GetComplianceOperatorNamespace()
+
+	if _, err := ensureMetricsServiceAndSecret(ctx, kClient, operatorNs); err != nil {
+		setupLog.Error(err, "Error creating metrics service/secret")
+		os.Exit(1)
+	}
+
+	mgr.GetWebhookServer().Register(requester.WebhookPath, &webhook.Admission{
+		Handler: requester.NewHandler(mgr.GetScheme()),
+	})
+
+	if err := ensureRequesterWebhookConfiguration(ctx, kClient, operatorNs); err != nil {
+		setupLog.Error(err, "Error creating the requester webhook configuration")
+		os.Exit(1)
+	}
+}
+
+func requesterWebhookConfiguration(ns string) *admissionregistrationv1.MutatingWebhookConfiguration {
+	path := requester.WebhookPath
+	port := webhookPort
+	failurePolicy := admissionregistrationv1.Fail
+	sideEffects := admissionregistrationv1.SideEffectClassNone
+	return &admissionregistrationv1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: requesterWebhookName,
+			Annotations: map[string]string{
+				"service.beta.openshift.io/inject-cabundle": "true",
+			},
+		},
+		Webhooks: []admissionregistrationv1.MutatingWebhook{
+			{
+				Name: "requester.compliance.openshift.io",
+				ClientConfig: admissionregistrationv1.WebhookClientConfig{
+					Service: &admissionregistrationv1.ServiceReference{
+						Namespace: ns,
+						Name:      metricsServiceName,
+						Path:      &path,
+						Port:      &port,
+					},
+				},
+				Rules: []admissionregistrationv1.RuleWithOperations{
+					{
+						Operations: []admissionregistrationv1.OperationType{
+							admissionregistrationv1.Create,
+							admissionregistrationv1.Update,
+						},
+						Rule: admissionregistrationv1.Rule{
+							APIGroups:   []string{compv1alpha1.SchemeGroupVersion.Group},
+							APIVersions: []string{compv1alpha1.SchemeGroupVersion.Version},
+							Resources:   requester.Resources,
+						},
+					},
+				},
+				// The recorded requesters can't be trusted if the webhook is skipped
+				FailurePolicy:           &failurePolicy,
+				SideEffects:             &sideEffects,
+				AdmissionReviewVersions: []string{"v1"},
+			},
+		},
+	}
+}
+
+func ensureRequesterWebhookConfiguration(ctx context.Context, kClient *kubernetes.Clientset, ns string) error {
+	newConfig := requesterWebhookConfiguration(ns)
+	configs := kClient.AdmissionregistrationV1().MutatingWebhookConfigurations()
+	_, err := configs.Create(ctx, newConfig, metav1.CreateOptions{})
+	if !kerr.IsAlreadyExists(err) {
+		return err
+	}
+
+	curConfig, err := configs.Get(ctx, newConfig.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	// The CA bundle is injected by the service CA operator
+	for i := range newConfig.Webhooks {
+		if i < len(curConfig.Webhooks) {
+			newConfig.Webhooks[i].ClientConfig.CABundle = curConfig.Webhooks[i].ClientConfig.CABundle
+		}
+	}
+	if reflect.DeepEqual(curConfig.Webhooks, newConfig.Webhooks) {
+		return nil
+	}
+	configCopy := curConfig.DeepCopy()
+	configCopy.Webhooks = newConfig.Webhooks
+	_, err = configs.Update(ctx, configCopy, metav1.UpdateOptions{})
+	return err
+}
+
 func getSchedulingInfo(ctx context.Context, cli client.Reader) (utils.CtlplaneSchedulingInfo, error) {
 	key := types.NamespacedName{
 		Name:      common.GetComplianceOperatorName(),
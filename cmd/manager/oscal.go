@@ -0,0 +1,95 @@
+/*
+Copyright © 2020 Red Hat Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package manager
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/export"
+)
+
+var OSCALCmd = &cobra.Command{
+	Use:   "oscal",
+	Short: "Generates the OSCAL assessment results of a suite.",
+	Long: "Generates an OSCAL assessment-results document from the results of the " +
+		"scans of a ComplianceSuite and the controls of their rules.",
+	Run: oscalMain,
+}
+
+func init() {
+	defineOSCALFlags(OSCALCmd)
+}
+
+type oscalConfig struct {
+	SuiteName string
+	Namespace string
+	Output    string
+}
+
+func defineOSCALFlags(cmd *cobra.Command) {
+	cmd.Flags().String("suite", "", "The suite whose results are assessed.")
+	cmd.Flags().String("namespace", "openshift-compliance", "The namespace of the suite.")
+	cmd.Flags().String("output", "-", "The file to write to, or - for the standard output.")
+}
+
+func parseOSCALConfig(cmd *cobra.Command) *oscalConfig {
+	var conf oscalConfig
+	conf.SuiteName = getValidStringArg(cmd, "suite")
+	conf.Namespace = getValidStringArg(cmd, "namespace")
+	conf.Output, _ = cmd.Flags().GetString("output")
+	return &conf
+}
+
+func oscalMain(cmd *cobra.Command, args []string) {
+	conf := parseOSCALConfig(cmd)
+
+	crclient, err := createCrClient(getConfig())
+	if err != nil {
+		FATAL("Cannot create kube client for compliance-operator types: %v", err)
+	}
+
+	suite := &compv1alpha1.ComplianceSuite{}
+	if err := crclient.getClient().Get(context.TODO(), getObjKey(conf.SuiteName, conf.Namespace), suite); err != nil {
+		FATAL("Cannot get the suite: %v", err)
+	}
+	if suite.Status.Phase != compv1alpha1.PhaseDone {
+		// The document might go to the standard output
+		fmt.Fprintf(os.Stderr, "The suite is in the %s phase, its results might not be complete\n", suite.Status.Phase)
+	}
+	assessment, err := export.GetAssessment(context.TODO(), crclient.getClient(), suite)
+	if err != nil {
+		FATAL("Cannot get the results of the suite: %v", err)
+	}
+
+	out := os.Stdout
+	if conf.Output != "-" {
+		out, err = os.Create(filepath.Clean(conf.Output))
+		if err != nil {
+			FATAL("Cannot create the output file: %v", err)
+		}
+		// #nosec
+		defer out.Close()
+	}
+	if err := export.RenderOSCAL(assessment, out); err != nil {
+		FATAL("Cannot generate the assessment results: %v", err)
+	}
+}
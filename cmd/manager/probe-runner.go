@@ -0,0 +1,215 @@
+/*
+Copyright © 2020 Red Hat Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/utils"
+)
+
+const (
+	// The shell the probes of the custom rules run with
+	probeShell = "/bin/sh"
+	// The PATH the probes run with
+	probePath = "/usr/local/bin:/usr/bin:/bin:/usr/sbin:/sbin"
+	// The directory the probes run in
+	probeWorkDir = "/tmp"
+	// How much of the output of a probe is logged to explain its result
+	probeOutputLimit = 4096
+	// The default timeout of a probe, in seconds
+	probeDefaultTimeout = 30
+)
+
+var ProbeRunnerCmd = &cobra.Command{
+	Use:   "probe-runner",
+	Short: "Runs the probes of the custom rules on a node.",
+	Long:  "Runs the probes of the custom rules of a tailored profile on a node.",
+	Run:   runProbeRunner,
+}
+
+func init() {
+	defineProbeRunnerFlags(ProbeRunnerCmd)
+}
+
+type probeRunnerConfig struct {
+	CustomRules string
+	HostRoot    string
+	OutputFile  string
+	SkipProbes  bool
+}
+
+func defineProbeRunnerFlags(cmd *cobra.Command) {
+	cmd.Flags().String("custom-rules", "", "The path to the custom rules of the tailored profile.")
+	cmd.Flags().String("host-root", "/host", "The path the root of the node is mounted at.")
+	cmd.Flags().String("output-file", "", "A file to write the results of the custom rules to.")
+	cmd.Flags().Bool("skip-probes", false, "Report the custom rules as MANUAL instead of running their probes.")
+	cmd.Flags().Bool("debug", false, "Print debug messages.")
+}
+
+func parseProbeRunnerConfig(cmd *cobra.Command) *probeRunnerConfig {
+	var conf probeRunnerConfig
+	conf.CustomRules = getValidStringArg(cmd, "custom-rules")
+	conf.HostRoot = getValidStringArg(cmd, "host-root")
+	conf.OutputFile = getValidStringArg(cmd, "output-file")
+	conf.SkipProbes, _ = cmd.Flags().GetBool("skip-probes")
+	debugLog, _ = cmd.Flags().GetBool("debug")
+	return &conf
+}
+
+func runProbeRunner(cmd *cobra.Command, args []string) {
+	conf := parseProbeRunnerConfig(cmd)
+
+	rules, err := loadCustomRules(conf.CustomRules)
+	if err != nil {
+		FATAL("Error loading custom rules: %v", err)
+	}
+	var results []*compv1alpha1.ComplianceCheckResult
+	if conf.SkipProbes {
+		results = skipCustomRuleProbes(rules)
+	} else {
+		results = runCustomRuleProbes(rules, conf.HostRoot)
+	}
+	if len(results) == 0 {
+		LOG("No custom rule probes to run")
+		return
+	}
+	if err := saveCustomRuleResults(results, conf.OutputFile); err != nil {
+		FATAL("Error saving custom rule results: %v", err)
+	}
+}
+
+// runCustomRuleProbes runs the probes of the rules one after the other. The
+// rules without a probe are checked by platform scans and are skipped.
+func runCustomRuleProbes(rules []compv1alpha1.CustomRule, hostRoot string) []*compv1alpha1.ComplianceCheckResult {
+	results := make([]*compv1alpha1.ComplianceCheckResult, 0, len(rules))
+	for i := range rules {
+		rule := &rules[i]
+		if rule.CheckType() != compv1alpha1.CheckTypeNode {
+			continue
+		}
+
+		status, warning, output := runCustomRuleProbe(rule.Spec.Probe, hostRoot)
+		LOG("Custom rule %s: %s", rule.Name, status)
+		// The output of the probe can hold anything it read on the node, so
+		// it's only logged, the result just explains why it isn't a PASS
+		if output = strings.TrimSpace(output); output != "" {
+			LOG("Custom rule %s output:\n%s", rule.Name, output)
+		}
+		results = append(results, utils.NewCustomRuleCheckResult(rule, status, warning))
+	}
+	return results
+}
+
+// skipCustomRuleProbes reports the rules with a probe as MANUAL, for the
+// scans that don't allow running the probes on the nodes
+func skipCustomRuleProbes(rules []compv1alpha1.CustomRule) []*compv1alpha1.ComplianceCheckResult {
+	results := make([]*compv1alpha1.ComplianceCheckResult, 0, len(rules))
+	for i := range rules {
+		rule := &rules[i]
+		if rule.CheckType() != compv1alpha1.CheckTypeNode {
+			continue
+		}
+		LOG("Custom rule %s: not running the probe, node probes aren't allowed", rule.Name)
+		results = append(results, utils.NewCustomRuleCheckResult(rule, compv1alpha1.CheckResultManual,
+			"The probe didn't run, the scan doesn't allow node probes"))
+	}
+	return results
+}
+
+// runCustomRuleProbe runs the script of the probe in its own process group,
+// with nothing in its environment but the root of the host and a PATH. The
+// whole group is killed once the probe times out. A probe that can't run or
+// doesn't exit in time is an ERROR, otherwise its exit status is its result.
+// The warning explains any result that isn't a PASS, and the end of the
+// output of the probe is returned along with it.
+func runCustomRuleProbe(probe *compv1alpha1.CustomRuleProbe, hostRoot string) (compv1alpha1.ComplianceCheckStatus, string, string) {
+	timeout := time.Duration(probeDefaultTimeout) * time.Second
+	if probe.TimeoutSeconds > 0 {
+		timeout = time.Duration(probe.TimeoutSeconds) * time.Second
+	}
+
+	output := &tailBuffer{limit: probeOutputLimit}
+	// #nosec G204 -- the script is the point of the probe, it's declared by
+	// the administrator in the CustomRule
+	cmd := exec.Command(probeShell, "-c", probe.Script)
+	cmd.Env = []string{"HOST_ROOT=" + hostRoot, "PATH=" + probePath}
+	cmd.Dir = probeWorkDir
+	cmd.Stdout = output
+	cmd.Stderr = output
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+
+	if err := cmd.Start(); err != nil {
+		return compv1alpha1.CheckResultError, fmt.Sprintf("The probe could not be started: %v", err), ""
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-time.After(timeout):
+		// Kill the whole process group, not just the shell
+		_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+		<-done
+		return compv1alpha1.CheckResultError, fmt.Sprintf("The probe timed out after %s", timeout), output.String()
+	}
+
+	exitStatus := 0
+	if err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return compv1alpha1.CheckResultError, fmt.Sprintf("The probe failed to run: %v", err), output.String()
+		}
+		exitStatus = exitErr.ExitCode()
+	}
+
+	status := utils.CustomRuleProbeStatus(exitStatus)
+	if status == compv1alpha1.CheckResultPass {
+		return status, "", output.String()
+	}
+	return status, fmt.Sprintf("The probe exited with status %d", exitStatus), output.String()
+}
+
+// tailBuffer keeps the last bytes written to it, up to its limit. The output
+// and errors of a probe share it, exec only writes to it from one goroutine.
+type tailBuffer struct {
+	limit int
+	buf   []byte
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.buf = append(b.buf, p...)
+	if len(b.buf) > b.limit {
+		b.buf = b.buf[len(b.buf)-b.limit:]
+	}
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string {
+	return string(b.buf)
+}
@@ -0,0 +1,98 @@
+package manager
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+)
+
+var _ = Describe("Testing the probes of custom rules", func() {
+	newProbeRule := func(name, script string) compv1alpha1.CustomRule {
+		rule := compv1alpha1.CustomRule{
+			Spec: compv1alpha1.CustomRuleSpec{
+				Title:    "Rule " + name,
+				Severity: compv1alpha1.CheckResultSeverityMedium,
+				Probe: &compv1alpha1.CustomRuleProbe{
+					Script:         script,
+					TimeoutSeconds: 1,
+				},
+			},
+		}
+		rule.Name = name
+		return rule
+	}
+
+	It("maps the exit status of the probes to the results", func() {
+		rules := []compv1alpha1.CustomRule{
+			newProbeRule("passes", "exit 0"),
+			newProbeRule("fails", "echo kubelet is disabled; exit 1"),
+			newProbeRule("not-applicable", "exit 2"),
+			newProbeRule("errors", "exit 3"),
+		}
+		results := runCustomRuleProbes(rules, "/")
+		Expect(results).To(HaveLen(4))
+
+		Expect(results[0].Status).To(Equal(compv1alpha1.CheckResultPass))
+		Expect(results[0].Warnings).To(BeEmpty())
+		Expect(results[1].Status).To(Equal(compv1alpha1.CheckResultFail))
+		Expect(results[1].Warnings).To(ConsistOf("The probe exited with status 1"))
+		Expect(results[2].Status).To(Equal(compv1alpha1.CheckResultNotApplicable))
+		Expect(results[3].Status).To(Equal(compv1alpha1.CheckResultError))
+	})
+
+	It("skips the rules without a probe", func() {
+		platformRule := compv1alpha1.CustomRule{
+			Spec: compv1alpha1.CustomRuleSpec{
+				Inputs:     []compv1alpha1.CustomRuleInput{{Name: "oauth", Path: "/apis/config.openshift.io/v1/oauths/cluster"}},
+				Expression: "oauth != null",
+			},
+		}
+		results := runCustomRuleProbes([]compv1alpha1.CustomRule{platformRule, newProbeRule("passes", "exit 0")}, "/")
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].ID).To(HaveSuffix("passes"))
+	})
+
+	It("only passes the root of the host to the probes", func() {
+		probe := &compv1alpha1.CustomRuleProbe{
+			Script: `test "$HOST_ROOT" = /host && test -z "$KUBERNETES_SERVICE_HOST"`,
+		}
+		status, warning, _ := runCustomRuleProbe(probe, "/host")
+		Expect(warning).To(BeEmpty())
+		Expect(status).To(Equal(compv1alpha1.CheckResultPass))
+	})
+
+	It("kills the whole probe once it times out", func() {
+		probe := &compv1alpha1.CustomRuleProbe{
+			Script:         "sleep 30 & wait",
+			TimeoutSeconds: 1,
+		}
+		start := time.Now()
+		status, warning, _ := runCustomRuleProbe(probe, "/")
+		Expect(time.Since(start)).To(BeNumerically("<", 10*time.Second))
+		Expect(status).To(Equal(compv1alpha1.CheckResultError))
+		Expect(warning).To(ContainSubstring("timed out after 1s"))
+	})
+
+	It("keeps the end of a long output out of the warnings", func() {
+		probe := &compv1alpha1.CustomRuleProbe{
+			Script: "i=0; while [ $i -lt 1000 ]; do echo line $i; i=$((i+1)); done; echo the end >&2; exit 1",
+		}
+		status, warning, output := runCustomRuleProbe(probe, "/")
+		Expect(status).To(Equal(compv1alpha1.CheckResultFail))
+		Expect(warning).To(Equal("The probe exited with status 1"))
+		Expect(output).To(HaveSuffix("the end\n"))
+		Expect(output).ToNot(ContainSubstring("line 0\n"))
+		Expect(len(output)).To(BeNumerically("<=", probeOutputLimit))
+	})
+
+	It("reports the rules as MANUAL when the probes can't run", func() {
+		rules := []compv1alpha1.CustomRule{newProbeRule("passes", "exit 0")}
+		results := skipCustomRuleProbes(rules)
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].Status).To(Equal(compv1alpha1.CheckResultManual))
+		Expect(results[0].Warnings).To(ConsistOf("The probe didn't run, the scan doesn't allow node probes"))
+	})
+})
@@ -10,6 +10,8 @@ import (
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	"github.com/antchfx/xmlquery"
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -34,6 +36,7 @@ func defineProfileParserFlags(cmd *cobra.Command) {
 	cmd.Flags().String("ds-path", "/content/ssg-ocp4-ds.xml", "Path to the datastream xml file")
 	cmd.Flags().String("name", "", "Name of the ProfileBundle object")
 	cmd.Flags().String("namespace", "", "Namespace of the ProfileBundle object")
+	cmd.Flags().String("standards-configmap", "", "Optional ConfigMap in the ProfileBundle namespace that configures the compliance standards")
 
 	flags := cmd.Flags()
 
@@ -86,6 +89,29 @@ func getProfileBundle(pcfg *profileparser.ParserConfig) (*cmpv1alpha1.ProfileBun
 	return &pb, nil
 }
 
+// getStandardsConfig reads the compliance standards configuration from the
+// ConfigMap passed on the command line. A nil configuration is returned if
+// no ConfigMap was given.
+func getStandardsConfig(cmd *cobra.Command, pcfg *profileparser.ParserConfig) (*profileparser.StandardsConfig, error) {
+	cmName, _ := cmd.Flags().GetString("standards-configmap")
+	if cmName == "" {
+		return nil, nil
+	}
+
+	cm := corev1.ConfigMap{}
+	key := types.NamespacedName{Name: cmName, Namespace: pcfg.ProfileBundleKey.Namespace}
+	if err := pcfg.Client.Get(context.TODO(), key, &cm); err != nil {
+		return nil, fmt.Errorf("couldn't get standards ConfigMap %s: %w", cmName, err)
+	}
+
+	data, ok := cm.Data[profileparser.StandardsConfigMapKey]
+	if !ok {
+		return nil, fmt.Errorf("standards ConfigMap %s has no %s key", cmName, profileparser.StandardsConfigMapKey)
+	}
+
+	return profileparser.ParseStandardsConfig(data)
+}
+
 // updateProfileBundleStatus updates the status of the given ProfileBundle. If
 // the given error is nil, the status will be valid, else it'll be invalid
 func updateProfileBundleStatus(pcfg *profileparser.ParserConfig, pb *cmpv1alpha1.ProfileBundle, err error) {
@@ -123,6 +149,13 @@ func runProfileParser(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	pcfg.Standards, err = getStandardsConfig(cmd, pcfg)
+	if err != nil {
+		cmdLog.Error(err, "Couldn't read the standards configuration")
+		updateProfileBundleStatus(pcfg, pb, err)
+		os.Exit(1)
+	}
+
 	contentFile, err := readContent(pcfg.DataStreamPath)
 	if err != nil {
 		cmdLog.Error(err, "Couldn't read the content")
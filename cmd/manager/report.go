@@ -0,0 +1,207 @@
+/*
+Copyright © 2020 Red Hat Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package manager
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	backoff "github.com/cenkalti/backoff/v4"
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/export"
+)
+
+// The name the HTML report of a scan is stored under with its raw results
+const scanReportName = "report"
+
+var ReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Generates a human-readable HTML report of the results of a suite or a scan.",
+	Long: "Generates an HTML report of the results of the scans of a ComplianceSuite, " +
+		"or of a single ComplianceScan, that can be printed to PDF.",
+	Run: reportMain,
+}
+
+func init() {
+	defineReportFlags(ReportCmd)
+}
+
+type reportConfig struct {
+	SuiteName string
+	ScanName  string
+	Namespace string
+	Output    string
+}
+
+func defineReportFlags(cmd *cobra.Command) {
+	cmd.Flags().String("suite", "", "The suite whose results are reported.")
+	cmd.Flags().String("scan", "", "The scan whose results are reported, instead of those of a suite.")
+	cmd.Flags().String("namespace", "openshift-compliance", "The namespace of the suite or the scan.")
+	cmd.Flags().String("output", "-", "The file to write to, or - for the standard output.")
+}
+
+func parseReportConfig(cmd *cobra.Command) *reportConfig {
+	var conf reportConfig
+	conf.SuiteName, _ = cmd.Flags().GetString("suite")
+	conf.ScanName, _ = cmd.Flags().GetString("scan")
+	if (conf.SuiteName == "") == (conf.ScanName == "") {
+		FATAL("Exactly one of the --suite and --scan arguments must be given")
+	}
+	conf.Namespace = getValidStringArg(cmd, "namespace")
+	conf.Output, _ = cmd.Flags().GetString("output")
+	return &conf
+}
+
+func reportMain(cmd *cobra.Command, args []string) {
+	conf := parseReportConfig(cmd)
+
+	crclient, err := createCrClient(getConfig())
+	if err != nil {
+		FATAL("Cannot create kube client for compliance-operator types: %v", err)
+	}
+
+	var phase compv1alpha1.ComplianceScanStatusPhase
+	var assessment *export.Assessment
+	if conf.SuiteName != "" {
+		suite := &compv1alpha1.ComplianceSuite{}
+		if err := crclient.getClient().Get(context.TODO(), getObjKey(conf.SuiteName, conf.Namespace), suite); err != nil {
+			FATAL("Cannot get the suite: %v", err)
+		}
+		phase = suite.Status.Phase
+		assessment, err = export.GetAssessment(context.TODO(), crclient.getClient(), suite)
+		if err != nil {
+			FATAL("Cannot get the results of the suite: %v", err)
+		}
+	} else {
+		scan := &compv1alpha1.ComplianceScan{}
+		if err := crclient.getClient().Get(context.TODO(), getObjKey(conf.ScanName, conf.Namespace), scan); err != nil {
+			FATAL("Cannot get the scan: %v", err)
+		}
+		phase = scan.Status.Phase
+		assessment, err = export.GetScanAssessment(context.TODO(), crclient.getClient(), scan)
+		if err != nil {
+			FATAL("Cannot get the results of the scan: %v", err)
+		}
+	}
+	if phase != compv1alpha1.PhaseDone {
+		// The report might go to the standard output
+		fmt.Fprintf(os.Stderr, "The results are in the %s phase, they might not be complete\n", phase)
+	}
+
+	out := os.Stdout
+	if conf.Output != "-" {
+		out, err = os.Create(filepath.Clean(conf.Output))
+		if err != nil {
+			FATAL("Cannot create the output file: %v", err)
+		}
+		// #nosec
+		defer out.Close()
+	}
+	if err := export.RenderHTML(assessment, out); err != nil {
+		FATAL("Cannot generate the report: %v", err)
+	}
+}
+
+// storeReport stores the HTML report of the scan, if the scan stores one,
+// and records in the status of the scan whether it was stored. The report
+// isn't part of the results, failing to store it doesn't fail the
+// aggregation.
+func storeReport(crClient aggregatorCrClient, conf *aggregatorConfig, scan *compv1alpha1.ComplianceScan) {
+	if conf.ResultServerURI == "" {
+		return
+	}
+	reportErr := storeScanReport(crClient, conf, scan)
+	if reportErr != nil {
+		cmdLog.Error(reportErr, "Cannot store the HTML report of the scan")
+		crClient.getRecorder().Event(scan, v1.EventTypeWarning, "ReportNotStored",
+			"The HTML report of the scan couldn't be stored with its raw results: "+reportErr.Error())
+	}
+	if err := setReportStored(crClient, scan, reportErr == nil); err != nil {
+		cmdLog.Error(err, "Cannot record whether the HTML report was stored")
+	}
+}
+
+// setReportStored records in the status of the scan whether its HTML report
+// was stored. The scan controller updates the status as well while the
+// aggregator runs.
+func setReportStored(crClient aggregatorCrClient, scan *compv1alpha1.ComplianceScan, stored bool) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		found := &compv1alpha1.ComplianceScan{}
+		if err := crClient.getClient().Get(context.TODO(), getObjKey(scan.Name, scan.Namespace), found); err != nil {
+			return err
+		}
+		found.Status.HTMLReportStored = &stored
+		return crClient.getClient().Status().Update(context.TODO(), found)
+	})
+}
+
+// storeScanReport generates the HTML report of the scan and stores it with
+// the raw results of the scan, through its result server
+func storeScanReport(crClient aggregatorCrClient, conf *aggregatorConfig, scan *compv1alpha1.ComplianceScan) error {
+	// The scan only ends once it's aggregated, the results are as of now
+	scan = scan.DeepCopy()
+	if scan.Status.EndTimestamp == nil {
+		now := metav1.Now()
+		scan.Status.EndTimestamp = &now
+	}
+	assessment, err := export.GetScanAssessment(context.TODO(), crClient.getClient(), scan)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := export.RenderHTML(assessment, &buf); err != nil {
+		return err
+	}
+
+	transport, err := getMutualHttpsTransport(conf.Cert, conf.Key, conf.CA)
+	if err != nil {
+		return err
+	}
+	return backoff.Retry(func() error {
+		return uploadReport(&http.Client{Transport: transport}, conf.ResultServerURI, buf.Bytes())
+	}, backoff.WithMaxRetries(backoff.NewExponentialBackOff(), maxRetries))
+}
+
+// uploadReport posts the HTML report to the result server, which stores it
+// in the directory of the current results of the scan
+func uploadReport(client *http.Client, url string, report []byte) error {
+	cmdLog.Info("Trying to upload the report to the resultserver", "url", url)
+	req, err := http.NewRequest("POST", url, bytes.NewReader(report))
+	if err != nil {
+		return backoff.Permanent(err)
+	}
+	req.Header.Add("Content-Type", "text/html")
+	req.Header.Add("X-Report-Name", scanReportName)
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("the resultserver answered %s", resp.Status)
+	}
+	cmdLog.Info("Uploaded the report to the resultserver")
+	return nil
+}
@@ -0,0 +1,78 @@
+package manager
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+)
+
+var _ = Describe("Testing the HTML reports", func() {
+	It("uploads the report of the scan to the resultserver", func() {
+		var header http.Header
+		var body []byte
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header = r.Header
+			body, _ = io.ReadAll(r.Body)
+		}))
+		defer server.Close()
+
+		Expect(uploadReport(server.Client(), server.URL, []byte("<html></html>"))).To(Succeed())
+		Expect(header.Get("Content-Type")).To(Equal("text/html"))
+		Expect(header.Get("X-Report-Name")).To(Equal(scanReportName))
+		Expect(string(body)).To(Equal("<html></html>"))
+	})
+
+	It("fails if the resultserver doesn't store the report", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "Error creating file", 500)
+		}))
+		defer server.Close()
+
+		Expect(uploadReport(server.Client(), server.URL, []byte("<html></html>"))).ToNot(Succeed())
+	})
+
+	It("stores the reports as HTML files and the raw results as XML files", func() {
+		Expect(reportExtension("text/html")).To(Equal(".html"))
+		Expect(reportExtension("text/html; charset=utf-8")).To(Equal(".html"))
+		Expect(reportExtension("application/xml")).To(Equal(".xml"))
+		Expect(reportExtension("")).To(Equal(".xml"))
+	})
+
+	It("records in the status of the scan whether the report was stored", func() {
+		scheme := getScheme()
+		scan := &compv1alpha1.ComplianceScan{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "bar",
+			},
+			Status: compv1alpha1.ComplianceScanStatus{
+				Phase: compv1alpha1.PhaseAggregating,
+			},
+		}
+		crClient := &aggregatorCrClientFake{
+			scheme: scheme,
+			client: fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithStatusSubresource(scan).
+				WithRuntimeObjects(scan).
+				Build(),
+		}
+
+		found := &compv1alpha1.ComplianceScan{}
+		for _, stored := range []bool{false, true} {
+			Expect(setReportStored(crClient, scan, stored)).To(Succeed())
+			Expect(crClient.client.Get(context.TODO(), getObjKey("foo", "bar"), found)).To(Succeed())
+			Expect(found.Status.HTMLReportStored).ToNot(BeNil())
+			Expect(*found.Status.HTMLReportStored).To(Equal(stored))
+			Expect(found.Status.Phase).To(Equal(compv1alpha1.PhaseAggregating))
+		}
+	})
+})
@@ -21,6 +21,7 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/json"
 	goerrors "errors"
 	"flag"
 	"fmt"
@@ -78,6 +79,9 @@ type scapresultsConfig struct {
 	Cert               string
 	Key                string
 	CA                 string
+
+	// The results of the custom rules checked by the scan
+	CustomRulesOutputFile string
 }
 
 func defineResultcollectorFlags(cmd *cobra.Command) {
@@ -86,6 +90,7 @@ func defineResultcollectorFlags(cmd *cobra.Command) {
 	cmd.Flags().String("exit-code-file", "", "A file containing the oscap command's exit code.")
 	cmd.Flags().String("oscap-output-file", "", "A file containing the oscap command's output.")
 	cmd.Flags().String("warnings-output-file", "", "A file containing the warnings to output.")
+	cmd.Flags().String("custom-rules-output-file", "", "A file containing the results of the custom rules.")
 	cmd.Flags().String("owner", "", "The compliance scan that owns the configMap objects.")
 	cmd.Flags().String("config-map-name", "", "The configMap to upload to, typically the podname.")
 	cmd.Flags().String("node-name", "", "The node that was scanned.")
@@ -122,6 +127,7 @@ func parseConfig(cmd *cobra.Command) *scapresultsConfig {
 		conf.ResultServerURI = "http://" + conf.ScanName + "-rs:8080/"
 	}
 	conf.WarningsOutputFile, _ = cmd.Flags().GetString("warnings-output-file")
+	conf.CustomRulesOutputFile, _ = cmd.Flags().GetString("custom-rules-output-file")
 
 	// platform scans have no node name
 	conf.NodeName, _ = cmd.Flags().GetString("node-name")
@@ -295,11 +301,32 @@ func readWarningsFile(filename string) string {
 	return strings.Trim(string(contents), "\n")
 }
 
+// exitCodeWithCustomRules returns the exit code of a scan whose custom
+// rules were checked as well: a compliant scan is non-compliant if any of
+// its custom rules failed.
+func exitCodeWithCustomRules(exitcode, customRuleResults string) string {
+	if exitcode != common.OpenSCAPExitCodeCompliant || customRuleResults == "" {
+		return exitcode
+	}
+
+	var checks []compv1alpha1.ComplianceCheckResult
+	if err := json.Unmarshal([]byte(customRuleResults), &checks); err != nil {
+		DBG("Error while parsing the custom rule results: %v", err)
+		return exitcode
+	}
+	for i := range checks {
+		if checks[i].Status == compv1alpha1.CheckResultFail {
+			return common.OpenSCAPExitCodeNonCompliant
+		}
+	}
+	return exitcode
+}
+
 func uploadToResultServer(arfContents *resultFileContents, scapresultsconf *scapresultsConfig) error {
 	return backoff.Retry(func() error {
 		url := scapresultsconf.ResultServerURI
 		cmdLog.Info("Trying to upload to resultserver", "url", url)
-		transport, err := getMutualHttpsTransport(scapresultsconf)
+		transport, err := getMutualHttpsTransport(scapresultsconf.Cert, scapresultsconf.Key, scapresultsconf.CA)
 		if err != nil {
 			cmdLog.Error(err, "Failed to get https transport")
 			return err
@@ -330,6 +357,8 @@ func uploadToResultServer(arfContents *resultFileContents, scapresultsconf *scap
 func uploadResultConfigMap(xccdfContents *resultFileContents, exitcode string,
 	scapresultsconf *scapresultsConfig, client *complianceCrClient) error {
 	warnings := readWarningsFile(scapresultsconf.WarningsOutputFile)
+	customRuleResults := readWarningsFile(scapresultsconf.CustomRulesOutputFile)
+	exitcode = exitCodeWithCustomRules(exitcode, customRuleResults)
 
 	return backoff.Retry(func() error {
 		cmdLog.Info("Trying to upload results ConfigMap")
@@ -339,6 +368,9 @@ func uploadResultConfigMap(xccdfContents *resultFileContents, exitcode string,
 		}
 		confMap := utils.GetResultConfigMap(openscapScan, scapresultsconf.ConfigMapName, "results",
 			scapresultsconf.NodeName, xccdfContents.contents, xccdfContents.compressed, exitcode, warnings)
+		if customRuleResults != "" {
+			confMap.Data[utils.CustomRulesResultKey] = customRuleResults
+		}
 		err = client.client.Create(context.TODO(), confMap)
 
 		if errors.IsAlreadyExists(err) {
@@ -436,12 +468,12 @@ func getOscapExitCode(scapresultsconf *scapresultsConfig) string {
 	return strings.Trim(string(exitcode), "\n")
 }
 
-func getMutualHttpsTransport(c *scapresultsConfig) (*http.Transport, error) {
-	cert, err := tls.LoadX509KeyPair(c.Cert, c.Key)
+func getMutualHttpsTransport(certFile, keyFile, caFile string) (*http.Transport, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
 	if err != nil {
 		return nil, err
 	}
-	ca, err := os.ReadFile(c.CA)
+	ca, err := os.ReadFile(caFile)
 	if err != nil {
 		return nil, err
 	}
@@ -6,6 +6,8 @@ import (
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+
+	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/common"
 )
 
 var _ = Describe("Resultcollector", func() {
@@ -57,4 +59,25 @@ var _ = Describe("Resultcollector", func() {
 			Expect(err).To(BeEquivalentTo(timeoutErr))
 		})
 	})
+
+	Context("Testing the exit code with custom rules", func() {
+		const (
+			passed = `[{"id":"custom_rule_a","status":"PASS"}]`
+			failed = `[{"id":"custom_rule_a","status":"PASS"},{"id":"custom_rule_b","status":"FAIL"}]`
+		)
+
+		It("keeps a compliant exit code if all the custom rules passed", func() {
+			Expect(exitCodeWithCustomRules(common.OpenSCAPExitCodeCompliant, passed)).To(Equal(common.OpenSCAPExitCodeCompliant))
+			Expect(exitCodeWithCustomRules(common.OpenSCAPExitCodeCompliant, "")).To(Equal(common.OpenSCAPExitCodeCompliant))
+		})
+
+		It("turns a compliant exit code non-compliant if a custom rule failed", func() {
+			Expect(exitCodeWithCustomRules(common.OpenSCAPExitCodeCompliant, failed)).To(Equal(common.OpenSCAPExitCodeNonCompliant))
+		})
+
+		It("keeps the other exit codes", func() {
+			Expect(exitCodeWithCustomRules(common.OpenSCAPExitCodeNonCompliant, passed)).To(Equal(common.OpenSCAPExitCodeNonCompliant))
+			Expect(exitCodeWithCustomRules("1", failed)).To(Equal("1"))
+		})
+	})
 })
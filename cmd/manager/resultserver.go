@@ -159,6 +159,15 @@ func rotateResultDirectories(rootPath string, rotation uint16) error {
 	return lastError
 }
 
+// reportExtension returns the extension of the file a report of the content
+// type is stored in
+func reportExtension(contentType string) string {
+	if strings.HasPrefix(contentType, "text/html") {
+		return ".html"
+	}
+	return ".xml"
+}
+
 func server(c *resultServerConfig) {
 	exit := make(chan os.Signal, 1)
 	signal.Notify(exit, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
@@ -208,8 +217,8 @@ func server(c *resultServerConfig) {
 		} else if encoding == "bzip2" {
 			extraExtension = "." + extraExtension
 		}
-		// TODO(jaosorior): Check that content-type is application/xml
-		filePath := path.Join(c.Path, filename+".xml"+extraExtension)
+		// The raw results are XML, the reports of the aggregator are HTML
+		filePath := path.Join(c.Path, filename+reportExtension(r.Header.Get("Content-Type"))+extraExtension)
 		cleanPath := filepath.Clean(filePath)
 		f, err := os.Create(cleanPath)
 		if err != nil {
@@ -40,6 +40,10 @@ func init() {
 	rootCmd.AddCommand(manager.ResultcollectorCmd)
 	rootCmd.AddCommand(manager.ResultServerCmd)
 	rootCmd.AddCommand(manager.RerunnerCmd)
+	rootCmd.AddCommand(manager.ProbeRunnerCmd)
+	rootCmd.AddCommand(manager.ExportCmd)
+	rootCmd.AddCommand(manager.OSCALCmd)
+	rootCmd.AddCommand(manager.ReportCmd)
 }
 
 func main() {
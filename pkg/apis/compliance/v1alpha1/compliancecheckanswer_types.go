@@ -0,0 +1,88 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ComplianceCheckResultAnswerAnnotation names the ComplianceCheckAnswer that
+// answered a manual ComplianceCheckResult
+const ComplianceCheckResultAnswerAnnotation = "compliance.openshift.io/answer"
+
+// ComplianceCheckAnswerSpec defines the desired state of ComplianceCheckAnswer
+type ComplianceCheckAnswerSpec struct {
+	// The name of the Rule whose manual check is answered
+	Rule string `json:"rule"`
+	// The names of the scans whose results are answered. If empty, the
+	// results of all the scans in the namespace of the answer are answered.
+	// +optional
+	// +nullable
+	Scans []string `json:"scans,omitempty"`
+	// The answer to the questionnaire of the rule, which becomes the status
+	// of the answered check results
+	// +kubebuilder:validation:Enum=PASS;FAIL;NOT-APPLICABLE
+	Answer ComplianceCheckStatus `json:"answer"`
+	// What the answer is based on, e.g. the output of the commands the
+	// instructions of the rule ask to run. Either the evidence or a
+	// reference to it must be set.
+	// +optional
+	Evidence string `json:"evidence,omitempty"`
+	// A reference to an attachment the answer is based on, e.g. the URL
+	// of a document
+	// +optional
+	EvidenceRef string `json:"evidenceRef,omitempty"`
+	// Who answered. On OpenShift, the operator sets it to the user who
+	// created or last changed the answer, overwriting any value given.
+	// +optional
+	Author string `json:"author,omitempty"`
+	// When the answer expires. From then on, the check is MANUAL again
+	// until it's answered anew.
+	ExpiresAt metav1.Time `json:"expiresAt"`
+}
+
+// +kubebuilder:object:root=true
+
+// ComplianceCheckAnswer records the answer to the manual check of a rule, so
+// that the results of the rule count toward compliance
+// +kubebuilder:resource:path=compliancecheckanswers,scope=Namespaced,shortName=answer;answers
+// +kubebuilder:printcolumn:name="Rule",type="string",JSONPath=`.spec.rule`
+// +kubebuilder:printcolumn:name="Answer",type="string",JSONPath=`.spec.answer`
+// +kubebuilder:printcolumn:name="Author",type="string",JSONPath=`.spec.author`
+// +kubebuilder:printcolumn:name="Expires",type="string",JSONPath=`.spec.expiresAt`
+type ComplianceCheckAnswer struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ComplianceCheckAnswerSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ComplianceCheckAnswerList contains a list of ComplianceCheckAnswer
+type ComplianceCheckAnswerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ComplianceCheckAnswer `json:"items"`
+}
+
+// AppliesToScan returns whether the answer answers the results of the scan
+func (a *ComplianceCheckAnswer) AppliesToScan(scanName string) bool {
+	if len(a.Spec.Scans) == 0 {
+		return true
+	}
+	for _, name := range a.Spec.Scans {
+		if name == scanName {
+			return true
+		}
+	}
+	return false
+}
+
+// HasEvidence returns whether the answer is backed by evidence or a
+// reference to it
+func (a *ComplianceCheckAnswer) HasEvidence() bool {
+	return a.Spec.Evidence != "" || a.Spec.EvidenceRef != ""
+}
+
+func init() {
+	SchemeBuilder.Register(&ComplianceCheckAnswer{}, &ComplianceCheckAnswerList{})
+}
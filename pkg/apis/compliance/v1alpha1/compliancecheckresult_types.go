@@ -53,6 +53,8 @@ const (
 	CheckResultNotApplicable ComplianceCheckStatus = "NOT-APPLICABLE"
 	// The check reports different results from different sources, typically cluster nodes
 	CheckResultInconsistent ComplianceCheckStatus = "INCONSISTENT"
+	// The check failed, but the failure is covered by an active ComplianceException
+	CheckResultExcepted ComplianceCheckStatus = "EXCEPTED"
 	// The check didn't yield a usable result
 	CheckResultNoResult ComplianceCheckStatus = ""
 )
@@ -95,6 +97,30 @@ type ComplianceCheckResult struct {
 	Warnings []string `json:"warnings,omitempty"`
 	// It stores a list of values used by the check
 	ValuesUsed []string `json:"valuesUsed,omitempty"`
+	// The result of the check on each node, ordered by node name. Only set
+	// if the scan reports per-node results.
+	// +optional
+	// +nullable
+	NodeResults []ComplianceCheckNodeResult `json:"nodeResults,omitempty"`
+}
+
+// ComplianceCheckNodeResult is the result of a check on a single node
+type ComplianceCheckNodeResult struct {
+	// The name of the node
+	Node string `json:"node"`
+	// The result of the check on the node
+	Status ComplianceCheckStatus `json:"status"`
+}
+
+// GetNodeResult returns the result of the check on the node, if the scan
+// reported it
+func (ccr *ComplianceCheckResult) GetNodeResult(node string) (ComplianceCheckStatus, bool) {
+	for _, nr := range ccr.NodeResults {
+		if nr.Node == node {
+			return nr.Status, true
+		}
+	}
+	return "", false
 }
 
 // +kubebuilder:object:root=true
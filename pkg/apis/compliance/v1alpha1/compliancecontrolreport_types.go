@@ -0,0 +1,117 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ComplianceControlReportSpec defines which results are rolled up and to
+// which compliance standard
+type ComplianceControlReportSpec struct {
+	// The compliance standard to roll the results up to, e.g. NIST-800-53.
+	// This must match the standard used in the
+	// control.compliance.openshift.io/<standard> annotation of the Rules.
+	Standard string `json:"standard"`
+	// The name of the ComplianceSuite whose ComplianceCheckResults are
+	// rolled up. The suite must be in the same namespace.
+	SuiteName string `json:"suiteName"`
+}
+
+// ControlCheckReference references a ComplianceCheckResult that
+// contributes to the status of a control
+type ControlCheckReference struct {
+	// The name of the ComplianceCheckResult
+	Name string `json:"name"`
+	// The status of the ComplianceCheckResult
+	Status ComplianceCheckStatus `json:"status"`
+}
+
+// ControlResult is the rolled up result of a single control
+type ControlResult struct {
+	// The identifier of the control within the standard, e.g. AC-2
+	ID string `json:"id"`
+	// The status of the control. A control only passes if all of
+	// the checks mapped to it pass or are not applicable. A control
+	// whose only failures are excepted is EXCEPTED.
+	Status ComplianceCheckStatus `json:"status"`
+	// The checks mapped to this control
+	Checks []ControlCheckReference `json:"checks,omitempty"`
+}
+
+// ControlSummary counts the controls by status
+type ControlSummary struct {
+	Total         int `json:"total"`
+	Passed        int `json:"passed"`
+	Failed        int `json:"failed"`
+	Manual        int `json:"manual"`
+	Excepted      int `json:"excepted"`
+	Error         int `json:"error"`
+	Inconsistent  int `json:"inconsistent"`
+	NotApplicable int `json:"notApplicable"`
+}
+
+// ComplianceControlReportStatus defines the observed state of ComplianceControlReport
+type ComplianceControlReportStatus struct {
+	// Counts of the controls by status
+	// +optional
+	Summary ControlSummary `json:"summary,omitempty"`
+	// The per-control results, sorted by control ID
+	// +optional
+	Controls []ControlResult `json:"controls,omitempty"`
+	// Defines the conditions for the ComplianceControlReport. Valid conditions are:
+	//  - Ready: Indicates if the report is up to date with the suite's results.
+	// +optional
+	Conditions Conditions `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ComplianceControlReport rolls up the results of a ComplianceSuite to the
+// controls of a compliance standard
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=compliancecontrolreports,scope=Namespaced,shortName=controlreport;controlreports
+// +kubebuilder:printcolumn:name="Standard",type="string",JSONPath=`.spec.standard`
+// +kubebuilder:printcolumn:name="Suite",type="string",JSONPath=`.spec.suiteName`
+// +kubebuilder:printcolumn:name="Total",type="integer",JSONPath=`.status.summary.total`
+// +kubebuilder:printcolumn:name="Passed",type="integer",JSONPath=`.status.summary.passed`
+// +kubebuilder:printcolumn:name="Failed",type="integer",JSONPath=`.status.summary.failed`
+// +kubebuilder:printcolumn:name="Manual",type="integer",JSONPath=`.status.summary.manual`
+// +kubebuilder:printcolumn:name="Excepted",type="integer",JSONPath=`.status.summary.excepted`,priority=1
+type ComplianceControlReport struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ComplianceControlReportSpec   `json:"spec,omitempty"`
+	Status ComplianceControlReportStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ComplianceControlReportList contains a list of ComplianceControlReport
+type ComplianceControlReportList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ComplianceControlReport `json:"items"`
+}
+
+func (s *ComplianceControlReportStatus) SetConditionPending(msg string) {
+	s.Conditions.SetCondition(Condition{
+		Type:    "Ready",
+		Status:  corev1.ConditionFalse,
+		Reason:  "Pending",
+		Message: msg,
+	})
+}
+
+func (s *ComplianceControlReportStatus) SetConditionReady() {
+	s.Conditions.SetCondition(Condition{
+		Type:    "Ready",
+		Status:  corev1.ConditionTrue,
+		Reason:  "Processed",
+		Message: "The control report is up to date with the suite's results",
+	})
+}
+
+func init() {
+	SchemeBuilder.Register(&ComplianceControlReport{}, &ComplianceControlReportList{})
+}
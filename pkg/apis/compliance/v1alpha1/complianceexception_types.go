@@ -0,0 +1,121 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ComplianceExceptionFinalizer is a finalizer for ComplianceExceptions. It gets
+// automatically added by the ComplianceException controller in order to
+// restore the status of the excepted check results.
+const ComplianceExceptionFinalizer = "exception.finalizers.compliance.openshift.io"
+
+// ComplianceCheckResultExceptionAnnotation names the ComplianceException that
+// excepts a ComplianceCheckResult
+const ComplianceCheckResultExceptionAnnotation = "compliance.openshift.io/exception"
+
+// ComplianceCheckResultExceptedStatusAnnotation stores the status the
+// ComplianceCheckResult had before being excepted, so it can be restored
+// once the exception expires or is removed
+const ComplianceCheckResultExceptedStatusAnnotation = "compliance.openshift.io/excepted-status"
+
+// ExceptionScope narrows down the results an exception applies to
+type ExceptionScope struct {
+	// The node roles whose results are excepted, e.g. worker. If empty,
+	// the results of all scans are excepted.
+	// +optional
+	// +nullable
+	NodeRoles []string `json:"nodeRoles,omitempty"`
+}
+
+// ComplianceExceptionSpec defines the desired state of ComplianceException
+type ComplianceExceptionSpec struct {
+	// The name of the Rule whose failures are excepted
+	Rule string `json:"rule"`
+	// Narrows down the results the exception applies to
+	// +optional
+	Scope ExceptionScope `json:"scope,omitempty"`
+	// Why the failures are acceptable. It can't be empty.
+	// +kubebuilder:validation:Pattern=^.+$
+	Justification string `json:"justification"`
+	// Who approved the exception. On OpenShift, the operator sets it to
+	// the user who created or last changed the exception, overwriting any
+	// value given.
+	// +optional
+	Approver string `json:"approver,omitempty"`
+	// When the exception expires. From then on, the rule is evaluated
+	// normally again.
+	ExpiresAt metav1.Time `json:"expiresAt"`
+}
+
+// ComplianceExceptionState defines the state of the exception
+type ComplianceExceptionState string
+
+const (
+	// ComplianceExceptionStateActive is a state where the exception is in effect
+	ComplianceExceptionStateActive ComplianceExceptionState = "ACTIVE"
+	// ComplianceExceptionStateExpired is a state where the exception is past its expiry date
+	ComplianceExceptionStateExpired ComplianceExceptionState = "EXPIRED"
+	// ComplianceExceptionStateError is a state where the exception can't be applied
+	ComplianceExceptionStateError ComplianceExceptionState = "ERROR"
+)
+
+// ComplianceExceptionStatus defines the observed state of ComplianceException
+type ComplianceExceptionStatus struct {
+	// The current state of the exception
+	State ComplianceExceptionState `json:"state,omitempty"`
+	// +optional
+	ErrorMessage string `json:"errorMessage,omitempty"`
+	// The names of the ComplianceCheckResults currently excepted
+	// +optional
+	// +nullable
+	ExceptedChecks []string `json:"exceptedChecks,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ComplianceException excepts the failures of a rule until an expiry date
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=complianceexceptions,scope=Namespaced,shortName=exception;exceptions
+// +kubebuilder:printcolumn:name="Rule",type="string",JSONPath=`.spec.rule`
+// +kubebuilder:printcolumn:name="Expires",type="string",JSONPath=`.spec.expiresAt`
+// +kubebuilder:printcolumn:name="State",type="string",JSONPath=`.status.state`
+type ComplianceException struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ComplianceExceptionSpec   `json:"spec,omitempty"`
+	Status ComplianceExceptionStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ComplianceExceptionList contains a list of ComplianceException
+type ComplianceExceptionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ComplianceException `json:"items"`
+}
+
+// GetReference returns how the check results excepted by the exception
+// reference it
+func (e *ComplianceException) GetReference() string {
+	return e.Namespace + "/" + e.Name
+}
+
+// AppliesToNodeRole returns whether the exception applies to the results of
+// scans of the node role. Exceptions without node roles apply to all scans.
+func (e *ComplianceException) AppliesToNodeRole(role string) bool {
+	if len(e.Spec.Scope.NodeRoles) == 0 {
+		return true
+	}
+	for _, scopeRole := range e.Spec.Scope.NodeRoles {
+		if scopeRole == role {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	SchemeBuilder.Register(&ComplianceException{}, &ComplianceExceptionList{})
+}
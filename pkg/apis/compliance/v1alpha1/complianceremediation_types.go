@@ -21,6 +21,8 @@ const (
 	RemediationError               RemediationApplicationState = "Error"
 	RemediationMissingDependencies RemediationApplicationState = "MissingDependencies"
 	RemediationNeedsReview         RemediationApplicationState = "NeedsReview"
+	RemediationRolledBack          RemediationApplicationState = "RolledBack"
+	RemediationDrifted             RemediationApplicationState = "Drifted"
 )
 
 // +kubebuilder:validation:Enum=Configuration;Enforcement
@@ -31,6 +33,35 @@ const (
 	EnforcementRemediation   RemediationType = "Enforcement"
 )
 
+// RemediationEnforcementMode defines how the policy of an Enforcement
+// remediation is enforced
+type RemediationEnforcementMode string
+
+const (
+	// The policy engine only reports the objects that violate the policy
+	EnforcementModeAudit RemediationEnforcementMode = "Audit"
+	// The policy engine rejects the objects that violate the policy
+	EnforcementModeDeny RemediationEnforcementMode = "Deny"
+)
+
+const (
+	// EnforcementEngineGatekeeper is the OPA Gatekeeper policy engine
+	EnforcementEngineGatekeeper = "gatekeeper"
+	// EnforcementEngineKyverno is the Kyverno policy engine
+	EnforcementEngineKyverno = "kyverno"
+)
+
+type RemediationPatchType string
+
+const (
+	// The object of the remediation is a strategic merge patch. Custom
+	// resources don't support strategic merge, so they're patched with a
+	// JSON merge patch instead.
+	StrategicMergePatchRemediation RemediationPatchType = "StrategicMergePatch"
+	// The remediation is a list of JSON patch operations
+	JSONPatchRemediation RemediationPatchType = "JSONPatch"
+)
+
 const (
 	RemediationEnforcementEmpty string = ""
 	RemediationEnforcementOff   string = "off"
@@ -54,6 +85,10 @@ const (
 	// RemediationValueRequiredProcessedLabel specifies that a remediation's needed value
 	// has been processed.
 	RemediationValueRequiredProcessedLabel = "compliance.openshift.io/value-required-processed"
+	// RemediationValuesLabel marks a ConfigMap whose data provides the values
+	// of XCCDF variables that remediations need. The keys are the variable
+	// names, e.g. var-sshd-idle-timeout.
+	RemediationValuesLabel = "compliance.openshift.io/remediation-values"
 	// RemediationCreatedByOperatorAnnotation specifies that a remediation was
 	// created by the Compliance Operator; this is used for the Compliance Operator to
 	// know whether it can delete the object or not when un-applying a remediation.
@@ -84,12 +119,29 @@ const (
 	RemediationUnsetValueAnnotation = "compliance.openshift.io/unset-value"
 	// RemediationValueUsedAnnotation specifies the values used for a remediation
 	RemediationValueUsedAnnotation = "compliance.openshift.io/xccdf-value-used"
+	// RemediationSourcedValueAnnotation specifies the values of a remediation
+	// that were taken from a remediation values ConfigMap
+	RemediationSourcedValueAnnotation = "compliance.openshift.io/sourced-value"
 	// OCPVersionDependencyAnnotation specifies that the OCP cluster needs to fall
 	// into a range in order to be applied
 	OCPVersionDependencyAnnotation = "compliance.openshift.io/ocp-version"
 	// K8SVersionDependencyAnnotation specifies that the k8s cluster needs to fall
 	// into a range in order to be applied
 	K8SVersionDependencyAnnotation = "compliance.openshift.io/k8s-version"
+	// RemediationPreviewAnnotation specifies that the remediation is only to be
+	// previewed. While it's set, the operator neither applies nor un-applies the
+	// remediation, but records what applying it would change in the cluster.
+	RemediationPreviewAnnotation = "compliance.openshift.io/preview"
+	// RemediationRolledBackAnnotation specifies that the remediation was
+	// rolled back by its ComplianceSuite. The value is the reason. While it's
+	// set, the suite doesn't apply the remediation automatically again.
+	RemediationRolledBackAnnotation = "compliance.openshift.io/rolled-back"
+	// RemediationDisruptionAnnotation specifies the estimated disruption of
+	// the fix the remediation comes from, as defined in the data stream
+	RemediationDisruptionAnnotation = "compliance.openshift.io/disruption"
+	// RemediationSeverityAnnotation specifies the severity of the rule the
+	// remediation comes from
+	RemediationSeverityAnnotation = "compliance.openshift.io/severity"
 )
 
 var (
@@ -112,6 +164,13 @@ type ComplianceRemediationSpecMeta struct {
 	// stays in compliance via means of authorization.
 	// +kubebuilder:default="Configuration"
 	Type RemediationType `json:"type,omitempty"`
+	// How the policy of an Enforcement remediation is enforced by its
+	// engine. With Audit, the engine only reports the objects that violate
+	// the policy. With Deny, it rejects them. If unset, the policy is
+	// applied as the content defines it.
+	// +optional
+	// +kubebuilder:validation:Enum=Audit;Deny
+	EnforcementMode RemediationEnforcementMode `json:"enforcementMode,omitempty"`
 }
 
 type ComplianceRemediationPayload struct {
@@ -121,6 +180,53 @@ type ComplianceRemediationPayload struct {
 	// +kubebuilder:validation:EmbeddedResource
 	// +kubebuilder:validation:nullable
 	Object *unstructured.Unstructured `json:"object,omitempty"`
+	// How the object is applied. If unset, the object is the full object
+	// to create or update. With StrategicMergePatch, the object only holds
+	// the fields to change in an existing object. With JSONPatch, the object
+	// only identifies an existing object, and the jsonPatch operations
+	// change it.
+	// +optional
+	// +kubebuilder:validation:Enum=JSONPatch;StrategicMergePatch
+	PatchType RemediationPatchType `json:"patchType,omitempty"`
+	// The operations of a JSONPatch remediation
+	// +optional
+	// +nullable
+	JSONPatch []JSONPatchOperation `json:"jsonPatch,omitempty"`
+	// The fix the object was rendered from. Only kept if the fix uses
+	// values that weren't set by the scan, so that the object can be
+	// rendered again once they're provided.
+	// +optional
+	Template *RemediationTemplate `json:"template,omitempty"`
+}
+
+// RemediationTemplate is the unrendered fix of a remediation
+type RemediationTemplate struct {
+	// The content of the fix, with the references to XCCDF variables
+	Content string `json:"content"`
+	// The position of the object of the remediation among the objects of
+	// the fix
+	// +optional
+	Index int `json:"index,omitempty"`
+	// The values the fix was rendered with
+	// +optional
+	Values map[string]string `json:"values,omitempty"`
+}
+
+// IsPatch returns whether the payload patches an existing object instead of
+// defining a whole object
+func (p *ComplianceRemediationPayload) IsPatch() bool {
+	return p.PatchType != ""
+}
+
+// JSONPatchOperation is an RFC 6902 JSON patch operation
+type JSONPatchOperation struct {
+	// +kubebuilder:validation:Enum=add;remove;replace;test
+	Op string `json:"op"`
+	// The JSON pointer of the field, e.g. /spec/audit/profile
+	Path string `json:"path"`
+	// The JSON-encoded value of the operation. Not used by remove.
+	// +optional
+	Value string `json:"value,omitempty"`
 }
 
 func (p *ComplianceRemediationPayload) normalized() *ComplianceRemediationPayload {
@@ -154,6 +260,106 @@ type ComplianceRemediationStatus struct {
 	// +kubebuilder:default="NotApplied"
 	ApplicationState RemediationApplicationState `json:"applicationState,omitempty"`
 	ErrorMessage     string                      `json:"errorMessage,omitempty"`
+	// What applying the remediation would change in the cluster. Only set
+	// while the remediation carries the preview annotation.
+	// +optional
+	Preview *RemediationPreview `json:"preview,omitempty"`
+	// Why the remediation was rolled back
+	// +optional
+	RollbackReason string `json:"rollbackReason,omitempty"`
+	// How the object in the cluster drifted from the applied remediation.
+	// Only set while the remediation is in the Drifted state.
+	// +optional
+	Drift *RemediationDrift `json:"drift,omitempty"`
+	// The values the fields touched by a patch remediation had before it
+	// was applied. They are restored when the remediation is un-applied.
+	// +optional
+	// +nullable
+	PreImage []RemediationFieldValue `json:"preImage,omitempty"`
+	// The state of the policy of an applied Enforcement remediation, as
+	// reported by its engine
+	// +optional
+	Enforcement *RemediationEnforcementStatus `json:"enforcement,omitempty"`
+}
+
+// RemediationEnforcementStatus is the state of a policy in its engine
+type RemediationEnforcementStatus struct {
+	// The policy engine, gatekeeper or kyverno
+	Engine string `json:"engine"`
+	// How the engine enforces the policy
+	// +optional
+	Mode RemediationEnforcementMode `json:"mode,omitempty"`
+	// Whether the engine reports the policy as active
+	Ready bool `json:"ready"`
+	// The number of objects that violate the policy, as of the last audit
+	// of the engine. Unset if the engine doesn't report it.
+	// +optional
+	Violations *int64 `json:"violations,omitempty"`
+	// Why the policy isn't active, or why its state couldn't be read
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// RemediationFieldValue is the value of a field of an object in the cluster
+type RemediationFieldValue struct {
+	// The JSON pointer of the field, e.g. /spec/audit/profile
+	Path string `json:"path"`
+	// The JSON-encoded value of the field. Empty if the field wasn't set.
+	// +optional
+	Value string `json:"value,omitempty"`
+}
+
+// RemediationDrift records how the object of an applied remediation was
+// changed or deleted since
+type RemediationDrift struct {
+	// The fields of the remediation that the object no longer matches. The
+	// current value is the one in the cluster and the desired value is the
+	// one of the remediation.
+	// +optional
+	// +nullable
+	Changes []RemediationFieldChange `json:"changes,omitempty"`
+	// When the drift was first detected
+	DetectedAt metav1.Time `json:"detectedAt"`
+}
+
+// RemediationPreview is the outcome of a server-side dry-run apply of the
+// remediation object
+type RemediationPreview struct {
+	// The fields of the live object that applying the remediation would change
+	// +optional
+	// +nullable
+	Changes []RemediationFieldChange `json:"changes,omitempty"`
+	// The fields that are managed by another field manager and that applying
+	// the remediation would take over
+	// +optional
+	// +nullable
+	Conflicts []RemediationFieldConflict `json:"conflicts,omitempty"`
+	// Why the preview couldn't be computed
+	// +optional
+	ErrorMessage string `json:"errorMessage,omitempty"`
+}
+
+// RemediationFieldChange is a field that applying the remediation would change
+type RemediationFieldChange struct {
+	// The path of the field, e.g. .spec.config.ignition
+	Path string `json:"path"`
+	// The JSON-encoded value of the field in the cluster. Empty if the field
+	// isn't set yet.
+	// +optional
+	Current string `json:"current,omitempty"`
+	// The JSON-encoded value of the field once the remediation is applied.
+	// Empty if the field would be removed.
+	// +optional
+	Desired string `json:"desired,omitempty"`
+}
+
+// RemediationFieldConflict is a field owned by another field manager
+type RemediationFieldConflict struct {
+	// The path of the field, e.g. .data.key
+	Path string `json:"path"`
+	// The conflict as reported by the API server, including the manager
+	// that owns the field
+	Message string `json:"message,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -186,6 +392,12 @@ func (r *ComplianceRemediation) GetScan() string {
 	return r.Labels[ComplianceScanLabel]
 }
 
+// GetGroup returns the name of the ComplianceRemediationGroup the
+// remediation belongs to, if any
+func (r *ComplianceRemediation) GetGroup() string {
+	return r.Labels[RemediationGroupLabel]
+}
+
 func (r *ComplianceRemediation) GetMcName() string {
 	if r.GetScan() == "" {
 		return ""
@@ -220,8 +432,20 @@ func (r *ComplianceRemediation) IsApplied() bool {
 	applied := r.Status.ApplicationState == RemediationApplied
 	outDatedButApplied := r.Spec.Apply && r.Status.ApplicationState == RemediationOutdated
 	appliedButUnmet := r.Spec.Apply && r.Status.ApplicationState == RemediationMissingDependencies
+	appliedButDrifted := r.Spec.Apply && r.Status.ApplicationState == RemediationDrifted
+
+	return applied || outDatedButApplied || appliedButUnmet || appliedButDrifted
+}
 
-	return applied || outDatedButApplied || appliedButUnmet
+// IsInCluster tells whether the object of the ComplianceRemediation was
+// applied and not un-applied since, whether or not it's still the one the
+// remediation asks for
+func (r *ComplianceRemediation) IsInCluster() bool {
+	switch r.Status.ApplicationState {
+	case RemediationApplied, RemediationDrifted, RemediationOutdated:
+		return true
+	}
+	return false
 }
 
 func (r *ComplianceRemediation) HasUnmetDependencies() bool {
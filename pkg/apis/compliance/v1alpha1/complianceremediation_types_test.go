@@ -105,4 +105,23 @@ var _ = Describe("Testing ComplianceRemediation API", func() {
 			Expect(err).To(MatchError(KubeDepsNotFound))
 		})
 	})
+
+	Context("Telling whether the object of a remediation is in the cluster", func() {
+		It("is in the cluster once applied, until it's un-applied", func() {
+			rem := &ComplianceRemediation{}
+			for state, inCluster := range map[RemediationApplicationState]bool{
+				RemediationPending:             false,
+				RemediationApplied:             true,
+				RemediationDrifted:             true,
+				RemediationOutdated:            true,
+				RemediationNotApplied:          false,
+				RemediationError:               false,
+				RemediationNeedsReview:         false,
+				RemediationMissingDependencies: false,
+			} {
+				rem.Status.ApplicationState = state
+				Expect(rem.IsInCluster()).To(Equal(inCluster), string(state))
+			}
+		})
+	})
 })
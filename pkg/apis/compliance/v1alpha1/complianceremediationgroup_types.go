@@ -0,0 +1,128 @@
+package v1alpha1
+
+import (
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RemediationGroupLabel names the ComplianceRemediationGroup a remediation
+// belongs to
+const RemediationGroupLabel = "compliance.openshift.io/remediation-group"
+
+// RemediationGroupOrderAnnotation specifies the position of a remediation
+// in its group
+const RemediationGroupOrderAnnotation = "compliance.openshift.io/remediation-order"
+
+// RemediationGroupMember is a remediation of a group and its position
+type RemediationGroupMember struct {
+	// The name of the ComplianceRemediation
+	Name string `json:"name"`
+	// Members are applied in ascending order and un-applied in descending
+	// order. Members with the same order don't wait for each other.
+	// +kubebuilder:default=0
+	Order int32 `json:"order"`
+}
+
+// ComplianceRemediationGroupSpec defines the desired state of ComplianceRemediationGroup
+type ComplianceRemediationGroupSpec struct {
+	// The remediations of the group. They are applied and un-applied
+	// together.
+	Members []RemediationGroupMember `json:"members"`
+}
+
+// RemediationGroupPhase defines the phase of a remediation group
+type RemediationGroupPhase string
+
+const (
+	// RemediationGroupNotApplied is a phase where no member is applied
+	RemediationGroupNotApplied RemediationGroupPhase = "NotApplied"
+	// RemediationGroupApplying is a phase where the members are being applied
+	RemediationGroupApplying RemediationGroupPhase = "Applying"
+	// RemediationGroupApplied is a phase where all members are applied
+	RemediationGroupApplied RemediationGroupPhase = "Applied"
+	// RemediationGroupUnapplying is a phase where the members are being un-applied
+	RemediationGroupUnapplying RemediationGroupPhase = "Unapplying"
+	// RemediationGroupError is a phase where a member couldn't be un-applied
+	RemediationGroupError RemediationGroupPhase = "Error"
+	// RemediationGroupRolledBack is a phase where the group was un-applied
+	// because a member couldn't be applied
+	RemediationGroupRolledBack RemediationGroupPhase = "RolledBack"
+)
+
+// RemediationGroupMemberStatus is the state of a member of a group
+type RemediationGroupMemberStatus struct {
+	// The name of the ComplianceRemediation
+	Name string `json:"name"`
+	// The position of the remediation in the group
+	Order int32 `json:"order"`
+	// Whether the remediation is set to be applied
+	Apply bool `json:"apply"`
+	// The application state of the remediation
+	// +optional
+	ApplicationState RemediationApplicationState `json:"applicationState,omitempty"`
+}
+
+// ComplianceRemediationGroupStatus defines the observed state of ComplianceRemediationGroup
+type ComplianceRemediationGroupStatus struct {
+	// The phase of the group
+	Phase RemediationGroupPhase `json:"phase,omitempty"`
+	// Why the group was rolled back or is in the Error phase
+	// +optional
+	ErrorMessage string `json:"errorMessage,omitempty"`
+	// The members of the group, in the order they're applied
+	// +optional
+	// +nullable
+	Members []RemediationGroupMemberStatus `json:"members,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ComplianceRemediationGroup ties remediations that fix the same issue
+// together, so that they're applied in order and succeed or fail together
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=complianceremediationgroups,scope=Namespaced,shortName=remgroup;remgroups
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=`.status.phase`
+type ComplianceRemediationGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ComplianceRemediationGroupSpec   `json:"spec,omitempty"`
+	Status ComplianceRemediationGroupStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ComplianceRemediationGroupList contains a list of ComplianceRemediationGroup
+type ComplianceRemediationGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ComplianceRemediationGroup `json:"items"`
+}
+
+// GetMember returns the member of the group with the given name, if any
+func (g *ComplianceRemediationGroup) GetMember(name string) *RemediationGroupMember {
+	for i := range g.Spec.Members {
+		if g.Spec.Members[i].Name == name {
+			return &g.Spec.Members[i]
+		}
+	}
+	return nil
+}
+
+// SortedMembers returns the members of the group in the order they're
+// applied
+func (g *ComplianceRemediationGroup) SortedMembers() []RemediationGroupMember {
+	members := append([]RemediationGroupMember{}, g.Spec.Members...)
+	sort.SliceStable(members, func(i, j int) bool {
+		if members[i].Order != members[j].Order {
+			return members[i].Order < members[j].Order
+		}
+		return members[i].Name < members[j].Name
+	})
+	return members
+}
+
+func init() {
+	SchemeBuilder.Register(&ComplianceRemediationGroup{}, &ComplianceRemediationGroupList{})
+}
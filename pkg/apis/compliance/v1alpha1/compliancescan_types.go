@@ -161,8 +161,44 @@ type RawResultStorageSettings struct {
 	// in case the target set of nodes have custom taints that don't allow certain
 	// workloads to run. Defaults to allowing scheduling on master nodes.
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// Defines whether a human-readable HTML report of the results of the
+	// scan is stored along with its raw results. The report is generated
+	// once the results of the scan are aggregated. Defaults to false.
+	// +optional
+	HTMLReport bool `json:"htmlReport,omitempty"`
+}
+
+// AggregatorSettings defines how the aggregator creates the result objects
+// of a scan
+type AggregatorSettings struct {
+	// The number of check results the aggregator creates or updates at the
+	// same time, along with their remediations. Defaults to 10.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=10
+	Concurrency int32 `json:"concurrency,omitempty"`
+	// The number of requests per second the aggregator sends to the API
+	// server at most. Defaults to 20.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=20
+	QPS int32 `json:"qps,omitempty"`
+	// The number of requests the aggregator may send at once on top of
+	// qps. Defaults to 30.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=30
+	Burst int32 `json:"burst,omitempty"`
 }
 
+// ExportFormat is a format the results of a scan are exported to
+// +kubebuilder:validation:Enum=sarif;junit
+type ExportFormat string
+
+const (
+	// ExportFormatSARIF exports the results as a SARIF 2.1.0 log
+	ExportFormatSARIF ExportFormat = "sarif"
+	// ExportFormatJUnit exports the results as a JUnit XML report
+	ExportFormatJUnit ExportFormat = "junit"
+)
+
 // ComplianceScanSettings groups together settings of a ComplianceScan
 type ComplianceScanSettings struct {
 	// Enable debug logging of workloads and OpenSCAP
@@ -204,6 +240,28 @@ type ComplianceScanSettings struct {
 	// +kubebuilder:default=false
 	ShowNotApplicable bool `json:"showNotApplicable,omitempty"`
 
+	// Determines whether the check results of node scans list the result
+	// of each node, instead of only the result all nodes agree on, or
+	// INCONSISTENT if they don't.
+	// +kubebuilder:default=false
+	PerNodeResults bool `json:"perNodeResults,omitempty"`
+
+	// Determines whether node scans run the probe scripts of the
+	// CustomRules of their tailored profile on the nodes. The results of
+	// the rules whose probes don't run are MANUAL.
+	// +kubebuilder:default=false
+	AllowNodeProbes bool `json:"allowNodeProbes,omitempty"`
+
+	// Specifies how the aggregator creates the result objects of the scan.
+	// +optional
+	Aggregator AggregatorSettings `json:"aggregator,omitempty"`
+
+	// The formats the results of the scan are forwarded to, along with the
+	// ComplianceCheckResult objects. The results exported to each format are
+	// stored in a ConfigMap owned by the scan.
+	// +optional
+	ExportFormats []ExportFormat `json:"exportFormats,omitempty"`
+
 	// Defines the PriorityClass to use for launching scan related pods,
 	// the Name of a desired PriorityClass should be set here, this is an
 	// optional field, if PriorityClass is invalid or not found, it will be ignored.
@@ -282,10 +340,17 @@ type ComplianceScanStatus struct {
 	Conditions Conditions `json:"conditions,omitempty"`
 	//Is the number of retries left for the scan on timeout
 	RemainingRetries int `json:"remainingRetries,omitempty"`
+	// Is the number of times the aggregation of the results was retried
+	// after the aggregator failed
+	AggregatorRetries int `json:"aggregatorRetries,omitempty"`
 	// Is the time when the scan was started
 	StartTimestamp *metav1.Time `json:"startTimestamp,omitempty"`
 	// Is the time when the scan was finished
 	EndTimestamp *metav1.Time `json:"endTimestamp,omitempty"`
+	// Is whether the HTML report of the scan was stored with its raw
+	// results. It's only set when the scan stores an HTML report.
+	// +optional
+	HTMLReportStored *bool `json:"htmlReportStored,omitempty"`
 }
 
 // StorageReference stores a reference to where certain objects are being stored
@@ -426,3 +491,7 @@ func (s *ComplianceScanStatus) SetConditionReady() {
 func (s *ComplianceScanStatus) SetConditionTimeout() {
 	s.Conditions.SetConditionTimeout("scan")
 }
+
+func (s *ComplianceScanStatus) SetConditionAggregationFailed() {
+	s.Conditions.SetConditionAggregationFailed("scan")
+}
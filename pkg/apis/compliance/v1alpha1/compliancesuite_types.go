@@ -3,6 +3,7 @@ package v1alpha1
 import (
 	"reflect"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -15,6 +16,15 @@ const SuiteLabel = "compliance.openshift.io/suite"
 // compliance suite controller
 const SuiteScriptLabel = "compliance.openshift.io/suite-script"
 
+// RemediationRolloutCanaryLabel marks the ComplianceScans that verify the
+// canary nodes of a staged remediation rollout. The value is the name of the
+// ComplianceSuite rolling out the remediations.
+const RemediationRolloutCanaryLabel = "compliance.openshift.io/canary-for-suite"
+
+// RemediationRolloutHaltedCondition is the condition a ComplianceSuite gets
+// when the staged rollout of its remediations was halted
+const RemediationRolloutHaltedCondition ConditionType = "RemediationRolloutHalted"
+
 // SuiteFinalizer is a finalizer for ComplianceSuites. It gets automatically
 // added by the ComplianceSuite controller in order to delete resources.
 const SuiteFinalizer = "suite.finalizers.compliance.openshift.io"
@@ -86,6 +96,199 @@ type ComplianceSuiteSettings struct {
 	// defaulting to False.
 	// +kubebuilder:default=false
 	Suspend bool `json:"suspend,omitempty"`
+	// Defines how MachineConfig and KubeletConfig remediations are rolled
+	// out. If unset, each affected MachineConfigPool gets all of them at once.
+	// +optional
+	RemediationRollout *RemediationRolloutSettings `json:"remediationRollout,omitempty"`
+	// Defines whether the MachineConfig and KubeletConfig remediations that
+	// were applied together are reverted if they degrade a pool or make
+	// other checks fail. If unset, remediations are never rolled back.
+	// +optional
+	RemediationRollback *RemediationRollbackSettings `json:"remediationRollback,omitempty"`
+	// Limits the remediations that are applied automatically by their
+	// disruption. The remediations beyond the limit wait until they are
+	// applied manually. If unset, all the remediations are auto-applied.
+	// +optional
+	AutoApplyPolicy *RemediationAutoApplyPolicy `json:"autoApplyPolicy,omitempty"`
+	// Defines whether the objects of applied remediations that were changed
+	// or deleted since are restored automatically. If false, such
+	// remediations are only marked as drifted.
+	// +optional
+	ReapplyDriftedRemediations bool `json:"reapplyDriftedRemediations,omitempty"`
+
+	// Defines whether an OSCAL assessment-results document of the results of
+	// the suite is generated whenever its scans are done. The document is
+	// stored in a ConfigMap owned by the suite.
+	// +optional
+	OSCALAssessmentResults bool `json:"oscalAssessmentResults,omitempty"`
+}
+
+// RemediationAutoApplyPolicy defines the highest disruption of the
+// remediations that are applied automatically
+// +k8s:openapi-gen=true
+type RemediationAutoApplyPolicy struct {
+	// The highest disruption of the remediations that are applied
+	// automatically. Remediations whose disruption is unknown are never
+	// applied automatically.
+	// +kubebuilder:validation:Enum=low;medium;high
+	MaxDisruption string `json:"maxDisruption"`
+	// Overrides the highest disruption for the remediations of rules of a
+	// given severity
+	// +optional
+	// +nullable
+	SeverityOverrides []SeverityDisruptionOverride `json:"severityOverrides,omitempty"`
+}
+
+// SeverityDisruptionOverride overrides the highest disruption of the
+// remediations that are applied automatically for a rule severity
+// +k8s:openapi-gen=true
+type SeverityDisruptionOverride struct {
+	// The severity of the rules
+	// +kubebuilder:validation:Enum=unknown;info;low;medium;high
+	Severity ComplianceCheckResultSeverity `json:"severity"`
+	// The highest disruption of the remediations of these rules that are
+	// applied automatically
+	// +kubebuilder:validation:Enum=low;medium;high
+	MaxDisruption string `json:"maxDisruption"`
+}
+
+// disruptionLevels orders the disruption estimates of the fixes. Any other
+// estimate, including "unknown", is above all of them.
+var disruptionLevels = map[string]int{
+	"low":    1,
+	"medium": 2,
+	"high":   3,
+}
+
+// Allows returns whether a remediation of the given disruption and rule
+// severity is applied automatically under the policy
+func (p *RemediationAutoApplyPolicy) Allows(disruption string, severity ComplianceCheckResultSeverity) bool {
+	maxDisruption := p.MaxDisruption
+	for _, override := range p.SeverityOverrides {
+		if override.Severity == severity {
+			maxDisruption = override.MaxDisruption
+			break
+		}
+	}
+
+	level, known := disruptionLevels[disruption]
+	return known && level <= disruptionLevels[maxDisruption]
+}
+
+// RemediationRollbackSettings defines when a batch of remediations is rolled
+// back
+// +k8s:openapi-gen=true
+type RemediationRollbackSettings struct {
+	// How long the pools and the check results are watched once a batch of
+	// remediations was rolled out. A degraded pool or a new check failure
+	// within this window rolls the batch back.
+	// +kubebuilder:default="1h"
+	Window metav1.Duration `json:"window,omitempty"`
+}
+
+// RemediationRolloutSettings defines a staged rollout of the remediations
+// that require the nodes to reboot
+// +k8s:openapi-gen=true
+type RemediationRolloutSettings struct {
+	// The names of the MachineConfigPools that get the remediations first.
+	// The other affected pools stay paused until the nodes of these pools
+	// are updated and a rescan of just those nodes passes. A canary pool is
+	// typically a custom pool that selects a subset of the nodes of another
+	// pool.
+	// +kubebuilder:validation:MinItems=1
+	CanaryPools []string `json:"canaryPools"`
+}
+
+// RemediationRolloutPhase is the phase of a staged remediation rollout
+type RemediationRolloutPhase string
+
+const (
+	// RemediationRolloutCanaryUpdating means the canary pools are being updated
+	RemediationRolloutCanaryUpdating RemediationRolloutPhase = "CanaryUpdating"
+	// RemediationRolloutCanaryVerifying means the canary nodes are being rescanned
+	RemediationRolloutCanaryVerifying RemediationRolloutPhase = "CanaryVerifying"
+	// RemediationRolloutDone means the remediations were rolled out to all the pools
+	RemediationRolloutDone RemediationRolloutPhase = "Done"
+	// RemediationRolloutHalted means the canary nodes broke and the rollout was stopped
+	RemediationRolloutHalted RemediationRolloutPhase = "Halted"
+)
+
+// RemediationBatchPhase is the phase of a batch of remediations that can be
+// rolled back
+type RemediationBatchPhase string
+
+const (
+	// RemediationBatchApplying means the remediations of the batch are being applied
+	RemediationBatchApplying RemediationBatchPhase = "Applying"
+	// RemediationBatchWatching means the batch was rolled out and the pools
+	// are being watched while they update
+	RemediationBatchWatching RemediationBatchPhase = "Watching"
+	// RemediationBatchVerifying means the pools were updated and the suite
+	// is being rescanned to look for new failures
+	RemediationBatchVerifying RemediationBatchPhase = "Verifying"
+	// RemediationBatchSucceeded means the watch window passed without issues
+	RemediationBatchSucceeded RemediationBatchPhase = "Succeeded"
+	// RemediationBatchRolledBack means the remediations of the batch were reverted
+	RemediationBatchRolledBack RemediationBatchPhase = "RolledBack"
+)
+
+// KubeletConfigSnapshot is the spec a KubeletConfig had before a batch of
+// remediations was merged into it
+type KubeletConfigSnapshot struct {
+	// The name of the KubeletConfig
+	Name string `json:"name"`
+	// The JSON-encoded spec of the KubeletConfig. Empty if the KubeletConfig
+	// was created by the batch.
+	// +optional
+	Spec string `json:"spec,omitempty"`
+}
+
+// RemediationBatchStatus tracks the MachineConfig and KubeletConfig
+// remediations that a suite applied together
+// +k8s:openapi-gen=true
+type RemediationBatchStatus struct {
+	// The phase the batch is in
+	Phase RemediationBatchPhase `json:"phase,omitempty"`
+	// The names of the ComplianceRemediations of the batch
+	// +optional
+	// +nullable
+	Remediations []string `json:"remediations,omitempty"`
+	// The names of the MachineConfigPools the batch was applied to
+	// +optional
+	// +nullable
+	Pools []string `json:"pools,omitempty"`
+	// The names of the ComplianceCheckResults that were failing before the
+	// batch was applied
+	// +optional
+	// +nullable
+	BaselineFailures []string `json:"baselineFailures,omitempty"`
+	// The KubeletConfigs the batch was merged into, as they were before
+	// +optional
+	// +nullable
+	KubeletConfigs []KubeletConfigSnapshot `json:"kubeletConfigs,omitempty"`
+	// When the batch was rolled out to all the pools
+	// +optional
+	RolledOutAt *metav1.Time `json:"rolledOutAt,omitempty"`
+	// When the suite was rescanned to look for new failures
+	// +optional
+	RescannedAt *metav1.Time `json:"rescannedAt,omitempty"`
+	// Why the batch was rolled back
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// RemediationRolloutStatus is the state of a staged remediation rollout
+// +k8s:openapi-gen=true
+type RemediationRolloutStatus struct {
+	// The phase the rollout is in
+	Phase RemediationRolloutPhase `json:"phase,omitempty"`
+	// The names of the ComplianceScans that rescan the canary nodes
+	// +optional
+	// +nullable
+	CanaryScans []string `json:"canaryScans,omitempty"`
+	// Why the rollout was halted
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // ComplianceSuiteSpec defines the desired state of ComplianceSuite
@@ -107,6 +310,14 @@ type ComplianceSuiteStatus struct {
 	ErrorMessage string                        `json:"errorMessage,omitempty"`
 	// +optional
 	Conditions Conditions `json:"conditions,omitempty"`
+	// The state of the staged rollout of the remediations, if the suite
+	// rolls them out in stages
+	// +optional
+	RemediationRollout *RemediationRolloutStatus `json:"remediationRollout,omitempty"`
+	// The last batch of remediations the suite applied, if the suite rolls
+	// back remediations
+	// +optional
+	RemediationBatch *RemediationBatchStatus `json:"remediationBatch,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -247,3 +458,35 @@ func (s *ComplianceSuiteStatus) SetConditionsProcessing() {
 func (s *ComplianceSuiteStatus) SetConditionReady() {
 	s.Conditions.SetConditionReady("suite")
 }
+
+func (s *ComplianceSuiteStatus) SetConditionRolloutHalted(reason ConditionReason, message string) {
+	s.Conditions.SetCondition(Condition{
+		Type:    RemediationRolloutHaltedCondition,
+		Status:  corev1.ConditionTrue,
+		Reason:  reason,
+		Message: message,
+	})
+}
+
+// ShouldRollbackRemediations returns whether the suite rolls back the
+// remediations that degrade a pool or make other checks fail
+func (s *ComplianceSuite) ShouldRollbackRemediations() bool {
+	return s.Spec.RemediationRollback != nil
+}
+
+// ShouldAutoApplyRemediation returns whether the remediation is within the
+// auto-apply policy of the suite. Remediations applied through the
+// apply-remediations annotation aren't subject to the policy.
+func (s *ComplianceSuite) ShouldAutoApplyRemediation(rem *ComplianceRemediation) bool {
+	if s.Spec.AutoApplyPolicy == nil || s.ApplyRemediationsAnnotationSet() {
+		return true
+	}
+	return s.Spec.AutoApplyPolicy.Allows(rem.Annotations[RemediationDisruptionAnnotation],
+		ComplianceCheckResultSeverity(rem.Annotations[RemediationSeverityAnnotation]))
+}
+
+// HasStagedRemediationRollout returns whether the suite rolls out the
+// remediations that require the nodes to reboot to canary pools first
+func (s *ComplianceSuite) HasStagedRemediationRollout() bool {
+	return s.Spec.RemediationRollout != nil && len(s.Spec.RemediationRollout.CanaryPools) > 0
+}
@@ -0,0 +1,37 @@
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Testing ComplianceSuite API", func() {
+	When("evaluating the auto-apply policy", func() {
+		var policy *RemediationAutoApplyPolicy
+
+		BeforeEach(func() {
+			policy = &RemediationAutoApplyPolicy{
+				MaxDisruption: "low",
+				SeverityOverrides: []SeverityDisruptionOverride{
+					{Severity: CheckResultSeverityHigh, MaxDisruption: "medium"},
+				},
+			}
+		})
+
+		It("allows remediations up to the maximum disruption", func() {
+			Expect(policy.Allows("low", CheckResultSeverityMedium)).To(BeTrue())
+			Expect(policy.Allows("medium", CheckResultSeverityMedium)).To(BeFalse())
+			Expect(policy.Allows("high", CheckResultSeverityLow)).To(BeFalse())
+		})
+
+		It("uses the override of the severity", func() {
+			Expect(policy.Allows("medium", CheckResultSeverityHigh)).To(BeTrue())
+			Expect(policy.Allows("high", CheckResultSeverityHigh)).To(BeFalse())
+		})
+
+		It("never allows remediations of unknown disruption", func() {
+			Expect(policy.Allows("", CheckResultSeverityHigh)).To(BeFalse())
+			Expect(policy.Allows("unknown", CheckResultSeverityLow)).To(BeFalse())
+		})
+	})
+})
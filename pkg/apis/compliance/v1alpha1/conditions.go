@@ -231,3 +231,13 @@ func (conditions *Conditions) SetConditionTimeout(what string) {
 	})
 	conditions.RemoveCondition("Processing")
 }
+
+func (conditions *Conditions) SetConditionAggregationFailed(what string) {
+	conditions.SetCondition(Condition{
+		Type:    "Ready",
+		Status:  corev1.ConditionFalse,
+		Reason:  "AggregationFailed",
+		Message: fmt.Sprintf("The results of the %s couldn't be aggregated", what),
+	})
+	conditions.RemoveCondition("Processing")
+}
@@ -0,0 +1,142 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CustomRuleInput is an API resource a CustomRule checks
+type CustomRuleInput struct {
+	// The name the resource is known by in the expression. It must be a
+	// valid CEL identifier.
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z_][a-zA-Z0-9_]*$`
+	Name string `json:"name"`
+	// The API path of the resource, e.g.
+	// /apis/config.openshift.io/v1/oauths/cluster. The path of a collection,
+	// e.g. /api/v1/namespaces, makes the list of the resources available.
+	// +kubebuilder:validation:Pattern=^/.+$
+	Path string `json:"path"`
+}
+
+// CustomRuleProbe is a script that checks a node
+type CustomRuleProbe struct {
+	// The script that checks the node, run by /bin/sh. The root filesystem
+	// of the node is mounted read-only at the path in the HOST_ROOT
+	// environment variable. The exit status of the script is the result of
+	// the check: 0 is PASS, 1 is FAIL, 2 is NOT-APPLICABLE and any other
+	// status is ERROR.
+	// +kubebuilder:validation:MinLength=1
+	Script string `json:"script"`
+	// How long the script may run, in seconds, before it's killed and the
+	// check is an ERROR
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=300
+	// +kubebuilder:default=30
+	// +optional
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+	// Whether the script needs to read files of the node that only root
+	// can read. The probes of a scan run as an unprivileged user without
+	// any capabilities, unless one of them runs as root, in which case
+	// they all run as root with the DAC_READ_SEARCH capability.
+	// +optional
+	RunAsRoot bool `json:"runAsRoot,omitempty"`
+}
+
+// CustomRuleSpec defines the desired state of CustomRule
+type CustomRuleSpec struct {
+	// The title of the rule. It can't be empty.
+	// +kubebuilder:validation:Pattern=^.+$
+	Title string `json:"title"`
+	// What the rule checks
+	// +optional
+	Description string `json:"description,omitempty"`
+	// Why the rule matters
+	// +optional
+	Rationale string `json:"rationale,omitempty"`
+	// The severity of the rule
+	// +kubebuilder:validation:Enum=unknown;info;low;medium;high
+	// +kubebuilder:default=medium
+	// +optional
+	Severity ComplianceCheckResultSeverity `json:"severity,omitempty"`
+	// How to fix or check the rule manually
+	// +optional
+	Instructions string `json:"instructions,omitempty"`
+	// The API resources the expression checks. Required unless the rule
+	// has a probe.
+	// +kubebuilder:validation:MinItems=1
+	// +optional
+	Inputs []CustomRuleInput `json:"inputs,omitempty"`
+	// A CEL expression that returns true if the resources comply with the
+	// rule, and false otherwise. The resources are available by the names
+	// of the inputs. Required unless the rule has a probe.
+	// +kubebuilder:validation:Pattern=^.+$
+	// +optional
+	Expression string `json:"expression,omitempty"`
+	// A script that checks the nodes instead of the API resources. A rule
+	// with a probe is checked by the node scans of a TailoredProfile, and
+	// can't have inputs or an expression.
+	// +optional
+	Probe *CustomRuleProbe `json:"probe,omitempty"`
+	// A message added to the warnings of the result when the rule fails
+	// +optional
+	FailureMessage string `json:"failureMessage,omitempty"`
+}
+
+// CustomRulePhase defines whether the rule can be used
+type CustomRulePhase string
+
+const (
+	// CustomRulePhaseReady is a phase where the rule can be checked
+	CustomRulePhaseReady CustomRulePhase = "READY"
+	// CustomRulePhaseError is a phase where the rule is invalid, e.g. its
+	// expression doesn't compile
+	CustomRulePhaseError CustomRulePhase = "ERROR"
+)
+
+// CustomRuleStatus defines the observed state of CustomRule
+type CustomRuleStatus struct {
+	// Whether the rule can be used
+	Phase CustomRulePhase `json:"phase,omitempty"`
+	// Why the rule is invalid
+	// +optional
+	ErrorMessage string `json:"errorMessage,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// CustomRule is a rule checked without the need to build a data stream,
+// either on the platform by a CEL expression against API resources, or on
+// the nodes by a probe script
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=customrules,scope=Namespaced,shortName=crule;crules
+// +kubebuilder:printcolumn:name="Title",type="string",JSONPath=`.spec.title`
+// +kubebuilder:printcolumn:name="Severity",type="string",JSONPath=`.spec.severity`
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=`.status.phase`
+type CustomRule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CustomRuleSpec   `json:"spec,omitempty"`
+	Status CustomRuleStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// CustomRuleList contains a list of CustomRule
+type CustomRuleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CustomRule `json:"items"`
+}
+
+// CheckType returns the type of the scans that check the rule, either
+// CheckTypeNode for a rule with a probe or CheckTypePlatform
+func (r *CustomRule) CheckType() string {
+	if r.Spec.Probe != nil {
+		return CheckTypeNode
+	}
+	return CheckTypePlatform
+}
+
+func init() {
+	SchemeBuilder.Register(&CustomRule{}, &CustomRuleList{})
+}
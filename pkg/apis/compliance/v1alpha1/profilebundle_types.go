@@ -35,6 +35,20 @@ type ProfileBundleSpec struct {
 	ContentImage string `json:"contentImage"`
 	// Is the path for the file in the image that contains the content for this bundle.
 	ContentFile string `json:"contentFile"`
+	// Is a reference to a ConfigMap in the same namespace that configures
+	// the compliance standards and annotation formats used when parsing the
+	// references of the rules in this bundle. If unset, the built-in
+	// standards are used.
+	// +optional
+	StandardsConfigMap *StandardsConfigMapRef `json:"standardsConfigMap,omitempty"`
+}
+
+// StandardsConfigMapRef is a reference to a ConfigMap that contains the
+// compliance standards configuration. It assumes a key called
+// `standards.yaml` which will have the configuration contents.
+type StandardsConfigMapRef struct {
+	// Name of the ConfigMap being referenced
+	Name string `json:"name"`
 }
 
 // Defines the observed state of ProfileBundle
@@ -0,0 +1,158 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RemediationApprovalFinalizer is a finalizer for RemediationApprovals. It
+// gets automatically added by the RemediationApproval controller in order to
+// release the remediations held for review.
+const RemediationApprovalFinalizer = "approval.finalizers.compliance.openshift.io"
+
+// RemediationApprovalAnnotation names the RemediationApproval that holds a
+// ComplianceRemediation for review. While it's set, the suite doesn't apply
+// the remediation automatically.
+const RemediationApprovalAnnotation = "compliance.openshift.io/approval"
+
+// RemediationRejectedAnnotation names the RemediationApproval a
+// ComplianceRemediation was rejected in. It outlives the approval, and while
+// it's set, the suite doesn't apply the remediation automatically.
+const RemediationRejectedAnnotation = "compliance.openshift.io/rejected"
+
+// RemediationDecisionType is the decision a reviewer made on a remediation
+type RemediationDecisionType string
+
+const (
+	// RemediationDecisionPending means no decision was made yet
+	RemediationDecisionPending RemediationDecisionType = "Pending"
+	// RemediationDecisionApproved means the remediation is to be applied
+	RemediationDecisionApproved RemediationDecisionType = "Approved"
+	// RemediationDecisionRejected means the remediation is not to be applied
+	RemediationDecisionRejected RemediationDecisionType = "Rejected"
+)
+
+// RemediationDecision is the decision a reviewer made on a remediation
+type RemediationDecision struct {
+	// The name of the ComplianceRemediation
+	Remediation string `json:"remediation"`
+	// Whether the remediation is approved or rejected
+	// +kubebuilder:validation:Enum=Approved;Rejected
+	Decision RemediationDecisionType `json:"decision"`
+	// Who made the decision. On OpenShift, the operator sets it to the user
+	// who added or last changed the decision, overwriting any value given.
+	// +optional
+	Reviewer string `json:"reviewer,omitempty"`
+	// Why the decision was made
+	// +optional
+	Comment string `json:"comment,omitempty"`
+}
+
+// RemediationApprovalSpec defines the desired state of RemediationApproval
+type RemediationApprovalSpec struct {
+	// The name of the ComplianceSuite whose pending remediations are reviewed
+	Suite string `json:"suite"`
+	// The decisions on the remediations held for review. A decision is
+	// final once it's recorded in the status.
+	// +optional
+	// +nullable
+	Decisions []RemediationDecision `json:"decisions,omitempty"`
+}
+
+// RemediationReview is a remediation held for review and its decision
+type RemediationReview struct {
+	// The name of the ComplianceRemediation
+	Name string `json:"name"`
+	// The kind of the object the remediation applies
+	// +optional
+	Kind string `json:"kind,omitempty"`
+	// The name of the object the remediation applies
+	// +optional
+	ObjectName string `json:"objectName,omitempty"`
+	// An estimate of the disruption that applying the remediation causes,
+	// as defined by the fix of the rule
+	// +optional
+	Disruption string `json:"disruption,omitempty"`
+	// What applying the remediation would change in the cluster
+	// +optional
+	// +nullable
+	Changes []RemediationFieldChange `json:"changes,omitempty"`
+	// Why the changes couldn't be previewed
+	// +optional
+	PreviewError string `json:"previewError,omitempty"`
+	// The decision on the remediation
+	Decision RemediationDecisionType `json:"decision"`
+	// Who made the decision
+	// +optional
+	Reviewer string `json:"reviewer,omitempty"`
+	// Why the decision was made
+	// +optional
+	Comment string `json:"comment,omitempty"`
+	// When the decision was recorded
+	// +optional
+	DecidedAt *metav1.Time `json:"decidedAt,omitempty"`
+}
+
+// RemediationApprovalPhase defines the phase of the review
+type RemediationApprovalPhase string
+
+const (
+	// RemediationApprovalPhasePending is a phase where the suite is still running
+	RemediationApprovalPhasePending RemediationApprovalPhase = "PENDING"
+	// RemediationApprovalPhaseReviewing is a phase where some remediations lack a decision
+	RemediationApprovalPhaseReviewing RemediationApprovalPhase = "REVIEWING"
+	// RemediationApprovalPhaseDone is a phase where all remediations were decided on
+	RemediationApprovalPhaseDone RemediationApprovalPhase = "DONE"
+	// RemediationApprovalPhaseError is a phase where the review can't proceed
+	RemediationApprovalPhaseError RemediationApprovalPhase = "ERROR"
+)
+
+// RemediationApprovalStatus defines the observed state of RemediationApproval
+type RemediationApprovalStatus struct {
+	// The phase of the review
+	Phase RemediationApprovalPhase `json:"phase,omitempty"`
+	// +optional
+	ErrorMessage string `json:"errorMessage,omitempty"`
+	// The remediations held for review, sorted by name
+	// +optional
+	// +nullable
+	Remediations []RemediationReview `json:"remediations,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RemediationApproval holds the pending remediations of a suite run until
+// they are approved or rejected
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=remediationapprovals,scope=Namespaced,shortName=approval;approvals
+// +kubebuilder:printcolumn:name="Suite",type="string",JSONPath=`.spec.suite`
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=`.status.phase`
+type RemediationApproval struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RemediationApprovalSpec   `json:"spec,omitempty"`
+	Status RemediationApprovalStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RemediationApprovalList contains a list of RemediationApproval
+type RemediationApprovalList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RemediationApproval `json:"items"`
+}
+
+// GetDecision returns the decision the spec has for the remediation, if any
+func (a *RemediationApproval) GetDecision(remediation string) *RemediationDecision {
+	for i := range a.Spec.Decisions {
+		if a.Spec.Decisions[i].Remediation == remediation {
+			return &a.Spec.Decisions[i]
+		}
+	}
+	return nil
+}
+
+func init() {
+	SchemeBuilder.Register(&RemediationApproval{}, &RemediationApprovalList{})
+}
@@ -18,6 +18,16 @@ const RuleHideTagAnnotationKey = "compliance.openshift.io/hide-tag"
 // RuleVariableAnnotationKey store list of xccdf variables used to render the rule
 const RuleVariableAnnotationKey = "compliance.openshift.io/rule-variable"
 
+// RuleControlAnnotationPrefix is the prefix of the annotations that list the
+// controls of a compliance standard a rule maps to. The full key is the
+// prefix followed by the name of the standard, the value is a semicolon
+// separated list of controls.
+const RuleControlAnnotationPrefix = "control.compliance.openshift.io/"
+
+// RuleControlSeparator separates the controls in the value of a rule's
+// control annotation
+const RuleControlSeparator = ";"
+
 const (
 	CheckTypePlatform = "Platform"
 	CheckTypeNode     = "Node"
@@ -24,11 +24,45 @@ type VariableValueSpec struct {
 	Value string `json:"value"`
 }
 
+// ProfileSetOperation defines how the rules of the composed profiles are combined
+type ProfileSetOperation string
+
+const (
+	// ProfileSetOperationUnion selects the rules that are in any of the profiles
+	ProfileSetOperationUnion ProfileSetOperation = "Union"
+	// ProfileSetOperationIntersection selects the rules that are in all of the profiles
+	ProfileSetOperationIntersection ProfileSetOperation = "Intersection"
+)
+
+// ProfileComposition specifies the profiles whose rules are combined in a TailoredProfile
+type ProfileComposition struct {
+	// The names of the Profiles to combine
+	// +kubebuilder:validation:MinItems=1
+	Profiles []string `json:"profiles"`
+	// How the rules of the profiles are combined
+	// +kubebuilder:validation:Enum=Union;Intersection
+	// +kubebuilder:default=Union
+	// +optional
+	Operation ProfileSetOperation `json:"operation,omitempty"`
+}
+
 // TailoredProfileSpec defines the desired state of TailoredProfile
 type TailoredProfileSpec struct {
 	// +optional
 	// Points to the name of the profile to extend
 	Extends string `json:"extends,omitempty"`
+	// +optional
+	// Points to the name of a TailoredProfile to extend. The selections
+	// and values of the extended TailoredProfile are applied first and the
+	// ones of this TailoredProfile on top of them. This can't be used
+	// together with extends.
+	ExtendsTailoredProfile string `json:"extendsTailoredProfile,omitempty"`
+	// +optional
+	// Composes the rules of several profiles, possibly from different
+	// ProfileBundles. The selections of this TailoredProfile are applied
+	// on top of the composed rules. This can't be used together with
+	// extends or extendsTailoredProfile.
+	Compose *ProfileComposition `json:"compose,omitempty"`
 	// Title for the tailored profile. It can't be empty.
 	// +kubebuilder:validation:Pattern=^.+$
 	Title string `json:"title"`
@@ -51,6 +85,11 @@ type TailoredProfileSpec struct {
 	// +optional
 	// +nullable
 	SetValues []VariableValueSpec `json:"setValues,omitempty"`
+	// Adds the referenced CustomRules, which are checked by the platform
+	// scans of the tailored profile
+	// +optional
+	// +nullable
+	CustomRules []RuleReferenceSpec `json:"customRules,omitempty"`
 }
 
 // TailoredProfileState defines the state fo the tailored profile
@@ -74,6 +113,29 @@ type TailoredProfileStatus struct {
 	// The current state of the tailored profile
 	State        TailoredProfileState `json:"state,omitempty"`
 	ErrorMessage string               `json:"errorMessage,omitempty"`
+	// The tailorings generated for a composed tailored profile. The rules
+	// are split per ProfileBundle and scan type, so each of the outputs
+	// is scanned separately.
+	// +optional
+	Outputs []TailoringOutput `json:"outputs,omitempty"`
+}
+
+// TailoringOutput is a tailoring generated from a composed tailored profile
+type TailoringOutput struct {
+	// The name of the output, used as the name of the scan
+	Name string `json:"name"`
+	// The XCCDF ID of the tailored profile in the tailoring
+	ID string `json:"id"`
+	// The ProfileBundle whose content is tailored
+	ProfileBundle string `json:"profileBundle"`
+	// The type of scan to run with the tailoring
+	ScanType ComplianceScanType `json:"scanType"`
+	// The product the nodes are scanned for, as annotated on the composed
+	// profiles. It's only set for node scans.
+	// +optional
+	Product string `json:"product,omitempty"`
+	// Points to the generated resource
+	OutputRef OutputRef `json:"outputRef"`
 }
 
 // OutputRef is a reference to the object created from the tailored profile
@@ -2,6 +2,7 @@ package v1alpha1
 
 import (
 	"errors"
+	"fmt"
 	"strconv"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -64,6 +65,23 @@ func (v *Variable) SetValue(val string) error {
 	return nil
 }
 
+// ValidateValue returns an error if the value doesn't have the type of the
+// variable, or isn't one of its selections if it has any
+func (v *Variable) ValidateValue(val string) error {
+	if err := v.validateType(val); err != nil {
+		return err
+	}
+	if len(v.Selections) == 0 {
+		return nil
+	}
+	for _, selection := range v.Selections {
+		if selection.Value == val {
+			return nil
+		}
+	}
+	return fmt.Errorf("value %s isn't one of the selections of the variable", val)
+}
+
 func (v *Variable) validateType(val string) error {
 	var err error
 	switch v.Type {
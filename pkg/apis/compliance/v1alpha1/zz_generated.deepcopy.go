@@ -26,6 +26,115 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AggregatorSettings) DeepCopyInto(out *AggregatorSettings) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AggregatorSettings.
+func (in *AggregatorSettings) DeepCopy() *AggregatorSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(AggregatorSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComplianceCheckAnswer) DeepCopyInto(out *ComplianceCheckAnswer) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceCheckAnswer.
+func (in *ComplianceCheckAnswer) DeepCopy() *ComplianceCheckAnswer {
+	if in == nil {
+		return nil
+	}
+	out := new(ComplianceCheckAnswer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ComplianceCheckAnswer) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComplianceCheckAnswerList) DeepCopyInto(out *ComplianceCheckAnswerList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ComplianceCheckAnswer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceCheckAnswerList.
+func (in *ComplianceCheckAnswerList) DeepCopy() *ComplianceCheckAnswerList {
+	if in == nil {
+		return nil
+	}
+	out := new(ComplianceCheckAnswerList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ComplianceCheckAnswerList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComplianceCheckAnswerSpec) DeepCopyInto(out *ComplianceCheckAnswerSpec) {
+	*out = *in
+	if in.Scans != nil {
+		in, out := &in.Scans, &out.Scans
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.ExpiresAt.DeepCopyInto(&out.ExpiresAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceCheckAnswerSpec.
+func (in *ComplianceCheckAnswerSpec) DeepCopy() *ComplianceCheckAnswerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ComplianceCheckAnswerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComplianceCheckNodeResult) DeepCopyInto(out *ComplianceCheckNodeResult) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceCheckNodeResult.
+func (in *ComplianceCheckNodeResult) DeepCopy() *ComplianceCheckNodeResult {
+	if in == nil {
+		return nil
+	}
+	out := new(ComplianceCheckNodeResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ComplianceCheckResult) DeepCopyInto(out *ComplianceCheckResult) {
 	*out = *in
@@ -41,6 +150,11 @@ func (in *ComplianceCheckResult) DeepCopyInto(out *ComplianceCheckResult) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.NodeResults != nil {
+		in, out := &in.NodeResults, &out.NodeResults
+		*out = make([]ComplianceCheckNodeResult, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceCheckResult.
@@ -94,26 +208,26 @@ func (in *ComplianceCheckResultList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ComplianceRemediation) DeepCopyInto(out *ComplianceRemediation) {
+func (in *ComplianceControlReport) DeepCopyInto(out *ComplianceControlReport) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceRemediation.
-func (in *ComplianceRemediation) DeepCopy() *ComplianceRemediation {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceControlReport.
+func (in *ComplianceControlReport) DeepCopy() *ComplianceControlReport {
 	if in == nil {
 		return nil
 	}
-	out := new(ComplianceRemediation)
+	out := new(ComplianceControlReport)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ComplianceRemediation) DeepCopyObject() runtime.Object {
+func (in *ComplianceControlReport) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -121,31 +235,31 @@ func (in *ComplianceRemediation) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ComplianceRemediationList) DeepCopyInto(out *ComplianceRemediationList) {
+func (in *ComplianceControlReportList) DeepCopyInto(out *ComplianceControlReportList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]ComplianceRemediation, len(*in))
+		*out = make([]ComplianceControlReport, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceRemediationList.
-func (in *ComplianceRemediationList) DeepCopy() *ComplianceRemediationList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceControlReportList.
+func (in *ComplianceControlReportList) DeepCopy() *ComplianceControlReportList {
 	if in == nil {
 		return nil
 	}
-	out := new(ComplianceRemediationList)
+	out := new(ComplianceControlReportList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ComplianceRemediationList) DeepCopyObject() runtime.Object {
+func (in *ComplianceControlReportList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -153,74 +267,52 @@ func (in *ComplianceRemediationList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ComplianceRemediationPayload) DeepCopyInto(out *ComplianceRemediationPayload) {
+func (in *ComplianceControlReportSpec) DeepCopyInto(out *ComplianceControlReportSpec) {
 	*out = *in
-	if in.Object != nil {
-		in, out := &in.Object, &out.Object
-		*out = (*in).DeepCopy()
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceRemediationPayload.
-func (in *ComplianceRemediationPayload) DeepCopy() *ComplianceRemediationPayload {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceControlReportSpec.
+func (in *ComplianceControlReportSpec) DeepCopy() *ComplianceControlReportSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ComplianceRemediationPayload)
+	out := new(ComplianceControlReportSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ComplianceRemediationSpec) DeepCopyInto(out *ComplianceRemediationSpec) {
+func (in *ComplianceControlReportStatus) DeepCopyInto(out *ComplianceControlReportStatus) {
 	*out = *in
-	out.ComplianceRemediationSpecMeta = in.ComplianceRemediationSpecMeta
-	in.Current.DeepCopyInto(&out.Current)
-	in.Outdated.DeepCopyInto(&out.Outdated)
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceRemediationSpec.
-func (in *ComplianceRemediationSpec) DeepCopy() *ComplianceRemediationSpec {
-	if in == nil {
-		return nil
+	out.Summary = in.Summary
+	if in.Controls != nil {
+		in, out := &in.Controls, &out.Controls
+		*out = make([]ControlResult, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	out := new(ComplianceRemediationSpec)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ComplianceRemediationSpecMeta) DeepCopyInto(out *ComplianceRemediationSpecMeta) {
-	*out = *in
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceRemediationSpecMeta.
-func (in *ComplianceRemediationSpecMeta) DeepCopy() *ComplianceRemediationSpecMeta {
-	if in == nil {
-		return nil
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	out := new(ComplianceRemediationSpecMeta)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ComplianceRemediationStatus) DeepCopyInto(out *ComplianceRemediationStatus) {
-	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceRemediationStatus.
-func (in *ComplianceRemediationStatus) DeepCopy() *ComplianceRemediationStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceControlReportStatus.
+func (in *ComplianceControlReportStatus) DeepCopy() *ComplianceControlReportStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ComplianceRemediationStatus)
+	out := new(ComplianceControlReportStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ComplianceScan) DeepCopyInto(out *ComplianceScan) {
+func (in *ComplianceException) DeepCopyInto(out *ComplianceException) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -228,18 +320,18 @@ func (in *ComplianceScan) DeepCopyInto(out *ComplianceScan) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceScan.
-func (in *ComplianceScan) DeepCopy() *ComplianceScan {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceException.
+func (in *ComplianceException) DeepCopy() *ComplianceException {
 	if in == nil {
 		return nil
 	}
-	out := new(ComplianceScan)
+	out := new(ComplianceException)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ComplianceScan) DeepCopyObject() runtime.Object {
+func (in *ComplianceException) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -247,31 +339,31 @@ func (in *ComplianceScan) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ComplianceScanList) DeepCopyInto(out *ComplianceScanList) {
+func (in *ComplianceExceptionList) DeepCopyInto(out *ComplianceExceptionList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]ComplianceScan, len(*in))
+		*out = make([]ComplianceException, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceScanList.
-func (in *ComplianceScanList) DeepCopy() *ComplianceScanList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceExceptionList.
+func (in *ComplianceExceptionList) DeepCopy() *ComplianceExceptionList {
 	if in == nil {
 		return nil
 	}
-	out := new(ComplianceScanList)
+	out := new(ComplianceExceptionList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ComplianceScanList) DeepCopyObject() runtime.Object {
+func (in *ComplianceExceptionList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -279,133 +371,1198 @@ func (in *ComplianceScanList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ComplianceScanSettings) DeepCopyInto(out *ComplianceScanSettings) {
+func (in *ComplianceExceptionSpec) DeepCopyInto(out *ComplianceExceptionSpec) {
 	*out = *in
-	in.RawResultStorage.DeepCopyInto(&out.RawResultStorage)
-	if in.ScanTolerations != nil {
-		in, out := &in.ScanTolerations, &out.ScanTolerations
-		*out = make([]v1.Toleration, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.StrictNodeScan != nil {
-		in, out := &in.StrictNodeScan, &out.StrictNodeScan
-		*out = new(bool)
-		**out = **in
-	}
-	if in.ScanLimits != nil {
-		in, out := &in.ScanLimits, &out.ScanLimits
-		*out = make(map[v1.ResourceName]resource.Quantity, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val.DeepCopy()
-		}
-	}
+	in.Scope.DeepCopyInto(&out.Scope)
+	in.ExpiresAt.DeepCopyInto(&out.ExpiresAt)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceScanSettings.
-func (in *ComplianceScanSettings) DeepCopy() *ComplianceScanSettings {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceExceptionSpec.
+func (in *ComplianceExceptionSpec) DeepCopy() *ComplianceExceptionSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ComplianceScanSettings)
+	out := new(ComplianceExceptionSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ComplianceScanSpec) DeepCopyInto(out *ComplianceScanSpec) {
+func (in *ComplianceExceptionStatus) DeepCopyInto(out *ComplianceExceptionStatus) {
 	*out = *in
-	if in.NodeSelector != nil {
-		in, out := &in.NodeSelector, &out.NodeSelector
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.TailoringConfigMap != nil {
-		in, out := &in.TailoringConfigMap, &out.TailoringConfigMap
-		*out = new(TailoringConfigMapRef)
-		**out = **in
+	if in.ExceptedChecks != nil {
+		in, out := &in.ExceptedChecks, &out.ExceptedChecks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	in.ComplianceScanSettings.DeepCopyInto(&out.ComplianceScanSettings)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceScanSpec.
-func (in *ComplianceScanSpec) DeepCopy() *ComplianceScanSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceExceptionStatus.
+func (in *ComplianceExceptionStatus) DeepCopy() *ComplianceExceptionStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ComplianceScanSpec)
+	out := new(ComplianceExceptionStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ComplianceScanSpecWrapper) DeepCopyInto(out *ComplianceScanSpecWrapper) {
+func (in *ComplianceRemediation) DeepCopyInto(out *ComplianceRemediation) {
 	*out = *in
-	in.ComplianceScanSpec.DeepCopyInto(&out.ComplianceScanSpec)
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceScanSpecWrapper.
-func (in *ComplianceScanSpecWrapper) DeepCopy() *ComplianceScanSpecWrapper {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceRemediation.
+func (in *ComplianceRemediation) DeepCopy() *ComplianceRemediation {
 	if in == nil {
 		return nil
 	}
-	out := new(ComplianceScanSpecWrapper)
+	out := new(ComplianceRemediation)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ComplianceRemediation) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ComplianceScanStatus) DeepCopyInto(out *ComplianceScanStatus) {
+func (in *ComplianceRemediationGroup) DeepCopyInto(out *ComplianceRemediationGroup) {
 	*out = *in
-	out.ResultsStorage = in.ResultsStorage
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make(Conditions, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.StartTimestamp != nil {
-		in, out := &in.StartTimestamp, &out.StartTimestamp
-		*out = (*in).DeepCopy()
-	}
-	if in.EndTimestamp != nil {
-		in, out := &in.EndTimestamp, &out.EndTimestamp
-		*out = (*in).DeepCopy()
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceScanStatus.
-func (in *ComplianceScanStatus) DeepCopy() *ComplianceScanStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceRemediationGroup.
+func (in *ComplianceRemediationGroup) DeepCopy() *ComplianceRemediationGroup {
 	if in == nil {
 		return nil
 	}
-	out := new(ComplianceScanStatus)
+	out := new(ComplianceRemediationGroup)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ComplianceScanStatusWrapper) DeepCopyInto(out *ComplianceScanStatusWrapper) {
-	*out = *in
-	in.ComplianceScanStatus.DeepCopyInto(&out.ComplianceScanStatus)
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ComplianceRemediationGroup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComplianceRemediationGroupList) DeepCopyInto(out *ComplianceRemediationGroupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ComplianceRemediationGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceRemediationGroupList.
+func (in *ComplianceRemediationGroupList) DeepCopy() *ComplianceRemediationGroupList {
+	if in == nil {
+		return nil
+	}
+	out := new(ComplianceRemediationGroupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ComplianceRemediationGroupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComplianceRemediationGroupSpec) DeepCopyInto(out *ComplianceRemediationGroupSpec) {
+	*out = *in
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]RemediationGroupMember, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceRemediationGroupSpec.
+func (in *ComplianceRemediationGroupSpec) DeepCopy() *ComplianceRemediationGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ComplianceRemediationGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComplianceRemediationGroupStatus) DeepCopyInto(out *ComplianceRemediationGroupStatus) {
+	*out = *in
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]RemediationGroupMemberStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceRemediationGroupStatus.
+func (in *ComplianceRemediationGroupStatus) DeepCopy() *ComplianceRemediationGroupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ComplianceRemediationGroupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComplianceRemediationList) DeepCopyInto(out *ComplianceRemediationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ComplianceRemediation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceRemediationList.
+func (in *ComplianceRemediationList) DeepCopy() *ComplianceRemediationList {
+	if in == nil {
+		return nil
+	}
+	out := new(ComplianceRemediationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ComplianceRemediationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComplianceRemediationPayload) DeepCopyInto(out *ComplianceRemediationPayload) {
+	*out = *in
+	if in.Object != nil {
+		in, out := &in.Object, &out.Object
+		*out = (*in).DeepCopy()
+	}
+	if in.JSONPatch != nil {
+		in, out := &in.JSONPatch, &out.JSONPatch
+		*out = make([]JSONPatchOperation, len(*in))
+		copy(*out, *in)
+	}
+	if in.Template != nil {
+		in, out := &in.Template, &out.Template
+		*out = new(RemediationTemplate)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceRemediationPayload.
+func (in *ComplianceRemediationPayload) DeepCopy() *ComplianceRemediationPayload {
+	if in == nil {
+		return nil
+	}
+	out := new(ComplianceRemediationPayload)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComplianceRemediationSpec) DeepCopyInto(out *ComplianceRemediationSpec) {
+	*out = *in
+	out.ComplianceRemediationSpecMeta = in.ComplianceRemediationSpecMeta
+	in.Current.DeepCopyInto(&out.Current)
+	in.Outdated.DeepCopyInto(&out.Outdated)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceRemediationSpec.
+func (in *ComplianceRemediationSpec) DeepCopy() *ComplianceRemediationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ComplianceRemediationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComplianceRemediationSpecMeta) DeepCopyInto(out *ComplianceRemediationSpecMeta) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceRemediationSpecMeta.
+func (in *ComplianceRemediationSpecMeta) DeepCopy() *ComplianceRemediationSpecMeta {
+	if in == nil {
+		return nil
+	}
+	out := new(ComplianceRemediationSpecMeta)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComplianceRemediationStatus) DeepCopyInto(out *ComplianceRemediationStatus) {
+	*out = *in
+	if in.Preview != nil {
+		in, out := &in.Preview, &out.Preview
+		*out = new(RemediationPreview)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = new(RemediationDrift)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PreImage != nil {
+		in, out := &in.PreImage, &out.PreImage
+		*out = make([]RemediationFieldValue, len(*in))
+		copy(*out, *in)
+	}
+	if in.Enforcement != nil {
+		in, out := &in.Enforcement, &out.Enforcement
+		*out = new(RemediationEnforcementStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceRemediationStatus.
+func (in *ComplianceRemediationStatus) DeepCopy() *ComplianceRemediationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ComplianceRemediationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComplianceScan) DeepCopyInto(out *ComplianceScan) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceScan.
+func (in *ComplianceScan) DeepCopy() *ComplianceScan {
+	if in == nil {
+		return nil
+	}
+	out := new(ComplianceScan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ComplianceScan) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComplianceScanList) DeepCopyInto(out *ComplianceScanList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ComplianceScan, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceScanList.
+func (in *ComplianceScanList) DeepCopy() *ComplianceScanList {
+	if in == nil {
+		return nil
+	}
+	out := new(ComplianceScanList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ComplianceScanList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComplianceScanSettings) DeepCopyInto(out *ComplianceScanSettings) {
+	*out = *in
+	in.RawResultStorage.DeepCopyInto(&out.RawResultStorage)
+	if in.ScanTolerations != nil {
+		in, out := &in.ScanTolerations, &out.ScanTolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.StrictNodeScan != nil {
+		in, out := &in.StrictNodeScan, &out.StrictNodeScan
+		*out = new(bool)
+		**out = **in
+	}
+	out.Aggregator = in.Aggregator
+	if in.ExportFormats != nil {
+		in, out := &in.ExportFormats, &out.ExportFormats
+		*out = make([]ExportFormat, len(*in))
+		copy(*out, *in)
+	}
+	if in.ScanLimits != nil {
+		in, out := &in.ScanLimits, &out.ScanLimits
+		*out = make(map[v1.ResourceName]resource.Quantity, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceScanSettings.
+func (in *ComplianceScanSettings) DeepCopy() *ComplianceScanSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(ComplianceScanSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComplianceScanSpec) DeepCopyInto(out *ComplianceScanSpec) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TailoringConfigMap != nil {
+		in, out := &in.TailoringConfigMap, &out.TailoringConfigMap
+		*out = new(TailoringConfigMapRef)
+		**out = **in
+	}
+	in.ComplianceScanSettings.DeepCopyInto(&out.ComplianceScanSettings)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceScanSpec.
+func (in *ComplianceScanSpec) DeepCopy() *ComplianceScanSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ComplianceScanSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComplianceScanSpecWrapper) DeepCopyInto(out *ComplianceScanSpecWrapper) {
+	*out = *in
+	in.ComplianceScanSpec.DeepCopyInto(&out.ComplianceScanSpec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceScanSpecWrapper.
+func (in *ComplianceScanSpecWrapper) DeepCopy() *ComplianceScanSpecWrapper {
+	if in == nil {
+		return nil
+	}
+	out := new(ComplianceScanSpecWrapper)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComplianceScanStatus) DeepCopyInto(out *ComplianceScanStatus) {
+	*out = *in
+	out.ResultsStorage = in.ResultsStorage
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.StartTimestamp != nil {
+		in, out := &in.StartTimestamp, &out.StartTimestamp
+		*out = (*in).DeepCopy()
+	}
+	if in.EndTimestamp != nil {
+		in, out := &in.EndTimestamp, &out.EndTimestamp
+		*out = (*in).DeepCopy()
+	}
+	if in.HTMLReportStored != nil {
+		in, out := &in.HTMLReportStored, &out.HTMLReportStored
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceScanStatus.
+func (in *ComplianceScanStatus) DeepCopy() *ComplianceScanStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ComplianceScanStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComplianceScanStatusWrapper) DeepCopyInto(out *ComplianceScanStatusWrapper) {
+	*out = *in
+	in.ComplianceScanStatus.DeepCopyInto(&out.ComplianceScanStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceScanStatusWrapper.
+func (in *ComplianceScanStatusWrapper) DeepCopy() *ComplianceScanStatusWrapper {
+	if in == nil {
+		return nil
+	}
+	out := new(ComplianceScanStatusWrapper)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComplianceSuite) DeepCopyInto(out *ComplianceSuite) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceSuite.
+func (in *ComplianceSuite) DeepCopy() *ComplianceSuite {
+	if in == nil {
+		return nil
+	}
+	out := new(ComplianceSuite)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ComplianceSuite) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComplianceSuiteList) DeepCopyInto(out *ComplianceSuiteList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ComplianceSuite, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceSuiteList.
+func (in *ComplianceSuiteList) DeepCopy() *ComplianceSuiteList {
+	if in == nil {
+		return nil
+	}
+	out := new(ComplianceSuiteList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ComplianceSuiteList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComplianceSuiteSettings) DeepCopyInto(out *ComplianceSuiteSettings) {
+	*out = *in
+	if in.RemediationRollout != nil {
+		in, out := &in.RemediationRollout, &out.RemediationRollout
+		*out = new(RemediationRolloutSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RemediationRollback != nil {
+		in, out := &in.RemediationRollback, &out.RemediationRollback
+		*out = new(RemediationRollbackSettings)
+		**out = **in
+	}
+	if in.AutoApplyPolicy != nil {
+		in, out := &in.AutoApplyPolicy, &out.AutoApplyPolicy
+		*out = new(RemediationAutoApplyPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceSuiteSettings.
+func (in *ComplianceSuiteSettings) DeepCopy() *ComplianceSuiteSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(ComplianceSuiteSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComplianceSuiteSpec) DeepCopyInto(out *ComplianceSuiteSpec) {
+	*out = *in
+	in.ComplianceSuiteSettings.DeepCopyInto(&out.ComplianceSuiteSettings)
+	if in.Scans != nil {
+		in, out := &in.Scans, &out.Scans
+		*out = make([]ComplianceScanSpecWrapper, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceSuiteSpec.
+func (in *ComplianceSuiteSpec) DeepCopy() *ComplianceSuiteSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ComplianceSuiteSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComplianceSuiteStatus) DeepCopyInto(out *ComplianceSuiteStatus) {
+	*out = *in
+	if in.ScanStatuses != nil {
+		in, out := &in.ScanStatuses, &out.ScanStatuses
+		*out = make([]ComplianceScanStatusWrapper, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RemediationRollout != nil {
+		in, out := &in.RemediationRollout, &out.RemediationRollout
+		*out = new(RemediationRolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RemediationBatch != nil {
+		in, out := &in.RemediationBatch, &out.RemediationBatch
+		*out = new(RemediationBatchStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceSuiteStatus.
+func (in *ComplianceSuiteStatus) DeepCopy() *ComplianceSuiteStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ComplianceSuiteStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Condition.
+func (in *Condition) DeepCopy() *Condition {
+	if in == nil {
+		return nil
+	}
+	out := new(Condition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in Conditions) DeepCopyInto(out *Conditions) {
+	{
+		in := &in
+		*out = make(Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Conditions.
+func (in Conditions) DeepCopy() Conditions {
+	if in == nil {
+		return nil
+	}
+	out := new(Conditions)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControlCheckReference) DeepCopyInto(out *ControlCheckReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlCheckReference.
+func (in *ControlCheckReference) DeepCopy() *ControlCheckReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ControlCheckReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControlResult) DeepCopyInto(out *ControlResult) {
+	*out = *in
+	if in.Checks != nil {
+		in, out := &in.Checks, &out.Checks
+		*out = make([]ControlCheckReference, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlResult.
+func (in *ControlResult) DeepCopy() *ControlResult {
+	if in == nil {
+		return nil
+	}
+	out := new(ControlResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControlSummary) DeepCopyInto(out *ControlSummary) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlSummary.
+func (in *ControlSummary) DeepCopy() *ControlSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(ControlSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CustomRule) DeepCopyInto(out *CustomRule) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomRule.
+func (in *CustomRule) DeepCopy() *CustomRule {
+	if in == nil {
+		return nil
+	}
+	out := new(CustomRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CustomRule) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CustomRuleInput) DeepCopyInto(out *CustomRuleInput) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomRuleInput.
+func (in *CustomRuleInput) DeepCopy() *CustomRuleInput {
+	if in == nil {
+		return nil
+	}
+	out := new(CustomRuleInput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CustomRuleList) DeepCopyInto(out *CustomRuleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CustomRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomRuleList.
+func (in *CustomRuleList) DeepCopy() *CustomRuleList {
+	if in == nil {
+		return nil
+	}
+	out := new(CustomRuleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CustomRuleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CustomRuleProbe) DeepCopyInto(out *CustomRuleProbe) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomRuleProbe.
+func (in *CustomRuleProbe) DeepCopy() *CustomRuleProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(CustomRuleProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CustomRuleSpec) DeepCopyInto(out *CustomRuleSpec) {
+	*out = *in
+	if in.Inputs != nil {
+		in, out := &in.Inputs, &out.Inputs
+		*out = make([]CustomRuleInput, len(*in))
+		copy(*out, *in)
+	}
+	if in.Probe != nil {
+		in, out := &in.Probe, &out.Probe
+		*out = new(CustomRuleProbe)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomRuleSpec.
+func (in *CustomRuleSpec) DeepCopy() *CustomRuleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CustomRuleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CustomRuleStatus) DeepCopyInto(out *CustomRuleStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomRuleStatus.
+func (in *CustomRuleStatus) DeepCopy() *CustomRuleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CustomRuleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExceptionScope) DeepCopyInto(out *ExceptionScope) {
+	*out = *in
+	if in.NodeRoles != nil {
+		in, out := &in.NodeRoles, &out.NodeRoles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExceptionScope.
+func (in *ExceptionScope) DeepCopy() *ExceptionScope {
+	if in == nil {
+		return nil
+	}
+	out := new(ExceptionScope)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FixDefinition) DeepCopyInto(out *FixDefinition) {
+	*out = *in
+	if in.FixObject != nil {
+		in, out := &in.FixObject, &out.FixObject
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FixDefinition.
+func (in *FixDefinition) DeepCopy() *FixDefinition {
+	if in == nil {
+		return nil
+	}
+	out := new(FixDefinition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JSONPatchOperation) DeepCopyInto(out *JSONPatchOperation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JSONPatchOperation.
+func (in *JSONPatchOperation) DeepCopy() *JSONPatchOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(JSONPatchOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeletConfigSnapshot) DeepCopyInto(out *KubeletConfigSnapshot) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeletConfigSnapshot.
+func (in *KubeletConfigSnapshot) DeepCopy() *KubeletConfigSnapshot {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeletConfigSnapshot)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamedObjectReference) DeepCopyInto(out *NamedObjectReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamedObjectReference.
+func (in *NamedObjectReference) DeepCopy() *NamedObjectReference {
+	if in == nil {
+		return nil
+	}
+	out := new(NamedObjectReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OutputRef) DeepCopyInto(out *OutputRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutputRef.
+func (in *OutputRef) DeepCopy() *OutputRef {
+	if in == nil {
+		return nil
+	}
+	out := new(OutputRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Profile) DeepCopyInto(out *Profile) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.ProfilePayload.DeepCopyInto(&out.ProfilePayload)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Profile.
+func (in *Profile) DeepCopy() *Profile {
+	if in == nil {
+		return nil
+	}
+	out := new(Profile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Profile) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProfileBundle) DeepCopyInto(out *ProfileBundle) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceScanStatusWrapper.
-func (in *ComplianceScanStatusWrapper) DeepCopy() *ComplianceScanStatusWrapper {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProfileBundle.
+func (in *ProfileBundle) DeepCopy() *ProfileBundle {
 	if in == nil {
 		return nil
 	}
-	out := new(ComplianceScanStatusWrapper)
+	out := new(ProfileBundle)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProfileBundle) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ComplianceSuite) DeepCopyInto(out *ComplianceSuite) {
+func (in *ProfileBundleList) DeepCopyInto(out *ProfileBundleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ProfileBundle, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProfileBundleList.
+func (in *ProfileBundleList) DeepCopy() *ProfileBundleList {
+	if in == nil {
+		return nil
+	}
+	out := new(ProfileBundleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProfileBundleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProfileBundleSpec) DeepCopyInto(out *ProfileBundleSpec) {
+	*out = *in
+	if in.StandardsConfigMap != nil {
+		in, out := &in.StandardsConfigMap, &out.StandardsConfigMap
+		*out = new(StandardsConfigMapRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProfileBundleSpec.
+func (in *ProfileBundleSpec) DeepCopy() *ProfileBundleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProfileBundleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProfileBundleStatus) DeepCopyInto(out *ProfileBundleStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProfileBundleStatus.
+func (in *ProfileBundleStatus) DeepCopy() *ProfileBundleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ProfileBundleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProfileComposition) DeepCopyInto(out *ProfileComposition) {
+	*out = *in
+	if in.Profiles != nil {
+		in, out := &in.Profiles, &out.Profiles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProfileComposition.
+func (in *ProfileComposition) DeepCopy() *ProfileComposition {
+	if in == nil {
+		return nil
+	}
+	out := new(ProfileComposition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProfileList) DeepCopyInto(out *ProfileList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Profile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProfileList.
+func (in *ProfileList) DeepCopy() *ProfileList {
+	if in == nil {
+		return nil
+	}
+	out := new(ProfileList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProfileList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProfilePayload) DeepCopyInto(out *ProfilePayload) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]ProfileRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make([]ProfileValue, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProfilePayload.
+func (in *ProfilePayload) DeepCopy() *ProfilePayload {
+	if in == nil {
+		return nil
+	}
+	out := new(ProfilePayload)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RawResultStorageSettings) DeepCopyInto(out *RawResultStorageSettings) {
+	*out = *in
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.PVAccessModes != nil {
+		in, out := &in.PVAccessModes, &out.PVAccessModes
+		*out = make([]v1.PersistentVolumeAccessMode, len(*in))
+		copy(*out, *in)
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RawResultStorageSettings.
+func (in *RawResultStorageSettings) DeepCopy() *RawResultStorageSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(RawResultStorageSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemediationApproval) DeepCopyInto(out *RemediationApproval) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -413,18 +1570,18 @@ func (in *ComplianceSuite) DeepCopyInto(out *ComplianceSuite) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceSuite.
-func (in *ComplianceSuite) DeepCopy() *ComplianceSuite {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationApproval.
+func (in *RemediationApproval) DeepCopy() *RemediationApproval {
 	if in == nil {
 		return nil
 	}
-	out := new(ComplianceSuite)
+	out := new(RemediationApproval)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ComplianceSuite) DeepCopyObject() runtime.Object {
+func (in *RemediationApproval) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -432,31 +1589,31 @@ func (in *ComplianceSuite) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ComplianceSuiteList) DeepCopyInto(out *ComplianceSuiteList) {
+func (in *RemediationApprovalList) DeepCopyInto(out *RemediationApprovalList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]ComplianceSuite, len(*in))
+		*out = make([]RemediationApproval, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceSuiteList.
-func (in *ComplianceSuiteList) DeepCopy() *ComplianceSuiteList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationApprovalList.
+func (in *RemediationApprovalList) DeepCopy() *RemediationApprovalList {
 	if in == nil {
 		return nil
 	}
-	out := new(ComplianceSuiteList)
+	out := new(RemediationApprovalList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ComplianceSuiteList) DeepCopyObject() runtime.Object {
+func (in *RemediationApprovalList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -464,382 +1621,380 @@ func (in *ComplianceSuiteList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ComplianceSuiteSettings) DeepCopyInto(out *ComplianceSuiteSettings) {
+func (in *RemediationApprovalSpec) DeepCopyInto(out *RemediationApprovalSpec) {
 	*out = *in
+	if in.Decisions != nil {
+		in, out := &in.Decisions, &out.Decisions
+		*out = make([]RemediationDecision, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceSuiteSettings.
-func (in *ComplianceSuiteSettings) DeepCopy() *ComplianceSuiteSettings {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationApprovalSpec.
+func (in *RemediationApprovalSpec) DeepCopy() *RemediationApprovalSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ComplianceSuiteSettings)
+	out := new(RemediationApprovalSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ComplianceSuiteSpec) DeepCopyInto(out *ComplianceSuiteSpec) {
+func (in *RemediationApprovalStatus) DeepCopyInto(out *RemediationApprovalStatus) {
 	*out = *in
-	out.ComplianceSuiteSettings = in.ComplianceSuiteSettings
-	if in.Scans != nil {
-		in, out := &in.Scans, &out.Scans
-		*out = make([]ComplianceScanSpecWrapper, len(*in))
+	if in.Remediations != nil {
+		in, out := &in.Remediations, &out.Remediations
+		*out = make([]RemediationReview, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceSuiteSpec.
-func (in *ComplianceSuiteSpec) DeepCopy() *ComplianceSuiteSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationApprovalStatus.
+func (in *RemediationApprovalStatus) DeepCopy() *RemediationApprovalStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RemediationApprovalStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemediationAutoApplyPolicy) DeepCopyInto(out *RemediationAutoApplyPolicy) {
+	*out = *in
+	if in.SeverityOverrides != nil {
+		in, out := &in.SeverityOverrides, &out.SeverityOverrides
+		*out = make([]SeverityDisruptionOverride, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationAutoApplyPolicy.
+func (in *RemediationAutoApplyPolicy) DeepCopy() *RemediationAutoApplyPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RemediationAutoApplyPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemediationBatchStatus) DeepCopyInto(out *RemediationBatchStatus) {
+	*out = *in
+	if in.Remediations != nil {
+		in, out := &in.Remediations, &out.Remediations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Pools != nil {
+		in, out := &in.Pools, &out.Pools
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.BaselineFailures != nil {
+		in, out := &in.BaselineFailures, &out.BaselineFailures
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.KubeletConfigs != nil {
+		in, out := &in.KubeletConfigs, &out.KubeletConfigs
+		*out = make([]KubeletConfigSnapshot, len(*in))
+		copy(*out, *in)
+	}
+	if in.RolledOutAt != nil {
+		in, out := &in.RolledOutAt, &out.RolledOutAt
+		*out = (*in).DeepCopy()
+	}
+	if in.RescannedAt != nil {
+		in, out := &in.RescannedAt, &out.RescannedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationBatchStatus.
+func (in *RemediationBatchStatus) DeepCopy() *RemediationBatchStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ComplianceSuiteSpec)
+	out := new(RemediationBatchStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ComplianceSuiteStatus) DeepCopyInto(out *ComplianceSuiteStatus) {
+func (in *RemediationDecision) DeepCopyInto(out *RemediationDecision) {
 	*out = *in
-	if in.ScanStatuses != nil {
-		in, out := &in.ScanStatuses, &out.ScanStatuses
-		*out = make([]ComplianceScanStatusWrapper, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make(Conditions, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceSuiteStatus.
-func (in *ComplianceSuiteStatus) DeepCopy() *ComplianceSuiteStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationDecision.
+func (in *RemediationDecision) DeepCopy() *RemediationDecision {
 	if in == nil {
 		return nil
 	}
-	out := new(ComplianceSuiteStatus)
+	out := new(RemediationDecision)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Condition.
-func (in *Condition) DeepCopy() *Condition {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemediationDrift) DeepCopyInto(out *RemediationDrift) {
+	*out = *in
+	if in.Changes != nil {
+		in, out := &in.Changes, &out.Changes
+		*out = make([]RemediationFieldChange, len(*in))
+		copy(*out, *in)
+	}
+	in.DetectedAt.DeepCopyInto(&out.DetectedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationDrift.
+func (in *RemediationDrift) DeepCopy() *RemediationDrift {
 	if in == nil {
 		return nil
 	}
-	out := new(Condition)
+	out := new(RemediationDrift)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in Conditions) DeepCopyInto(out *Conditions) {
-	{
-		in := &in
-		*out = make(Conditions, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+func (in *RemediationEnforcementStatus) DeepCopyInto(out *RemediationEnforcementStatus) {
+	*out = *in
+	if in.Violations != nil {
+		in, out := &in.Violations, &out.Violations
+		*out = new(int64)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Conditions.
-func (in Conditions) DeepCopy() Conditions {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationEnforcementStatus.
+func (in *RemediationEnforcementStatus) DeepCopy() *RemediationEnforcementStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(Conditions)
+	out := new(RemediationEnforcementStatus)
 	in.DeepCopyInto(out)
-	return *out
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FixDefinition) DeepCopyInto(out *FixDefinition) {
+func (in *RemediationFieldChange) DeepCopyInto(out *RemediationFieldChange) {
 	*out = *in
-	if in.FixObject != nil {
-		in, out := &in.FixObject, &out.FixObject
-		*out = (*in).DeepCopy()
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FixDefinition.
-func (in *FixDefinition) DeepCopy() *FixDefinition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationFieldChange.
+func (in *RemediationFieldChange) DeepCopy() *RemediationFieldChange {
 	if in == nil {
 		return nil
 	}
-	out := new(FixDefinition)
+	out := new(RemediationFieldChange)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NamedObjectReference) DeepCopyInto(out *NamedObjectReference) {
+func (in *RemediationFieldConflict) DeepCopyInto(out *RemediationFieldConflict) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamedObjectReference.
-func (in *NamedObjectReference) DeepCopy() *NamedObjectReference {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationFieldConflict.
+func (in *RemediationFieldConflict) DeepCopy() *RemediationFieldConflict {
 	if in == nil {
 		return nil
 	}
-	out := new(NamedObjectReference)
+	out := new(RemediationFieldConflict)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OutputRef) DeepCopyInto(out *OutputRef) {
+func (in *RemediationFieldValue) DeepCopyInto(out *RemediationFieldValue) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutputRef.
-func (in *OutputRef) DeepCopy() *OutputRef {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationFieldValue.
+func (in *RemediationFieldValue) DeepCopy() *RemediationFieldValue {
 	if in == nil {
 		return nil
 	}
-	out := new(OutputRef)
+	out := new(RemediationFieldValue)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Profile) DeepCopyInto(out *Profile) {
+func (in *RemediationGroupMember) DeepCopyInto(out *RemediationGroupMember) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.ProfilePayload.DeepCopyInto(&out.ProfilePayload)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Profile.
-func (in *Profile) DeepCopy() *Profile {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationGroupMember.
+func (in *RemediationGroupMember) DeepCopy() *RemediationGroupMember {
 	if in == nil {
 		return nil
 	}
-	out := new(Profile)
+	out := new(RemediationGroupMember)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Profile) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProfileBundle) DeepCopyInto(out *ProfileBundle) {
+func (in *RemediationGroupMemberStatus) DeepCopyInto(out *RemediationGroupMemberStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProfileBundle.
-func (in *ProfileBundle) DeepCopy() *ProfileBundle {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationGroupMemberStatus.
+func (in *RemediationGroupMemberStatus) DeepCopy() *RemediationGroupMemberStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ProfileBundle)
+	out := new(RemediationGroupMemberStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ProfileBundle) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProfileBundleList) DeepCopyInto(out *ProfileBundleList) {
+func (in *RemediationObjectDependencyReference) DeepCopyInto(out *RemediationObjectDependencyReference) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]ProfileBundle, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProfileBundleList.
-func (in *ProfileBundleList) DeepCopy() *ProfileBundleList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationObjectDependencyReference.
+func (in *RemediationObjectDependencyReference) DeepCopy() *RemediationObjectDependencyReference {
 	if in == nil {
 		return nil
 	}
-	out := new(ProfileBundleList)
+	out := new(RemediationObjectDependencyReference)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ProfileBundleList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProfileBundleSpec) DeepCopyInto(out *ProfileBundleSpec) {
+func (in *RemediationPreview) DeepCopyInto(out *RemediationPreview) {
 	*out = *in
+	if in.Changes != nil {
+		in, out := &in.Changes, &out.Changes
+		*out = make([]RemediationFieldChange, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conflicts != nil {
+		in, out := &in.Conflicts, &out.Conflicts
+		*out = make([]RemediationFieldConflict, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProfileBundleSpec.
-func (in *ProfileBundleSpec) DeepCopy() *ProfileBundleSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationPreview.
+func (in *RemediationPreview) DeepCopy() *RemediationPreview {
 	if in == nil {
 		return nil
 	}
-	out := new(ProfileBundleSpec)
+	out := new(RemediationPreview)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProfileBundleStatus) DeepCopyInto(out *ProfileBundleStatus) {
+func (in *RemediationReview) DeepCopyInto(out *RemediationReview) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make(Conditions, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Changes != nil {
+		in, out := &in.Changes, &out.Changes
+		*out = make([]RemediationFieldChange, len(*in))
+		copy(*out, *in)
+	}
+	if in.DecidedAt != nil {
+		in, out := &in.DecidedAt, &out.DecidedAt
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProfileBundleStatus.
-func (in *ProfileBundleStatus) DeepCopy() *ProfileBundleStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationReview.
+func (in *RemediationReview) DeepCopy() *RemediationReview {
 	if in == nil {
 		return nil
 	}
-	out := new(ProfileBundleStatus)
+	out := new(RemediationReview)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProfileList) DeepCopyInto(out *ProfileList) {
+func (in *RemediationRollbackSettings) DeepCopyInto(out *RemediationRollbackSettings) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]Profile, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	out.Window = in.Window
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProfileList.
-func (in *ProfileList) DeepCopy() *ProfileList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationRollbackSettings.
+func (in *RemediationRollbackSettings) DeepCopy() *RemediationRollbackSettings {
 	if in == nil {
 		return nil
 	}
-	out := new(ProfileList)
+	out := new(RemediationRollbackSettings)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ProfileList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProfilePayload) DeepCopyInto(out *ProfilePayload) {
+func (in *RemediationRolloutSettings) DeepCopyInto(out *RemediationRolloutSettings) {
 	*out = *in
-	if in.Rules != nil {
-		in, out := &in.Rules, &out.Rules
-		*out = make([]ProfileRule, len(*in))
-		copy(*out, *in)
-	}
-	if in.Values != nil {
-		in, out := &in.Values, &out.Values
-		*out = make([]ProfileValue, len(*in))
+	if in.CanaryPools != nil {
+		in, out := &in.CanaryPools, &out.CanaryPools
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProfilePayload.
-func (in *ProfilePayload) DeepCopy() *ProfilePayload {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationRolloutSettings.
+func (in *RemediationRolloutSettings) DeepCopy() *RemediationRolloutSettings {
 	if in == nil {
 		return nil
 	}
-	out := new(ProfilePayload)
+	out := new(RemediationRolloutSettings)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RawResultStorageSettings) DeepCopyInto(out *RawResultStorageSettings) {
+func (in *RemediationRolloutStatus) DeepCopyInto(out *RemediationRolloutStatus) {
 	*out = *in
-	if in.StorageClassName != nil {
-		in, out := &in.StorageClassName, &out.StorageClassName
-		*out = new(string)
-		**out = **in
-	}
-	if in.PVAccessModes != nil {
-		in, out := &in.PVAccessModes, &out.PVAccessModes
-		*out = make([]v1.PersistentVolumeAccessMode, len(*in))
+	if in.CanaryScans != nil {
+		in, out := &in.CanaryScans, &out.CanaryScans
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.NodeSelector != nil {
-		in, out := &in.NodeSelector, &out.NodeSelector
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Tolerations != nil {
-		in, out := &in.Tolerations, &out.Tolerations
-		*out = make([]v1.Toleration, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RawResultStorageSettings.
-func (in *RawResultStorageSettings) DeepCopy() *RawResultStorageSettings {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationRolloutStatus.
+func (in *RemediationRolloutStatus) DeepCopy() *RemediationRolloutStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(RawResultStorageSettings)
+	out := new(RemediationRolloutStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RemediationObjectDependencyReference) DeepCopyInto(out *RemediationObjectDependencyReference) {
+func (in *RemediationTemplate) DeepCopyInto(out *RemediationTemplate) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationObjectDependencyReference.
-func (in *RemediationObjectDependencyReference) DeepCopy() *RemediationObjectDependencyReference {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationTemplate.
+func (in *RemediationTemplate) DeepCopy() *RemediationTemplate {
 	if in == nil {
 		return nil
 	}
-	out := new(RemediationObjectDependencyReference)
+	out := new(RemediationTemplate)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -944,7 +2099,7 @@ func (in *ScanSetting) DeepCopyInto(out *ScanSetting) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.ComplianceSuiteSettings = in.ComplianceSuiteSettings
+	in.ComplianceSuiteSettings.DeepCopyInto(&out.ComplianceSuiteSettings)
 	in.ComplianceScanSettings.DeepCopyInto(&out.ComplianceScanSettings)
 	if in.Roles != nil {
 		in, out := &in.Roles, &out.Roles
@@ -1114,6 +2269,36 @@ func (in *ScanSettingList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SeverityDisruptionOverride) DeepCopyInto(out *SeverityDisruptionOverride) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeverityDisruptionOverride.
+func (in *SeverityDisruptionOverride) DeepCopy() *SeverityDisruptionOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(SeverityDisruptionOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StandardsConfigMapRef) DeepCopyInto(out *StandardsConfigMapRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StandardsConfigMapRef.
+func (in *StandardsConfigMapRef) DeepCopy() *StandardsConfigMapRef {
+	if in == nil {
+		return nil
+	}
+	out := new(StandardsConfigMapRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageReference) DeepCopyInto(out *StorageReference) {
 	*out = *in
@@ -1135,7 +2320,7 @@ func (in *TailoredProfile) DeepCopyInto(out *TailoredProfile) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TailoredProfile.
@@ -1191,6 +2376,11 @@ func (in *TailoredProfileList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TailoredProfileSpec) DeepCopyInto(out *TailoredProfileSpec) {
 	*out = *in
+	if in.Compose != nil {
+		in, out := &in.Compose, &out.Compose
+		*out = new(ProfileComposition)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.EnableRules != nil {
 		in, out := &in.EnableRules, &out.EnableRules
 		*out = make([]RuleReferenceSpec, len(*in))
@@ -1211,6 +2401,11 @@ func (in *TailoredProfileSpec) DeepCopyInto(out *TailoredProfileSpec) {
 		*out = make([]VariableValueSpec, len(*in))
 		copy(*out, *in)
 	}
+	if in.CustomRules != nil {
+		in, out := &in.CustomRules, &out.CustomRules
+		*out = make([]RuleReferenceSpec, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TailoredProfileSpec.
@@ -1227,6 +2422,11 @@ func (in *TailoredProfileSpec) DeepCopy() *TailoredProfileSpec {
 func (in *TailoredProfileStatus) DeepCopyInto(out *TailoredProfileStatus) {
 	*out = *in
 	out.OutputRef = in.OutputRef
+	if in.Outputs != nil {
+		in, out := &in.Outputs, &out.Outputs
+		*out = make([]TailoringOutput, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TailoredProfileStatus.
@@ -1254,6 +2454,22 @@ func (in *TailoringConfigMapRef) DeepCopy() *TailoringConfigMapRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TailoringOutput) DeepCopyInto(out *TailoringOutput) {
+	*out = *in
+	out.OutputRef = in.OutputRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TailoringOutput.
+func (in *TailoringOutput) DeepCopy() *TailoringOutput {
+	if in == nil {
+		return nil
+	}
+	out := new(TailoringOutput)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ValueSelection) DeepCopyInto(out *ValueSelection) {
 	*out = *in
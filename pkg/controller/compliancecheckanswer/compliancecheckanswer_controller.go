@@ -0,0 +1,136 @@
+package compliancecheckanswer
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/common"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/metrics"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/utils"
+)
+
+var log = logf.Log.WithName("compliancecheckanswerctrl")
+
+// Add creates a new ComplianceCheckAnswer Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, met *metrics.Metrics, _ utils.CtlplaneSchedulingInfo, _ *kubernetes.Clientset) error {
+	return add(mgr, newReconciler(mgr, met))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, met *metrics.Metrics) reconcile.Reconciler {
+	return &ReconcileComplianceCheckAnswer{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: common.NewSafeRecorder("compliancecheckanswerctrl", mgr),
+		Metrics:  met,
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("compliancecheckanswer-controller").
+		For(&compv1alpha1.ComplianceCheckAnswer{}).
+		Complete(r)
+}
+
+// blank assignment to verify that ReconcileComplianceCheckAnswer implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileComplianceCheckAnswer{}
+
+// ReconcileComplianceCheckAnswer reconciles a ComplianceCheckAnswer object
+type ReconcileComplianceCheckAnswer struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	Client   client.Client
+	Scheme   *runtime.Scheme
+	Recorder *common.SafeRecorder
+	Metrics  *metrics.Metrics
+}
+
+// Reconcile makes the ComplianceCheckResults that were answered MANUAL again
+// once their answer expires or is deleted. The answers themselves are
+// applied by the aggregator when the results are created.
+func (r *ReconcileComplianceCheckAnswer) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling ComplianceCheckAnswer")
+
+	instance := &compv1alpha1.ComplianceCheckAnswer{}
+	err := r.Client.Get(ctx, request.NamespacedName, instance)
+	if errors.IsNotFound(err) {
+		// The answer was deleted, the results it answered aren't
+		// answered anymore
+		_, err := r.revertCheckResults(ctx, request.Namespace, request.Name, reqLogger)
+		return reconcile.Result{}, err
+	} else if err != nil {
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	untilExpiry := time.Until(instance.Spec.ExpiresAt.Time)
+	if untilExpiry > 0 {
+		// Come back when the answer expires
+		return reconcile.Result{RequeueAfter: untilExpiry}, nil
+	}
+
+	reverted, err := r.revertCheckResults(ctx, instance.Namespace, instance.Name, reqLogger)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if reverted > 0 {
+		reqLogger.Info("Answer expired", "ComplianceCheckAnswer.ExpiresAt", instance.Spec.ExpiresAt)
+		r.Recorder.Eventf(instance, corev1.EventTypeWarning, "AnswerExpired",
+			"The answer for rule %s expired on %s, %d results are MANUAL again",
+			instance.Spec.Rule, instance.Spec.ExpiresAt.UTC().Format(time.RFC3339), reverted)
+	}
+	return reconcile.Result{}, nil
+}
+
+// revertCheckResults makes the results in the namespace that were answered
+// by the named answer MANUAL again. It returns how many were reverted.
+func (r *ReconcileComplianceCheckAnswer) revertCheckResults(ctx context.Context, namespace, name string, logger logr.Logger) (int, error) {
+	checkList := &compv1alpha1.ComplianceCheckResultList{}
+	if err := r.Client.List(ctx, checkList, client.InNamespace(namespace)); err != nil {
+		return 0, err
+	}
+
+	reverted := 0
+	for i := range checkList.Items {
+		check := &checkList.Items[i]
+		if check.Annotations[compv1alpha1.ComplianceCheckResultAnswerAnnotation] != name {
+			continue
+		}
+		logger.Info("Reverting answered check result", "ComplianceCheckResult.Name", check.Name)
+		if err := r.Client.Update(ctx, revertCheckResult(check)); err != nil {
+			return reverted, err
+		}
+		reverted++
+	}
+	return reverted, nil
+}
+
+func revertCheckResult(check *compv1alpha1.ComplianceCheckResult) *compv1alpha1.ComplianceCheckResult {
+	checkCopy := check.DeepCopy()
+	delete(checkCopy.Annotations, compv1alpha1.ComplianceCheckResultAnswerAnnotation)
+	// A failure that was answered and then excepted isn't a failure anymore
+	delete(checkCopy.Annotations, compv1alpha1.ComplianceCheckResultExceptionAnnotation)
+	delete(checkCopy.Annotations, compv1alpha1.ComplianceCheckResultExceptedStatusAnnotation)
+	if checkCopy.Labels == nil {
+		checkCopy.Labels = make(map[string]string)
+	}
+	checkCopy.Labels[compv1alpha1.ComplianceCheckResultStatusLabel] = string(compv1alpha1.CheckResultManual)
+	checkCopy.Status = compv1alpha1.CheckResultManual
+	return checkCopy
+}
@@ -0,0 +1,152 @@
+package compliancecheckanswer
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/ComplianceAsCode/compliance-operator/pkg/apis"
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/common"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/metrics"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/metrics/metricsfakes"
+)
+
+const namespace = "test-ns"
+
+func newCheck(name, answer string, status compv1alpha1.ComplianceCheckStatus) *compv1alpha1.ComplianceCheckResult {
+	check := &compv1alpha1.ComplianceCheckResult{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				compv1alpha1.ComplianceCheckResultStatusLabel: string(status),
+			},
+			Annotations: map[string]string{},
+		},
+		Status: status,
+	}
+	if answer != "" {
+		check.Annotations[compv1alpha1.ComplianceCheckResultAnswerAnnotation] = answer
+	}
+	return check
+}
+
+var _ = Describe("ComplianceCheckAnswer controller", func() {
+	var (
+		ctx        = context.Background()
+		answerKey  = types.NamespacedName{Name: "idp-answer", Namespace: namespace}
+		reconciler *ReconcileComplianceCheckAnswer
+		answer     *compv1alpha1.ComplianceCheckAnswer
+	)
+
+	reconcileAnswer := func() reconcile.Result {
+		res, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: answerKey})
+		Expect(err).To(BeNil())
+		return res
+	}
+
+	getCheck := func(name string) *compv1alpha1.ComplianceCheckResult {
+		check := &compv1alpha1.ComplianceCheckResult{}
+		Expect(reconciler.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, check)).To(BeNil())
+		return check
+	}
+
+	expectManual := func(name string) {
+		check := getCheck(name)
+		Expect(check.Status).To(Equal(compv1alpha1.CheckResultManual))
+		Expect(check.Labels).To(HaveKeyWithValue(compv1alpha1.ComplianceCheckResultStatusLabel, "MANUAL"))
+		Expect(check.Annotations).ToNot(HaveKey(compv1alpha1.ComplianceCheckResultAnswerAnnotation))
+	}
+
+	BeforeEach(func() {
+		cscheme := scheme.Scheme
+		err := apis.AddToScheme(cscheme)
+		Expect(err).To(BeNil())
+
+		answer = &compv1alpha1.ComplianceCheckAnswer{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      answerKey.Name,
+				Namespace: namespace,
+			},
+			Spec: compv1alpha1.ComplianceCheckAnswerSpec{
+				Rule:      "ocp4-idp-is-configured",
+				Answer:    compv1alpha1.CheckResultPass,
+				Evidence:  "The cluster authenticates users against LDAP",
+				ExpiresAt: metav1.NewTime(time.Now().Add(24 * time.Hour)),
+			},
+		}
+
+		objs := []runtime.Object{
+			newCheck("ocp4-cis-idp-is-configured", answerKey.Name, compv1alpha1.CheckResultPass),
+			newCheck("ocp4-moderate-idp-is-configured", answerKey.Name, compv1alpha1.CheckResultPass),
+			newCheck("ocp4-cis-other-manual-check", "other-answer", compv1alpha1.CheckResultFail),
+		}
+
+		client := fake.NewClientBuilder().
+			WithScheme(cscheme).
+			WithRuntimeObjects(objs...).
+			Build()
+
+		mockMetrics := metrics.NewMetrics(&metricsfakes.FakeImpl{})
+		err = mockMetrics.Register()
+		Expect(err).To(BeNil())
+
+		reconciler = &ReconcileComplianceCheckAnswer{
+			Client:   client,
+			Scheme:   cscheme,
+			Recorder: &common.SafeRecorder{},
+			Metrics:  mockMetrics,
+		}
+	})
+
+	JustBeforeEach(func() {
+		Expect(reconciler.Client.Create(ctx, answer)).To(BeNil())
+	})
+
+	Context("with an active answer", func() {
+		It("keeps the answered results and comes back when the answer expires", func() {
+			res := reconcileAnswer()
+			Expect(res.RequeueAfter).To(BeNumerically(">", 23*time.Hour))
+			Expect(res.RequeueAfter).To(BeNumerically("<=", 24*time.Hour))
+			Expect(getCheck("ocp4-cis-idp-is-configured").Status).To(Equal(compv1alpha1.CheckResultPass))
+		})
+	})
+
+	Context("with an expired answer", func() {
+		BeforeEach(func() {
+			answer.Spec.ExpiresAt = metav1.NewTime(time.Now().Add(-time.Hour))
+		})
+
+		It("makes the answered results MANUAL again", func() {
+			res := reconcileAnswer()
+			Expect(res.RequeueAfter).To(BeZero())
+			expectManual("ocp4-cis-idp-is-configured")
+			expectManual("ocp4-moderate-idp-is-configured")
+		})
+
+		It("leaves the results of other answers alone", func() {
+			reconcileAnswer()
+			check := getCheck("ocp4-cis-other-manual-check")
+			Expect(check.Status).To(Equal(compv1alpha1.CheckResultFail))
+			Expect(check.Annotations).To(HaveKeyWithValue(compv1alpha1.ComplianceCheckResultAnswerAnnotation, "other-answer"))
+		})
+	})
+
+	Context("with a deleted answer", func() {
+		It("makes the answered results MANUAL again", func() {
+			Expect(reconciler.Client.Delete(ctx, answer)).To(BeNil())
+			reconcileAnswer()
+			expectManual("ocp4-cis-idp-is-configured")
+			expectManual("ocp4-moderate-idp-is-configured")
+		})
+	})
+})
@@ -0,0 +1,13 @@
+package compliancecheckanswer
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestCompliancecheckanswer(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Compliancecheckanswer Suite")
+}
@@ -0,0 +1,249 @@
+package compliancecontrolreport
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/metrics"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/utils"
+)
+
+var log = logf.Log.WithName("compliancecontrolreportctrl")
+
+func (r *ReconcileComplianceControlReport) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&compv1alpha1.ComplianceControlReport{}).
+		Complete(r)
+}
+
+// Add creates a new ComplianceControlReport Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, met *metrics.Metrics, _ utils.CtlplaneSchedulingInfo, _ *kubernetes.Clientset) error {
+	return add(mgr, newReconciler(mgr, met))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, met *metrics.Metrics) reconcile.Reconciler {
+	return &ReconcileComplianceControlReport{Client: mgr.GetClient(), Scheme: mgr.GetScheme(), Metrics: met}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	mapper := &suiteMapper{mgr.GetClient()}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("compliancecontrolreport-controller").
+		For(&compv1alpha1.ComplianceControlReport{}).
+		Watches(&compv1alpha1.ComplianceSuite{}, handler.EnqueueRequestsFromMapFunc(mapper.MapSuite)).
+		Watches(&compv1alpha1.ComplianceCheckResult{}, handler.EnqueueRequestsFromMapFunc(mapper.MapCheckResult)).
+		Complete(r)
+}
+
+// blank assignment to verify that ReconcileComplianceControlReport implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileComplianceControlReport{}
+
+// ReconcileComplianceControlReport reconciles a ComplianceControlReport object
+type ReconcileComplianceControlReport struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	Client  client.Client
+	Scheme  *runtime.Scheme
+	Metrics *metrics.Metrics
+}
+
+// Reconcile rolls the ComplianceCheckResults of the referenced suite up to
+// the controls of the report's standard and stores them in the status.
+func (r *ReconcileComplianceControlReport) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling ComplianceControlReport")
+
+	instance := &compv1alpha1.ComplianceControlReport{}
+	err := r.Client.Get(ctx, request.NamespacedName, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Return and don't requeue
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	suite := &compv1alpha1.ComplianceSuite{}
+	suiteKey := client.ObjectKey{Name: instance.Spec.SuiteName, Namespace: instance.Namespace}
+	if err := r.Client.Get(ctx, suiteKey, suite); err != nil {
+		if errors.IsNotFound(err) {
+			reqLogger.Info("Suite not found, waiting for it", "ComplianceSuite.Name", suiteKey.Name)
+			return r.updateStatus(ctx, instance, func(s *compv1alpha1.ComplianceControlReportStatus) {
+				s.SetConditionPending(fmt.Sprintf("The ComplianceSuite %s does not exist", suiteKey.Name))
+			})
+		}
+		return reconcile.Result{}, err
+	}
+
+	if suite.Status.Phase != compv1alpha1.PhaseDone {
+		reqLogger.Info("Suite is not done yet, waiting for it", "ComplianceSuite.Phase", suite.Status.Phase)
+		return r.updateStatus(ctx, instance, func(s *compv1alpha1.ComplianceControlReportStatus) {
+			s.SetConditionPending(fmt.Sprintf("The ComplianceSuite %s is in the %s phase", suite.Name, suite.Status.Phase))
+		})
+	}
+
+	controlMap, err := r.getRuleControls(ctx, instance)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	checkList := &compv1alpha1.ComplianceCheckResultList{}
+	listOpts := []client.ListOption{
+		client.InNamespace(instance.Namespace),
+		client.MatchingLabels{compv1alpha1.SuiteLabel: suite.Name},
+	}
+	if err := r.Client.List(ctx, checkList, listOpts...); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	controls := rollupControls(controlMap, checkList.Items)
+	summary := summarizeControls(controls)
+
+	return r.updateStatus(ctx, instance, func(s *compv1alpha1.ComplianceControlReportStatus) {
+		s.Controls = controls
+		s.Summary = summary
+		s.SetConditionReady()
+	})
+}
+
+// getRuleControls returns a map of rule names, as used in the rule annotation
+// of ComplianceCheckResults, to the controls of the report's standard that
+// the rule maps to.
+func (r *ReconcileComplianceControlReport) getRuleControls(ctx context.Context, report *compv1alpha1.ComplianceControlReport) (map[string][]string, error) {
+	ruleList := &compv1alpha1.RuleList{}
+	if err := r.Client.List(ctx, ruleList, client.InNamespace(report.Namespace)); err != nil {
+		return nil, err
+	}
+
+	controlMap := make(map[string][]string)
+	for ruleName, controls := range utils.GetRuleControls(ruleList.Items) {
+		if standardControls := controls[report.Spec.Standard]; len(standardControls) > 0 {
+			controlMap[ruleName] = standardControls
+		}
+	}
+
+	return controlMap, nil
+}
+
+// rollupControls computes the per-control results from the check results.
+// Check results whose rule doesn't map to any control are ignored.
+func rollupControls(ruleControls map[string][]string, checks []compv1alpha1.ComplianceCheckResult) []compv1alpha1.ControlResult {
+	controlChecks := make(map[string][]compv1alpha1.ControlCheckReference)
+	for i := range checks {
+		check := &checks[i]
+		ruleName, ok := check.Annotations[compv1alpha1.ComplianceCheckResultRuleAnnotation]
+		if !ok {
+			continue
+		}
+
+		for _, ctrlID := range ruleControls[ruleName] {
+			controlChecks[ctrlID] = append(controlChecks[ctrlID], compv1alpha1.ControlCheckReference{
+				Name:   check.Name,
+				Status: check.Status,
+			})
+		}
+	}
+
+	controls := make([]compv1alpha1.ControlResult, 0, len(controlChecks))
+	for ctrlID, refs := range controlChecks {
+		sort.Slice(refs, func(i, j int) bool { return refs[i].Name < refs[j].Name })
+		controls = append(controls, compv1alpha1.ControlResult{
+			ID:     ctrlID,
+			Status: controlStatus(refs),
+			Checks: refs,
+		})
+	}
+
+	sort.Slice(controls, func(i, j int) bool { return controls[i].ID < controls[j].ID })
+	return controls
+}
+
+// controlStatus returns the status of a control given the checks mapped to it.
+// A failed check fails the control. Otherwise, any check that couldn't be
+// evaluated automatically makes the control need attention, in the order
+// ERROR, INCONSISTENT, MANUAL. A control whose only failures are covered by
+// an exception is EXCEPTED. The control passes if at least one of the
+// checks passed and the rest were informational or not applicable.
+func controlStatus(refs []compv1alpha1.ControlCheckReference) compv1alpha1.ComplianceCheckStatus {
+	statusCount := make(map[compv1alpha1.ComplianceCheckStatus]int)
+	for _, ref := range refs {
+		statusCount[ref.Status]++
+	}
+
+	for _, status := range []compv1alpha1.ComplianceCheckStatus{
+		compv1alpha1.CheckResultFail,
+		compv1alpha1.CheckResultError,
+		compv1alpha1.CheckResultNoResult,
+		compv1alpha1.CheckResultInconsistent,
+		compv1alpha1.CheckResultManual,
+		compv1alpha1.CheckResultExcepted,
+		compv1alpha1.CheckResultPass,
+	} {
+		if statusCount[status] == 0 {
+			continue
+		}
+		if status == compv1alpha1.CheckResultNoResult {
+			return compv1alpha1.CheckResultError
+		}
+		return status
+	}
+
+	return compv1alpha1.CheckResultNotApplicable
+}
+
+func summarizeControls(controls []compv1alpha1.ControlResult) compv1alpha1.ControlSummary {
+	summary := compv1alpha1.ControlSummary{Total: len(controls)}
+	for _, control := range controls {
+		switch control.Status {
+		case compv1alpha1.CheckResultPass:
+			summary.Passed++
+		case compv1alpha1.CheckResultFail:
+			summary.Failed++
+		case compv1alpha1.CheckResultManual:
+			summary.Manual++
+		case compv1alpha1.CheckResultExcepted:
+			summary.Excepted++
+		case compv1alpha1.CheckResultError:
+			summary.Error++
+		case compv1alpha1.CheckResultInconsistent:
+			summary.Inconsistent++
+		case compv1alpha1.CheckResultNotApplicable:
+			summary.NotApplicable++
+		}
+	}
+	return summary
+}
+
+// updateStatus applies the mutation to a copy of the report's status and
+// only updates the object if that changed anything
+func (r *ReconcileComplianceControlReport) updateStatus(ctx context.Context, report *compv1alpha1.ComplianceControlReport, mutate func(s *compv1alpha1.ComplianceControlReportStatus)) (reconcile.Result, error) {
+	reportCopy := report.DeepCopy()
+	mutate(&reportCopy.Status)
+	if reflect.DeepEqual(report.Status, reportCopy.Status) {
+		return reconcile.Result{}, nil
+	}
+
+	if err := r.Client.Status().Update(ctx, reportCopy); err != nil {
+		return reconcile.Result{}, fmt.Errorf("couldn't update ComplianceControlReport status: %w", err)
+	}
+	return reconcile.Result{}, nil
+}
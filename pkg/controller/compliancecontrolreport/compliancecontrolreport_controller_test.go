@@ -0,0 +1,197 @@
+package compliancecontrolreport
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/ComplianceAsCode/compliance-operator/pkg/apis"
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/metrics"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/metrics/metricsfakes"
+)
+
+func newRule(name, controls string) *compv1alpha1.Rule {
+	return &compv1alpha1.Rule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ocp4-" + name,
+			Namespace: "test-ns",
+			Annotations: map[string]string{
+				compv1alpha1.RuleIDAnnotationKey:                         name,
+				compv1alpha1.RuleControlAnnotationPrefix + "NIST-800-53": controls,
+			},
+		},
+	}
+}
+
+func newCheck(suite, rule string, status compv1alpha1.ComplianceCheckStatus) *compv1alpha1.ComplianceCheckResult {
+	return &compv1alpha1.ComplianceCheckResult{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      suite + "-" + rule,
+			Namespace: "test-ns",
+			Labels: map[string]string{
+				compv1alpha1.SuiteLabel: suite,
+			},
+			Annotations: map[string]string{
+				compv1alpha1.ComplianceCheckResultRuleAnnotation: rule,
+			},
+		},
+		Status: status,
+	}
+}
+
+var _ = Describe("ComplianceControlReport controller", func() {
+	var (
+		ctx        = context.Background()
+		namespace  = "test-ns"
+		reportKey  = types.NamespacedName{Name: "nist", Namespace: namespace}
+		reconciler *ReconcileComplianceControlReport
+		suite      *compv1alpha1.ComplianceSuite
+	)
+
+	BeforeEach(func() {
+		cscheme := scheme.Scheme
+		err := apis.AddToScheme(cscheme)
+		Expect(err).To(BeNil())
+
+		suite = &compv1alpha1.ComplianceSuite{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-suite",
+				Namespace: namespace,
+			},
+			Status: compv1alpha1.ComplianceSuiteStatus{
+				Phase: compv1alpha1.PhaseDone,
+			},
+		}
+
+		report := &compv1alpha1.ComplianceControlReport{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      reportKey.Name,
+				Namespace: namespace,
+			},
+			Spec: compv1alpha1.ComplianceControlReportSpec{
+				Standard:  "NIST-800-53",
+				SuiteName: suite.Name,
+			},
+		}
+
+		objs := []runtime.Object{
+			suite, report,
+			newRule("rule-a", "AC-2;AC-3"),
+			newRule("rule-b", "AC-2"),
+			newRule("rule-c", "AU-9"),
+			newRule("rule-d", "SC-8"),
+			newRule("rule-e", ""),
+			newCheck(suite.Name, "rule-a", compv1alpha1.CheckResultPass),
+			newCheck(suite.Name, "rule-b", compv1alpha1.CheckResultFail),
+			newCheck(suite.Name, "rule-c", compv1alpha1.CheckResultManual),
+			newCheck(suite.Name, "rule-d", compv1alpha1.CheckResultNotApplicable),
+			newCheck(suite.Name, "rule-e", compv1alpha1.CheckResultFail),
+			newCheck("other-suite", "rule-c", compv1alpha1.CheckResultFail),
+		}
+
+		client := fake.NewClientBuilder().
+			WithScheme(cscheme).
+			WithStatusSubresource(&compv1alpha1.ComplianceControlReport{}).
+			WithRuntimeObjects(objs...).
+			Build()
+
+		mockMetrics := metrics.NewMetrics(&metricsfakes.FakeImpl{})
+		err = mockMetrics.Register()
+		Expect(err).To(BeNil())
+
+		reconciler = &ReconcileComplianceControlReport{Client: client, Scheme: cscheme, Metrics: mockMetrics}
+	})
+
+	It("rolls up the suite results to controls", func() {
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: reportKey})
+		Expect(err).To(BeNil())
+
+		report := &compv1alpha1.ComplianceControlReport{}
+		err = reconciler.Client.Get(ctx, reportKey, report)
+		Expect(err).To(BeNil())
+		Expect(report.Status.Conditions.IsTrueFor("Ready")).To(BeTrue())
+
+		Expect(report.Status.Controls).To(HaveLen(4))
+		statuses := map[string]compv1alpha1.ComplianceCheckStatus{}
+		for _, control := range report.Status.Controls {
+			statuses[control.ID] = control.Status
+		}
+		Expect(statuses).To(Equal(map[string]compv1alpha1.ComplianceCheckStatus{
+			"AC-2": compv1alpha1.CheckResultFail,
+			"AC-3": compv1alpha1.CheckResultPass,
+			"AU-9": compv1alpha1.CheckResultManual,
+			"SC-8": compv1alpha1.CheckResultNotApplicable,
+		}))
+		Expect(report.Status.Controls[0].Checks).To(HaveLen(2))
+
+		Expect(report.Status.Summary).To(Equal(compv1alpha1.ControlSummary{
+			Total:         4,
+			Passed:        1,
+			Failed:        1,
+			Manual:        1,
+			NotApplicable: 1,
+		}))
+	})
+
+	It("waits for the suite to be done", func() {
+		suite.Status.Phase = compv1alpha1.PhaseRunning
+		err := reconciler.Client.Update(ctx, suite)
+		Expect(err).To(BeNil())
+
+		_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: reportKey})
+		Expect(err).To(BeNil())
+
+		report := &compv1alpha1.ComplianceControlReport{}
+		err = reconciler.Client.Get(ctx, reportKey, report)
+		Expect(err).To(BeNil())
+		Expect(report.Status.Conditions.IsFalseFor("Ready")).To(BeTrue())
+		Expect(report.Status.Controls).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Computing the control status", func() {
+	ref := func(status compv1alpha1.ComplianceCheckStatus) compv1alpha1.ControlCheckReference {
+		return compv1alpha1.ControlCheckReference{Status: status}
+	}
+
+	It("fails if any check fails", func() {
+		Expect(controlStatus([]compv1alpha1.ControlCheckReference{
+			ref(compv1alpha1.CheckResultError), ref(compv1alpha1.CheckResultFail), ref(compv1alpha1.CheckResultPass),
+		})).To(Equal(compv1alpha1.CheckResultFail))
+	})
+
+	It("needs review if a check is manual", func() {
+		Expect(controlStatus([]compv1alpha1.ControlCheckReference{
+			ref(compv1alpha1.CheckResultManual), ref(compv1alpha1.CheckResultPass),
+		})).To(Equal(compv1alpha1.CheckResultManual))
+	})
+
+	It("errors if a check has no result", func() {
+		Expect(controlStatus([]compv1alpha1.ControlCheckReference{
+			ref(compv1alpha1.CheckResultNoResult), ref(compv1alpha1.CheckResultManual),
+		})).To(Equal(compv1alpha1.CheckResultError))
+	})
+
+	It("is excepted if the only failures are excepted", func() {
+		Expect(controlStatus([]compv1alpha1.ControlCheckReference{
+			ref(compv1alpha1.CheckResultExcepted), ref(compv1alpha1.CheckResultPass),
+		})).To(Equal(compv1alpha1.CheckResultExcepted))
+		Expect(controlStatus([]compv1alpha1.ControlCheckReference{
+			ref(compv1alpha1.CheckResultExcepted), ref(compv1alpha1.CheckResultFail),
+		})).To(Equal(compv1alpha1.CheckResultFail))
+	})
+
+	It("passes if the checks pass or are informational", func() {
+		Expect(controlStatus([]compv1alpha1.ControlCheckReference{
+			ref(compv1alpha1.CheckResultInfo), ref(compv1alpha1.CheckResultPass), ref(compv1alpha1.CheckResultNotApplicable),
+		})).To(Equal(compv1alpha1.CheckResultPass))
+	})
+})
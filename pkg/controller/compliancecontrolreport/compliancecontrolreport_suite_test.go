@@ -0,0 +1,13 @@
+package compliancecontrolreport
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestCompliancecontrolreport(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Compliancecontrolreport Suite")
+}
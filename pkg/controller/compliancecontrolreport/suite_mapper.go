@@ -0,0 +1,54 @@
+package compliancecontrolreport
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+)
+
+type suiteMapper struct {
+	client.Client
+}
+
+// MapSuite enqueues the reports that roll up the results of a suite
+func (s *suiteMapper) MapSuite(ctx context.Context, obj client.Object) []reconcile.Request {
+	return s.requestsForSuite(ctx, obj.GetName(), obj.GetNamespace())
+}
+
+// MapCheckResult enqueues the reports that roll up the suite a check result
+// belongs to
+func (s *suiteMapper) MapCheckResult(ctx context.Context, obj client.Object) []reconcile.Request {
+	suiteName, ok := obj.GetLabels()[compv1alpha1.SuiteLabel]
+	if !ok || suiteName == "" {
+		return nil
+	}
+	return s.requestsForSuite(ctx, suiteName, obj.GetNamespace())
+}
+
+func (s *suiteMapper) requestsForSuite(ctx context.Context, suiteName, namespace string) []reconcile.Request {
+	var requests []reconcile.Request
+
+	reportList := compv1alpha1.ComplianceControlReportList{}
+	err := s.List(ctx, &reportList, client.InNamespace(namespace))
+	if err != nil {
+		return requests
+	}
+
+	for _, report := range reportList.Items {
+		if report.Spec.SuiteName != suiteName {
+			continue
+		}
+
+		objKey := types.NamespacedName{
+			Name:      report.GetName(),
+			Namespace: report.GetNamespace(),
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: objKey})
+	}
+
+	return requests
+}
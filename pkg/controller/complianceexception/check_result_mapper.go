@@ -0,0 +1,38 @@
+package complianceexception
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+)
+
+type checkResultMapper struct {
+	client.Client
+}
+
+// Map enqueues the exceptions in the namespace of the check result, so that
+// new or updated results get excepted and results released by another
+// exception can be picked up
+func (c *checkResultMapper) Map(ctx context.Context, obj client.Object) []reconcile.Request {
+	var requests []reconcile.Request
+
+	excList := compv1alpha1.ComplianceExceptionList{}
+	err := c.List(ctx, &excList, client.InNamespace(obj.GetNamespace()))
+	if err != nil {
+		return requests
+	}
+
+	for i := range excList.Items {
+		objKey := types.NamespacedName{
+			Name:      excList.Items[i].GetName(),
+			Namespace: excList.Items[i].GetNamespace(),
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: objKey})
+	}
+
+	return requests
+}
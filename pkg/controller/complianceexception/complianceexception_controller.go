@@ -0,0 +1,306 @@
+package complianceexception
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/common"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/metrics"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/utils"
+)
+
+var log = logf.Log.WithName("complianceexceptionctrl")
+
+func (r *ReconcileComplianceException) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&compv1alpha1.ComplianceException{}).
+		Complete(r)
+}
+
+// Add creates a new ComplianceException Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, met *metrics.Metrics, _ utils.CtlplaneSchedulingInfo, _ *kubernetes.Clientset) error {
+	return add(mgr, newReconciler(mgr, met))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, met *metrics.Metrics) reconcile.Reconciler {
+	return &ReconcileComplianceException{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: common.NewSafeRecorder("complianceexceptionctrl", mgr),
+		Metrics:  met,
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	mapper := &checkResultMapper{mgr.GetClient()}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("complianceexception-controller").
+		For(&compv1alpha1.ComplianceException{}).
+		Watches(&compv1alpha1.ComplianceCheckResult{}, handler.EnqueueRequestsFromMapFunc(mapper.Map)).
+		Complete(r)
+}
+
+// blank assignment to verify that ReconcileComplianceException implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileComplianceException{}
+
+// ReconcileComplianceException reconciles a ComplianceException object
+type ReconcileComplianceException struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	Client   client.Client
+	Scheme   *runtime.Scheme
+	Recorder *common.SafeRecorder
+	Metrics  *metrics.Metrics
+}
+
+// Reconcile marks the failed ComplianceCheckResults of the excepted rule as
+// EXCEPTED while the exception is active, and restores their status once the
+// exception expires or is deleted.
+func (r *ReconcileComplianceException) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling ComplianceException")
+
+	instance := &compv1alpha1.ComplianceException{}
+	err := r.Client.Get(ctx, request.NamespacedName, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Return and don't requeue
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	if instance.ObjectMeta.DeletionTimestamp.IsZero() {
+		if !common.ContainsFinalizer(instance.ObjectMeta.Finalizers, compv1alpha1.ComplianceExceptionFinalizer) {
+			excCopy := instance.DeepCopy()
+			excCopy.ObjectMeta.Finalizers = append(excCopy.ObjectMeta.Finalizers, compv1alpha1.ComplianceExceptionFinalizer)
+			return reconcile.Result{}, r.Client.Update(ctx, excCopy)
+		}
+	} else {
+		// The object is being deleted
+		return reconcile.Result{}, r.exceptionDeleteHandler(ctx, instance, reqLogger)
+	}
+
+	ruleID, err := r.getRuleID(ctx, instance)
+	if err != nil && !common.IsRetriable(err) {
+		return r.updateStatus(ctx, instance, func(s *compv1alpha1.ComplianceExceptionStatus) {
+			s.State = compv1alpha1.ComplianceExceptionStateError
+			s.ErrorMessage = err.Error()
+			s.ExceptedChecks = nil
+		})
+	} else if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	untilExpiry := time.Until(instance.Spec.ExpiresAt.Time)
+	active := untilExpiry > 0
+
+	excepted, err := r.reconcileCheckResults(ctx, instance, ruleID, active, reqLogger)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if !active {
+		if instance.Status.State != compv1alpha1.ComplianceExceptionStateExpired {
+			reqLogger.Info("Exception expired", "ComplianceException.ExpiresAt", instance.Spec.ExpiresAt)
+			r.Recorder.Eventf(instance, corev1.EventTypeWarning, "ExceptionExpired",
+				"The exception for rule %s expired on %s, its results are evaluated normally again",
+				instance.Spec.Rule, instance.Spec.ExpiresAt.UTC().Format(time.RFC3339))
+		}
+		return r.updateStatus(ctx, instance, func(s *compv1alpha1.ComplianceExceptionStatus) {
+			s.State = compv1alpha1.ComplianceExceptionStateExpired
+			s.ErrorMessage = ""
+			s.ExceptedChecks = nil
+		})
+	}
+
+	res, err := r.updateStatus(ctx, instance, func(s *compv1alpha1.ComplianceExceptionStatus) {
+		s.State = compv1alpha1.ComplianceExceptionStateActive
+		s.ErrorMessage = ""
+		s.ExceptedChecks = excepted
+	})
+	if err != nil {
+		return res, err
+	}
+	// Come back when the exception expires
+	return reconcile.Result{RequeueAfter: untilExpiry}, nil
+}
+
+// getRuleID returns the DNS-friendly name of the excepted rule, as used in
+// the rule annotation of the ComplianceCheckResults
+func (r *ReconcileComplianceException) getRuleID(ctx context.Context, exc *compv1alpha1.ComplianceException) (string, error) {
+	rule := &compv1alpha1.Rule{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: exc.Spec.Rule, Namespace: exc.Namespace}, rule)
+	if errors.IsNotFound(err) {
+		return "", common.NewNonRetriableCtrlError("fetching excepted rule: %w", err)
+	} else if err != nil {
+		return "", err
+	}
+
+	ruleID, ok := rule.Annotations[compv1alpha1.RuleIDAnnotationKey]
+	if !ok || ruleID == "" {
+		return "", common.NewNonRetriableCtrlError("Rule '%s' has no %s annotation", rule.Name, compv1alpha1.RuleIDAnnotationKey)
+	}
+	return ruleID, nil
+}
+
+// reconcileCheckResults excepts the failed results of the rule in the scope
+// of the exception if it's active, and restores the results it excepted that
+// are no longer covered. It returns the names of the excepted results. Only
+// the results in the namespace of the exception are considered, so that
+// creating an exception can't change results the creator has no access to.
+func (r *ReconcileComplianceException) reconcileCheckResults(ctx context.Context, exc *compv1alpha1.ComplianceException, ruleID string, active bool, logger logr.Logger) ([]string, error) {
+	excepted := []string{}
+	scanRoles := map[types.NamespacedName]string{}
+
+	checkList := &compv1alpha1.ComplianceCheckResultList{}
+	if err := r.Client.List(ctx, checkList, client.InNamespace(exc.Namespace)); err != nil {
+		return nil, err
+	}
+
+	for i := range checkList.Items {
+		check := &checkList.Items[i]
+		if check.Annotations[compv1alpha1.ComplianceCheckResultRuleAnnotation] != ruleID &&
+			!isExceptedBy(check, exc) {
+			continue
+		}
+
+		covered := false
+		if active && check.Annotations[compv1alpha1.ComplianceCheckResultRuleAnnotation] == ruleID {
+			var err error
+			covered, err = r.inScope(ctx, exc, check, scanRoles)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		switch {
+		case covered && isExceptedBy(check, exc):
+			excepted = append(excepted, check.Name)
+		case covered && check.Status == compv1alpha1.CheckResultFail && exceptedBy(check) == "":
+			logger.Info("Excepting check result", "ComplianceCheckResult.Name", check.Name)
+			if err := r.Client.Update(ctx, exceptCheckResult(check, exc)); err != nil {
+				return nil, err
+			}
+			excepted = append(excepted, check.Name)
+		case !covered && isExceptedBy(check, exc):
+			logger.Info("Restoring check result", "ComplianceCheckResult.Name", check.Name)
+			if err := r.Client.Update(ctx, restoreCheckResult(check)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	sort.Strings(excepted)
+	return excepted, nil
+}
+
+// inScope returns whether the check result is in the node roles the
+// exception is scoped to, if any
+func (r *ReconcileComplianceException) inScope(ctx context.Context, exc *compv1alpha1.ComplianceException, check *compv1alpha1.ComplianceCheckResult, scanRoles map[types.NamespacedName]string) (bool, error) {
+	if len(exc.Spec.Scope.NodeRoles) == 0 {
+		return true, nil
+	}
+
+	scanKey := types.NamespacedName{Name: check.Labels[compv1alpha1.ComplianceScanLabel], Namespace: check.Namespace}
+	role, ok := scanRoles[scanKey]
+	if !ok {
+		scan := &compv1alpha1.ComplianceScan{}
+		err := r.Client.Get(ctx, scanKey, scan)
+		if err != nil && !errors.IsNotFound(err) {
+			return false, err
+		} else if err == nil {
+			role = utils.GetFirstNodeRole(scan.Spec.NodeSelector)
+		}
+		scanRoles[scanKey] = role
+	}
+	return exc.AppliesToNodeRole(role), nil
+}
+
+func (r *ReconcileComplianceException) exceptionDeleteHandler(ctx context.Context, exc *compv1alpha1.ComplianceException, logger logr.Logger) error {
+	if _, err := r.reconcileCheckResults(ctx, exc, "", false, logger); err != nil {
+		return err
+	}
+
+	excCopy := exc.DeepCopy()
+	// remove our finalizer from the list and update it.
+	excCopy.ObjectMeta.Finalizers = common.RemoveFinalizer(excCopy.ObjectMeta.Finalizers, compv1alpha1.ComplianceExceptionFinalizer)
+	return r.Client.Update(ctx, excCopy)
+}
+
+// updateStatus applies the mutation to a copy of the exception's status and
+// only updates the object if that changed anything
+func (r *ReconcileComplianceException) updateStatus(ctx context.Context, exc *compv1alpha1.ComplianceException, mutate func(s *compv1alpha1.ComplianceExceptionStatus)) (reconcile.Result, error) {
+	excCopy := exc.DeepCopy()
+	mutate(&excCopy.Status)
+	if reflect.DeepEqual(exc.Status, excCopy.Status) {
+		return reconcile.Result{}, nil
+	}
+
+	if err := r.Client.Status().Update(ctx, excCopy); err != nil {
+		return reconcile.Result{}, fmt.Errorf("couldn't update ComplianceException status: %w", err)
+	}
+	return reconcile.Result{}, nil
+}
+
+func exceptedBy(check *compv1alpha1.ComplianceCheckResult) string {
+	return check.Annotations[compv1alpha1.ComplianceCheckResultExceptionAnnotation]
+}
+
+func isExceptedBy(check *compv1alpha1.ComplianceCheckResult, exc *compv1alpha1.ComplianceException) bool {
+	return exceptedBy(check) == exc.GetReference()
+}
+
+func exceptCheckResult(check *compv1alpha1.ComplianceCheckResult, exc *compv1alpha1.ComplianceException) *compv1alpha1.ComplianceCheckResult {
+	checkCopy := check.DeepCopy()
+	if checkCopy.Annotations == nil {
+		checkCopy.Annotations = make(map[string]string)
+	}
+	if checkCopy.Labels == nil {
+		checkCopy.Labels = make(map[string]string)
+	}
+	checkCopy.Annotations[compv1alpha1.ComplianceCheckResultExceptionAnnotation] = exc.GetReference()
+	checkCopy.Annotations[compv1alpha1.ComplianceCheckResultExceptedStatusAnnotation] = string(check.Status)
+	checkCopy.Labels[compv1alpha1.ComplianceCheckResultStatusLabel] = string(compv1alpha1.CheckResultExcepted)
+	checkCopy.Status = compv1alpha1.CheckResultExcepted
+	return checkCopy
+}
+
+func restoreCheckResult(check *compv1alpha1.ComplianceCheckResult) *compv1alpha1.ComplianceCheckResult {
+	checkCopy := check.DeepCopy()
+	status := compv1alpha1.ComplianceCheckStatus(checkCopy.Annotations[compv1alpha1.ComplianceCheckResultExceptedStatusAnnotation])
+	if status == compv1alpha1.CheckResultNoResult {
+		status = compv1alpha1.CheckResultFail
+	}
+	delete(checkCopy.Annotations, compv1alpha1.ComplianceCheckResultExceptionAnnotation)
+	delete(checkCopy.Annotations, compv1alpha1.ComplianceCheckResultExceptedStatusAnnotation)
+	if checkCopy.Labels == nil {
+		checkCopy.Labels = make(map[string]string)
+	}
+	checkCopy.Labels[compv1alpha1.ComplianceCheckResultStatusLabel] = string(status)
+	checkCopy.Status = status
+	return checkCopy
+}
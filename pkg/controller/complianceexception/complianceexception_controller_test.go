@@ -0,0 +1,260 @@
+package complianceexception
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/ComplianceAsCode/compliance-operator/pkg/apis"
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/common"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/metrics"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/metrics/metricsfakes"
+)
+
+const namespace = "test-ns"
+
+func newScan(name, role string) *compv1alpha1.ComplianceScan {
+	return &compv1alpha1.ComplianceScan{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: compv1alpha1.ComplianceScanSpec{
+			ScanType:     compv1alpha1.ScanTypeNode,
+			NodeSelector: map[string]string{"node-role.kubernetes.io/" + role: ""},
+		},
+	}
+}
+
+func newCheck(scan, rule string, status compv1alpha1.ComplianceCheckStatus) *compv1alpha1.ComplianceCheckResult {
+	return &compv1alpha1.ComplianceCheckResult{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      scan + "-" + rule,
+			Namespace: namespace,
+			Labels: map[string]string{
+				compv1alpha1.ComplianceScanLabel:              scan,
+				compv1alpha1.ComplianceCheckResultStatusLabel: string(status),
+			},
+			Annotations: map[string]string{
+				compv1alpha1.ComplianceCheckResultRuleAnnotation: rule,
+			},
+		},
+		Status: status,
+	}
+}
+
+var _ = Describe("ComplianceException controller", func() {
+	var (
+		ctx        = context.Background()
+		excKey     = types.NamespacedName{Name: "audit-waiver", Namespace: namespace}
+		reconciler *ReconcileComplianceException
+		exc        *compv1alpha1.ComplianceException
+	)
+
+	reconcileException := func() reconcile.Result {
+		res, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: excKey})
+		Expect(err).To(BeNil())
+		return res
+	}
+
+	getException := func() *compv1alpha1.ComplianceException {
+		found := &compv1alpha1.ComplianceException{}
+		Expect(reconciler.Client.Get(ctx, excKey, found)).To(BeNil())
+		return found
+	}
+
+	getCheck := func(name string) *compv1alpha1.ComplianceCheckResult {
+		check := &compv1alpha1.ComplianceCheckResult{}
+		Expect(reconciler.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, check)).To(BeNil())
+		return check
+	}
+
+	BeforeEach(func() {
+		cscheme := scheme.Scheme
+		err := apis.AddToScheme(cscheme)
+		Expect(err).To(BeNil())
+
+		rule := &compv1alpha1.Rule{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "rhcos4-audit-rules-login-events",
+				Namespace: namespace,
+				Annotations: map[string]string{
+					compv1alpha1.RuleIDAnnotationKey: "audit-rules-login-events",
+				},
+			},
+		}
+
+		exc = &compv1alpha1.ComplianceException{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      excKey.Name,
+				Namespace: namespace,
+			},
+			Spec: compv1alpha1.ComplianceExceptionSpec{
+				Rule:          rule.Name,
+				Justification: "Login events are audited by the SIEM agent",
+				Approver:      "security-team",
+				ExpiresAt:     metav1.NewTime(time.Now().Add(24 * time.Hour)),
+			},
+		}
+
+		objs := []runtime.Object{
+			rule,
+			newScan("e8-worker", "worker"),
+			newScan("e8-master", "master"),
+			newCheck("e8-worker", "audit-rules-login-events", compv1alpha1.CheckResultFail),
+			newCheck("e8-master", "audit-rules-login-events", compv1alpha1.CheckResultFail),
+			newCheck("e8-worker", "audit-rules-other", compv1alpha1.CheckResultFail),
+			newCheck("e8-master", "audit-rules-other", compv1alpha1.CheckResultPass),
+		}
+
+		client := fake.NewClientBuilder().
+			WithScheme(cscheme).
+			WithStatusSubresource(&compv1alpha1.ComplianceException{}).
+			WithRuntimeObjects(objs...).
+			Build()
+
+		mockMetrics := metrics.NewMetrics(&metricsfakes.FakeImpl{})
+		err = mockMetrics.Register()
+		Expect(err).To(BeNil())
+
+		reconciler = &ReconcileComplianceException{
+			Client:   client,
+			Scheme:   cscheme,
+			Recorder: &common.SafeRecorder{},
+			Metrics:  mockMetrics,
+		}
+	})
+
+	JustBeforeEach(func() {
+		Expect(reconciler.Client.Create(ctx, exc)).To(BeNil())
+	})
+
+	Context("with an active exception", func() {
+		It("excepts the failures of the rule", func() {
+			By("Adding the finalizer")
+			reconcileException()
+			Expect(getException().Finalizers).To(ContainElement(compv1alpha1.ComplianceExceptionFinalizer))
+
+			By("Excepting the failed results")
+			res := reconcileException()
+			Expect(res.RequeueAfter).To(BeNumerically(">", 23*time.Hour))
+
+			found := getException()
+			Expect(found.Status.State).To(Equal(compv1alpha1.ComplianceExceptionStateActive))
+			Expect(found.Status.ExceptedChecks).To(Equal([]string{
+				"e8-master-audit-rules-login-events",
+				"e8-worker-audit-rules-login-events",
+			}))
+
+			check := getCheck("e8-worker-audit-rules-login-events")
+			Expect(check.Status).To(Equal(compv1alpha1.CheckResultExcepted))
+			Expect(check.Labels).To(HaveKeyWithValue(compv1alpha1.ComplianceCheckResultStatusLabel, "EXCEPTED"))
+			Expect(check.Annotations).To(HaveKeyWithValue(compv1alpha1.ComplianceCheckResultExceptionAnnotation, "test-ns/audit-waiver"))
+			Expect(check.Annotations).To(HaveKeyWithValue(compv1alpha1.ComplianceCheckResultExceptedStatusAnnotation, "FAIL"))
+
+			Expect(getCheck("e8-worker-audit-rules-other").Status).To(Equal(compv1alpha1.CheckResultFail))
+		})
+
+		It("doesn't except the results in other namespaces", func() {
+			other := newCheck("e8-worker", "audit-rules-login-events", compv1alpha1.CheckResultFail)
+			other.Namespace = "other-ns"
+			Expect(reconciler.Client.Create(ctx, other)).To(BeNil())
+
+			reconcileException()
+			reconcileException()
+
+			found := &compv1alpha1.ComplianceCheckResult{}
+			Expect(reconciler.Client.Get(ctx, types.NamespacedName{Name: other.Name, Namespace: "other-ns"}, found)).To(BeNil())
+			Expect(found.Status).To(Equal(compv1alpha1.CheckResultFail))
+			Expect(found.Annotations).ToNot(HaveKey(compv1alpha1.ComplianceCheckResultExceptionAnnotation))
+		})
+
+		It("restores the results when deleted", func() {
+			reconcileException()
+			reconcileException()
+
+			Expect(reconciler.Client.Delete(ctx, getException())).To(BeNil())
+			reconcileException()
+
+			check := getCheck("e8-worker-audit-rules-login-events")
+			Expect(check.Status).To(Equal(compv1alpha1.CheckResultFail))
+			Expect(check.Labels).To(HaveKeyWithValue(compv1alpha1.ComplianceCheckResultStatusLabel, "FAIL"))
+			Expect(check.Annotations).ToNot(HaveKey(compv1alpha1.ComplianceCheckResultExceptionAnnotation))
+
+			found := &compv1alpha1.ComplianceException{}
+			err := reconciler.Client.Get(ctx, excKey, found)
+			Expect(err).ToNot(BeNil())
+		})
+	})
+
+	Context("with an exception scoped to a node role", func() {
+		BeforeEach(func() {
+			exc.Spec.Scope.NodeRoles = []string{"worker"}
+		})
+
+		It("only excepts the results of that role", func() {
+			reconcileException()
+			reconcileException()
+
+			Expect(getCheck("e8-worker-audit-rules-login-events").Status).To(Equal(compv1alpha1.CheckResultExcepted))
+			Expect(getCheck("e8-master-audit-rules-login-events").Status).To(Equal(compv1alpha1.CheckResultFail))
+		})
+	})
+
+	Context("with an expired exception", func() {
+		It("evaluates the rule normally again", func() {
+			reconcileException()
+			reconcileException()
+			Expect(getCheck("e8-worker-audit-rules-login-events").Status).To(Equal(compv1alpha1.CheckResultExcepted))
+
+			found := getException()
+			found.Spec.ExpiresAt = metav1.NewTime(time.Now().Add(-time.Minute))
+			Expect(reconciler.Client.Update(ctx, found)).To(BeNil())
+			res := reconcileException()
+			Expect(res.RequeueAfter).To(BeZero())
+
+			found = getException()
+			Expect(found.Status.State).To(Equal(compv1alpha1.ComplianceExceptionStateExpired))
+			Expect(found.Status.ExceptedChecks).To(BeEmpty())
+			Expect(getCheck("e8-worker-audit-rules-login-events").Status).To(Equal(compv1alpha1.CheckResultFail))
+			Expect(getCheck("e8-master-audit-rules-login-events").Status).To(Equal(compv1alpha1.CheckResultFail))
+		})
+	})
+
+	Context("with an unknown rule", func() {
+		BeforeEach(func() {
+			exc.Spec.Rule = "unexistent"
+		})
+
+		It("reports an error", func() {
+			reconcileException()
+			reconcileException()
+
+			found := getException()
+			Expect(found.Status.State).To(Equal(compv1alpha1.ComplianceExceptionStateError))
+			Expect(found.Status.ErrorMessage).To(ContainSubstring("fetching excepted rule"))
+		})
+	})
+
+	Context("mapping check results", func() {
+		It("enqueues the exceptions in scope of the result's namespace", func() {
+			mapper := &checkResultMapper{reconciler.Client}
+			requests := mapper.Map(ctx, getCheck("e8-worker-audit-rules-other"))
+			Expect(requests).To(HaveLen(1))
+			Expect(requests[0].NamespacedName).To(Equal(excKey))
+
+			other := newCheck("e8-worker", "audit-rules-other", compv1alpha1.CheckResultFail)
+			other.Namespace = "other-ns"
+			Expect(mapper.Map(ctx, other)).To(BeEmpty())
+		})
+	})
+})
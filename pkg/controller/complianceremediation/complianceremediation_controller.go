@@ -25,6 +25,8 @@ import (
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -62,10 +64,43 @@ func newReconciler(mgr manager.Manager, met *metrics.Metrics) reconcile.Reconcil
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
 func add(mgr manager.Manager, r reconcile.Reconciler) error {
-	// Watch for changes to primary resource ComplianceRemediation
-	return ctrl.NewControllerManagedBy(mgr).
+	mapper := &createdObjectMapper{mgr.GetClient()}
+	valuesMapper := &valuesMapper{mgr.GetClient()}
+
+	// Watch for changes to primary resource ComplianceRemediation, and to
+	// the remediation values ConfigMaps
+	b := ctrl.NewControllerManagedBy(mgr).
 		Named("complianceremediation-controller").
-		For(&compv1alpha1.ComplianceRemediation{}).Complete(r)
+		For(&compv1alpha1.ComplianceRemediation{}).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(valuesMapper.Map))
+
+	// Watch the metadata of the MachineConfigs and KubeletConfigs we create
+	// in order to detect them drifting. Clusters without the MCO don't serve
+	// them, and the controller wouldn't start with a watch on them; their
+	// remediations are checked for drift periodically instead.
+	mcoWatches := []struct {
+		obj client.Object
+		fn  handler.MapFunc
+	}{
+		{&mcfgv1.MachineConfig{}, mapper.MapMachineConfig},
+		{&mcfgv1.KubeletConfig{}, mapper.MapKubeletConfig},
+	}
+	for _, w := range mcoWatches {
+		gvk, err := apiutil.GVKForObject(w.obj, mgr.GetScheme())
+		if err != nil {
+			return err
+		}
+		served, err := isServed(mgr.GetRESTMapper(), gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			log.Error(err, "Cannot tell whether the kind is served, not watching it", "Kind", gvk.Kind)
+			continue
+		} else if !served {
+			log.Info("The kind isn't served, not watching it", "Kind", gvk.Kind)
+			continue
+		}
+		b = b.WatchesMetadata(w.obj, handler.EnqueueRequestsFromMapFunc(w.fn))
+	}
+	return b.Complete(r)
 }
 
 // blank assignment to verify that ReconcileComplianceRemediation implements reconcile.Reconciler
@@ -163,6 +198,41 @@ func (r *ReconcileComplianceRemediation) Reconcile(ctx context.Context, request
 		}
 	}
 
+	// The values the remediation lacks might be provided by a remediation
+	// values ConfigMap
+	if needsValues(remediationInstance) {
+		hasUpdate, valuesErr := r.resolveValues(remediationInstance, reqLogger)
+		if valuesErr != nil {
+			return common.ReturnWithRetriableError(reqLogger, valuesErr)
+		} else if hasUpdate {
+			return reconcile.Result{}, nil
+		}
+	}
+
+	if remediationInstance.HasAnnotation(compv1alpha1.RemediationPreviewAnnotation) {
+		return r.reconcilePreview(remediationInstance, reqLogger)
+	}
+
+	// The object of an applied remediation might have been changed or
+	// deleted since
+	if isCheckedForDrift(remediationInstance) {
+		drifted, res, driftErr := r.reconcileDrift(remediationInstance, reqLogger)
+		if drifted || driftErr != nil {
+			return res, driftErr
+		}
+	}
+
+	// The members of a group are applied in order and un-applied in the
+	// reverse order
+	if remediationInstance.GetGroup() != "" {
+		waiting, groupErr := r.waitForGroupMembers(remediationInstance, reqLogger)
+		if groupErr != nil {
+			return common.ReturnWithRetriableError(reqLogger, groupErr)
+		} else if waiting {
+			return reconcile.Result{RequeueAfter: groupOrderRequeueTime}, nil
+		}
+	}
+
 	//if no UnmetDependencies, UnsetValue, ValueRequired
 	if !(remediationInstance.HasUnmetDependencies() || remediationInstance.HasAnnotation(compv1alpha1.RemediationUnsetValueAnnotation) || remediationInstance.HasAnnotation(compv1alpha1.RemediationValueRequiredAnnotation)) {
 		reconcileErr = r.reconcileRemediation(remediationInstance, reqLogger)
@@ -186,6 +256,13 @@ func (r *ReconcileComplianceRemediation) Reconcile(ctx context.Context, request
 		return reconcile.Result{Requeue: true, RequeueAfter: defaultDependencyRequeueTime}, nil
 	}
 	reqLogger.Info("Done reconciling")
+	// The state of an applied policy is read from its engine periodically
+	if remediationInstance.Spec.Apply && getEnforcementBackend(remediationInstance.Spec.Current.Object) != nil {
+		return reconcile.Result{RequeueAfter: enforcementStatusInterval}, nil
+	}
+	if isCheckedForDrift(remediationInstance) {
+		return reconcile.Result{RequeueAfter: driftCheckInterval}, nil
+	}
 	return reconcile.Result{}, nil
 }
 
@@ -209,6 +286,16 @@ func (r *ReconcileComplianceRemediation) reconcileRemediation(instance *compv1al
 			return err
 		}
 	}
+	// Policies are enforced in the mode of the remediation, by an engine
+	// that must be installed
+	if err := setEnforcementMode(instance, obj); err != nil {
+		return common.WrapNonRetriableCtrlError(err)
+	}
+	if instance.Spec.Apply {
+		if err := r.verifyEnforcementEngine(obj); err != nil {
+			return err
+		}
+	}
 
 	objectLogger := logger.WithValues("Object.Name", obj.GetName(), "Object.Namespace", obj.GetNamespace(), "Object.Kind", obj.GetKind())
 	objectLogger.Info("Reconciling remediation object")
@@ -226,6 +313,14 @@ func (r *ReconcileComplianceRemediation) reconcileRemediation(instance *compv1al
 			"Unable to get fix object for ComplianceRemediation. "+
 				"Make sure the CRD is installed: %w", err)
 	} else if kerrors.IsNotFound(err) {
+		if payload := getApplicablePayload(instance); payload.IsPatch() {
+			if instance.Spec.Apply {
+				return common.NewNonRetriableCtrlError("Unable to patch fix object from ComplianceRemediation: the object doesn't exist")
+			}
+			objectLogger.Info("The object wasn't found, so no action is needed to unapply it")
+			instance.Status.PreImage = nil
+			return nil
+		}
 		if instance.Spec.Apply {
 			instance.AddOwnershipLabels(obj)
 			// Going through remediation list, to make sure all the related
@@ -246,17 +341,30 @@ func (r *ReconcileComplianceRemediation) reconcileRemediation(instance *compv1al
 		return err
 	}
 
+	payload := getApplicablePayload(instance)
 	if instance.Spec.Apply {
 		err = r.setRemediations(instance, objectLogger, true)
 		if err != nil {
 			return fmt.Errorf("failed to set related remediations to apply: %w", err)
 		}
+		if payload.IsPatch() {
+			return r.patchExistingObject(instance, payload, obj, found, objectLogger)
+		}
 		return r.patchRemediation(obj, objectLogger)
 	}
 	err = r.setRemediations(instance, objectLogger, false)
 	if err != nil {
 		return fmt.Errorf("failed to set related remediations to unapply: %w", err)
 	}
+	if payload.IsPatch() {
+		// The object isn't the operator's to delete, so only the fields
+		// the remediation touched are restored
+		if err := r.restorePreImage(instance, found, objectLogger); err != nil {
+			return err
+		}
+		instance.Status.PreImage = nil
+		return nil
+	}
 	return r.deleteRemediation(obj, found, objectLogger)
 }
 
@@ -274,12 +382,19 @@ func (r *ReconcileComplianceRemediation) setRemediations(instance *compv1alpha1.
 			continue
 		}
 
+		// Grouped remediations are related through their group. Otherwise,
 		// filter the name of the remediation, to take out the "-number" suffix
-		reg := regexp.MustCompile(remediationSuffixRegex)
-		filteredName := reg.ReplaceAllString(remName, "${1}")
-		filterdInstanceName := reg.ReplaceAllString(instance.Name, "${1}")
+		var related bool
+		if instance.GetGroup() != "" {
+			related = remediation.GetGroup() == instance.GetGroup()
+		} else {
+			reg := regexp.MustCompile(remediationSuffixRegex)
+			filteredName := reg.ReplaceAllString(remName, "${1}")
+			filterdInstanceName := reg.ReplaceAllString(instance.Name, "${1}")
+			related = filteredName == filterdInstanceName
+		}
 
-		if filteredName == filterdInstanceName {
+		if related {
 			if remediation.Spec.Apply != apply {
 				remediation.Spec.Apply = apply
 				err = r.Client.Update(context.TODO(), &remediation)
@@ -327,6 +442,9 @@ func (r *ReconcileComplianceRemediation) patchRemediation(remObj *unstructured.U
 
 func (r *ReconcileComplianceRemediation) deleteRemediation(remObj *unstructured.Unstructured, foundObj *unstructured.Unstructured, logger logr.Logger) error {
 
+	// Several remediations are merged into the same KubeletConfig, so one
+	// of them can't be unapplied on its own. Rolling back a suite's batch
+	// of remediations restores the whole KubeletConfig instead.
 	if utils.IsKubeletConfig(remObj) {
 		logger.Info("Can't unapply since it is KubeletConfig Remediation")
 		return nil
@@ -634,6 +752,9 @@ func (r *ReconcileComplianceRemediation) reconcileRemediationStatus(instance *co
 	instanceCopy := instance.DeepCopy()
 	logger.Info("Updating status of remediation")
 	r.setRemediationStatus(instanceCopy, errorApplying, logger)
+	instanceCopy.Status.Enforcement = r.getEnforcementStatus(instanceCopy, logger)
+	// The preview is only kept while the remediation is being previewed
+	instanceCopy.Status.Preview = nil
 
 	if err := r.Client.Status().Update(context.TODO(), instanceCopy); err != nil {
 		// metric remediation error
@@ -728,7 +849,7 @@ func (r *ReconcileComplianceRemediation) verifyAndCompleteKC(obj *unstructured.U
 	}
 
 	// We will need to create a kubelet config if there is no custom KC
-	kubeletName := "compliance-operator-kubelet-" + pool.GetName()
+	kubeletName := utils.OperatorKubeletConfigPrefix + pool.GetName()
 
 	// Set kubelet config name
 	obj.SetName(kubeletName)
@@ -771,6 +892,8 @@ func isNoLongerOutdated(r *compv1alpha1.ComplianceRemediation) bool {
 }
 
 func (r *ReconcileComplianceRemediation) setRemediationStatus(rem *compv1alpha1.ComplianceRemediation, errorApplying error, logger logr.Logger) {
+	rem.Status.RollbackReason = ""
+	rem.Status.Drift = nil
 	if errorApplying != nil {
 		if wasErrorOnOptionalRemediation(rem, errorApplying) {
 			logger.Info("Optional remediation couldn't be applied")
@@ -786,6 +909,12 @@ func (r *ReconcileComplianceRemediation) setRemediationStatus(rem *compv1alpha1.
 	}
 
 	if !rem.Spec.Apply {
+		if reason, ok := rem.GetAnnotations()[compv1alpha1.RemediationRolledBackAnnotation]; ok {
+			logger.Info("Remediation was rolled back")
+			rem.Status.ApplicationState = compv1alpha1.RemediationRolledBack
+			rem.Status.RollbackReason = reason
+			return
+		}
 		logger.Info("Remediation will now be unapplied")
 		rem.Status.ApplicationState = compv1alpha1.RemediationNotApplied
 		return
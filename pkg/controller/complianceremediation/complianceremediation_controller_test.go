@@ -18,12 +18,17 @@ import (
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
@@ -881,5 +886,1062 @@ var _ = Describe("Testing complianceremediation controller", func() {
 				})
 			})
 		})
+
+		Context("with a rolled back remediation", func() {
+			BeforeEach(func() {
+				remediationinstance.Annotations = map[string]string{
+					compv1alpha1.RemediationRolledBackAnnotation: "The MachineConfigPool worker is degraded",
+				}
+				err := reconciler.Client.Update(context.TODO(), remediationinstance)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should report the rollback reason", func() {
+				err := reconciler.reconcileRemediationStatus(remediationinstance, logger, nil)
+				Expect(err).To(BeNil())
+
+				found := &compv1alpha1.ComplianceRemediation{}
+				err = reconciler.Client.Get(context.TODO(), types.NamespacedName{Name: remediationinstance.Name, Namespace: remediationinstance.Namespace}, found)
+				Expect(err).To(BeNil())
+				Expect(found.Status.ApplicationState).To(Equal(compv1alpha1.RemediationRolledBack))
+				Expect(found.Status.RollbackReason).To(Equal("The MachineConfigPool worker is degraded"))
+
+				By("applying the remediation again")
+				found.Spec.Apply = true
+				err = reconciler.Client.Update(context.TODO(), found)
+				Expect(err).To(BeNil())
+				err = reconciler.reconcileRemediationStatus(found, logger, nil)
+				Expect(err).To(BeNil())
+				err = reconciler.Client.Get(context.TODO(), types.NamespacedName{Name: remediationinstance.Name, Namespace: remediationinstance.Namespace}, found)
+				Expect(err).To(BeNil())
+				Expect(found.Status.ApplicationState).To(Equal(compv1alpha1.RemediationApplied))
+				Expect(found.Status.RollbackReason).To(BeEmpty())
+			})
+		})
+	})
+
+	Context("previewing remediations", func() {
+		var remKey types.NamespacedName
+
+		BeforeEach(func() {
+			remKey = types.NamespacedName{Name: remediationinstance.Name}
+			remediationinstance.Spec.Apply = true
+			remediationinstance.Annotations = map[string]string{
+				compv1alpha1.RemediationPreviewAnnotation: "",
+			}
+			cm := &corev1.ConfigMap{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "ConfigMap",
+					APIVersion: "v1",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-cm",
+					Namespace: "test-ns",
+				},
+				Data: map[string]string{
+					"key":     "val",
+					"new-key": "new-val",
+				},
+			}
+			unstructuredCM, err := runtime.DefaultUnstructuredConverter.ToUnstructured(cm)
+			Expect(err).ToNot(HaveOccurred())
+			remediationinstance.Spec.Current.Object = &unstructured.Unstructured{
+				Object: unstructuredCM,
+			}
+			err = reconciler.Client.Update(context.TODO(), remediationinstance)
+			Expect(err).NotTo(HaveOccurred())
+			remediationinstance.Status.ApplicationState = compv1alpha1.RemediationNotApplied
+			err = reconciler.Client.Status().Update(context.TODO(), remediationinstance)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should record the changes to the live object without applying them", func() {
+			liveCM := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-cm",
+					Namespace: "test-ns",
+				},
+				Data: map[string]string{
+					"key": "old-val",
+				},
+			}
+			err := reconciler.Client.Create(context.TODO(), liveCM)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: remKey})
+			Expect(err).To(BeNil())
+
+			By("the remediation status should hold the diff")
+			found := &compv1alpha1.ComplianceRemediation{}
+			err = reconciler.Client.Get(context.TODO(), remKey, found)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found.Status.ApplicationState).To(Equal(compv1alpha1.RemediationNotApplied))
+			Expect(found.Status.Preview).ToNot(BeNil())
+			Expect(found.Status.Preview.ErrorMessage).To(BeEmpty())
+			Expect(found.Status.Preview.Changes).To(Equal([]compv1alpha1.RemediationFieldChange{
+				{Path: ".data.key", Current: `"old-val"`, Desired: `"val"`},
+				{Path: ".data.new-key", Desired: `"new-val"`},
+			}))
+
+			By("the object should be left untouched")
+			foundCM := &corev1.ConfigMap{}
+			err = reconciler.Client.Get(context.TODO(), types.NamespacedName{Name: "my-cm", Namespace: "test-ns"}, foundCM)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(foundCM.Data).To(Equal(map[string]string{"key": "old-val"}))
+
+			By("removing the preview annotation the remediation gets applied")
+			delete(found.Annotations, compv1alpha1.RemediationPreviewAnnotation)
+			err = reconciler.Client.Update(context.TODO(), found)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: remKey})
+			Expect(err).To(BeNil())
+
+			err = reconciler.Client.Get(context.TODO(), remKey, found)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found.Status.ApplicationState).To(Equal(compv1alpha1.RemediationApplied))
+			Expect(found.Status.Preview).To(BeNil())
+			err = reconciler.Client.Get(context.TODO(), types.NamespacedName{Name: "my-cm", Namespace: "test-ns"}, foundCM)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(foundCM.Data["key"]).To(Equal("val"))
+		})
+
+		It("should preview the creation of a missing object", func() {
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: remKey})
+			Expect(err).To(BeNil())
+
+			found := &compv1alpha1.ComplianceRemediation{}
+			err = reconciler.Client.Get(context.TODO(), remKey, found)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found.Status.Preview).ToNot(BeNil())
+			paths := []string{}
+			for _, change := range found.Status.Preview.Changes {
+				Expect(change.Current).To(BeEmpty())
+				paths = append(paths, change.Path)
+			}
+			Expect(paths).To(ContainElements(".data.key", ".data.new-key", ".metadata.name", ".kind"))
+
+			foundCM := &corev1.ConfigMap{}
+			err = reconciler.Client.Get(context.TODO(), types.NamespacedName{Name: "my-cm", Namespace: "test-ns"}, foundCM)
+			Expect(kerrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should flag fields owned by other managers as conflicts", func() {
+			err := kerrors.NewApplyConflict([]metav1.StatusCause{
+				{
+					Type:    metav1.CauseTypeFieldManagerConflict,
+					Field:   ".data.key",
+					Message: `conflict with "kubectl-edit" using v1`,
+				},
+			}, "Apply failed with 1 conflict")
+			Expect(conflictsFromError(err)).To(Equal([]compv1alpha1.RemediationFieldConflict{
+				{Path: ".data.key", Message: `conflict with "kubectl-edit" using v1`},
+			}))
+		})
+
+		It("should ignore server-managed fields in the diff", func() {
+			live := map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"name":            "my-cm",
+					"resourceVersion": "12",
+					"managedFields":   []interface{}{"manager"},
+				},
+				"spec": map[string]interface{}{
+					"list": []interface{}{"a", "b"},
+				},
+				"status": map[string]interface{}{"ready": true},
+			}
+			desired := map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"name":            "my-cm",
+					"resourceVersion": "13",
+				},
+				"spec": map[string]interface{}{
+					"list": []interface{}{"a"},
+				},
+			}
+			Expect(diffObjects(live, desired)).To(Equal([]compv1alpha1.RemediationFieldChange{
+				{Path: ".spec.list", Current: `["a","b"]`, Desired: `["a"]`},
+			}))
+		})
+	})
+
+	Context("detecting remediation drift", func() {
+		var (
+			remKey   types.NamespacedName
+			cmKey    = types.NamespacedName{Name: "my-cm", Namespace: "test-ns"}
+			recorder *record.FakeRecorder
+		)
+
+		getRemediation := func() *compv1alpha1.ComplianceRemediation {
+			found := &compv1alpha1.ComplianceRemediation{}
+			err := reconciler.Client.Get(context.TODO(), remKey, found)
+			Expect(err).NotTo(HaveOccurred())
+			return found
+		}
+
+		BeforeEach(func() {
+			recorder = record.NewFakeRecorder(10)
+			reconciler.Recorder = recorder
+			remKey = types.NamespacedName{Name: remediationinstance.Name}
+			remediationinstance.Spec.Apply = true
+			remediationinstance.Annotations = map[string]string{}
+			cm := &corev1.ConfigMap{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "ConfigMap",
+					APIVersion: "v1",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      cmKey.Name,
+					Namespace: cmKey.Namespace,
+				},
+				Data: map[string]string{
+					"key": "val",
+				},
+			}
+			unstructuredCM, err := runtime.DefaultUnstructuredConverter.ToUnstructured(cm)
+			Expect(err).ToNot(HaveOccurred())
+			remediationinstance.Spec.Current.Object = &unstructured.Unstructured{
+				Object: unstructuredCM,
+			}
+			err = reconciler.Client.Update(context.TODO(), remediationinstance)
+			Expect(err).NotTo(HaveOccurred())
+			remediationinstance.Status.ApplicationState = compv1alpha1.RemediationApplied
+			err = reconciler.Client.Status().Update(context.TODO(), remediationinstance)
+			Expect(err).NotTo(HaveOccurred())
+
+			liveCM := cm.DeepCopy()
+			liveCM.Data = map[string]string{"key": "edited", "other": "kept"}
+			compv1alpha1.AddRemediationAnnotation(liveCM)
+			err = reconciler.Client.Create(context.TODO(), liveCM)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should mark the edited object as drifted", func() {
+			res, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: remKey})
+			Expect(err).To(BeNil())
+			Expect(res.RequeueAfter).To(Equal(driftCheckInterval))
+
+			found := getRemediation()
+			Expect(found.Status.ApplicationState).To(Equal(compv1alpha1.RemediationDrifted))
+			Expect(found.IsApplied()).To(BeTrue())
+			Expect(found.Status.Drift).ToNot(BeNil())
+			Expect(found.Status.Drift.Changes).To(Equal([]compv1alpha1.RemediationFieldChange{
+				{Path: ".data.key", Current: `"edited"`, Desired: `"val"`},
+			}))
+			Expect(recorder.Events).To(Receive(ContainSubstring("RemediationDrifted")))
+
+			By("leaving the object as it is")
+			foundCM := &corev1.ConfigMap{}
+			err = reconciler.Client.Get(context.TODO(), cmKey, foundCM)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(foundCM.Data["key"]).To(Equal("edited"))
+
+			By("clearing the drift once the object matches again")
+			foundCM.Data["key"] = "val"
+			err = reconciler.Client.Update(context.TODO(), foundCM)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: remKey})
+			Expect(err).To(BeNil())
+
+			found = getRemediation()
+			Expect(found.Status.ApplicationState).To(Equal(compv1alpha1.RemediationApplied))
+			Expect(found.Status.Drift).To(BeNil())
+		})
+
+		It("should mark the deleted object as drifted", func() {
+			foundCM := &corev1.ConfigMap{}
+			err := reconciler.Client.Get(context.TODO(), cmKey, foundCM)
+			Expect(err).NotTo(HaveOccurred())
+			err = reconciler.Client.Delete(context.TODO(), foundCM)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: remKey})
+			Expect(err).To(BeNil())
+
+			found := getRemediation()
+			Expect(found.Status.ApplicationState).To(Equal(compv1alpha1.RemediationDrifted))
+			Expect(found.Status.Drift.Changes).To(ContainElement(compv1alpha1.RemediationFieldChange{
+				Path: ".data.key", Desired: `"val"`,
+			}))
+		})
+
+		It("should re-apply the drifted object if the suite says so", func() {
+			suite := &compv1alpha1.ComplianceSuite{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "mySuite",
+				},
+				Spec: compv1alpha1.ComplianceSuiteSpec{
+					ComplianceSuiteSettings: compv1alpha1.ComplianceSuiteSettings{
+						ReapplyDriftedRemediations: true,
+					},
+				},
+			}
+			err := reconciler.Client.Create(context.TODO(), suite)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: remKey})
+			Expect(err).To(BeNil())
+
+			found := getRemediation()
+			Expect(found.Status.ApplicationState).To(Equal(compv1alpha1.RemediationApplied))
+			Expect(recorder.Events).To(Receive(ContainSubstring("RemediationReapplied")))
+
+			foundCM := &corev1.ConfigMap{}
+			err = reconciler.Client.Get(context.TODO(), cmKey, foundCM)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(foundCM.Data).To(Equal(map[string]string{"key": "val", "other": "kept"}))
+		})
+
+		It("should enqueue the remediation of a created MachineConfig", func() {
+			mcRem := remediationinstance.DeepCopy()
+			mcRem.Spec.Current.Object = &unstructured.Unstructured{}
+			mcRem.Spec.Current.Object.SetAPIVersion("machineconfiguration.openshift.io/v1")
+			mcRem.Spec.Current.Object.SetKind("MachineConfig")
+			err := reconciler.Client.Update(context.TODO(), mcRem)
+			Expect(err).NotTo(HaveOccurred())
+
+			mc := &mcfgv1.MachineConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "75-testRem",
+				},
+			}
+			mapper := &createdObjectMapper{reconciler.Client}
+			Expect(mapper.MapMachineConfig(context.TODO(), mc)).To(BeEmpty())
+
+			compv1alpha1.AddRemediationAnnotation(mc)
+			requests := mapper.MapMachineConfig(context.TODO(), mc)
+			Expect(requests).To(HaveLen(1))
+			Expect(requests[0].NamespacedName).To(Equal(remKey))
+		})
+	})
+
+	Context("applying patch remediations", func() {
+		var (
+			remKey types.NamespacedName
+			cmKey  = types.NamespacedName{Name: "my-cm", Namespace: "test-ns"}
+		)
+
+		getRemediation := func() *compv1alpha1.ComplianceRemediation {
+			found := &compv1alpha1.ComplianceRemediation{}
+			err := reconciler.Client.Get(context.TODO(), remKey, found)
+			Expect(err).NotTo(HaveOccurred())
+			return found
+		}
+
+		getCMData := func() map[string]string {
+			foundCM := &corev1.ConfigMap{}
+			err := reconciler.Client.Get(context.TODO(), cmKey, foundCM)
+			Expect(err).NotTo(HaveOccurred())
+			return foundCM.Data
+		}
+
+		setPayload := func(payload compv1alpha1.ComplianceRemediationPayload) {
+			remediationinstance.Spec.Current = payload
+			err := reconciler.Client.Update(context.TODO(), remediationinstance)
+			Expect(err).NotTo(HaveOccurred())
+			remediationinstance.Status.ApplicationState = compv1alpha1.RemediationNotApplied
+			err = reconciler.Client.Status().Update(context.TODO(), remediationinstance)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		unapply := func() {
+			found := getRemediation()
+			found.Spec.Apply = false
+			err := reconciler.Client.Update(context.TODO(), found)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: remKey})
+			Expect(err).To(BeNil())
+		}
+
+		cmStub := func() *unstructured.Unstructured {
+			stub := &unstructured.Unstructured{}
+			stub.SetAPIVersion("v1")
+			stub.SetKind("ConfigMap")
+			stub.SetName(cmKey.Name)
+			stub.SetNamespace(cmKey.Namespace)
+			return stub
+		}
+
+		BeforeEach(func() {
+			reconciler.Recorder = record.NewFakeRecorder(10)
+			remKey = types.NamespacedName{Name: remediationinstance.Name}
+			remediationinstance.Spec.Apply = true
+			remediationinstance.Annotations = map[string]string{}
+
+			liveCM := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      cmKey.Name,
+					Namespace: cmKey.Namespace,
+				},
+				Data: map[string]string{
+					"key":   "old-val",
+					"other": "kept",
+				},
+			}
+			err := reconciler.Client.Create(context.TODO(), liveCM)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should restore the fields of a strategic merge patch on unapply", func() {
+			patchObj := cmStub()
+			err := unstructured.SetNestedStringMap(patchObj.Object, map[string]string{
+				"key":   "val",
+				"added": "new-val",
+			}, "data")
+			Expect(err).NotTo(HaveOccurred())
+			setPayload(compv1alpha1.ComplianceRemediationPayload{
+				Object:    patchObj,
+				PatchType: compv1alpha1.StrategicMergePatchRemediation,
+			})
+
+			_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: remKey})
+			Expect(err).To(BeNil())
+
+			found := getRemediation()
+			Expect(found.Status.ApplicationState).To(Equal(compv1alpha1.RemediationApplied))
+			Expect(found.Status.PreImage).To(Equal([]compv1alpha1.RemediationFieldValue{
+				{Path: "/data/added"},
+				{Path: "/data/key", Value: `"old-val"`},
+			}))
+			Expect(getCMData()).To(Equal(map[string]string{"key": "val", "added": "new-val", "other": "kept"}))
+
+			By("un-applying the remediation")
+			unapply()
+			found = getRemediation()
+			Expect(found.Status.ApplicationState).To(Equal(compv1alpha1.RemediationNotApplied))
+			Expect(found.Status.PreImage).To(BeNil())
+			Expect(getCMData()).To(Equal(map[string]string{"key": "old-val", "other": "kept"}))
+		})
+
+		It("should restore the fields of a JSON patch on unapply", func() {
+			setPayload(compv1alpha1.ComplianceRemediationPayload{
+				Object:    cmStub(),
+				PatchType: compv1alpha1.JSONPatchRemediation,
+				JSONPatch: []compv1alpha1.JSONPatchOperation{
+					{Op: "replace", Path: "/data/key", Value: `"val"`},
+					{Op: "remove", Path: "/data/other"},
+				},
+			})
+
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: remKey})
+			Expect(err).To(BeNil())
+
+			found := getRemediation()
+			Expect(found.Status.ApplicationState).To(Equal(compv1alpha1.RemediationApplied))
+			Expect(found.Status.PreImage).To(Equal([]compv1alpha1.RemediationFieldValue{
+				{Path: "/data/key", Value: `"old-val"`},
+				{Path: "/data/other", Value: `"kept"`},
+			}))
+			Expect(getCMData()).To(Equal(map[string]string{"key": "val"}))
+
+			By("keeping the pre-image when the remediation is reconciled again")
+			_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: remKey})
+			Expect(err).To(BeNil())
+			Expect(getRemediation().Status.PreImage).To(HaveLen(2))
+
+			By("un-applying the remediation")
+			unapply()
+			Expect(getRemediation().Status.PreImage).To(BeNil())
+			Expect(getCMData()).To(Equal(map[string]string{"key": "old-val", "other": "kept"}))
+		})
+
+		It("should report an error if the object to patch doesn't exist", func() {
+			stub := cmStub()
+			stub.SetName("missing-cm")
+			setPayload(compv1alpha1.ComplianceRemediationPayload{
+				Object:    stub,
+				PatchType: compv1alpha1.JSONPatchRemediation,
+				JSONPatch: []compv1alpha1.JSONPatchOperation{
+					{Op: "replace", Path: "/data/key", Value: `"val"`},
+				},
+			})
+
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: remKey})
+			Expect(err).To(BeNil())
+
+			found := getRemediation()
+			Expect(found.Status.ApplicationState).To(Equal(compv1alpha1.RemediationError))
+			Expect(found.Status.ErrorMessage).To(ContainSubstring("the object doesn't exist"))
+		})
+
+		It("should detect the drift of a JSON patch", func() {
+			setPayload(compv1alpha1.ComplianceRemediationPayload{
+				Object:    cmStub(),
+				PatchType: compv1alpha1.JSONPatchRemediation,
+				JSONPatch: []compv1alpha1.JSONPatchOperation{
+					{Op: "replace", Path: "/data/key", Value: `"val"`},
+				},
+			})
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: remKey})
+			Expect(err).To(BeNil())
+			Expect(getRemediation().Status.ApplicationState).To(Equal(compv1alpha1.RemediationApplied))
+
+			liveCM := &corev1.ConfigMap{}
+			err = reconciler.Client.Get(context.TODO(), cmKey, liveCM)
+			Expect(err).NotTo(HaveOccurred())
+			liveCM.Data["key"] = "edited"
+			err = reconciler.Client.Update(context.TODO(), liveCM)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: remKey})
+			Expect(err).To(BeNil())
+			found := getRemediation()
+			Expect(found.Status.ApplicationState).To(Equal(compv1alpha1.RemediationDrifted))
+			Expect(found.Status.Drift.Changes).To(Equal([]compv1alpha1.RemediationFieldChange{
+				{Path: ".data.key", Current: `"edited"`, Desired: `"val"`},
+			}))
+		})
+
+		It("should preview a patch without applying it", func() {
+			remediationinstance.Annotations = map[string]string{compv1alpha1.RemediationPreviewAnnotation: ""}
+			setPayload(compv1alpha1.ComplianceRemediationPayload{
+				Object:    cmStub(),
+				PatchType: compv1alpha1.JSONPatchRemediation,
+				JSONPatch: []compv1alpha1.JSONPatchOperation{
+					{Op: "replace", Path: "/data/key", Value: `"val"`},
+				},
+			})
+
+			// The fake client ignores dry-run patches, so they're applied to
+			// a copy of the object in a scratch client instead
+			reconciler.Client = interceptor.NewClient(reconciler.Client.(client.WithWatch), interceptor.Funcs{
+				Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+					scratch := fake.NewClientBuilder().WithScheme(c.Scheme()).WithObjects(obj.DeepCopyObject().(client.Object)).Build()
+					return scratch.Patch(ctx, obj, patch)
+				},
+			})
+
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: remKey})
+			Expect(err).To(BeNil())
+
+			found := getRemediation()
+			Expect(found.Status.Preview).ToNot(BeNil())
+			Expect(found.Status.Preview.ErrorMessage).To(BeEmpty())
+			Expect(found.Status.Preview.Changes).To(Equal([]compv1alpha1.RemediationFieldChange{
+				{Path: ".data.key", Current: `"old-val"`, Desired: `"val"`},
+			}))
+			Expect(found.Status.PreImage).To(BeNil())
+			Expect(getCMData()).To(Equal(map[string]string{"key": "old-val", "other": "kept"}))
+		})
+	})
+
+	Context("applying grouped remediations", func() {
+		var (
+			firstKey  types.NamespacedName
+			secondKey = types.NamespacedName{Name: "testRem-1"}
+		)
+
+		cmKey := func(name string) types.NamespacedName {
+			return types.NamespacedName{Name: name, Namespace: "test-ns"}
+		}
+
+		newCMObject := func(name string) *unstructured.Unstructured {
+			obj := &unstructured.Unstructured{}
+			obj.SetAPIVersion("v1")
+			obj.SetKind("ConfigMap")
+			obj.SetName(name)
+			obj.SetNamespace("test-ns")
+			return obj
+		}
+
+		cmExists := func(name string) bool {
+			err := reconciler.Client.Get(context.TODO(), cmKey(name), &corev1.ConfigMap{})
+			if kerrors.IsNotFound(err) {
+				return false
+			}
+			Expect(err).NotTo(HaveOccurred())
+			return true
+		}
+
+		getRem := func(key types.NamespacedName) *compv1alpha1.ComplianceRemediation {
+			found := &compv1alpha1.ComplianceRemediation{}
+			err := reconciler.Client.Get(context.TODO(), key, found)
+			Expect(err).NotTo(HaveOccurred())
+			return found
+		}
+
+		reconcileRem := func(key types.NamespacedName) reconcile.Result {
+			res, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			Expect(err).To(BeNil())
+			return res
+		}
+
+		BeforeEach(func() {
+			firstKey = types.NamespacedName{Name: remediationinstance.Name}
+			remediationinstance.Annotations = map[string]string{}
+			remediationinstance.Labels[compv1alpha1.RemediationGroupLabel] = remediationinstance.Name
+			remediationinstance.Spec.Current.Object = newCMObject("first-cm")
+			err := reconciler.Client.Update(context.TODO(), remediationinstance)
+			Expect(err).NotTo(HaveOccurred())
+			remediationinstance.Status.ApplicationState = compv1alpha1.RemediationNotApplied
+			err = reconciler.Client.Status().Update(context.TODO(), remediationinstance)
+			Expect(err).NotTo(HaveOccurred())
+
+			second := remediationinstance.DeepCopy()
+			second.ObjectMeta = metav1.ObjectMeta{
+				Name:   secondKey.Name,
+				Labels: remediationinstance.Labels,
+			}
+			second.Spec.Current.Object = newCMObject("second-cm")
+			err = reconciler.Client.Create(context.TODO(), second)
+			Expect(err).NotTo(HaveOccurred())
+			second.Status.ApplicationState = compv1alpha1.RemediationNotApplied
+			err = reconciler.Client.Status().Update(context.TODO(), second)
+			Expect(err).NotTo(HaveOccurred())
+
+			group := &compv1alpha1.ComplianceRemediationGroup{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: remediationinstance.Name,
+				},
+				Spec: compv1alpha1.ComplianceRemediationGroupSpec{
+					Members: []compv1alpha1.RemediationGroupMember{
+						{Name: firstKey.Name, Order: 0},
+						{Name: secondKey.Name, Order: 1},
+					},
+				},
+			}
+			err = reconciler.Client.Create(context.TODO(), group)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should apply the members in order", func() {
+			second := getRem(secondKey)
+			second.Spec.Apply = true
+			err := reconciler.Client.Update(context.TODO(), second)
+			Expect(err).NotTo(HaveOccurred())
+
+			By("waiting for the first member")
+			res := reconcileRem(secondKey)
+			Expect(res.RequeueAfter).To(Equal(groupOrderRequeueTime))
+			Expect(cmExists("second-cm")).To(BeFalse())
+			Expect(getRem(firstKey).Spec.Apply).To(BeTrue())
+
+			By("applying the first member")
+			reconcileRem(firstKey)
+			Expect(getRem(firstKey).Status.ApplicationState).To(Equal(compv1alpha1.RemediationApplied))
+			Expect(cmExists("first-cm")).To(BeTrue())
+
+			By("applying the second member")
+			reconcileRem(secondKey)
+			Expect(getRem(secondKey).Status.ApplicationState).To(Equal(compv1alpha1.RemediationApplied))
+			Expect(cmExists("second-cm")).To(BeTrue())
+		})
+
+		It("should un-apply the members in reverse order", func() {
+			for _, key := range []types.NamespacedName{firstKey, secondKey} {
+				rem := getRem(key)
+				rem.Spec.Apply = true
+				err := reconciler.Client.Update(context.TODO(), rem)
+				Expect(err).NotTo(HaveOccurred())
+				reconcileRem(key)
+			}
+			Expect(cmExists("first-cm")).To(BeTrue())
+			Expect(cmExists("second-cm")).To(BeTrue())
+
+			first := getRem(firstKey)
+			first.Spec.Apply = false
+			err := reconciler.Client.Update(context.TODO(), first)
+			Expect(err).NotTo(HaveOccurred())
+
+			By("waiting for the second member")
+			res := reconcileRem(firstKey)
+			Expect(res.RequeueAfter).To(Equal(groupOrderRequeueTime))
+			Expect(cmExists("first-cm")).To(BeTrue())
+			Expect(getRem(secondKey).Spec.Apply).To(BeFalse())
+
+			By("un-applying the second member first")
+			reconcileRem(secondKey)
+			Expect(cmExists("second-cm")).To(BeFalse())
+			reconcileRem(firstKey)
+			Expect(cmExists("first-cm")).To(BeFalse())
+			Expect(getRem(firstKey).Status.ApplicationState).To(Equal(compv1alpha1.RemediationNotApplied))
+		})
+	})
+
+	Context("resolving values from a remediation values ConfigMap", func() {
+		var (
+			remKey = types.NamespacedName{Name: "sshd-idle-timeout", Namespace: "test-ns"}
+			cmKey  = types.NamespacedName{Name: "sshd-config", Namespace: "test-ns"}
+		)
+
+		const fix = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: sshd-config
+  namespace: test-ns
+data:
+  timeout: "{{.var_sshd_idle_timeout}}"
+  keepalive: "{{.var_sshd_keepalive}}"
+`
+
+		getRemediation := func() *compv1alpha1.ComplianceRemediation {
+			found := &compv1alpha1.ComplianceRemediation{}
+			err := reconciler.Client.Get(context.TODO(), remKey, found)
+			Expect(err).NotTo(HaveOccurred())
+			return found
+		}
+
+		reconcileRem := func() {
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: remKey})
+			Expect(err).To(BeNil())
+		}
+
+		setValues := func(data map[string]string) {
+			valuesCM := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "remediation-values",
+					Namespace: remKey.Namespace,
+					Labels: map[string]string{
+						compv1alpha1.RemediationValuesLabel: "",
+					},
+				},
+			}
+			err := reconciler.Client.Get(context.TODO(), client.ObjectKeyFromObject(valuesCM), valuesCM)
+			if kerrors.IsNotFound(err) {
+				valuesCM.Data = data
+				err = reconciler.Client.Create(context.TODO(), valuesCM)
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+				valuesCM.Data = data
+				err = reconciler.Client.Update(context.TODO(), valuesCM)
+			}
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		getCMData := func() map[string]string {
+			foundCM := &corev1.ConfigMap{}
+			err := reconciler.Client.Get(context.TODO(), cmKey, foundCM)
+			Expect(err).NotTo(HaveOccurred())
+			return foundCM.Data
+		}
+
+		BeforeEach(func() {
+			reconciler.Recorder = record.NewFakeRecorder(10)
+
+			// What the parser creates from the fix with the keepalive
+			// value set by the scan, but not the timeout
+			obj := &unstructured.Unstructured{}
+			obj.SetAPIVersion("v1")
+			obj.SetKind("ConfigMap")
+			obj.SetName(cmKey.Name)
+			obj.SetNamespace(cmKey.Namespace)
+			err := unstructured.SetNestedStringMap(obj.Object, map[string]string{
+				"timeout":   "",
+				"keepalive": "3",
+			}, "data")
+			Expect(err).NotTo(HaveOccurred())
+
+			rem := &compv1alpha1.ComplianceRemediation{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      remKey.Name,
+					Namespace: remKey.Namespace,
+					Labels: map[string]string{
+						compv1alpha1.RemediationUnsetValueLabel: "",
+					},
+					Annotations: map[string]string{
+						compv1alpha1.RemediationUnsetValueAnnotation: "var-sshd-idle-timeout",
+						compv1alpha1.RemediationValueUsedAnnotation:  "var-sshd-keepalive",
+					},
+				},
+				Spec: compv1alpha1.ComplianceRemediationSpec{
+					ComplianceRemediationSpecMeta: compv1alpha1.ComplianceRemediationSpecMeta{
+						Apply: true,
+						Type:  compv1alpha1.ConfigurationRemediation,
+					},
+					Current: compv1alpha1.ComplianceRemediationPayload{
+						Object: obj,
+						Template: &compv1alpha1.RemediationTemplate{
+							Content: fix,
+							Values: map[string]string{
+								"var_sshd_keepalive": "3",
+							},
+						},
+					},
+				},
+			}
+			err = reconciler.Client.Create(context.TODO(), rem)
+			Expect(err).NotTo(HaveOccurred())
+			rem.Status.ApplicationState = compv1alpha1.RemediationNeedsReview
+			err = reconciler.Client.Status().Update(context.TODO(), rem)
+			Expect(err).NotTo(HaveOccurred())
+
+			variable := &compv1alpha1.Variable{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "rhcos4-var-sshd-idle-timeout",
+					Namespace: remKey.Namespace,
+				},
+				VariablePayload: compv1alpha1.VariablePayload{
+					ID:   "xccdf_org.ssgproject.content_value_var_sshd_idle_timeout",
+					Type: compv1alpha1.VarTypeNumber,
+					Selections: []compv1alpha1.ValueSelection{
+						{Description: "5_minutes", Value: "300"},
+						{Description: "10_minutes", Value: "600"},
+					},
+				},
+			}
+			err = reconciler.Client.Create(context.TODO(), variable)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should stay in review while the value isn't provided", func() {
+			setValues(map[string]string{"var-other": "1"})
+			reconcileRem()
+
+			found := getRemediation()
+			Expect(found.Status.ApplicationState).To(Equal(compv1alpha1.RemediationNeedsReview))
+			Expect(found.GetAnnotations()).To(HaveKeyWithValue(compv1alpha1.RemediationUnsetValueAnnotation, "var-sshd-idle-timeout"))
+			Expect(found.GetAnnotations()).NotTo(HaveKey(compv1alpha1.RemediationSourcedValueAnnotation))
+		})
+
+		It("should render the remediation with the provided value and apply it", func() {
+			setValues(map[string]string{"var-sshd-idle-timeout": "300"})
+
+			By("rendering the remediation again")
+			reconcileRem()
+			found := getRemediation()
+			data, _, err := unstructured.NestedStringMap(found.Spec.Current.Object.Object, "data")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(data).To(Equal(map[string]string{"timeout": "300", "keepalive": "3"}))
+			Expect(found.Spec.Current.Template).NotTo(BeNil())
+			Expect(found.GetAnnotations()).NotTo(HaveKey(compv1alpha1.RemediationUnsetValueAnnotation))
+			Expect(found.GetAnnotations()).To(HaveKeyWithValue(compv1alpha1.RemediationSourcedValueAnnotation, "var-sshd-idle-timeout"))
+			Expect(found.GetAnnotations()).To(HaveKeyWithValue(compv1alpha1.RemediationValueUsedAnnotation, "var-sshd-keepalive,var-sshd-idle-timeout"))
+			Expect(found.GetLabels()).NotTo(HaveKey(compv1alpha1.RemediationUnsetValueLabel))
+
+			By("applying the rendered remediation")
+			reconcileRem()
+			Expect(getRemediation().Status.ApplicationState).To(Equal(compv1alpha1.RemediationApplied))
+			Expect(getCMData()).To(HaveKeyWithValue("timeout", "300"))
+
+			By("rendering the remediation again once the value changes")
+			setValues(map[string]string{"var_sshd_idle_timeout": "600"})
+			reconcileRem()
+			reconcileRem()
+			Expect(getCMData()).To(HaveKeyWithValue("timeout", "600"))
+		})
+
+		It("should reject the values that aren't valid for their variable", func() {
+			for _, value := range []string{"five minutes", "900"} {
+				setValues(map[string]string{"var-sshd-idle-timeout": value})
+				reconcileRem()
+
+				found := getRemediation()
+				Expect(found.Status.ApplicationState).To(Equal(compv1alpha1.RemediationNeedsReview))
+				Expect(found.Status.ErrorMessage).To(ContainSubstring("var-sshd-idle-timeout"))
+				Expect(found.GetAnnotations()).To(HaveKeyWithValue(compv1alpha1.RemediationUnsetValueAnnotation, "var-sshd-idle-timeout"))
+				Expect(found.GetAnnotations()).NotTo(HaveKey(compv1alpha1.RemediationSourcedValueAnnotation))
+			}
+
+			By("rendering the remediation once the value is valid")
+			setValues(map[string]string{"var-sshd-idle-timeout": "600"})
+			reconcileRem()
+			found := getRemediation()
+			Expect(found.Status.ErrorMessage).To(BeEmpty())
+			Expect(found.GetAnnotations()).To(HaveKeyWithValue(compv1alpha1.RemediationSourcedValueAnnotation, "var-sshd-idle-timeout"))
+		})
+
+		It("should enqueue the remediations that lack values when a values ConfigMap changes", func() {
+			mapper := &valuesMapper{reconciler.Client}
+			valuesCM := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "remediation-values",
+					Namespace: remKey.Namespace,
+					Labels: map[string]string{
+						compv1alpha1.RemediationValuesLabel: "",
+					},
+				},
+			}
+			requests := mapper.Map(context.TODO(), valuesCM)
+			Expect(requests).To(HaveLen(1))
+			Expect(requests[0].NamespacedName).To(Equal(remKey))
+
+			valuesCM.Labels = nil
+			Expect(mapper.Map(context.TODO(), valuesCM)).To(BeEmpty())
+		})
+	})
+
+	Context("applying enforcement remediations", func() {
+		var (
+			remKey        = types.NamespacedName{Name: "require-labels", Namespace: "test-ns"}
+			templateGVK   = schema.GroupVersionKind{Group: "templates.gatekeeper.sh", Version: "v1", Kind: "ConstraintTemplate"}
+			constraintGVK = schema.GroupVersionKind{Group: "constraints.gatekeeper.sh", Version: "v1beta1", Kind: "K8sRequiredLabels"}
+			policyGVK     = schema.GroupVersionKind{Group: "kyverno.io", Version: "v1", Kind: "ClusterPolicy"}
+			reportGVK     = schema.GroupVersionKind{Group: "wgpolicyk8s.io", Version: "v1alpha2", Kind: "PolicyReport"}
+			clusterRepGVK = schema.GroupVersionKind{Group: "wgpolicyk8s.io", Version: "v1alpha2", Kind: "ClusterPolicyReport"}
+		)
+
+		newObject := func(gvk schema.GroupVersionKind, name string) *unstructured.Unstructured {
+			obj := &unstructured.Unstructured{}
+			obj.SetGroupVersionKind(gvk)
+			obj.SetName(name)
+			return obj
+		}
+
+		// setup serves the given kinds and creates a remediation for obj
+		setup := func(obj *unstructured.Unstructured, mode compv1alpha1.RemediationEnforcementMode, served ...schema.GroupVersionKind) {
+			var groupVersions []schema.GroupVersion
+			for _, gvk := range served {
+				groupVersions = append(groupVersions, gvk.GroupVersion())
+			}
+			mapper := meta.NewDefaultRESTMapper(groupVersions)
+			for _, gvk := range served {
+				scope := meta.RESTScopeRoot
+				if gvk == reportGVK {
+					scope = meta.RESTScopeNamespace
+				}
+				mapper.Add(gvk, scope)
+			}
+
+			rem := &compv1alpha1.ComplianceRemediation{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      remKey.Name,
+					Namespace: remKey.Namespace,
+				},
+				Spec: compv1alpha1.ComplianceRemediationSpec{
+					ComplianceRemediationSpecMeta: compv1alpha1.ComplianceRemediationSpecMeta{
+						Apply:           true,
+						Type:            compv1alpha1.EnforcementRemediation,
+						EnforcementMode: mode,
+					},
+					Current: compv1alpha1.ComplianceRemediationPayload{
+						Object: obj,
+					},
+				},
+				Status: compv1alpha1.ComplianceRemediationStatus{
+					ApplicationState: compv1alpha1.RemediationNotApplied,
+				},
+			}
+			reconciler.Client = fake.NewClientBuilder().
+				WithScheme(reconciler.Scheme).
+				WithRESTMapper(mapper).
+				WithStatusSubresource(rem, newObject(constraintGVK, ""), newObject(policyGVK, "")).
+				WithRuntimeObjects(rem).
+				Build()
+			reconciler.Recorder = record.NewFakeRecorder(10)
+		}
+
+		reconcileRem := func() reconcile.Result {
+			res, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: remKey})
+			Expect(err).To(BeNil())
+			return res
+		}
+
+		getRemediation := func() *compv1alpha1.ComplianceRemediation {
+			found := &compv1alpha1.ComplianceRemediation{}
+			err := reconciler.Client.Get(context.TODO(), remKey, found)
+			Expect(err).NotTo(HaveOccurred())
+			return found
+		}
+
+		getLive := func(gvk schema.GroupVersionKind, name string) *unstructured.Unstructured {
+			live := newObject(gvk, name)
+			err := reconciler.Client.Get(context.TODO(), client.ObjectKeyFromObject(live), live)
+			Expect(err).NotTo(HaveOccurred())
+			return live
+		}
+
+		setLiveStatus := func(gvk schema.GroupVersionKind, name string, status map[string]interface{}) {
+			live := getLive(gvk, name)
+			err := unstructured.SetNestedField(live.Object, status, "status")
+			Expect(err).NotTo(HaveOccurred())
+			err = reconciler.Client.Status().Update(context.TODO(), live)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		It("should fail if Gatekeeper isn't installed", func() {
+			setup(newObject(templateGVK, "k8srequiredlabels"), "")
+			reconcileRem()
+
+			found := getRemediation()
+			Expect(found.Status.ApplicationState).To(Equal(compv1alpha1.RemediationError))
+			Expect(found.Status.ErrorMessage).To(ContainSubstring("Gatekeeper isn't installed"))
+		})
+
+		It("should wait for Gatekeeper to serve the kind of a constraint", func() {
+			setup(newObject(constraintGVK, "ns-must-have-owner"), "", templateGVK)
+			res := reconcileRem()
+			Expect(res.RequeueAfter).To(Equal(constraintKindRequeueTime))
+
+			live := newObject(constraintGVK, "ns-must-have-owner")
+			err := reconciler.Client.Get(context.TODO(), client.ObjectKeyFromObject(live), live)
+			Expect(kerrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should apply a constraint in audit mode and report its state", func() {
+			setup(newObject(constraintGVK, "ns-must-have-owner"), compv1alpha1.EnforcementModeAudit, templateGVK, constraintGVK)
+
+			By("applying the constraint")
+			res := reconcileRem()
+			Expect(res.RequeueAfter).To(Equal(enforcementStatusInterval))
+			action, _, _ := unstructured.NestedString(getLive(constraintGVK, "ns-must-have-owner").Object, "spec", "enforcementAction")
+			Expect(action).To(Equal("dryrun"))
+			found := getRemediation()
+			Expect(found.Status.ApplicationState).To(Equal(compv1alpha1.RemediationApplied))
+			Expect(found.Status.Enforcement).To(Equal(&compv1alpha1.RemediationEnforcementStatus{
+				Engine:  compv1alpha1.EnforcementEngineGatekeeper,
+				Mode:    compv1alpha1.EnforcementModeAudit,
+				Message: "Gatekeeper doesn't enforce the constraint yet",
+			}))
+
+			By("reporting the audit of Gatekeeper")
+			setLiveStatus(constraintGVK, "ns-must-have-owner", map[string]interface{}{
+				"byPod": []interface{}{
+					map[string]interface{}{"id": "gatekeeper-audit", "enforced": true},
+				},
+				"totalViolations": int64(3),
+			})
+			reconcileRem()
+			violations := int64(3)
+			Expect(getRemediation().Status.Enforcement).To(Equal(&compv1alpha1.RemediationEnforcementStatus{
+				Engine:     compv1alpha1.EnforcementEngineGatekeeper,
+				Mode:       compv1alpha1.EnforcementModeAudit,
+				Ready:      true,
+				Violations: &violations,
+			}))
+		})
+
+		It("should apply a Kyverno policy in deny mode and count its violations", func() {
+			setup(newObject(policyGVK, "require-labels"), compv1alpha1.EnforcementModeDeny, policyGVK, reportGVK, clusterRepGVK)
+
+			reportResults := func(gvk schema.GroupVersionKind, name, namespace string, labels map[string]string, results ...interface{}) {
+				report := newObject(gvk, name)
+				report.SetNamespace(namespace)
+				report.SetLabels(labels)
+				report.Object["results"] = results
+				err := reconciler.Client.Create(context.TODO(), report)
+				Expect(err).NotTo(HaveOccurred())
+			}
+			policyLabels := map[string]string{"cpol.kyverno.io/require-labels": "1"}
+			reportResults(reportGVK, "polr-default", "default", policyLabels,
+				map[string]interface{}{"policy": "require-labels", "result": "fail"},
+				map[string]interface{}{"policy": "require-labels", "result": "pass"},
+				map[string]interface{}{"policy": "other", "result": "fail"},
+			)
+			reportResults(clusterRepGVK, "cpolr", "", policyLabels,
+				map[string]interface{}{"policy": "require-labels", "result": "fail"},
+			)
+			// The reports that aren't labeled with the policy aren't read
+			reportResults(reportGVK, "polr-other", "default", map[string]string{"cpol.kyverno.io/other": "1"},
+				map[string]interface{}{"policy": "require-labels", "result": "fail"},
+			)
+
+			reconcileRem()
+			action, _, _ := unstructured.NestedString(getLive(policyGVK, "require-labels").Object, "spec", "validationFailureAction")
+			Expect(action).To(Equal("Enforce"))
+
+			setLiveStatus(policyGVK, "require-labels", map[string]interface{}{
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "Ready", "status": "True"},
+				},
+			})
+			reconcileRem()
+			violations := int64(2)
+			Expect(getRemediation().Status.Enforcement).To(Equal(&compv1alpha1.RemediationEnforcementStatus{
+				Engine:     compv1alpha1.EnforcementEngineKyverno,
+				Mode:       compv1alpha1.EnforcementModeDeny,
+				Ready:      true,
+				Violations: &violations,
+			}))
+		})
 	})
 })
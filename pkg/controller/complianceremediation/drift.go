@@ -0,0 +1,222 @@
+package complianceremediation
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/utils"
+)
+
+// How often the objects of applied remediations are checked for drift. The
+// MachineConfigs and KubeletConfigs the operator created are also watched
+// on clusters that serve them, so their drift is detected right away.
+const driftCheckInterval = 10 * time.Minute
+
+// isCheckedForDrift returns whether the object of the remediation is
+// expected to be in the cluster as the remediation defines it
+func isCheckedForDrift(rem *compv1alpha1.ComplianceRemediation) bool {
+	if rem.HasUnmetDependencies() || rem.HasAnnotation(compv1alpha1.RemediationUnsetValueAnnotation) ||
+		rem.HasAnnotation(compv1alpha1.RemediationValueRequiredAnnotation) {
+		return false
+	}
+	return rem.Spec.Apply && isAppliedOrDrifted(rem)
+}
+
+// reconcileDrift compares the object of an applied remediation with the
+// object in the cluster. If they differ and the suite doesn't re-apply
+// drifted remediations, the remediation is marked as drifted and true is
+// returned. Otherwise the remediation is reconciled as usual, which
+// restores the object.
+func (r *ReconcileComplianceRemediation) reconcileDrift(instance *compv1alpha1.ComplianceRemediation, logger logr.Logger) (bool, reconcile.Result, error) {
+	changes, err := r.detectDrift(instance, logger)
+	if err != nil || len(changes) == 0 {
+		// Errors are surfaced when the remediation is reconciled
+		return false, reconcile.Result{}, nil
+	}
+
+	reapply, err := r.shouldReapplyDrifted(instance)
+	if err != nil {
+		return false, reconcile.Result{}, err
+	}
+	if reapply {
+		logger.Info("Re-applying drifted remediation", "Changes", len(changes))
+		r.Recorder.Eventf(instance, corev1.EventTypeWarning, "RemediationReapplied",
+			"The object of the remediation drifted in %d fields and is being restored", len(changes))
+		return false, reconcile.Result{}, nil
+	}
+
+	drift := &compv1alpha1.RemediationDrift{
+		Changes:    changes,
+		DetectedAt: metav1.Now(),
+	}
+	if instance.Status.Drift != nil {
+		drift.DetectedAt = instance.Status.Drift.DetectedAt
+	}
+	if instance.Status.ApplicationState == compv1alpha1.RemediationDrifted && reflect.DeepEqual(instance.Status.Drift, drift) {
+		return true, reconcile.Result{RequeueAfter: driftCheckInterval}, nil
+	}
+
+	if instance.Status.ApplicationState != compv1alpha1.RemediationDrifted {
+		logger.Info("Remediation drifted", "Changes", len(changes))
+		r.Recorder.Eventf(instance, corev1.EventTypeWarning, "RemediationDrifted",
+			"The object of the remediation drifted in %d fields since it was applied", len(changes))
+	}
+
+	instanceCopy := instance.DeepCopy()
+	instanceCopy.Status.ApplicationState = compv1alpha1.RemediationDrifted
+	instanceCopy.Status.ErrorMessage = ""
+	instanceCopy.Status.Drift = drift
+	if err := r.Client.Status().Update(context.TODO(), instanceCopy); err != nil {
+		return false, reconcile.Result{}, err
+	}
+	r.Metrics.IncComplianceRemediationStatus(instanceCopy.Name, instanceCopy.Status)
+	return true, reconcile.Result{RequeueAfter: driftCheckInterval}, nil
+}
+
+// detectDrift returns the fields of the remediation object that the object
+// in the cluster doesn't match. Fields that are only set in the cluster
+// aren't drift, since other remediations or controllers might own them.
+func (r *ReconcileComplianceRemediation) detectDrift(instance *compv1alpha1.ComplianceRemediation, logger logr.Logger) ([]compv1alpha1.RemediationFieldChange, error) {
+	obj := getApplicableObject(instance, logger)
+	if obj == nil {
+		return nil, nil
+	}
+	if utils.IsMachineConfig(obj) {
+		if err := r.verifyAndCompleteMC(obj, instance); err != nil {
+			return nil, err
+		}
+	}
+	if utils.IsKubeletConfig(obj) {
+		if err := r.verifyAndCompleteKC(obj, instance); err != nil {
+			return nil, err
+		}
+	}
+	if err := setEnforcementMode(instance, obj); err != nil {
+		return nil, err
+	}
+
+	live := obj.DeepCopy()
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: obj.GetName(), Namespace: obj.GetNamespace()}, live)
+	if kerrors.IsNotFound(err) {
+		live = nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var liveContent map[string]interface{}
+	if live != nil {
+		liveContent = live.Object
+	}
+	if payload := getApplicablePayload(instance); payload.PatchType == compv1alpha1.JSONPatchRemediation {
+		return diffJSONPatch(liveContent, payload.JSONPatch), nil
+	}
+	return diffDesiredFields(liveContent, obj.Object), nil
+}
+
+// shouldReapplyDrifted returns whether the suite of the remediation restores
+// the objects of drifted remediations
+func (r *ReconcileComplianceRemediation) shouldReapplyDrifted(instance *compv1alpha1.ComplianceRemediation) (bool, error) {
+	if instance.GetSuite() == "" {
+		return false, nil
+	}
+	suite := &compv1alpha1.ComplianceSuite{}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: instance.GetSuite(), Namespace: instance.Namespace}, suite)
+	if kerrors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return suite.Spec.ReapplyDriftedRemediations, nil
+}
+
+// diffDesiredFields returns the leaf fields of the desired object that the
+// live object doesn't match, sorted by path. The metadata and status are
+// left out, and lists are compared as a whole.
+func diffDesiredFields(live, desired map[string]interface{}) []compv1alpha1.RemediationFieldChange {
+	var changes []compv1alpha1.RemediationFieldChange
+	for key, desiredValue := range desired {
+		if key == "metadata" || key == "status" {
+			continue
+		}
+		diffDesiredValues("."+key, live[key], desiredValue, &changes)
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes
+}
+
+func diffDesiredValues(path string, live, desired interface{}, changes *[]compv1alpha1.RemediationFieldChange) {
+	desiredMap, desiredIsMap := desired.(map[string]interface{})
+	if desiredIsMap {
+		liveMap, _ := live.(map[string]interface{})
+		for key, desiredValue := range desiredMap {
+			diffDesiredValues(path+"."+key, liveMap[key], desiredValue, changes)
+		}
+		return
+	}
+
+	if reflect.DeepEqual(live, desired) {
+		return
+	}
+	*changes = append(*changes, compv1alpha1.RemediationFieldChange{
+		Path:    path,
+		Current: encodePreviewValue(live),
+		Desired: encodePreviewValue(desired),
+	})
+}
+
+type createdObjectMapper struct {
+	client.Client
+}
+
+// MapMachineConfig enqueues the remediation that created the MachineConfig
+func (m *createdObjectMapper) MapMachineConfig(ctx context.Context, obj client.Object) []reconcile.Request {
+	return m.mapCreatedObject(ctx, obj, func(rem *compv1alpha1.ComplianceRemediation) bool {
+		return utils.IsMachineConfig(rem.Spec.Current.Object) && rem.GetMcName() == obj.GetName()
+	})
+}
+
+// MapKubeletConfig enqueues the applied KubeletConfig remediations, since
+// they are all merged into the KubeletConfigs of their pools
+func (m *createdObjectMapper) MapKubeletConfig(ctx context.Context, obj client.Object) []reconcile.Request {
+	return m.mapCreatedObject(ctx, obj, func(rem *compv1alpha1.ComplianceRemediation) bool {
+		return utils.IsKubeletConfig(rem.Spec.Current.Object)
+	})
+}
+
+func (m *createdObjectMapper) mapCreatedObject(ctx context.Context, obj client.Object, matches func(rem *compv1alpha1.ComplianceRemediation) bool) []reconcile.Request {
+	var requests []reconcile.Request
+	if !compv1alpha1.RemediationWasCreatedByOperator(obj) {
+		return requests
+	}
+
+	remList := compv1alpha1.ComplianceRemediationList{}
+	if err := m.List(ctx, &remList); err != nil {
+		return requests
+	}
+
+	for i := range remList.Items {
+		rem := &remList.Items[i]
+		if !isCheckedForDrift(rem) || rem.Spec.Current.Object == nil || !matches(rem) {
+			continue
+		}
+		objKey := types.NamespacedName{
+			Name:      rem.GetName(),
+			Namespace: rem.GetNamespace(),
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: objKey})
+	}
+	return requests
+}
@@ -0,0 +1,322 @@
+package complianceremediation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/common"
+)
+
+// How often the state of an applied policy is read from its engine
+const enforcementStatusInterval = time.Minute
+
+// How long to wait for Gatekeeper to serve the kind of a constraint once
+// its ConstraintTemplate is created
+const constraintKindRequeueTime = 30 * time.Second
+
+const (
+	gatekeeperTemplatesGroup   = "templates.gatekeeper.sh"
+	gatekeeperConstraintsGroup = "constraints.gatekeeper.sh"
+	kyvernoGroup               = "kyverno.io"
+	// Kyverno labels the policy reports that hold results of a policy with
+	// the name of the policy, prefixed with the kind of the policy
+	kyvernoClusterPolicyLabelPrefix = "cpol.kyverno.io/"
+	kyvernoPolicyLabelPrefix        = "pol.kyverno.io/"
+)
+
+var (
+	constraintTemplateGK = schema.GroupKind{Group: gatekeeperTemplatesGroup, Kind: "ConstraintTemplate"}
+	clusterPolicyGK      = schema.GroupKind{Group: kyvernoGroup, Kind: "ClusterPolicy"}
+	// Kyverno reports the results of its audits in policy reports
+	policyReportGVK  = schema.GroupVersionKind{Group: "wgpolicyk8s.io", Version: "v1alpha2", Kind: "PolicyReport"}
+	policyReportGVKs = []schema.GroupVersionKind{
+		{Group: "wgpolicyk8s.io", Version: "v1alpha2", Kind: "ClusterPolicyReport"},
+		policyReportGVK,
+	}
+)
+
+// enforcementBackend knows how a policy engine serves, enforces and reports
+// on the policies of Enforcement remediations
+type enforcementBackend interface {
+	// engine returns the name of the policy engine
+	engine() string
+	// verifyInstalled returns an error if the engine doesn't serve the
+	// kind of the policy
+	verifyInstalled(mapper meta.RESTMapper, obj *unstructured.Unstructured) error
+	// setMode makes the engine enforce the policy in the given mode
+	setMode(obj *unstructured.Unstructured, mode compv1alpha1.RemediationEnforcementMode) error
+	// getStatus reads the state of the policy from the policy in the cluster
+	getStatus(ctx context.Context, c client.Client, live *unstructured.Unstructured) *compv1alpha1.RemediationEnforcementStatus
+}
+
+// getEnforcementBackend returns the backend of the policy engine the object
+// is a policy of, or nil if the object isn't a policy
+func getEnforcementBackend(obj *unstructured.Unstructured) enforcementBackend {
+	if obj == nil {
+		return nil
+	}
+	switch obj.GroupVersionKind().Group {
+	case gatekeeperTemplatesGroup, gatekeeperConstraintsGroup:
+		return &gatekeeperBackend{}
+	case kyvernoGroup:
+		return &kyvernoBackend{}
+	}
+	return nil
+}
+
+// setEnforcementMode sets the enforcement mode of the remediation on its
+// policy, if any
+func setEnforcementMode(instance *compv1alpha1.ComplianceRemediation, obj *unstructured.Unstructured) error {
+	backend := getEnforcementBackend(obj)
+	if backend == nil || instance.Spec.EnforcementMode == "" {
+		return nil
+	}
+	return backend.setMode(obj, instance.Spec.EnforcementMode)
+}
+
+// verifyEnforcementEngine returns an error if the remediation is a policy
+// whose engine isn't installed
+func (r *ReconcileComplianceRemediation) verifyEnforcementEngine(obj *unstructured.Unstructured) error {
+	backend := getEnforcementBackend(obj)
+	if backend == nil {
+		return nil
+	}
+	return backend.verifyInstalled(r.Client.RESTMapper(), obj)
+}
+
+// getEnforcementStatus returns the state of the policy of an applied
+// remediation, or nil if the remediation isn't an applied policy
+func (r *ReconcileComplianceRemediation) getEnforcementStatus(instance *compv1alpha1.ComplianceRemediation, logger logr.Logger) *compv1alpha1.RemediationEnforcementStatus {
+	if instance.Status.ApplicationState != compv1alpha1.RemediationApplied {
+		return nil
+	}
+	obj := getApplicableObject(instance, logger)
+	backend := getEnforcementBackend(obj)
+	if backend == nil {
+		return nil
+	}
+
+	live := obj.DeepCopy()
+	if err := r.Client.Get(context.TODO(), types.NamespacedName{Name: obj.GetName(), Namespace: obj.GetNamespace()}, live); err != nil {
+		return &compv1alpha1.RemediationEnforcementStatus{
+			Engine:  backend.engine(),
+			Message: fmt.Sprintf("couldn't get the policy: %s", err),
+		}
+	}
+	return backend.getStatus(context.TODO(), r.Client, live)
+}
+
+// isServed returns whether the API server serves the kind. Without a
+// version, any version of the kind is looked up.
+func isServed(mapper meta.RESTMapper, gk schema.GroupKind, versions ...string) (bool, error) {
+	_, err := mapper.RESTMapping(gk, versions...)
+	if meta.IsNoMatchError(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+type gatekeeperBackend struct{}
+
+func (b *gatekeeperBackend) engine() string {
+	return compv1alpha1.EnforcementEngineGatekeeper
+}
+
+func (b *gatekeeperBackend) verifyInstalled(mapper meta.RESTMapper, obj *unstructured.Unstructured) error {
+	served, err := isServed(mapper, constraintTemplateGK)
+	if err != nil {
+		return err
+	} else if !served {
+		return common.NewNonRetriableCtrlError("Gatekeeper isn't installed: the ConstraintTemplate kind isn't served")
+	}
+
+	gvk := obj.GroupVersionKind()
+	if gvk.Group != gatekeeperConstraintsGroup {
+		return nil
+	}
+	// Gatekeeper creates the kind of a constraint from its template
+	served, err = isServed(mapper, gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return err
+	} else if !served {
+		return common.NewRetriableCtrlErrorWithCustomHandler(func() (reconcile.Result, error) {
+			return reconcile.Result{RequeueAfter: constraintKindRequeueTime}, nil
+		}, "the constraint kind %s isn't served yet, its ConstraintTemplate might not be created", gvk.Kind)
+	}
+	return nil
+}
+
+func (b *gatekeeperBackend) setMode(obj *unstructured.Unstructured, mode compv1alpha1.RemediationEnforcementMode) error {
+	// Templates aren't enforced on their own
+	if obj.GroupVersionKind().Group != gatekeeperConstraintsGroup {
+		return nil
+	}
+	action := "deny"
+	if mode == compv1alpha1.EnforcementModeAudit {
+		action = "dryrun"
+	}
+	return unstructured.SetNestedField(obj.Object, action, "spec", "enforcementAction")
+}
+
+func (b *gatekeeperBackend) getStatus(_ context.Context, _ client.Client, live *unstructured.Unstructured) *compv1alpha1.RemediationEnforcementStatus {
+	status := &compv1alpha1.RemediationEnforcementStatus{
+		Engine: b.engine(),
+	}
+	byPod, _, _ := unstructured.NestedSlice(live.Object, "status", "byPod")
+
+	if live.GroupVersionKind().Group != gatekeeperConstraintsGroup {
+		status.Ready, _, _ = unstructured.NestedBool(live.Object, "status", "created")
+		var errs []string
+		for _, pod := range byPod {
+			podErrs, _, _ := unstructured.NestedSlice(asMap(pod), "errors")
+			for _, podErr := range podErrs {
+				if msg, ok := asMap(podErr)["message"].(string); ok {
+					errs = append(errs, msg)
+				}
+			}
+		}
+		if len(errs) > 0 {
+			status.Message = strings.Join(errs, "; ")
+		} else if !status.Ready {
+			status.Message = "Gatekeeper didn't create the template yet"
+		}
+		return status
+	}
+
+	// Gatekeeper denies violations unless told otherwise
+	status.Mode = compv1alpha1.EnforcementModeDeny
+	if action, _, _ := unstructured.NestedString(live.Object, "spec", "enforcementAction"); action != "" && action != "deny" {
+		status.Mode = compv1alpha1.EnforcementModeAudit
+	}
+	status.Ready = len(byPod) > 0
+	for _, pod := range byPod {
+		if enforced, _, _ := unstructured.NestedBool(asMap(pod), "enforced"); !enforced {
+			status.Ready = false
+		}
+	}
+	if !status.Ready {
+		status.Message = "Gatekeeper doesn't enforce the constraint yet"
+	}
+	if violations, found, _ := unstructured.NestedInt64(live.Object, "status", "totalViolations"); found {
+		status.Violations = &violations
+	}
+	return status
+}
+
+type kyvernoBackend struct{}
+
+func (b *kyvernoBackend) engine() string {
+	return compv1alpha1.EnforcementEngineKyverno
+}
+
+func (b *kyvernoBackend) verifyInstalled(mapper meta.RESTMapper, obj *unstructured.Unstructured) error {
+	gvk := obj.GroupVersionKind()
+	served, err := isServed(mapper, gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return err
+	} else if !served {
+		return common.NewNonRetriableCtrlError("Kyverno isn't installed: the %s kind isn't served", gvk.Kind)
+	}
+	return nil
+}
+
+func (b *kyvernoBackend) setMode(obj *unstructured.Unstructured, mode compv1alpha1.RemediationEnforcementMode) error {
+	action := "Enforce"
+	if mode == compv1alpha1.EnforcementModeAudit {
+		action = "Audit"
+	}
+	return unstructured.SetNestedField(obj.Object, action, "spec", "validationFailureAction")
+}
+
+func (b *kyvernoBackend) getStatus(ctx context.Context, c client.Client, live *unstructured.Unstructured) *compv1alpha1.RemediationEnforcementStatus {
+	status := &compv1alpha1.RemediationEnforcementStatus{
+		Engine: b.engine(),
+		// Kyverno only audits policies unless told otherwise
+		Mode: compv1alpha1.EnforcementModeAudit,
+	}
+	if action, _, _ := unstructured.NestedString(live.Object, "spec", "validationFailureAction"); strings.EqualFold(action, "enforce") {
+		status.Mode = compv1alpha1.EnforcementModeDeny
+	}
+
+	// Older versions of Kyverno only set the ready field
+	if ready, found, _ := unstructured.NestedBool(live.Object, "status", "ready"); found {
+		status.Ready = ready
+	}
+	conditions, _, _ := unstructured.NestedSlice(live.Object, "status", "conditions")
+	for _, cond := range conditions {
+		condMap := asMap(cond)
+		if condMap["type"] != "Ready" {
+			continue
+		}
+		status.Ready = condMap["status"] == "True"
+		if msg, ok := condMap["message"].(string); ok && !status.Ready {
+			status.Message = msg
+		}
+	}
+	if !status.Ready && status.Message == "" {
+		status.Message = "Kyverno isn't ready to apply the policy yet"
+	}
+
+	status.Violations = countKyvernoViolations(ctx, c, live)
+	return status
+}
+
+// countKyvernoViolations counts the failed results of the policy in the
+// policy reports. Only the reports Kyverno labeled with the policy are read,
+// and for a namespaced policy only those in its namespace. It returns nil if
+// the reports can't be read.
+func countKyvernoViolations(ctx context.Context, c client.Client, live *unstructured.Unstructured) *int64 {
+	policyName := live.GetName()
+	listOpts := []client.ListOption{client.HasLabels{kyvernoClusterPolicyLabelPrefix + live.GetName()}}
+	reportGVKs := policyReportGVKs
+	if live.GetNamespace() != "" {
+		// The results of namespaced policies name the policy with its
+		// namespace, and are only reported in that namespace
+		policyName = live.GetNamespace() + "/" + live.GetName()
+		listOpts = []client.ListOption{
+			client.HasLabels{kyvernoPolicyLabelPrefix + live.GetName()},
+			client.InNamespace(live.GetNamespace()),
+		}
+		reportGVKs = []schema.GroupVersionKind{policyReportGVK}
+	}
+
+	var violations int64
+	for _, gvk := range reportGVKs {
+		if served, err := isServed(c.RESTMapper(), gvk.GroupKind(), gvk.Version); err != nil || !served {
+			return nil
+		}
+		reports := &unstructured.UnstructuredList{}
+		reports.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := c.List(ctx, reports, listOpts...); err != nil {
+			return nil
+		}
+		for _, report := range reports.Items {
+			results, _, _ := unstructured.NestedSlice(report.Object, "results")
+			for _, result := range results {
+				resultMap := asMap(result)
+				if resultMap["policy"] == policyName && resultMap["result"] == "fail" {
+					violations++
+				}
+			}
+		}
+	}
+	return &violations
+}
+
+func asMap(value interface{}) map[string]interface{} {
+	m, _ := value.(map[string]interface{})
+	return m
+}
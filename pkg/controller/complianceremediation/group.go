@@ -0,0 +1,73 @@
+package complianceremediation
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+)
+
+// How often a group member that waits for other members is reconciled again
+const groupOrderRequeueTime = 10 * time.Second
+
+// waitForGroupMembers returns whether the remediation has to wait for other
+// members of its group. A member is applied once the members before it are
+// applied, and un-applied once the members after it are un-applied. Setting
+// apply on a member sets it on the whole group.
+func (r *ReconcileComplianceRemediation) waitForGroupMembers(instance *compv1alpha1.ComplianceRemediation, logger logr.Logger) (bool, error) {
+	group := &compv1alpha1.ComplianceRemediationGroup{}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: instance.GetGroup(), Namespace: instance.Namespace}, group)
+	if kerrors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	member := group.GetMember(instance.Name)
+	if member == nil {
+		return false, nil
+	}
+
+	if err := r.setRemediations(instance, logger, instance.Spec.Apply); err != nil {
+		return false, err
+	}
+
+	remList := &compv1alpha1.ComplianceRemediationList{}
+	err = r.Client.List(context.TODO(), remList, client.InNamespace(instance.Namespace),
+		client.MatchingLabels{compv1alpha1.RemediationGroupLabel: group.Name})
+	if err != nil {
+		return false, err
+	}
+
+	for i := range remList.Items {
+		rem := &remList.Items[i]
+		other := group.GetMember(rem.Name)
+		if other == nil || rem.Name == instance.Name {
+			continue
+		}
+		if instance.Spec.Apply && other.Order < member.Order && !isSettledForGroup(rem) {
+			logger.Info("Waiting for an earlier member of the group to be applied", "Group", group.Name, "Member", rem.Name)
+			return true, nil
+		}
+		if !instance.Spec.Apply && other.Order > member.Order && rem.IsInCluster() {
+			logger.Info("Waiting for a later member of the group to be un-applied", "Group", group.Name, "Member", rem.Name)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// isSettledForGroup returns whether the later members of the group can be
+// applied after this one. An optional remediation that couldn't be applied
+// doesn't hold them.
+func isSettledForGroup(rem *compv1alpha1.ComplianceRemediation) bool {
+	if rem.IsInCluster() {
+		return true
+	}
+	return rem.HasAnnotation(compv1alpha1.RemediationOptionalAnnotation) &&
+		rem.Status.ApplicationState == compv1alpha1.RemediationNotApplied && rem.Status.ErrorMessage != ""
+}
@@ -0,0 +1,384 @@
+package complianceremediation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/common"
+)
+
+// Fields of a strategic merge patch that identify the object instead of
+// changing it
+var patchIdentityFields = [][]string{
+	{"apiVersion"},
+	{"kind"},
+	{"metadata", "name"},
+	{"metadata", "namespace"},
+}
+
+// getApplicablePayload returns the payload that getApplicableObject takes
+// the object from
+func getApplicablePayload(instance *compv1alpha1.ComplianceRemediation) *compv1alpha1.ComplianceRemediationPayload {
+	if instance.Spec.Outdated.Object != nil {
+		return instance.Spec.Outdated.DeepCopy()
+	}
+	return instance.Spec.Current.DeepCopy()
+}
+
+// rawPatch returns the patch a patch remediation applies to its object
+func rawPatch(payload *compv1alpha1.ComplianceRemediationPayload, obj *unstructured.Unstructured) (client.Patch, error) {
+	switch payload.PatchType {
+	case compv1alpha1.StrategicMergePatchRemediation:
+		data, err := json.Marshal(obj.Object)
+		if err != nil {
+			return nil, err
+		}
+		return client.RawPatch(types.StrategicMergePatchType, data), nil
+	case compv1alpha1.JSONPatchRemediation:
+		ops := make([]map[string]interface{}, 0, len(payload.JSONPatch))
+		for _, op := range payload.JSONPatch {
+			encodedOp := map[string]interface{}{
+				"op":   op.Op,
+				"path": op.Path,
+			}
+			if op.Value != "" {
+				encodedOp["value"] = json.RawMessage(op.Value)
+			}
+			ops = append(ops, encodedOp)
+		}
+		data, err := json.Marshal(ops)
+		if err != nil {
+			return nil, err
+		}
+		return client.RawPatch(types.JSONPatchType, data), nil
+	}
+	return nil, fmt.Errorf("unknown remediation patch type %q", payload.PatchType)
+}
+
+// applyPatch patches the object in the cluster. Custom resources don't
+// support strategic merge patches, so they're patched with a JSON merge
+// patch instead.
+func (r *ReconcileComplianceRemediation) applyPatch(target *unstructured.Unstructured, patch client.Patch, opts ...client.PatchOption) error {
+	err := r.Client.Patch(context.TODO(), target, patch, opts...)
+	if kerrors.IsUnsupportedMediaType(err) && patch.Type() == types.StrategicMergePatchType {
+		data, dataErr := patch.Data(target)
+		if dataErr != nil {
+			return dataErr
+		}
+		err = r.Client.Patch(context.TODO(), target, client.RawPatch(types.MergePatchType, data), opts...)
+	}
+	return err
+}
+
+// patchExistingObject applies a patch remediation to the object found in
+// the cluster. The values the touched fields had are recorded first, so
+// un-applying the remediation can restore them.
+func (r *ReconcileComplianceRemediation) patchExistingObject(instance *compv1alpha1.ComplianceRemediation, payload *compv1alpha1.ComplianceRemediationPayload,
+	obj, found *unstructured.Unstructured, logger logr.Logger) error {
+	logger.Info("Remediation patch object")
+
+	// JSON patches aren't idempotent, e.g. removing a field twice fails,
+	// so once applied they're only re-applied if the object drifted
+	if payload.PatchType == compv1alpha1.JSONPatchRemediation && isAppliedOrDrifted(instance) &&
+		len(diffJSONPatch(found.Object, payload.JSONPatch)) == 0 {
+		logger.Info("The JSON patch is already in effect")
+		return nil
+	}
+
+	patch, err := rawPatch(payload, obj)
+	if err != nil {
+		return common.WrapNonRetriableCtrlError(err)
+	}
+	paths, err := touchedPaths(payload, obj, found.Object)
+	if err != nil {
+		return common.WrapNonRetriableCtrlError(err)
+	}
+	if err := r.recordPreImage(instance, paths, found.Object); err != nil {
+		return fmt.Errorf("couldn't record the pre-image of the remediation: %w", err)
+	}
+
+	target := found.DeepCopy()
+	patchErr := r.applyPatch(target, patch)
+	if kerrors.IsForbidden(patchErr) {
+		return common.NewNonRetriableCtrlError(
+			"Unable to patch fix object from ComplianceRemediation. "+
+				"Please update the compliance-operator's permissions: %s", patchErr)
+	} else if kerrors.IsInvalid(patchErr) || kerrors.IsBadRequest(patchErr) {
+		return common.NewNonRetriableCtrlError("Unable to patch fix object from ComplianceRemediation: %s", patchErr)
+	}
+	return patchErr
+}
+
+func isAppliedOrDrifted(instance *compv1alpha1.ComplianceRemediation) bool {
+	return instance.Status.ApplicationState == compv1alpha1.RemediationApplied ||
+		instance.Status.ApplicationState == compv1alpha1.RemediationDrifted
+}
+
+// recordPreImage adds the fields the patch touches for the first time to
+// the pre-image of the remediation. Fields that are already recorded keep
+// their value, since the remediation might have changed them since.
+func (r *ReconcileComplianceRemediation) recordPreImage(instance *compv1alpha1.ComplianceRemediation, paths []string, live map[string]interface{}) error {
+	recorded := make(map[string]bool, len(instance.Status.PreImage))
+	for _, field := range instance.Status.PreImage {
+		recorded[field.Path] = true
+	}
+
+	preImage := instance.Status.PreImage
+	for _, path := range paths {
+		if recorded[path] {
+			continue
+		}
+		field := compv1alpha1.RemediationFieldValue{Path: path}
+		if value, found := getPointer(live, parsePointer(path)); found {
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				return err
+			}
+			field.Value = string(encoded)
+		}
+		preImage = append(preImage, field)
+	}
+	if len(preImage) == len(instance.Status.PreImage) {
+		return nil
+	}
+
+	instance.Status.PreImage = preImage
+	return r.Client.Status().Update(context.TODO(), instance)
+}
+
+// restorePreImage sets the fields touched by a patch remediation back to
+// the values they had before it was applied, and removes the ones that
+// weren't set. The rest of the object is left alone.
+func (r *ReconcileComplianceRemediation) restorePreImage(instance *compv1alpha1.ComplianceRemediation, found *unstructured.Unstructured, logger logr.Logger) error {
+	if len(instance.Status.PreImage) == 0 {
+		logger.Info("No pre-image was recorded, so no action is needed to unapply it")
+		return nil
+	}
+
+	logger.Info("Remediation pre-image will be restored", "Fields", len(instance.Status.PreImage))
+	restored := found.DeepCopy()
+	for _, field := range instance.Status.PreImage {
+		segments := parsePointer(field.Path)
+		if field.Value == "" {
+			removePointer(restored.Object, segments)
+			continue
+		}
+		var value interface{}
+		if err := json.Unmarshal([]byte(field.Value), &value); err != nil {
+			return common.NewNonRetriableCtrlError("Unable to decode the pre-image of %s: %s", field.Path, err)
+		}
+		if !setPointer(restored.Object, segments, value) {
+			logger.Info("Can't restore field, since its parent is no longer an object", "Path", field.Path)
+		}
+	}
+
+	if reflect.DeepEqual(restored.Object, found.Object) {
+		return nil
+	}
+	updateErr := r.Client.Update(context.TODO(), restored)
+	if kerrors.IsForbidden(updateErr) {
+		return common.NewNonRetriableCtrlError(
+			"Unable to restore fix object from ComplianceRemediation. "+
+				"Please update the compliance-operator's permissions: %s", updateErr)
+	} else if kerrors.IsNotFound(updateErr) {
+		return nil
+	}
+	return updateErr
+}
+
+// touchedPaths returns the JSON pointers of the fields a patch changes,
+// sorted. Lists are touched as a whole, since their items can't be told
+// apart once they're patched.
+func touchedPaths(payload *compv1alpha1.ComplianceRemediationPayload, obj *unstructured.Unstructured, live map[string]interface{}) ([]string, error) {
+	touched := map[string]bool{}
+	switch payload.PatchType {
+	case compv1alpha1.StrategicMergePatchRemediation:
+		content := obj.DeepCopy().Object
+		for _, field := range patchIdentityFields {
+			unstructured.RemoveNestedField(content, field...)
+		}
+		delete(content, "status")
+		collectLeafPaths(nil, content, touched)
+	case compv1alpha1.JSONPatchRemediation:
+		for _, op := range payload.JSONPatch {
+			if op.Op == "test" {
+				continue
+			}
+			segments := parsePointer(op.Path)
+			if len(segments) == 0 {
+				return nil, fmt.Errorf("the json patch operation %s replaces the whole object", op.Op)
+			}
+			touched[formatPointer(wholeListPrefix(live, segments))] = true
+		}
+	}
+
+	paths := make([]string, 0, len(touched))
+	for path := range touched {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+func collectLeafPaths(prefix []string, value interface{}, touched map[string]bool) {
+	valueMap, isMap := value.(map[string]interface{})
+	if isMap && len(valueMap) == 0 {
+		// An empty object doesn't change anything
+		return
+	}
+	if !isMap {
+		if len(prefix) > 0 {
+			touched[formatPointer(prefix)] = true
+		}
+		return
+	}
+	for key, child := range valueMap {
+		// Strategic merge directives, like $patch, act on their parent
+		if strings.HasPrefix(key, "$") {
+			if len(prefix) > 0 {
+				touched[formatPointer(prefix)] = true
+			}
+			continue
+		}
+		collectLeafPaths(append(append([]string{}, prefix...), key), child, touched)
+	}
+}
+
+// wholeListPrefix cuts the pointer at the first list, or any other value
+// that isn't an object, it goes through in the live object
+func wholeListPrefix(live map[string]interface{}, segments []string) []string {
+	var current interface{} = live
+	for i, segment := range segments {
+		if current == nil {
+			// The rest of the path doesn't exist yet
+			return segments
+		}
+		currentMap, isMap := current.(map[string]interface{})
+		if !isMap {
+			return segments[:i]
+		}
+		current = currentMap[segment]
+	}
+	return segments
+}
+
+// parsePointer splits an RFC 6901 JSON pointer into its unescaped segments
+func parsePointer(pointer string) []string {
+	if pointer == "" || pointer == "/" {
+		return nil
+	}
+	segments := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	for i, segment := range segments {
+		segments[i] = strings.ReplaceAll(strings.ReplaceAll(segment, "~1", "/"), "~0", "~")
+	}
+	return segments
+}
+
+func formatPointer(segments []string) string {
+	escaped := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		escaped = append(escaped, strings.ReplaceAll(strings.ReplaceAll(segment, "~", "~0"), "/", "~1"))
+	}
+	return "/" + strings.Join(escaped, "/")
+}
+
+func getPointer(content map[string]interface{}, segments []string) (interface{}, bool) {
+	var current interface{} = content
+	for _, segment := range segments {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			value, found := node[segment]
+			if !found {
+				return nil, false
+			}
+			current = value
+		case []interface{}:
+			idx, err := strconv.Atoi(segment)
+			if err != nil || idx < 0 || idx >= len(node) {
+				return nil, false
+			}
+			current = node[idx]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// setPointer sets the field, creating the objects on its path. It returns
+// false if the path goes through something that isn't an object.
+func setPointer(content map[string]interface{}, segments []string, value interface{}) bool {
+	current := content
+	for _, segment := range segments[:len(segments)-1] {
+		child, found := current[segment]
+		if !found || child == nil {
+			child = map[string]interface{}{}
+			current[segment] = child
+		}
+		childMap, isMap := child.(map[string]interface{})
+		if !isMap {
+			return false
+		}
+		current = childMap
+	}
+	current[segments[len(segments)-1]] = value
+	return true
+}
+
+func removePointer(content map[string]interface{}, segments []string) {
+	parent, found := getPointer(content, segments[:len(segments)-1])
+	if !found {
+		return
+	}
+	if parentMap, isMap := parent.(map[string]interface{}); isMap {
+		delete(parentMap, segments[len(segments)-1])
+	}
+}
+
+// diffJSONPatch returns the fields that the operations of a JSON patch
+// remediation set or remove and that the live object no longer matches
+func diffJSONPatch(live map[string]interface{}, ops []compv1alpha1.JSONPatchOperation) []compv1alpha1.RemediationFieldChange {
+	var changes []compv1alpha1.RemediationFieldChange
+	for _, op := range ops {
+		segments := parsePointer(op.Path)
+		if len(segments) == 0 || len(wholeListPrefix(live, segments)) != len(segments) {
+			// Operations on list items can't be told apart once applied
+			continue
+		}
+		liveValue, _ := getPointer(live, segments)
+		var desired interface{}
+		switch op.Op {
+		case "add", "replace":
+			if err := json.Unmarshal([]byte(op.Value), &desired); err != nil {
+				continue
+			}
+		case "remove":
+		default:
+			continue
+		}
+		if reflect.DeepEqual(liveValue, desired) {
+			continue
+		}
+		changes = append(changes, compv1alpha1.RemediationFieldChange{
+			Path:    "." + strings.Join(segments, "."),
+			Current: encodePreviewValue(liveValue),
+			Desired: encodePreviewValue(desired),
+		})
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes
+}
@@ -0,0 +1,231 @@
+package complianceremediation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/go-logr/logr"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/common"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/utils"
+)
+
+// The field manager the dry-run applies are made as
+const previewFieldManager = "compliance-operator"
+
+// Fields that the API server manages and that would show up as noise in the
+// diff between the live object and the dry-run result
+var previewIgnoredMetadata = []string{
+	"managedFields",
+	"resourceVersion",
+	"generation",
+	"creationTimestamp",
+	"uid",
+	"selfLink",
+}
+
+// reconcilePreview records what applying the remediation would change in the
+// cluster instead of applying or un-applying it
+func (r *ReconcileComplianceRemediation) reconcilePreview(instance *compv1alpha1.ComplianceRemediation, logger logr.Logger) (reconcile.Result, error) {
+	logger.Info("Previewing remediation")
+
+	preview, err := r.previewRemediation(instance, logger)
+	if err != nil {
+		if common.IsRetriable(err) {
+			return common.ReturnWithRetriableError(logger, err)
+		}
+		preview = &compv1alpha1.RemediationPreview{ErrorMessage: err.Error()}
+	}
+
+	if reflect.DeepEqual(instance.Status.Preview, preview) {
+		return reconcile.Result{}, nil
+	}
+
+	instanceCopy := instance.DeepCopy()
+	instanceCopy.Status.Preview = preview
+	if err := r.Client.Status().Update(context.TODO(), instanceCopy); err != nil {
+		logger.Error(err, "Failed to update the remediation preview")
+		return common.ReturnWithRetriableError(logger, err)
+	}
+	return reconcile.Result{}, nil
+}
+
+// previewRemediation does a server-side dry-run apply of the remediation
+// object and diffs the result against the live object
+func (r *ReconcileComplianceRemediation) previewRemediation(instance *compv1alpha1.ComplianceRemediation, logger logr.Logger) (*compv1alpha1.RemediationPreview, error) {
+	obj := getApplicableObject(instance, logger)
+	if obj == nil {
+		return nil, common.NewNonRetriableCtrlError("Invalid Remediation: No object given")
+	}
+	if utils.IsMachineConfig(obj) {
+		if err := r.verifyAndCompleteMC(obj, instance); err != nil {
+			return nil, err
+		}
+	}
+	if utils.IsKubeletConfig(obj) {
+		if err := r.verifyAndCompleteKC(obj, instance); err != nil {
+			return nil, err
+		}
+	}
+	if err := setEnforcementMode(instance, obj); err != nil {
+		return nil, common.WrapNonRetriableCtrlError(err)
+	}
+	if err := r.verifyEnforcementEngine(obj); err != nil {
+		return nil, err
+	}
+
+	payload := getApplicablePayload(instance)
+	live := obj.DeepCopy()
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: obj.GetName(), Namespace: obj.GetNamespace()}, live)
+	if kerrors.IsNotFound(err) && payload.IsPatch() {
+		return nil, common.NewNonRetriableCtrlError("Unable to preview the patch of the remediation: the object doesn't exist")
+	} else if kerrors.IsNotFound(err) {
+		// The object would be created, so it would carry the same
+		// labels and annotations as in createRemediation
+		live = nil
+		instance.AddOwnershipLabels(obj)
+		compv1alpha1.AddRemediationAnnotation(obj)
+	} else if kerrors.IsForbidden(err) {
+		return nil, common.NewNonRetriableCtrlError(
+			"Unable to get fix object from ComplianceRemediation. "+
+				"Please update the compliance-operator's permissions: %w", err)
+	} else if err != nil {
+		return nil, common.WrapNonRetriableCtrlError(
+			fmt.Errorf("unable to get fix object for ComplianceRemediation: %w", err))
+	}
+
+	if payload.IsPatch() {
+		return r.previewPatch(payload, obj, live)
+	}
+
+	preview := &compv1alpha1.RemediationPreview{}
+	dryRun := obj.DeepCopy()
+	err = r.Client.Patch(context.TODO(), dryRun, client.Apply, client.DryRunAll, client.FieldOwner(previewFieldManager))
+	if kerrors.IsConflict(err) {
+		preview.Conflicts = conflictsFromError(err)
+		// Take the fields over in order to still get the diff
+		dryRun = obj.DeepCopy()
+		err = r.Client.Patch(context.TODO(), dryRun, client.Apply, client.DryRunAll,
+			client.FieldOwner(previewFieldManager), client.ForceOwnership)
+	}
+	if err != nil {
+		return nil, common.WrapNonRetriableCtrlError(fmt.Errorf("dry-run apply of the remediation object failed: %w", err))
+	}
+
+	var liveContent map[string]interface{}
+	if live != nil {
+		liveContent = live.Object
+	}
+	preview.Changes = diffObjects(liveContent, dryRun.Object)
+	return preview, nil
+}
+
+// previewPatch does a dry-run of the patch of a patch remediation and diffs
+// the result against the live object. Patches don't take fields over from
+// other field managers, so there are no conflicts to report.
+func (r *ReconcileComplianceRemediation) previewPatch(payload *compv1alpha1.ComplianceRemediationPayload, obj, live *unstructured.Unstructured) (*compv1alpha1.RemediationPreview, error) {
+	patch, err := rawPatch(payload, obj)
+	if err != nil {
+		return nil, common.WrapNonRetriableCtrlError(err)
+	}
+	dryRun := live.DeepCopy()
+	if err := r.applyPatch(dryRun, patch, client.DryRunAll, client.FieldOwner(previewFieldManager)); err != nil {
+		return nil, common.WrapNonRetriableCtrlError(fmt.Errorf("dry-run patch of the remediation object failed: %w", err))
+	}
+	return &compv1alpha1.RemediationPreview{
+		Changes: diffObjects(live.Object, dryRun.Object),
+	}, nil
+}
+
+// conflictsFromError returns the field manager conflicts of a failed
+// server-side apply
+func conflictsFromError(err error) []compv1alpha1.RemediationFieldConflict {
+	var conflicts []compv1alpha1.RemediationFieldConflict
+	status, ok := err.(kerrors.APIStatus)
+	if !ok || status.Status().Details == nil {
+		return conflicts
+	}
+	for _, cause := range status.Status().Details.Causes {
+		if cause.Type != metav1.CauseTypeFieldManagerConflict {
+			continue
+		}
+		conflicts = append(conflicts, compv1alpha1.RemediationFieldConflict{
+			Path:    cause.Field,
+			Message: cause.Message,
+		})
+	}
+	return conflicts
+}
+
+// diffObjects returns the leaf fields that differ between the live and the
+// desired object, sorted by path. Lists are compared as a whole.
+func diffObjects(live, desired map[string]interface{}) []compv1alpha1.RemediationFieldChange {
+	live = withoutServerManagedFields(live)
+	desired = withoutServerManagedFields(desired)
+
+	var changes []compv1alpha1.RemediationFieldChange
+	diffValues("", live, desired, &changes)
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes
+}
+
+func diffValues(path string, live, desired interface{}, changes *[]compv1alpha1.RemediationFieldChange) {
+	liveMap, liveIsMap := live.(map[string]interface{})
+	desiredMap, desiredIsMap := desired.(map[string]interface{})
+	// A map on one side and nothing on the other is diffed field by field
+	if (liveIsMap || live == nil) && (desiredIsMap || desired == nil) && (liveIsMap || desiredIsMap) {
+		for key, desiredValue := range desiredMap {
+			diffValues(path+"."+key, liveMap[key], desiredValue, changes)
+		}
+		for key, liveValue := range liveMap {
+			if _, ok := desiredMap[key]; !ok {
+				diffValues(path+"."+key, liveValue, nil, changes)
+			}
+		}
+		return
+	}
+
+	if reflect.DeepEqual(live, desired) {
+		return
+	}
+	*changes = append(*changes, compv1alpha1.RemediationFieldChange{
+		Path:    path,
+		Current: encodePreviewValue(live),
+		Desired: encodePreviewValue(desired),
+	})
+}
+
+func encodePreviewValue(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(encoded)
+}
+
+func withoutServerManagedFields(content map[string]interface{}) map[string]interface{} {
+	if content == nil {
+		return nil
+	}
+	cleaned := (&unstructured.Unstructured{Object: content}).DeepCopy().Object
+	delete(cleaned, "status")
+	for _, field := range previewIgnoredMetadata {
+		unstructured.RemoveNestedField(cleaned, "metadata", field)
+	}
+	return cleaned
+}
@@ -0,0 +1,133 @@
+package complianceremediation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/common"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/utils"
+)
+
+// invalidValuesMessagePrefix starts the error message of a remediation whose
+// values were rejected
+const invalidValuesMessagePrefix = "The remediation values ConfigMaps provide invalid values: "
+
+// needsValues returns whether the remediation can be rendered again with
+// the values of the remediation values ConfigMaps, because it lacks values
+// or took them from the ConfigMaps before
+func needsValues(rem *compv1alpha1.ComplianceRemediation) bool {
+	return rem.Spec.Current.Template != nil &&
+		(rem.HasAnnotation(compv1alpha1.RemediationUnsetValueAnnotation) ||
+			rem.HasAnnotation(compv1alpha1.RemediationSourcedValueAnnotation))
+}
+
+// resolveValues renders the remediation again with the values of the
+// remediation values ConfigMaps of its namespace. It returns whether the
+// remediation was updated.
+func (r *ReconcileComplianceRemediation) resolveValues(rem *compv1alpha1.ComplianceRemediation, logger logr.Logger) (bool, error) {
+	values, err := utils.GetRemediationValues(context.TODO(), r.Client, rem.Namespace)
+	if err != nil {
+		return false, err
+	}
+
+	remCopy := rem.DeepCopy()
+	resolved, err := utils.ResolveRemediationValues(remCopy, values)
+	var invalidErr *utils.InvalidRemediationValuesError
+	if errors.As(err, &invalidErr) {
+		// The remediation keeps the values it had, and the admin is told
+		// which of the provided ones were rejected
+		return r.reportInvalidValues(rem, invalidErr, logger)
+	} else if err != nil {
+		return false, common.WrapNonRetriableCtrlError(err)
+	} else if !resolved {
+		return false, nil
+	}
+	if reflect.DeepEqual(rem.Spec, remCopy.Spec) && reflect.DeepEqual(rem.Annotations, remCopy.Annotations) &&
+		reflect.DeepEqual(rem.Labels, remCopy.Labels) {
+		return false, nil
+	}
+
+	logger.Info("Rendering remediation with the values of the remediation values ConfigMaps")
+	if err := r.Client.Update(context.TODO(), remCopy); err != nil {
+		return false, fmt.Errorf("updating remediation with provided values: %w", err)
+	}
+	// An applied remediation is applied again with the new values instead
+	// of being seen as drifted
+	updateStatus := false
+	if isAppliedOrDrifted(rem) && !reflect.DeepEqual(rem.Spec.Current, remCopy.Spec.Current) {
+		remCopy.Status.ApplicationState = compv1alpha1.RemediationPending
+		remCopy.Status.Drift = nil
+		updateStatus = true
+	}
+	if strings.HasPrefix(remCopy.Status.ErrorMessage, invalidValuesMessagePrefix) {
+		remCopy.Status.ErrorMessage = ""
+		updateStatus = true
+	}
+	if updateStatus {
+		if err := r.Client.Status().Update(context.TODO(), remCopy); err != nil {
+			return false, fmt.Errorf("updating remediation status: %w", err)
+		}
+	}
+	r.Recorder.Eventf(rem, corev1.EventTypeNormal, "RemediationValuesResolved",
+		"The remediation was rendered with the values: %s", remCopy.Annotations[compv1alpha1.RemediationSourcedValueAnnotation])
+	return true, nil
+}
+
+// reportInvalidValues records in the status of the remediation why the
+// values it needs were rejected. It returns whether the status was updated.
+func (r *ReconcileComplianceRemediation) reportInvalidValues(rem *compv1alpha1.ComplianceRemediation, invalidErr *utils.InvalidRemediationValuesError, logger logr.Logger) (bool, error) {
+	message := invalidValuesMessagePrefix + strings.Join(invalidErr.Reasons, "; ")
+	if rem.Status.ErrorMessage == message {
+		return false, nil
+	}
+
+	logger.Info("The remediation values ConfigMaps provide invalid values", "Reasons", invalidErr.Reasons)
+	remCopy := rem.DeepCopy()
+	remCopy.Status.ErrorMessage = message
+	if err := r.Client.Status().Update(context.TODO(), remCopy); err != nil {
+		return false, fmt.Errorf("updating remediation status: %w", err)
+	}
+	r.Recorder.Event(rem, corev1.EventTypeWarning, "RemediationValuesInvalid", message)
+	return true, nil
+}
+
+type valuesMapper struct {
+	client.Client
+}
+
+// Map enqueues the remediations that lack values or took them from a
+// remediation values ConfigMap when such a ConfigMap changes
+func (m *valuesMapper) Map(ctx context.Context, obj client.Object) []reconcile.Request {
+	var requests []reconcile.Request
+	if _, ok := obj.GetLabels()[compv1alpha1.RemediationValuesLabel]; !ok {
+		return requests
+	}
+
+	remList := compv1alpha1.ComplianceRemediationList{}
+	if err := m.List(ctx, &remList, client.InNamespace(obj.GetNamespace())); err != nil {
+		return requests
+	}
+
+	for i := range remList.Items {
+		rem := &remList.Items[i]
+		if !needsValues(rem) {
+			continue
+		}
+		objKey := types.NamespacedName{
+			Name:      rem.GetName(),
+			Namespace: rem.GetNamespace(),
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: objKey})
+	}
+	return requests
+}
@@ -0,0 +1,200 @@
+package complianceremediationgroup
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/common"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/metrics"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/utils"
+)
+
+var log = logf.Log.WithName("remediationgroupctrl")
+
+// Add creates a new ComplianceRemediationGroup Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, met *metrics.Metrics, _ utils.CtlplaneSchedulingInfo, _ *kubernetes.Clientset) error {
+	return add(mgr, newReconciler(mgr, met))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, met *metrics.Metrics) reconcile.Reconciler {
+	return &ReconcileComplianceRemediationGroup{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: common.NewSafeRecorder("remediationgroupctrl", mgr),
+		Metrics:  met,
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	remMapper := &remediationMapper{}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("complianceremediationgroup-controller").
+		For(&compv1alpha1.ComplianceRemediationGroup{}).
+		Watches(&compv1alpha1.ComplianceRemediation{}, handler.EnqueueRequestsFromMapFunc(remMapper.Map)).
+		Complete(r)
+}
+
+// blank assignment to verify that ReconcileComplianceRemediationGroup implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileComplianceRemediationGroup{}
+
+// ReconcileComplianceRemediationGroup reconciles a ComplianceRemediationGroup object
+type ReconcileComplianceRemediationGroup struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	Client   client.Client
+	Scheme   *runtime.Scheme
+	Recorder *common.SafeRecorder
+	Metrics  *metrics.Metrics
+}
+
+// Reconcile rolls the whole group back if one of its members can't be
+// applied, and reports the state of the members in the group status. The
+// ComplianceRemediation controller applies the members in order.
+func (r *ReconcileComplianceRemediationGroup) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling ComplianceRemediationGroup")
+
+	instance := &compv1alpha1.ComplianceRemediationGroup{}
+	err := r.Client.Get(ctx, request.NamespacedName, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Return and don't requeue
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	remList := &compv1alpha1.ComplianceRemediationList{}
+	if err := r.Client.List(ctx, remList, client.InNamespace(instance.Namespace),
+		client.MatchingLabels{compv1alpha1.RemediationGroupLabel: instance.Name}); err != nil {
+		return reconcile.Result{}, err
+	}
+	members := make([]*compv1alpha1.ComplianceRemediation, 0, len(instance.Spec.Members))
+	for _, member := range instance.SortedMembers() {
+		for i := range remList.Items {
+			if remList.Items[i].Name == member.Name {
+				members = append(members, &remList.Items[i])
+				break
+			}
+		}
+	}
+
+	if failed := getFailedMember(members); failed != nil {
+		if err := r.rollbackGroup(ctx, instance, members, failed, reqLogger); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	return r.updateStatus(ctx, instance, func(s *compv1alpha1.ComplianceRemediationGroupStatus) {
+		s.Phase, s.ErrorMessage = getPhase(members)
+		s.Members = make([]compv1alpha1.RemediationGroupMemberStatus, 0, len(members))
+		for _, rem := range members {
+			s.Members = append(s.Members, compv1alpha1.RemediationGroupMemberStatus{
+				Name:             rem.Name,
+				Order:            instance.GetMember(rem.Name).Order,
+				Apply:            rem.Spec.Apply,
+				ApplicationState: rem.Status.ApplicationState,
+			})
+		}
+	})
+}
+
+// rollbackGroup un-applies all members of the group, since one of them
+// couldn't be applied. The members are rolled back like the remediations of
+// a ComplianceSuite batch, so the suite doesn't apply them again on its own.
+func (r *ReconcileComplianceRemediationGroup) rollbackGroup(ctx context.Context, group *compv1alpha1.ComplianceRemediationGroup,
+	members []*compv1alpha1.ComplianceRemediation, failed *compv1alpha1.ComplianceRemediation, logger logr.Logger) error {
+	reason := fmt.Sprintf("The remediation %s of the group %s couldn't be applied: %s",
+		failed.Name, group.Name, failed.Status.ErrorMessage)
+	logger.Info("Rolling back remediation group", "Failed", failed.Name)
+
+	for _, rem := range members {
+		if !rem.Spec.Apply {
+			continue
+		}
+		rem.Spec.Apply = false
+		if rem.Annotations == nil {
+			rem.Annotations = make(map[string]string)
+		}
+		rem.Annotations[compv1alpha1.RemediationRolledBackAnnotation] = reason
+		if err := r.Client.Update(ctx, rem); err != nil {
+			return fmt.Errorf("couldn't roll back remediation %s: %w", rem.Name, err)
+		}
+	}
+
+	r.Recorder.Event(group, corev1.EventTypeWarning, "RemediationGroupRolledBack", reason)
+	return nil
+}
+
+func (r *ReconcileComplianceRemediationGroup) updateStatus(ctx context.Context, group *compv1alpha1.ComplianceRemediationGroup, mutate func(s *compv1alpha1.ComplianceRemediationGroupStatus)) (reconcile.Result, error) {
+	groupCopy := group.DeepCopy()
+	mutate(&groupCopy.Status)
+	if reflect.DeepEqual(group.Status, groupCopy.Status) {
+		return reconcile.Result{}, nil
+	}
+
+	if err := r.Client.Status().Update(ctx, groupCopy); err != nil {
+		return reconcile.Result{}, fmt.Errorf("couldn't update ComplianceRemediationGroup status: %w", err)
+	}
+	return reconcile.Result{}, nil
+}
+
+// getFailedMember returns the first member that is to be applied but failed
+func getFailedMember(members []*compv1alpha1.ComplianceRemediation) *compv1alpha1.ComplianceRemediation {
+	for _, rem := range members {
+		if rem.Spec.Apply && rem.Status.ApplicationState == compv1alpha1.RemediationError {
+			return rem
+		}
+	}
+	return nil
+}
+
+func getPhase(members []*compv1alpha1.ComplianceRemediation) (compv1alpha1.RemediationGroupPhase, string) {
+	for _, rem := range members {
+		if reason, ok := rem.GetAnnotations()[compv1alpha1.RemediationRolledBackAnnotation]; ok && !rem.Spec.Apply {
+			return compv1alpha1.RemediationGroupRolledBack, reason
+		}
+	}
+	for _, rem := range members {
+		if rem.Status.ApplicationState == compv1alpha1.RemediationError {
+			return compv1alpha1.RemediationGroupError, rem.Status.ErrorMessage
+		}
+	}
+
+	allApplied, anyApply, anyInCluster := len(members) > 0, false, false
+	for _, rem := range members {
+		inCluster := rem.IsInCluster()
+		allApplied = allApplied && rem.Spec.Apply && inCluster
+		anyApply = anyApply || rem.Spec.Apply
+		anyInCluster = anyInCluster || inCluster
+	}
+	switch {
+	case allApplied:
+		return compv1alpha1.RemediationGroupApplied, ""
+	case anyApply:
+		return compv1alpha1.RemediationGroupApplying, ""
+	case anyInCluster:
+		return compv1alpha1.RemediationGroupUnapplying, ""
+	}
+	return compv1alpha1.RemediationGroupNotApplied, ""
+}
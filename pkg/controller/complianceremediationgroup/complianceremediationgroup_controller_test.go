@@ -0,0 +1,174 @@
+package complianceremediationgroup
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/ComplianceAsCode/compliance-operator/pkg/apis"
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/common"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/metrics"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/metrics/metricsfakes"
+)
+
+const (
+	namespace = "test-ns"
+	groupName = "e8-kubelet-tls"
+)
+
+func newMember(name, kind string, apply bool, state compv1alpha1.RemediationApplicationState) *compv1alpha1.ComplianceRemediation {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind(kind)
+	obj.SetName(name + "-obj")
+
+	return &compv1alpha1.ComplianceRemediation{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				compv1alpha1.RemediationGroupLabel: groupName,
+			},
+		},
+		Spec: compv1alpha1.ComplianceRemediationSpec{
+			ComplianceRemediationSpecMeta: compv1alpha1.ComplianceRemediationSpecMeta{
+				Apply: apply,
+			},
+			Current: compv1alpha1.ComplianceRemediationPayload{
+				Object: obj,
+			},
+		},
+		Status: compv1alpha1.ComplianceRemediationStatus{
+			ApplicationState: state,
+		},
+	}
+}
+
+var _ = Describe("ComplianceRemediationGroup controller", func() {
+	var (
+		ctx        = context.Background()
+		groupKey   = types.NamespacedName{Name: groupName, Namespace: namespace}
+		reconciler *ReconcileComplianceRemediationGroup
+	)
+
+	setup := func(members ...*compv1alpha1.ComplianceRemediation) {
+		cscheme := scheme.Scheme
+		err := apis.AddToScheme(cscheme)
+		Expect(err).To(BeNil())
+
+		group := &compv1alpha1.ComplianceRemediationGroup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      groupName,
+				Namespace: namespace,
+			},
+			Spec: compv1alpha1.ComplianceRemediationGroupSpec{
+				Members: []compv1alpha1.RemediationGroupMember{
+					{Name: "e8-kubelet-tls-1", Order: 1},
+					{Name: "e8-kubelet-tls", Order: 0},
+				},
+			},
+		}
+		objs := []runtime.Object{group}
+		for _, member := range members {
+			objs = append(objs, member)
+		}
+
+		client := fake.NewClientBuilder().
+			WithScheme(cscheme).
+			WithStatusSubresource(&compv1alpha1.ComplianceRemediationGroup{}, &compv1alpha1.ComplianceRemediation{}).
+			WithRuntimeObjects(objs...).
+			Build()
+
+		mockMetrics := metrics.NewMetrics(&metricsfakes.FakeImpl{})
+		err = mockMetrics.Register()
+		Expect(err).To(BeNil())
+
+		reconciler = &ReconcileComplianceRemediationGroup{
+			Client:   client,
+			Scheme:   cscheme,
+			Recorder: &common.SafeRecorder{},
+			Metrics:  mockMetrics,
+		}
+	}
+
+	reconcileGroup := func() *compv1alpha1.ComplianceRemediationGroup {
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: groupKey})
+		Expect(err).To(BeNil())
+		found := &compv1alpha1.ComplianceRemediationGroup{}
+		Expect(reconciler.Client.Get(ctx, groupKey, found)).To(BeNil())
+		return found
+	}
+
+	getRemediation := func(name string) *compv1alpha1.ComplianceRemediation {
+		rem := &compv1alpha1.ComplianceRemediation{}
+		Expect(reconciler.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, rem)).To(BeNil())
+		return rem
+	}
+
+	It("should list the members in the order they're applied", func() {
+		setup(
+			newMember("e8-kubelet-tls", "MachineConfig", true, compv1alpha1.RemediationApplied),
+			newMember("e8-kubelet-tls-1", "KubeletConfig", true, compv1alpha1.RemediationPending),
+		)
+
+		group := reconcileGroup()
+		Expect(group.Status.Phase).To(Equal(compv1alpha1.RemediationGroupApplying))
+		Expect(group.Status.Members).To(Equal([]compv1alpha1.RemediationGroupMemberStatus{
+			{Name: "e8-kubelet-tls", Order: 0, Apply: true, ApplicationState: compv1alpha1.RemediationApplied},
+			{Name: "e8-kubelet-tls-1", Order: 1, Apply: true, ApplicationState: compv1alpha1.RemediationPending},
+		}))
+	})
+
+	It("should be applied once all members are applied", func() {
+		setup(
+			newMember("e8-kubelet-tls", "MachineConfig", true, compv1alpha1.RemediationApplied),
+			newMember("e8-kubelet-tls-1", "KubeletConfig", true, compv1alpha1.RemediationApplied),
+		)
+		Expect(reconcileGroup().Status.Phase).To(Equal(compv1alpha1.RemediationGroupApplied))
+	})
+
+	It("should be un-applying while a member is still applied", func() {
+		setup(
+			newMember("e8-kubelet-tls", "MachineConfig", false, compv1alpha1.RemediationApplied),
+			newMember("e8-kubelet-tls-1", "KubeletConfig", false, compv1alpha1.RemediationNotApplied),
+		)
+		Expect(reconcileGroup().Status.Phase).To(Equal(compv1alpha1.RemediationGroupUnapplying))
+	})
+
+	It("should roll back the whole group if a member fails", func() {
+		failed := newMember("e8-kubelet-tls-1", "KubeletConfig", true, compv1alpha1.RemediationError)
+		failed.Status.ErrorMessage = "invalid KubeletConfig"
+		setup(
+			newMember("e8-kubelet-tls", "MachineConfig", true, compv1alpha1.RemediationApplied),
+			failed,
+		)
+
+		group := reconcileGroup()
+		Expect(group.Status.Phase).To(Equal(compv1alpha1.RemediationGroupRolledBack))
+		Expect(group.Status.ErrorMessage).To(ContainSubstring("e8-kubelet-tls-1"))
+		Expect(group.Status.ErrorMessage).To(ContainSubstring("invalid KubeletConfig"))
+
+		for _, name := range []string{"e8-kubelet-tls", "e8-kubelet-tls-1"} {
+			rem := getRemediation(name)
+			Expect(rem.Spec.Apply).To(BeFalse())
+			Expect(rem.GetAnnotations()).To(HaveKey(compv1alpha1.RemediationRolledBackAnnotation))
+		}
+	})
+
+	It("should not list missing members", func() {
+		setup(newMember("e8-kubelet-tls", "MachineConfig", false, compv1alpha1.RemediationNotApplied))
+
+		group := reconcileGroup()
+		Expect(group.Status.Phase).To(Equal(compv1alpha1.RemediationGroupNotApplied))
+		Expect(group.Status.Members).To(HaveLen(1))
+	})
+})
@@ -0,0 +1,13 @@
+package complianceremediationgroup
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestComplianceremediationgroup(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Complianceremediationgroup Suite")
+}
@@ -0,0 +1,28 @@
+package complianceremediationgroup
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+)
+
+type remediationMapper struct{}
+
+// Map enqueues the group of the remediation, so that the group status
+// follows the state of its members
+func (r *remediationMapper) Map(ctx context.Context, obj client.Object) []reconcile.Request {
+	name, ok := obj.GetLabels()[compv1alpha1.RemediationGroupLabel]
+	if !ok {
+		return nil
+	}
+
+	objKey := types.NamespacedName{
+		Name:      name,
+		Namespace: obj.GetNamespace(),
+	}
+	return []reconcile.Request{{NamespacedName: objKey}}
+}
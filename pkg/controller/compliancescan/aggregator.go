@@ -4,11 +4,15 @@ import (
 	"context"
 	"fmt"
 	"path"
+	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
 	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/common"
@@ -17,6 +21,17 @@ import (
 
 const aggregatorSA = "remediation-aggregator"
 
+const aggregatorContainerName = "aggregator"
+
+// A failed aggregation is retried after a backoff that doubles with each
+// retry, up to a maximum. Once it was retried too often, the scan ends with
+// an error.
+const (
+	aggregatorRetryBaseBackoff = 10 * time.Second
+	aggregatorRetryMaxBackoff  = 5 * time.Minute
+	aggregatorMaxRetries       = 5
+)
+
 func getAggregatorPodName(scanName string) string {
 	return utils.DNSLengthName("aggregator-pod-", "aggregator-pod-%s", scanName)
 }
@@ -32,7 +47,7 @@ func (r *ReconcileComplianceScan) newAggregatorPod(scanInstance *compv1alpha1.Co
 	falseP := false
 	trueP := true
 
-	return &corev1.Pod{
+	pod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      podName,
 			Namespace: common.GetComplianceOperatorNamespace(),
@@ -76,14 +91,9 @@ func (r *ReconcileComplianceScan) newAggregatorPod(scanInstance *compv1alpha1.Co
 			},
 			Containers: []corev1.Container{
 				{
-					Name:  "aggregator",
-					Image: utils.GetComponentImage(utils.OPERATOR),
-					Command: []string{
-						"compliance-operator", "aggregator",
-						"--content=" + absContentPath(scanInstance.Spec.Content),
-						"--scan=" + scanInstance.Name,
-						"--namespace=" + scanInstance.Namespace,
-					},
+					Name:    aggregatorContainerName,
+					Image:   utils.GetComponentImage(utils.OPERATOR),
+					Command: getAggregatorCommand(scanInstance),
 					SecurityContext: &corev1.SecurityContext{
 						AllowPrivilegeEscalation: &falseP,
 						ReadOnlyRootFilesystem:   &trueP,
@@ -100,7 +110,9 @@ func (r *ReconcileComplianceScan) newAggregatorPod(scanInstance *compv1alpha1.Co
 					},
 				},
 			},
-			RestartPolicy: corev1.RestartPolicyOnFailure,
+			// Failed aggregations are retried by the scan controller with
+			// a backoff, see retryFailedAggregator
+			RestartPolicy: corev1.RestartPolicyNever,
 			Volumes: []corev1.Volume{
 				{
 					Name: "content-dir",
@@ -111,6 +123,55 @@ func (r *ReconcileComplianceScan) newAggregatorPod(scanInstance *compv1alpha1.Co
 			},
 		},
 	}
+
+	// The HTML report is stored with the raw results through the result
+	// server, with the client certificate of the scan
+	if scanInstance.Spec.RawResultStorage.HTMLReport {
+		container := &pod.Spec.Containers[0]
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      "tls",
+			MountPath: "/etc/pki/tls",
+			ReadOnly:  true,
+		})
+		pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+			Name: "tls",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: ClientCertPrefix + scanInstance.Name,
+				},
+			},
+		})
+	}
+	return pod
+}
+
+func getAggregatorCommand(scanInstance *compv1alpha1.ComplianceScan) []string {
+	command := []string{
+		"compliance-operator", "aggregator",
+		"--content=" + absContentPath(scanInstance.Spec.Content),
+		"--scan=" + scanInstance.Name,
+		"--namespace=" + scanInstance.Namespace,
+	}
+	// Unset settings keep the defaults of the aggregator
+	settings := scanInstance.Spec.Aggregator
+	if settings.Concurrency > 0 {
+		command = append(command, fmt.Sprintf("--concurrency=%d", settings.Concurrency))
+	}
+	if settings.QPS > 0 {
+		command = append(command, fmt.Sprintf("--qps=%d", settings.QPS))
+	}
+	if settings.Burst > 0 {
+		command = append(command, fmt.Sprintf("--burst=%d", settings.Burst))
+	}
+	if scanInstance.Spec.RawResultStorage.HTMLReport {
+		command = append(command,
+			"--resultserveruri="+getResultServerURI(scanInstance),
+			"--tls-client-cert=/etc/pki/tls/tls.crt",
+			"--tls-client-key=/etc/pki/tls/tls.key",
+			"--tls-ca=/etc/pki/tls/ca.crt",
+		)
+	}
+	return command
 }
 
 func (r *ReconcileComplianceScan) launchAggregatorPod(scanInstance *compv1alpha1.ComplianceScan, pod *corev1.Pod, logger logr.Logger) error {
@@ -142,3 +203,116 @@ func isAggregatorRunning(r *ReconcileComplianceScan, scanInstance *compv1alpha1.
 	podName := getAggregatorPodName(scanInstance.Name)
 	return isPodRunning(r, podName, common.GetComplianceOperatorNamespace(), podTimeoutDisable, logger)
 }
+
+// getAggregatorPod returns the aggregator pod of the scan
+func (r *ReconcileComplianceScan) getAggregatorPod(scanInstance *compv1alpha1.ComplianceScan) (*corev1.Pod, error) {
+	pod := &corev1.Pod{}
+	key := types.NamespacedName{Name: getAggregatorPodName(scanInstance.Name), Namespace: common.GetComplianceOperatorNamespace()}
+	if err := r.Client.Get(context.TODO(), key, pod); err != nil {
+		return nil, err
+	}
+	return pod, nil
+}
+
+// getAggregatorTermination returns the state of the aggregator container
+// once it terminated, or nil if it didn't
+func getAggregatorTermination(pod *corev1.Pod) *corev1.ContainerStateTerminated {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == aggregatorContainerName {
+			return status.State.Terminated
+		}
+	}
+	return nil
+}
+
+// getAggregatorMessage returns the termination message of the aggregator.
+// The aggregator reports the results it couldn't create in it.
+func getAggregatorMessage(pod *corev1.Pod) string {
+	if terminated := getAggregatorTermination(pod); terminated != nil {
+		return strings.TrimSpace(terminated.Message)
+	}
+	return ""
+}
+
+// appendWarning appends a warning to the warnings of a scan, one per line
+func appendWarning(warnings, warning string) string {
+	if warnings == "" {
+		return warning
+	}
+	return warnings + "\n" + warning
+}
+
+// aggregatorRetryBackoff returns how long to wait before retrying a failed
+// aggregation that was already retried the given number of times
+func aggregatorRetryBackoff(retries int) time.Duration {
+	backoff := aggregatorRetryBaseBackoff
+	for i := 0; i < retries; i++ {
+		backoff *= 2
+		if backoff >= aggregatorRetryMaxBackoff {
+			return aggregatorRetryMaxBackoff
+		}
+	}
+	return backoff
+}
+
+// retryFailedAggregator deletes the failed aggregator pod once the backoff
+// of the scan elapsed, so that a new aggregator pod is launched. The
+// aggregator picks up where the failed one stopped. Once the aggregator
+// failed too often, the scan is done with an error instead.
+func (r *ReconcileComplianceScan) retryFailedAggregator(instance *compv1alpha1.ComplianceScan, pod *corev1.Pod, logger logr.Logger) (reconcile.Result, error) {
+	if instance.Status.AggregatorRetries >= aggregatorMaxRetries {
+		return r.failAggregation(instance, pod, logger)
+	}
+
+	backoff := aggregatorRetryBackoff(instance.Status.AggregatorRetries)
+	failedAt := pod.CreationTimestamp.Time
+	if terminated := getAggregatorTermination(pod); terminated != nil && !terminated.FinishedAt.IsZero() {
+		failedAt = terminated.FinishedAt.Time
+	}
+	if wait := time.Until(failedAt.Add(backoff)); wait > 0 {
+		logger.Info("The aggregator failed, waiting before retrying", "retryIn", wait)
+		return reconcile.Result{RequeueAfter: wait}, nil
+	}
+
+	msg := getAggregatorMessage(pod)
+	if msg == "" {
+		msg = pod.Status.Message
+	}
+	logger.Info("Retrying the failed aggregator", "retries", instance.Status.AggregatorRetries, "message", msg)
+	if r.Recorder != nil {
+		r.Recorder.Eventf(instance, corev1.EventTypeWarning, "AggregatorFailed",
+			"The aggregator failed and is retried: %s", msg)
+	}
+
+	if err := r.Client.Delete(context.TODO(), pod); err != nil && !errors.IsNotFound(err) {
+		return reconcile.Result{}, err
+	}
+	instanceCopy := instance.DeepCopy()
+	instanceCopy.Status.AggregatorRetries++
+	if err := r.Client.Status().Update(context.TODO(), instanceCopy); err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{Requeue: true}, nil
+}
+
+// failAggregation finishes the scan with an error because the aggregator
+// kept failing. The failed aggregator pod is kept for inspection.
+func (r *ReconcileComplianceScan) failAggregation(instance *compv1alpha1.ComplianceScan, pod *corev1.Pod, logger logr.Logger) (reconcile.Result, error) {
+	msg := getAggregatorMessage(pod)
+	if msg == "" {
+		msg = pod.Status.Message
+	}
+	logger.Info("The aggregator failed too often, giving up", "retries", instance.Status.AggregatorRetries, "message", msg)
+
+	instanceCopy := instance.DeepCopy()
+	instanceCopy.Status.Phase = compv1alpha1.PhaseDone
+	instanceCopy.Status.EndTimestamp = &metav1.Time{Time: time.Now()}
+	instanceCopy.Status.Result = compv1alpha1.ResultError
+	instanceCopy.Status.ErrorMessage = fmt.Sprintf("The aggregator failed %d times: %s", instanceCopy.Status.AggregatorRetries+1, msg)
+	instanceCopy.Status.SetConditionAggregationFailed()
+	if err := r.updateStatusWithEvent(instanceCopy, logger); err != nil {
+		return reconcile.Result{}, err
+	}
+	r.Metrics.IncComplianceScanStatus(instanceCopy.Name, instanceCopy.Status)
+	return reconcile.Result{}, nil
+}
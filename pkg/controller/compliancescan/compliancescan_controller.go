@@ -303,6 +303,8 @@ func (r *ReconcileComplianceScan) phasePendingHandler(instance *compv1alpha1.Com
 	instance.Status.Result = compv1alpha1.ResultNotAvailable
 	instance.Status.StartTimestamp = &metav1.Time{Time: time.Now()}
 	instance.Status.EndTimestamp = nil
+	// The aggregator records whether the report of this run was stored
+	instance.Status.HTMLReportStored = nil
 	err := r.Client.Status().Update(context.TODO(), instance)
 	if err != nil {
 		logger.Error(err, "Cannot update the status")
@@ -611,6 +613,14 @@ func (r *ReconcileComplianceScan) phaseAggregatingHandler(h scanTypeHandler, log
 		logger.Error(err, "Failed to launch aggregator pod", "aggregator", aggregator)
 		return reconcile.Result{}, err
 	}
+	aggregatorPod, err := r.getAggregatorPod(instance)
+	if err == nil && aggregatorPod.DeletionTimestamp != nil {
+		// The pod of a retried aggregation is still going away
+		return reconcile.Result{Requeue: true, RequeueAfter: requeueAfterDefault / 2}, nil
+	} else if err == nil && aggregatorPod.Status.Phase == corev1.PodFailed {
+		return r.retryFailedAggregator(instance, aggregatorPod, logger)
+	}
+
 	running, err := isAggregatorRunning(r, instance, logger)
 	if errors.IsNotFound(err) {
 		// Suppress loud error message by requeueing
@@ -634,6 +644,13 @@ func (r *ReconcileComplianceScan) phaseAggregatingHandler(h scanTypeHandler, log
 
 	logger.Info("Moving on to the Done phase")
 
+	// The aggregator reports the results it couldn't create
+	if aggregatorPod, err := r.getAggregatorPod(instance); err == nil {
+		if msg := getAggregatorMessage(aggregatorPod); msg != "" {
+			instance.Status.Warnings = appendWarning(instance.Status.Warnings, msg)
+		}
+	}
+
 	result, isReady, err := gatherResults(r, h)
 
 	// We only wait if there are no errors.
@@ -728,6 +745,7 @@ func (r *ReconcileComplianceScan) phaseDoneHandler(h scanTypeHandler, instance *
 			instanceCopy.Status.Phase = compv1alpha1.PhasePending
 			instanceCopy.Status.Result = compv1alpha1.ResultNotAvailable
 			instanceCopy.Status.StartTimestamp = &metav1.Time{Time: time.Now()}
+			instanceCopy.Status.AggregatorRetries = 0
 			if instance.Status.CurrentIndex == math.MaxInt64 {
 				instanceCopy.Status.CurrentIndex = 0
 			} else {
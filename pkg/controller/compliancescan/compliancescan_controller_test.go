@@ -12,12 +12,14 @@ import (
 	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/common"
 	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/metrics"
 	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/metrics/metricsfakes"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/utils"
 	"github.com/go-logr/logr"
 	"github.com/go-logr/zapr"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -25,6 +27,7 @@ import (
 	kube "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	restclient "k8s.io/client-go/rest/fake"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -423,6 +426,283 @@ var _ = Describe("Testing compliancescan controller phases", func() {
 		})
 	})
 
+	Context("On the AGGREGATING phase", func() {
+		Context("With a failed aggregator pod", func() {
+			var aggregatorPod *corev1.Pod
+			var recorder *record.FakeRecorder
+
+			BeforeEach(func() {
+				recorder = record.NewFakeRecorder(10)
+				reconciler.Recorder = recorder
+
+				aggregatorPod = &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      getAggregatorPodName(compliancescaninstance.Name),
+						Namespace: common.GetComplianceOperatorNamespace(),
+					},
+					Status: corev1.PodStatus{
+						Phase: corev1.PodFailed,
+						ContainerStatuses: []corev1.ContainerStatus{
+							{
+								Name: aggregatorContainerName,
+								State: corev1.ContainerState{
+									Terminated: &corev1.ContainerStateTerminated{
+										ExitCode:   1,
+										Message:    "cannot mark ConfigMap as processed\n",
+										FinishedAt: metav1.Now(),
+									},
+								},
+							},
+						},
+					},
+				}
+				err := reconciler.Client.Create(context.TODO(), aggregatorPod)
+				Expect(err).To(BeNil())
+
+				// Set state to AGGREGATING
+				compliancescaninstance.Status.Phase = compv1alpha1.PhaseAggregating
+				err = reconciler.Client.Status().Update(context.TODO(), compliancescaninstance)
+				Expect(err).To(BeNil())
+			})
+
+			It("should wait for the backoff before retrying", func() {
+				result, err := reconciler.retryFailedAggregator(compliancescaninstance, aggregatorPod, logger)
+				Expect(err).To(BeNil())
+				Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+				Expect(result.RequeueAfter).To(BeNumerically("<=", aggregatorRetryBaseBackoff))
+
+				// The pod is kept until the backoff elapsed
+				pod, err := reconciler.getAggregatorPod(compliancescaninstance)
+				Expect(err).To(BeNil())
+				Expect(pod.Status.Phase).To(Equal(corev1.PodFailed))
+				Expect(recorder.Events).To(BeEmpty())
+			})
+
+			It("should delete the pod and count the retry once the backoff elapsed", func() {
+				finishedAt := metav1.NewTime(time.Now().Add(-aggregatorRetryBaseBackoff))
+				aggregatorPod.Status.ContainerStatuses[0].State.Terminated.FinishedAt = finishedAt
+
+				result, err := reconciler.retryFailedAggregator(compliancescaninstance, aggregatorPod, logger)
+				Expect(err).To(BeNil())
+				Expect(result.Requeue).To(BeTrue())
+
+				_, err = reconciler.getAggregatorPod(compliancescaninstance)
+				Expect(errors.IsNotFound(err)).To(BeTrue())
+
+				scan := &compv1alpha1.ComplianceScan{}
+				key := types.NamespacedName{
+					Name:      compliancescaninstance.Name,
+					Namespace: compliancescaninstance.Namespace,
+				}
+				err = reconciler.Client.Get(context.TODO(), key, scan)
+				Expect(err).To(BeNil())
+				Expect(scan.Status.AggregatorRetries).To(Equal(1))
+				Expect(scan.Status.Phase).To(Equal(compv1alpha1.PhaseAggregating))
+
+				Expect(recorder.Events).To(Receive(ContainSubstring("cannot mark ConfigMap as processed")))
+			})
+
+			It("should finish the scan with an error once the aggregator failed too often", func() {
+				compliancescaninstance.Status.AggregatorRetries = aggregatorMaxRetries
+				err := reconciler.Client.Status().Update(context.TODO(), compliancescaninstance)
+				Expect(err).To(BeNil())
+
+				result, err := reconciler.retryFailedAggregator(compliancescaninstance, aggregatorPod, logger)
+				Expect(err).To(BeNil())
+				Expect(result.Requeue).To(BeFalse())
+
+				// The failed pod is kept for inspection
+				_, err = reconciler.getAggregatorPod(compliancescaninstance)
+				Expect(err).To(BeNil())
+
+				scan := &compv1alpha1.ComplianceScan{}
+				key := types.NamespacedName{
+					Name:      compliancescaninstance.Name,
+					Namespace: compliancescaninstance.Namespace,
+				}
+				err = reconciler.Client.Get(context.TODO(), key, scan)
+				Expect(err).To(BeNil())
+				Expect(scan.Status.Phase).To(Equal(compv1alpha1.PhaseDone))
+				Expect(scan.Status.Result).To(Equal(compv1alpha1.ResultError))
+				Expect(scan.Status.ErrorMessage).To(ContainSubstring("cannot mark ConfigMap as processed"))
+				Expect(scan.Status.Conditions.GetCondition("Ready").Reason).To(Equal(compv1alpha1.ConditionReason("AggregationFailed")))
+			})
+
+			It("should read the termination message of the aggregator", func() {
+				Expect(getAggregatorMessage(aggregatorPod)).To(Equal("cannot mark ConfigMap as processed"))
+				Expect(getAggregatorMessage(&corev1.Pod{})).To(BeEmpty())
+			})
+		})
+
+		Context("Computing the retry backoff", func() {
+			It("should double the backoff with each retry", func() {
+				Expect(aggregatorRetryBackoff(0)).To(Equal(aggregatorRetryBaseBackoff))
+				Expect(aggregatorRetryBackoff(1)).To(Equal(2 * aggregatorRetryBaseBackoff))
+				Expect(aggregatorRetryBackoff(2)).To(Equal(4 * aggregatorRetryBaseBackoff))
+			})
+
+			It("should not go over the maximum backoff", func() {
+				Expect(aggregatorRetryBackoff(10)).To(Equal(aggregatorRetryMaxBackoff))
+				Expect(aggregatorRetryBackoff(1000)).To(Equal(aggregatorRetryMaxBackoff))
+			})
+		})
+
+		Context("Storing the HTML report", func() {
+			It("should not pass the result server to the aggregator by default", func() {
+				pod := reconciler.newAggregatorPod(compliancescaninstance, logger)
+				Expect(pod.Spec.Containers[0].Command).NotTo(ContainElement(HavePrefix("--resultserveruri=")))
+				Expect(pod.Spec.Volumes).To(HaveLen(1))
+			})
+
+			It("should pass the result server and its client certificate to the aggregator", func() {
+				compliancescaninstance.Spec.RawResultStorage.HTMLReport = true
+				pod := reconciler.newAggregatorPod(compliancescaninstance, logger)
+				Expect(pod.Spec.Containers[0].Command).To(ContainElement("--resultserveruri=" + getResultServerURI(compliancescaninstance)))
+				Expect(pod.Spec.Containers[0].Command).To(ContainElement("--tls-client-cert=/etc/pki/tls/tls.crt"))
+				Expect(pod.Spec.Containers[0].VolumeMounts).To(ContainElement(HaveField("MountPath", "/etc/pki/tls")))
+				Expect(pod.Spec.Volumes).To(ContainElement(HaveField("Secret.SecretName", ClientCertPrefix+compliancescaninstance.Name)))
+			})
+		})
+	})
+
+	Context("Replicating the tailoring", func() {
+		var origCM *corev1.ConfigMap
+		privKey := types.NamespacedName{Name: "test-tailoring", Namespace: common.GetComplianceOperatorNamespace()}
+
+		replicate := func() *corev1.ConfigMap {
+			err := reconciler.reconcileReplicatedTailoringConfigMap(compliancescaninstance, origCM.Name, origCM.Namespace,
+				privKey.Name, privKey.Namespace, compliancescaninstance.Name, logger)
+			Expect(err).To(BeNil())
+			privCM := &corev1.ConfigMap{}
+			Expect(reconciler.Client.Get(context.TODO(), privKey, privCM)).To(BeNil())
+			return privCM
+		}
+
+		BeforeEach(func() {
+			origCM = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "custom-tp",
+					Namespace: "tp-ns",
+				},
+				Data: map[string]string{
+					"tailoring.xml":       "<tailoring/>",
+					utils.CustomRulesFile: `[{"metadata":{"name":"oauth-token-max-age"}}]`,
+				},
+			}
+			Expect(reconciler.Client.Create(context.TODO(), origCM)).To(BeNil())
+		})
+
+		It("should copy the custom rules along with the tailoring", func() {
+			privCM := replicate()
+			Expect(privCM.Data).To(HaveKeyWithValue("tailoring.xml", "<tailoring/>"))
+			Expect(privCM.Data).To(HaveKeyWithValue(utils.CustomRulesFile, origCM.Data[utils.CustomRulesFile]))
+		})
+
+		It("should remove the custom rules once the tailored profile has none", func() {
+			replicate()
+			delete(origCM.Data, utils.CustomRulesFile)
+			Expect(reconciler.Client.Update(context.TODO(), origCM)).To(BeNil())
+
+			privCM := replicate()
+			Expect(privCM.Data).To(HaveKeyWithValue("tailoring.xml", "<tailoring/>"))
+			Expect(privCM.Data).ToNot(HaveKey(utils.CustomRulesFile))
+		})
+
+		It("should have the platform scan check the custom rules of the tailoring", func() {
+			compliancescaninstance.Spec.TailoringConfigMap = &compv1alpha1.TailoringConfigMapRef{Name: origCM.Name}
+			pod := reconciler.newPlatformScanPod(compliancescaninstance, logger)
+			Expect(pod.Spec.InitContainers).ToNot(BeEmpty())
+			var collectorCmd []string
+			for _, container := range pod.Spec.InitContainers {
+				if container.Name == PlatformScanResourceCollectorName {
+					collectorCmd = container.Command
+				}
+			}
+			Expect(collectorCmd).To(ContainElement("--custom-rules=" + OpenScapTailoringDir + "/" + utils.CustomRulesFile))
+			Expect(collectorCmd).To(ContainElement("--custom-rules-output-file=" + customRulesOutputFile))
+			Expect(pod.Spec.Containers[0].Command).To(ContainElement("--custom-rules-output-file=" + customRulesOutputFile))
+		})
+
+		It("should have the node scans run the probes of the tailoring", func() {
+			pod := newScanPodForNode(compliancescaninstance, nodeinstance1, logger)
+			for _, container := range pod.Spec.InitContainers {
+				Expect(container.Name).ToNot(Equal(NodeScanProbeRunnerName))
+			}
+
+			compliancescaninstance.Spec.TailoringConfigMap = &compv1alpha1.TailoringConfigMapRef{Name: origCM.Name}
+			pod = newScanPodForNode(compliancescaninstance, nodeinstance1, logger)
+			Expect(reconciler.addTailoringVolume(getReplicatedTailoringCMName(compliancescaninstance.Name), pod)).To(BeNil())
+			var probeRunner *corev1.Container
+			for i := range pod.Spec.InitContainers {
+				if pod.Spec.InitContainers[i].Name == NodeScanProbeRunnerName {
+					probeRunner = &pod.Spec.InitContainers[i]
+				}
+			}
+			Expect(probeRunner).ToNot(BeNil())
+			Expect(probeRunner.Command).To(ContainElement("--custom-rules=" + OpenScapTailoringDir + "/" + utils.CustomRulesFile))
+			Expect(probeRunner.Command).To(ContainElement("--output-file=" + customRulesOutputFile))
+			Expect(probeRunner.VolumeMounts).To(ContainElement(corev1.VolumeMount{
+				Name:      tailoringCMVolumeName,
+				MountPath: OpenScapTailoringDir,
+				ReadOnly:  true,
+			}))
+			Expect(probeRunner.VolumeMounts).To(ContainElement(corev1.VolumeMount{
+				Name:      "host",
+				MountPath: "/host",
+				ReadOnly:  true,
+			}))
+			Expect(*probeRunner.SecurityContext.ReadOnlyRootFilesystem).To(BeTrue())
+			Expect(*probeRunner.SecurityContext.AllowPrivilegeEscalation).To(BeFalse())
+			Expect(*probeRunner.SecurityContext.RunAsNonRoot).To(BeTrue())
+			Expect(probeRunner.SecurityContext.Capabilities.Add).To(BeEmpty())
+			Expect(probeRunner.SecurityContext.Capabilities.Drop).To(ConsistOf(corev1.Capability("ALL")))
+			// The probes only run if the scan allows them
+			Expect(probeRunner.Command).To(ContainElement("--skip-probes"))
+			Expect(pod.Spec.Containers[0].Command).To(ContainElement("--custom-rules-output-file=" + customRulesOutputFile))
+		})
+
+		It("should only run the probes as root if a rule asks for it", func() {
+			probesCM := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "probes-tp",
+					Namespace: compliancescaninstance.Namespace,
+				},
+				Data: map[string]string{
+					"tailoring.xml":       "<tailoring/>",
+					utils.CustomRulesFile: `[{"metadata":{"name":"sshd-config"},"spec":{"probe":{"script":"exit 0"}}}]`,
+				},
+			}
+			Expect(reconciler.Client.Create(context.TODO(), probesCM)).To(BeNil())
+			compliancescaninstance.Spec.ScanType = compv1alpha1.ScanTypeNode
+			compliancescaninstance.Spec.AllowNodeProbes = true
+			compliancescaninstance.Spec.TailoringConfigMap = &compv1alpha1.TailoringConfigMapRef{Name: probesCM.Name}
+
+			getProbeRunner := func(pod *corev1.Pod) *corev1.Container {
+				for i := range pod.Spec.InitContainers {
+					if pod.Spec.InitContainers[i].Name == NodeScanProbeRunnerName {
+						return &pod.Spec.InitContainers[i]
+					}
+				}
+				return nil
+			}
+
+			pod := newScanPodForNode(compliancescaninstance, nodeinstance1, logger)
+			Expect(reconciler.reconcileTailoring(compliancescaninstance, pod, logger)).To(BeNil())
+			probeRunner := getProbeRunner(pod)
+			Expect(probeRunner.Command).ToNot(ContainElement("--skip-probes"))
+			Expect(*probeRunner.SecurityContext.RunAsNonRoot).To(BeTrue())
+
+			probesCM.Data[utils.CustomRulesFile] = `[{"metadata":{"name":"sshd-config"},"spec":{"probe":{"script":"exit 0","runAsRoot":true}}}]`
+			Expect(reconciler.Client.Update(context.TODO(), probesCM)).To(BeNil())
+			pod = newScanPodForNode(compliancescaninstance, nodeinstance1, logger)
+			Expect(reconciler.reconcileTailoring(compliancescaninstance, pod, logger)).To(BeNil())
+			probeRunner = getProbeRunner(pod)
+			Expect(*probeRunner.SecurityContext.RunAsUser).To(BeZero())
+			Expect(*probeRunner.SecurityContext.RunAsNonRoot).To(BeFalse())
+			Expect(probeRunner.SecurityContext.Capabilities.Add).To(ConsistOf(corev1.Capability("DAC_READ_SEARCH")))
+		})
+	})
+
 	Context("On the DONE phase", func() {
 		Context("with delete flag off", func() {
 			BeforeEach(func() {
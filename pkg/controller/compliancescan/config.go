@@ -52,6 +52,9 @@ const (
 
 	PlatformScanName                  = "api-checks"
 	PlatformScanResourceCollectorName = "api-resource-collector"
+	// The init container of node scans that runs the probes of the
+	// CustomRules
+	NodeScanProbeRunnerName = "custom-rule-probes"
 	// This coincides with the default ocp_data_root var in CaC.
 	PlatformScanDataRoot = "/kubernetes-api-resources"
 )
@@ -1,21 +1,33 @@
 package compliancescan
 
 import (
+	"bytes"
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/export"
 )
 
 func NewForwarder(s *compv1alpha1.ComplianceScan) Forwarder {
 	// Figure out what type of forwarding implementation we need based on
 	// scan configuration. By default, use the noopForwarder which doesn't
 	// do anything and maintains backwards compatibility.
+	var f Forwarder = noopForwarder{}
 	if s.Spec.Debug {
 		logf.Log.Info("Forwarding compliance results and remediations to logs")
-		return logForwarder{}
+		f = logForwarder{}
+	} else {
+		logf.Log.Info("Result and remediation forwarding is disabled")
+	}
+	// The results are exported in addition to being forwarded
+	if len(s.Spec.ExportFormats) > 0 {
+		logf.Log.Info("Exporting compliance results", "formats", s.Spec.ExportFormats)
+		return &exportForwarder{Forwarder: f, scan: s}
 	}
-	logf.Log.Info("Result and remediation forwarding is disabled")
-	return noopForwarder{}
+	return f
 }
 
 type Forwarder interface {
@@ -23,6 +35,16 @@ type Forwarder interface {
 	SendComplianceRemediation(r *compv1alpha1.ComplianceRemediation) error
 }
 
+// ExportingForwarder is a Forwarder that also exports the results of the
+// scan, once they were all sent
+type ExportingForwarder interface {
+	Forwarder
+	// Export renders the results of the scan in each of its formats. The
+	// results are read from the ComplianceCheckResults that were stored,
+	// so that the exports of a resumed aggregation are complete.
+	Export(ctx context.Context, c client.Reader) (map[compv1alpha1.ExportFormat][]byte, error)
+}
+
 type logForwarder struct{}
 
 func (f logForwarder) SendComplianceCheckResult(c *compv1alpha1.ComplianceCheckResult) error {
@@ -44,3 +66,26 @@ func (f noopForwarder) SendComplianceCheckResult(c *compv1alpha1.ComplianceCheck
 func (f noopForwarder) SendComplianceRemediation(r *compv1alpha1.ComplianceRemediation) error {
 	return nil
 }
+
+// exportForwarder exports the stored results of the scan in the formats of
+// the scan, and passes the results it's sent on to the forwarder it wraps
+type exportForwarder struct {
+	Forwarder
+	scan *compv1alpha1.ComplianceScan
+}
+
+func (f *exportForwarder) Export(ctx context.Context, c client.Reader) (map[compv1alpha1.ExportFormat][]byte, error) {
+	results, err := export.GetScanResults(ctx, c, f.scan.Name, f.scan.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	exports := make(map[compv1alpha1.ExportFormat][]byte, len(f.scan.Spec.ExportFormats))
+	for _, format := range f.scan.Spec.ExportFormats {
+		var buf bytes.Buffer
+		if err := export.Render(format, results, &buf); err != nil {
+			return nil, err
+		}
+		exports[format] = buf.Bytes()
+	}
+	return exports, nil
+}
@@ -1,9 +1,13 @@
 package compliancescan
 
 import (
+	"context"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
 )
@@ -53,4 +57,62 @@ var _ = Describe("Test forwarding factory", func() {
 		})
 	})
 
+	Context("With export formats in scan", func() {
+		var s *compv1alpha1.ComplianceScan
+
+		BeforeEach(func() {
+			s = &compv1alpha1.ComplianceScan{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "test-ns",
+				},
+				Spec: compv1alpha1.ComplianceScanSpec{
+					ScanType: compv1alpha1.ScanTypeNode,
+					ComplianceScanSettings: compv1alpha1.ComplianceScanSettings{
+						ExportFormats: []compv1alpha1.ExportFormat{
+							compv1alpha1.ExportFormatSARIF,
+							compv1alpha1.ExportFormatJUnit,
+						},
+					},
+				},
+			}
+		})
+
+		It("should return an exporting implementation that still forwards", func() {
+			f := NewForwarder(s)
+			ef, ok := f.(ExportingForwarder)
+			Expect(ok).To(BeTrue())
+			Expect(ef.(*exportForwarder).Forwarder).To(Equal(noopForwarder{}))
+
+			s.Spec.Debug = true
+			f = NewForwarder(s)
+			Expect(f.(*exportForwarder).Forwarder).To(Equal(logForwarder{}))
+		})
+
+		It("should export the stored results of the scan in each format", func() {
+			stored := &compv1alpha1.ComplianceCheckResult{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-rule-1",
+					Namespace: "test-ns",
+					Labels:    map[string]string{compv1alpha1.ComplianceScanLabel: "test"},
+				},
+				ID:       "xccdf_org.ssgproject.content_rule_rule_1",
+				Status:   compv1alpha1.CheckResultFail,
+				Severity: compv1alpha1.CheckResultSeverityHigh,
+			}
+			scheme := runtime.NewScheme()
+			Expect(compv1alpha1.SchemeBuilder.AddToScheme(scheme)).To(Succeed())
+			client := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithRuntimeObjects(stored).
+				Build()
+
+			f := NewForwarder(s).(ExportingForwarder)
+			exports, err := f.Export(context.TODO(), client)
+			Expect(err).To(BeNil())
+			Expect(exports).To(HaveLen(2))
+			Expect(string(exports[compv1alpha1.ExportFormatSARIF])).To(ContainSubstring(`"ruleId": "xccdf_org.ssgproject.content_rule_rule_1"`))
+			Expect(string(exports[compv1alpha1.ExportFormatJUnit])).To(ContainSubstring(`<testcase name="rule-1" classname="test">`))
+		})
+	})
 })
@@ -25,6 +25,7 @@ const (
 	apiResourceCollectorSA  = "api-resource-collector"
 	tailoringCMVolumeName   = "tailoring"
 	tailoringNotFoundPrefix = "Tailoring ConfigMap not found: "
+	customRulesOutputFile   = "/reports/custom_rules_output"
 )
 
 func (r *ReconcileComplianceScan) launchScanPod(instance *compv1alpha1.ComplianceScan, pod *corev1.Pod, logger logr.Logger) error {
@@ -82,7 +83,7 @@ func newScanPodForNode(scanInstance *compv1alpha1.ComplianceScan, node *corev1.N
 	trueP := true
 	hostToContainer := corev1.MountPropagationHostToContainer
 
-	return &corev1.Pod{
+	pod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      podName,
 			Namespace: common.GetComplianceOperatorNamespace(),
@@ -182,6 +183,7 @@ func newScanPodForNode(scanInstance *compv1alpha1.ComplianceScan, node *corev1.N
 						"--tls-client-cert=/etc/pki/tls/tls.crt",
 						"--tls-client-key=/etc/pki/tls/tls.key",
 						"--tls-ca=/etc/pki/tls/ca.crt",
+						"--custom-rules-output-file=" + customRulesOutputFile,
 					},
 					ImagePullPolicy: corev1.PullAlways,
 					SecurityContext: &corev1.SecurityContext{
@@ -351,6 +353,76 @@ func newScanPodForNode(scanInstance *compv1alpha1.ComplianceScan, node *corev1.N
 			},
 		},
 	}
+
+	if scanInstance.Spec.TailoringConfigMap != nil {
+		// NOTE: Mounting the tailoring is handled in the
+		// addTailoringVolume function
+		pod.Spec.InitContainers = append(pod.Spec.InitContainers, newProbeRunnerContainer(scanInstance))
+	}
+	return pod
+}
+
+// newProbeRunnerContainer returns the init container that runs the probes of
+// the CustomRules of a tailored profile, before the node is scanned. The
+// probes only get a read-only view of the host, and run as an unprivileged
+// user without any capabilities unless one of them needs to run as root, see
+// runProbesAsRoot. Unless the scan allows node probes, the container only
+// reports the rules without running their probes.
+func newProbeRunnerContainer(scanInstance *compv1alpha1.ComplianceScan) corev1.Container {
+	falseP := false
+	trueP := true
+	nobodyUID := int64(65534)
+
+	command := []string{
+		"compliance-operator", "probe-runner",
+		fmt.Sprintf("--custom-rules=%s/%s", OpenScapTailoringDir, utils.CustomRulesFile),
+		"--host-root=/host",
+		"--output-file=" + customRulesOutputFile,
+	}
+	if !scanInstance.Spec.AllowNodeProbes {
+		command = append(command, "--skip-probes")
+	}
+
+	return corev1.Container{
+		Name:            NodeScanProbeRunnerName,
+		Image:           utils.GetComponentImage(utils.OPERATOR),
+		Command:         command,
+		ImagePullPolicy: corev1.PullAlways,
+		SecurityContext: &corev1.SecurityContext{
+			RunAsUser:                &nobodyUID,
+			RunAsNonRoot:             &trueP,
+			AllowPrivilegeEscalation: &falseP,
+			ReadOnlyRootFilesystem:   &trueP,
+			Capabilities: &corev1.Capabilities{
+				Drop: []corev1.Capability{"ALL"},
+			},
+		},
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("20Mi"),
+				corev1.ResourceCPU:    resource.MustParse("10m"),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("100Mi"),
+				corev1.ResourceCPU:    resource.MustParse("100m"),
+			},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      "host",
+				MountPath: "/host",
+				ReadOnly:  true,
+			},
+			{
+				Name:      "report-dir",
+				MountPath: "/reports",
+			},
+			{
+				Name:      "tmp-dir",
+				MountPath: "/tmp",
+			},
+		},
+	}
 }
 
 func (r *ReconcileComplianceScan) newPlatformScanPod(scanInstance *compv1alpha1.ComplianceScan, logger logr.Logger) *corev1.Pod {
@@ -374,6 +446,10 @@ func (r *ReconcileComplianceScan) newPlatformScanPod(scanInstance *compv1alpha1.
 		// addTailoringVolume function
 		tailoringArg := fmt.Sprintf("--tailoring=%s/tailoring.xml", OpenScapTailoringDir)
 		collectorCmd = append(collectorCmd, tailoringArg)
+		// The collector checks the CustomRules of the tailored profile, if
+		// it has any
+		customRulesArg := fmt.Sprintf("--custom-rules=%s/%s", OpenScapTailoringDir, utils.CustomRulesFile)
+		collectorCmd = append(collectorCmd, customRulesArg, "--custom-rules-output-file="+customRulesOutputFile)
 	}
 
 	falseP := false
@@ -499,6 +575,7 @@ func (r *ReconcileComplianceScan) newPlatformScanPod(scanInstance *compv1alpha1.
 						"--exit-code-file=/reports/exit_code",
 						"--oscap-output-file=/reports/cmd_output",
 						"--warnings-output-file=/reports/warning_output",
+						"--custom-rules-output-file=" + customRulesOutputFile,
 						"--config-map-name=" + cmName,
 						"--owner=" + scanInstance.Name,
 						"--namespace=" + scanInstance.Namespace,
@@ -683,9 +760,40 @@ func (r *ReconcileComplianceScan) reconcileTailoring(instance *compv1alpha1.Comp
 	if err := r.addTailoringVolume(tailoringCMName, pod); err != nil {
 		return err
 	}
+
+	if instance.Spec.ScanType == compv1alpha1.ScanTypeNode && instance.Spec.AllowNodeProbes {
+		cm := &corev1.ConfigMap{}
+		if err := r.Client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: ns}, cm); err != nil {
+			return err
+		}
+		asRoot, err := utils.CustomRuleProbesRunAsRoot(cm.Data[utils.CustomRulesFile])
+		if err != nil {
+			return common.NewNonRetriableCtrlError("Tailoring ConfigMap has invalid custom rules: %v", err)
+		}
+		if asRoot {
+			logger.Info("Running the probes of the custom rules as root")
+			runProbesAsRoot(pod)
+		}
+	}
 	return nil
 }
 
+// runProbesAsRoot lets the probes of the pod read any file of the host, for
+// the probes of the CustomRules that explicitly ask to run as root
+func runProbesAsRoot(pod *corev1.Pod) {
+	rootUID := int64(0)
+	falseP := false
+	for i := range pod.Spec.InitContainers {
+		container := &pod.Spec.InitContainers[i]
+		if container.Name != NodeScanProbeRunnerName {
+			continue
+		}
+		container.SecurityContext.RunAsUser = &rootUID
+		container.SecurityContext.RunAsNonRoot = &falseP
+		container.SecurityContext.Capabilities.Add = []corev1.Capability{"DAC_READ_SEARCH"}
+	}
+}
+
 func (r *ReconcileComplianceScan) addTailoringVolume(name string, pod *corev1.Pod) error {
 	mode := int32(0644)
 
@@ -704,7 +812,7 @@ func (r *ReconcileComplianceScan) addTailoringVolume(name string, pod *corev1.Po
 	// The index is used to get the references instead of copies
 	for i := range pod.Spec.InitContainers {
 		container := &pod.Spec.InitContainers[i]
-		if container.Name == PlatformScanResourceCollectorName {
+		if container.Name == PlatformScanResourceCollectorName || container.Name == NodeScanProbeRunnerName {
 			container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
 				Name:      tailoringCMVolumeName,
 				MountPath: OpenScapTailoringDir,
@@ -813,6 +921,8 @@ func (r *ReconcileComplianceScan) reconcileReplicatedTailoringConfigMap(scan *co
 	if origData == "" {
 		return common.NewNonRetriableCtrlError("Tailoring ConfigMap's key `tailoring.xml` is empty")
 	}
+	// Only set for a tailored profile with custom rules
+	origRules := origCM.Data[utils.CustomRulesFile]
 
 	privCM := &corev1.ConfigMap{}
 	privKey := types.NamespacedName{Name: privName, Namespace: privNs}
@@ -830,6 +940,9 @@ func (r *ReconcileComplianceScan) reconcileReplicatedTailoringConfigMap(scan *co
 			newCM.Data = make(map[string]string)
 		}
 		newCM.Data["tailoring.xml"] = origData
+		if origRules != "" {
+			newCM.Data[utils.CustomRulesFile] = origRules
+		}
 		logger.Info("Creating private Tailoring ConfigMap", "ConfigMap.Name", privName, "ConfigMap.Namespace", privNs)
 		err = r.Client.Create(context.TODO(), newCM)
 		// Ignore error if CM already exists
@@ -842,9 +955,10 @@ func (r *ReconcileComplianceScan) reconcileReplicatedTailoringConfigMap(scan *co
 		return err
 	}
 	privData, _ := privCM.Data["tailoring.xml"]
+	privRules := privCM.Data[utils.CustomRulesFile]
 
 	// privCM needs update
-	if privData != origData {
+	if privData != origData || privRules != origRules {
 		updatedCM := privCM.DeepCopy()
 		if updatedCM.Data == nil {
 			updatedCM.Data = make(map[string]string)
@@ -855,6 +969,11 @@ func (r *ReconcileComplianceScan) reconcileReplicatedTailoringConfigMap(scan *co
 		updatedCM.Labels[compv1alpha1.ComplianceScanLabel] = scanName
 		updatedCM.Labels[compv1alpha1.ScriptLabel] = ""
 		updatedCM.Data["tailoring.xml"] = origData
+		if origRules != "" {
+			updatedCM.Data[utils.CustomRulesFile] = origRules
+		} else {
+			delete(updatedCM.Data, utils.CustomRulesFile)
+		}
 		logger.Info("Updating private Tailoring ConfigMap", "ConfigMap.Name", privName, "ConfigMap.Namespace", privNs)
 		return r.Client.Update(context.TODO(), updatedCM)
 	}
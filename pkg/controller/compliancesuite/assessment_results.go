@@ -0,0 +1,116 @@
+package compliancesuite
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/export"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/utils"
+)
+
+const (
+	// The key the OSCAL assessment-results document is stored under
+	assessmentResultsKey = "assessment-results.json"
+	// Records when the scans the assessment results were generated from
+	// ended, so the document is only generated once per run of the suite
+	assessmentResultsEndAnnotation = "compliance.openshift.io/scans-end-timestamp"
+	// Marks the ConfigMaps whose data is compressed, as for the results of
+	// the scans
+	configMapCompressedAnnotation = "openscap-scan-result/compressed"
+)
+
+// getAssessmentResultsConfigMapName returns the name of the ConfigMap the
+// OSCAL assessment results of the suite are stored in
+func getAssessmentResultsConfigMapName(suiteName string) string {
+	return suiteName + "-oscal-assessment-results"
+}
+
+// reconcileAssessmentResults stores the OSCAL assessment results of the
+// suite once its scans are done, if the suite asks for them
+func (r *ReconcileComplianceSuite) reconcileAssessmentResults(suite *compv1alpha1.ComplianceSuite, logger logr.Logger) error {
+	if !suite.Spec.OSCALAssessmentResults || suite.Status.Phase != compv1alpha1.PhaseDone {
+		return nil
+	}
+
+	scansEnd := getScansEndTimestamp(suite)
+	key := types.NamespacedName{Name: getAssessmentResultsConfigMapName(suite.Name), Namespace: suite.Namespace}
+	found := &corev1.ConfigMap{}
+	err := r.Client.Get(context.TODO(), key, found)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	exists := err == nil
+	if exists && found.Annotations[assessmentResultsEndAnnotation] == scansEnd {
+		return nil
+	}
+
+	logger.Info("Generating the OSCAL assessment results of the suite")
+	assessment, err := export.GetAssessment(context.TODO(), r.Reader, suite)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := export.RenderOSCAL(assessment, &buf); err != nil {
+		return err
+	}
+	data, compressed, err := utils.GetConfigMapData(buf.Bytes())
+	if err != nil {
+		return err
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      key.Name,
+			Namespace: key.Namespace,
+			Labels: map[string]string{
+				compv1alpha1.SuiteLabel: suite.Name,
+			},
+			Annotations: map[string]string{
+				assessmentResultsEndAnnotation: scansEnd,
+			},
+		},
+		Data: map[string]string{
+			assessmentResultsKey: data,
+		},
+	}
+	if compressed {
+		cm.Annotations[configMapCompressedAnnotation] = ""
+	}
+	if err := controllerutil.SetControllerReference(suite, cm, r.Scheme); err != nil {
+		return err
+	}
+
+	if !exists {
+		err = r.Client.Create(context.TODO(), cm)
+	} else {
+		found.Labels = cm.Labels
+		found.Annotations = cm.Annotations
+		found.Data = cm.Data
+		err = r.Client.Update(context.TODO(), found)
+	}
+	if err != nil {
+		return err
+	}
+	logger.Info("Stored the OSCAL assessment results of the suite", "ConfigMap.Name", cm.Name)
+	return nil
+}
+
+// getScansEndTimestamp returns when the last of the scans of the suite ended
+func getScansEndTimestamp(suite *compv1alpha1.ComplianceSuite) string {
+	var end time.Time
+	for _, scanStatus := range suite.Status.ScanStatuses {
+		if scanStatus.EndTimestamp != nil && scanStatus.EndTimestamp.Time.After(end) {
+			end = scanStatus.EndTimestamp.Time
+		}
+	}
+	return end.UTC().Format(time.RFC3339)
+}
@@ -177,6 +177,9 @@ func (r *ReconcileComplianceSuite) Reconcile(ctx context.Context, request reconc
 		if updateErr != nil {
 			return reconcile.Result{}, fmt.Errorf("Error setting ready status for suite: %w", updateErr)
 		}
+		if err := r.reconcileAssessmentResults(suiteCopy, reqLogger); err != nil {
+			return common.ReturnWithRetriableError(reqLogger, fmt.Errorf("cannot store the assessment results of the suite: %w", err))
+		}
 		return res, r.reconcileScanRerunnerCronJob(suiteCopy, reqLogger)
 	}
 
@@ -432,6 +435,24 @@ func newScanForSuite(suite *compv1alpha1.ComplianceSuite, scanWrap *compv1alpha1
 // Reconcile the remediation application in the suite. Note that the suite that this takes is already
 // a copy, so it's safe to modify.
 func (r *ReconcileComplianceSuite) reconcileRemediations(suite *compv1alpha1.ComplianceSuite, logger logr.Logger) (reconcile.Result, error) {
+	// A rolled out batch of remediations is watched until its rollback
+	// window passes, whether or not the suite applies remediations anymore
+	if suite.ShouldRollbackRemediations() {
+		res, proceed, err := r.reconcileRemediationBatch(suite, logger)
+		if !proceed || err != nil {
+			return res, err
+		}
+	}
+
+	// A rollout that was started before the suite stopped rolling out its
+	// remediations in stages ends here, so its canary scans don't linger
+	if suite.Status.RemediationRollout != nil &&
+		suite.Status.RemediationRollout.Phase != compv1alpha1.RemediationRolloutDone &&
+		(!suite.ShouldApplyRemediations() || !suite.HasStagedRemediationRollout()) {
+		logger.Info("Ending the remediation rollout, the suite doesn't roll out its remediations in stages anymore")
+		return reconcile.Result{}, r.resetStagedRollout(suite)
+	}
+
 	// We don't need to do anything else unless auto-applied is enabled
 	if !suite.ShouldApplyRemediations() {
 		return reconcile.Result{}, nil
@@ -459,10 +480,26 @@ func (r *ReconcileComplianceSuite) reconcileRemediations(suite *compv1alpha1.Com
 	// This is to prevent unabled to unpause the MachineConfigPool
 	// when there are stucked scans.
 	if suite.Status.Phase != compv1alpha1.PhaseDone {
+		// A new run of the suite rolls its remediations out in stages again
+		if suite.Status.RemediationRollout != nil {
+			logger.Info("Resetting the remediation rollout of the previous run")
+			return reconcile.Result{}, r.resetStagedRollout(suite)
+		}
 		logger.Info("Waiting until all scans are in Done phase before post-processing remediations")
 		return reconcile.Result{}, nil
 	}
 
+	// A halted rollout leaves the pools as they are until the next run
+	if suite.Status.RemediationRollout != nil && suite.Status.RemediationRollout.Phase == compv1alpha1.RemediationRolloutHalted {
+		logger.Info("The remediation rollout is halted, not applying remediations")
+		return reconcile.Result{}, nil
+	}
+
+	underReview, err := r.isUnderReview(suite)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
 	// Construct the list of the statuses
 	for _, rem := range remList.Items {
 		// get relevant scan
@@ -479,6 +516,25 @@ func (r *ReconcileComplianceSuite) reconcileRemediations(suite *compv1alpha1.Com
 			continue
 		}
 
+		// Rolled back and rejected remediations are left alone until
+		// they are applied manually
+		if isRolledBack(&rem) || isRejected(&rem) {
+			continue
+		}
+
+		// Remediations under review are only applied once approved, which
+		// sets them to apply
+		if underReview && !rem.Spec.Apply {
+			continue
+		}
+
+		// Remediations beyond the auto-apply policy wait for a human
+		if !rem.Spec.Apply && !suite.ShouldAutoApplyRemediation(&rem) {
+			logger.Info("Remediation is beyond the auto-apply policy, not applying it", "ComplianceRemediation.Name", rem.Name,
+				"Disruption", rem.Annotations[compv1alpha1.RemediationDisruptionAnnotation])
+			continue
+		}
+
 		if err := r.applyRemediation(rem, suite, scan, mcfgpools, affectedMcfgPools, logger); err != nil {
 			return reconcile.Result{}, err
 		}
@@ -498,6 +554,9 @@ func (r *ReconcileComplianceSuite) reconcileRemediations(suite *compv1alpha1.Com
 				r.Recorder.Event(suite, corev1.EventTypeWarning, "CannotRemediate", "Remediation needs-review. Values not set"+" Remediation:"+rem.Name)
 				continue
 			}
+			if isRolledBack(&rem) || isRejected(&rem) || (!rem.Spec.Apply && (underReview || !suite.ShouldAutoApplyRemediation(&rem))) {
+				continue
+			}
 			logger.Info("Remediation not applied yet. Skipping post-processing", "ComplianceRemediation.Name", rem.Name)
 			return reconcile.Result{Requeue: true, RequeueAfter: 10 * time.Second}, nil
 		}
@@ -516,6 +575,20 @@ func (r *ReconcileComplianceSuite) reconcileRemediations(suite *compv1alpha1.Com
 			logger.Info("KubeletConfig render diff:", "MachineConfigPool.Name", pool.Name, "Diff", diffString)
 			return reconcile.Result{Requeue: true, RequeueAfter: 10 * time.Second}, nil
 		}
+	}
+
+	// With a staged rollout, the canary pools go first and the rest of the
+	// pools are only un-paused once the canary nodes pass a rescan
+	if len(affectedMcfgPools) > 0 && suite.HasStagedRemediationRollout() &&
+		(suite.Status.RemediationRollout == nil || suite.Status.RemediationRollout.Phase != compv1alpha1.RemediationRolloutDone) {
+		res, promoted, err := r.reconcileStagedRollout(suite, logger)
+		if !promoted || err != nil {
+			return res, err
+		}
+	}
+
+	for idx := range affectedMcfgPools {
+		pool := affectedMcfgPools[idx]
 		poolKey := types.NamespacedName{Name: pool.GetName()}
 		// refresh pool reference directly from the API Server
 		if getErr := r.Reader.Get(context.TODO(), poolKey, pool); getErr != nil {
@@ -533,6 +606,16 @@ func (r *ReconcileComplianceSuite) reconcileRemediations(suite *compv1alpha1.Com
 		}
 	}
 
+	// The rollback window of the batch starts once all its pools are un-paused
+	res := reconcile.Result{}
+	if suite.ShouldRollbackRemediations() && suite.Status.RemediationBatch != nil &&
+		suite.Status.RemediationBatch.Phase == compv1alpha1.RemediationBatchApplying {
+		if err := r.startWatchingRemediationBatch(suite, logger); err != nil {
+			return reconcile.Result{}, err
+		}
+		res = reconcile.Result{RequeueAfter: rollbackCheckInterval}
+	}
+
 	if suite.ApplyRemediationsAnnotationSet() || suite.RemoveOutdatedAnnotationSet() {
 		suiteCopy := suite.DeepCopy()
 		if suite.ApplyRemediationsAnnotationSet() {
@@ -542,9 +625,31 @@ func (r *ReconcileComplianceSuite) reconcileRemediations(suite *compv1alpha1.Com
 			delete(suiteCopy.Annotations, compv1alpha1.RemoveOutdatedAnnotation)
 		}
 		updateErr := r.Client.Update(context.TODO(), suiteCopy)
-		return reconcile.Result{}, updateErr
+		return res, updateErr
 	}
-	return reconcile.Result{}, nil
+	return res, nil
+}
+
+// isUnderReview returns whether a RemediationApproval holds the remediations
+// of the suite for review
+func (r *ReconcileComplianceSuite) isUnderReview(suite *compv1alpha1.ComplianceSuite) (bool, error) {
+	approvals := &compv1alpha1.RemediationApprovalList{}
+	if err := r.Client.List(context.TODO(), approvals, client.InNamespace(suite.Namespace)); err != nil {
+		return false, err
+	}
+	for i := range approvals.Items {
+		approval := &approvals.Items[i]
+		if approval.Spec.Suite == suite.Name && approval.DeletionTimestamp.IsZero() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// isRejected returns whether the remediation was rejected in a review and
+// isn't set to apply since
+func isRejected(rem *compv1alpha1.ComplianceRemediation) bool {
+	return !rem.Spec.Apply && rem.HasAnnotation(compv1alpha1.RemediationRejectedAnnotation)
 }
 
 func (r *ReconcileComplianceSuite) applyRemediation(rem compv1alpha1.ComplianceRemediation,
@@ -563,6 +668,18 @@ func (r *ReconcileComplianceSuite) applyRemediation(rem compv1alpha1.ComplianceR
 				foundPool = pool.DeepCopy()
 				affectedMcfgPools[pool.Name] = foundPool
 			}
+			if suite.HasStagedRemediationRollout() {
+				if err := r.pauseCanaryPools(suite, mcfgpools, affectedMcfgPools, logger); err != nil {
+					return err
+				}
+			}
+			// Remediations that weren't applied yet make up the batch
+			// that is rolled back together
+			if suite.ShouldRollbackRemediations() && !rem.Spec.Apply {
+				if err := r.addToRemediationBatch(suite, &rem, foundPool, logger); err != nil {
+					return err
+				}
+			}
 			// we will use the same logic here for Kubelet Config remediation
 			if err := r.applyMcfgRemediationAndPausePool(rem, suite, foundPool, logger); err != nil {
 				return err
@@ -602,7 +719,7 @@ func (r *ReconcileComplianceSuite) applyMcfgRemediationAndPausePool(rem compv1al
 	remCopy := rem.DeepCopy()
 	// Only pause pools where the pool wasn't paused before and
 	// the remediation hasn't been applied
-	if !pool.Spec.Paused {
+	if !pool.Spec.Paused && !isUpdatingCanaryPool(suite, pool.Name) {
 		logger.Info("Pausing pool", "MachineConfigPool.Name", pool.Name)
 		pool.Spec.Paused = true
 		if err := r.Client.Update(context.TODO(), pool); err != nil {
@@ -3,10 +3,13 @@ package compliancesuite
 import (
 	"context"
 	"encoding/json"
+	"time"
+
 	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/metrics"
 	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/metrics/metricsfakes"
 
 	"github.com/ComplianceAsCode/compliance-operator/pkg/apis"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/common"
 	"github.com/go-logr/logr"
 	"github.com/go-logr/zapr"
 	. "github.com/onsi/ginkgo"
@@ -15,6 +18,7 @@ import (
 	mcfgv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -232,6 +236,93 @@ var _ = Describe("ComplianceSuiteController", func() {
 				BeforeEach(suiteAndScansInDonePhase)
 				It("Should apply the remediation", reconcileShouldApplyTheRemediation)
 
+				Context("With an auto-apply policy", func() {
+					BeforeEach(func() {
+						suite.Spec.AutoApplyPolicy = &compv1alpha1.RemediationAutoApplyPolicy{
+							MaxDisruption: "low",
+						}
+					})
+					setDisruption := func(disruption string) {
+						rem := &compv1alpha1.ComplianceRemediation{}
+						key := types.NamespacedName{Name: remediationName, Namespace: namespace}
+						err := reconciler.Client.Get(ctx, key, rem)
+						Expect(err).To(BeNil())
+						rem.Annotations = map[string]string{
+							compv1alpha1.RemediationDisruptionAnnotation: disruption,
+							compv1alpha1.RemediationSeverityAnnotation:   "medium",
+						}
+						err = reconciler.Client.Update(ctx, rem)
+						Expect(err).To(BeNil())
+					}
+					It("Should apply the remediation within the policy", func() {
+						setDisruption("low")
+						rem := reconcileAndGetRemediation()
+						Expect(rem.Spec.Apply).To(BeTrue())
+					})
+					It("Should leave the remediation beyond the policy unapplied", func() {
+						setDisruption("high")
+						res, err := reconciler.reconcileRemediations(suite, logger)
+						Expect(err).To(BeNil())
+						Expect(res.Requeue).To(BeFalse())
+						rem := reconcileAndGetRemediation()
+						Expect(rem.Spec.Apply).To(BeFalse())
+					})
+				})
+
+				Context("With a RemediationApproval for the suite", func() {
+					BeforeEach(func() {
+						approval := &compv1alpha1.RemediationApproval{
+							ObjectMeta: metav1.ObjectMeta{
+								Name:      "review",
+								Namespace: namespace,
+							},
+							Spec: compv1alpha1.RemediationApprovalSpec{
+								Suite: suiteName,
+							},
+						}
+						err := reconciler.Client.Create(ctx, approval)
+						Expect(err).To(BeNil())
+					})
+					It("Should only apply the approved remediation", func() {
+						By("Leaving the remediation under review unapplied")
+						res, err := reconciler.reconcileRemediations(suite, logger)
+						Expect(err).To(BeNil())
+						Expect(res.Requeue).To(BeFalse())
+						rem := reconcileAndGetRemediation()
+						Expect(rem.Spec.Apply).To(BeFalse())
+
+						By("Applying the remediation once approved")
+						rem.Spec.Apply = true
+						err = reconciler.Client.Update(ctx, rem)
+						Expect(err).To(BeNil())
+						_, err = reconciler.reconcileRemediations(suite, logger)
+						Expect(err).To(BeNil())
+						rem = reconcileAndGetRemediation()
+						Expect(rem.Spec.Apply).To(BeTrue())
+					})
+				})
+
+				Context("With a remediation rejected in a review", func() {
+					BeforeEach(func() {
+						rem := &compv1alpha1.ComplianceRemediation{}
+						key := types.NamespacedName{Name: remediationName, Namespace: namespace}
+						err := reconciler.Client.Get(ctx, key, rem)
+						Expect(err).To(BeNil())
+						rem.Annotations = map[string]string{
+							compv1alpha1.RemediationRejectedAnnotation: "review",
+						}
+						err = reconciler.Client.Update(ctx, rem)
+						Expect(err).To(BeNil())
+					})
+					It("Should leave the remediation unapplied without the approval", func() {
+						res, err := reconciler.reconcileRemediations(suite, logger)
+						Expect(err).To(BeNil())
+						Expect(res.Requeue).To(BeFalse())
+						rem := reconcileAndGetRemediation()
+						Expect(rem.Spec.Apply).To(BeFalse())
+					})
+				})
+
 				Context("With remove-outdated annotation", func() {
 					BeforeEach(prepareForRemoveOutdatedScenarios)
 					It("Should remove the outdated remediation and remove the annotation", func() {
@@ -999,4 +1090,642 @@ var _ = Describe("ComplianceSuiteController", func() {
 		})
 	})
 
+	Context("When rolling out MachineConfig remediations in stages", func() {
+		var (
+			poolName       = "test-pool"
+			canaryPoolName = "test-pool-canary"
+			canaryLabels   = map[string]string{
+				"hops":   "malt",
+				"canary": "",
+			}
+			suiteKey = types.NamespacedName{Name: suiteName, Namespace: namespace}
+		)
+
+		getPool := func(name string) *mcfgv1.MachineConfigPool {
+			p := &mcfgv1.MachineConfigPool{}
+			err := reconciler.Client.Get(ctx, types.NamespacedName{Name: name}, p)
+			Expect(err).To(BeNil())
+			return p
+		}
+
+		getRollout := func() *compv1alpha1.RemediationRolloutStatus {
+			s := &compv1alpha1.ComplianceSuite{}
+			err := reconciler.Client.Get(ctx, suiteKey, s)
+			Expect(err).To(BeNil())
+			return s.Status.RemediationRollout
+		}
+
+		markCanaryPoolUpdated := func() {
+			p := getPool(canaryPoolName)
+			p.Status.Configuration.Name = p.Spec.Configuration.Name
+			p.Status.MachineCount = 1
+			p.Status.UpdatedMachineCount = 1
+			p.Status.Conditions = []mcfgv1.MachineConfigPoolCondition{
+				{Type: mcfgv1.MachineConfigPoolUpdated, Status: corev1.ConditionTrue},
+			}
+			err := reconciler.Client.Update(ctx, p)
+			Expect(err).To(BeNil())
+		}
+
+		newCheck := func(scan, rule string, status compv1alpha1.ComplianceCheckStatus) *compv1alpha1.ComplianceCheckResult {
+			return &compv1alpha1.ComplianceCheckResult{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      scan + "-" + rule,
+					Namespace: namespace,
+					Labels: map[string]string{
+						compv1alpha1.ComplianceScanLabel: scan,
+					},
+				},
+				ID:     "xccdf_org.ssgproject.content_rule_" + rule,
+				Status: status,
+			}
+		}
+
+		rollOutToCanary := func() {
+			By("Applying the remediation and pausing both pools")
+			rem := reconcileAndGetRemediation()
+			Expect(rem.Spec.Apply).To(BeTrue())
+			Expect(getPool(poolName).Spec.Paused).To(BeTrue())
+			Expect(getPool(canaryPoolName).Spec.Paused).To(BeTrue())
+
+			rem.Status.ApplicationState = compv1alpha1.RemediationApplied
+			err := reconciler.Client.Update(ctx, rem)
+			Expect(err).To(BeNil())
+
+			By("Un-pausing only the canary pool")
+			_, err = reconciler.reconcileRemediations(suite, logger)
+			Expect(err).To(BeNil())
+			Expect(getRollout().Phase).To(Equal(compv1alpha1.RemediationRolloutCanaryUpdating))
+			Expect(getPool(poolName).Spec.Paused).To(BeTrue())
+			Expect(getPool(canaryPoolName).Spec.Paused).To(BeFalse())
+
+			By("Waiting for the canary pool to be updated")
+			res, err := reconciler.reconcileRemediations(suite, logger)
+			Expect(err).To(BeNil())
+			Expect(res.Requeue).To(BeTrue())
+			Expect(getRollout().Phase).To(Equal(compv1alpha1.RemediationRolloutCanaryUpdating))
+			Expect(getPool(canaryPoolName).Spec.Paused).To(BeFalse())
+
+			By("Rescanning the canary nodes")
+			markCanaryPoolUpdated()
+			_, err = reconciler.reconcileRemediations(suite, logger)
+			Expect(err).To(BeNil())
+			rollout := getRollout()
+			Expect(rollout.Phase).To(Equal(compv1alpha1.RemediationRolloutCanaryVerifying))
+			Expect(rollout.CanaryScans).To(Equal([]string{"testScanNode-canary"}))
+
+			canaryScan := &compv1alpha1.ComplianceScan{}
+			err = reconciler.Client.Get(ctx, types.NamespacedName{Name: "testScanNode-canary", Namespace: namespace}, canaryScan)
+			Expect(err).To(BeNil())
+			Expect(canaryScan.Spec.NodeSelector).To(Equal(canaryLabels))
+			Expect(canaryScan.Labels).ToNot(HaveKey(compv1alpha1.SuiteLabel))
+			Expect(canaryScan.Labels).To(HaveKeyWithValue(compv1alpha1.RemediationRolloutCanaryLabel, suiteName))
+
+			canaryScan.Status.Phase = compv1alpha1.PhaseDone
+			err = reconciler.Client.Status().Update(ctx, canaryScan)
+			Expect(err).To(BeNil())
+		}
+
+		BeforeEach(func() {
+			pool := &mcfgv1.MachineConfigPool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: poolName,
+				},
+				Spec: mcfgv1.MachineConfigPoolSpec{
+					NodeSelector: &metav1.LabelSelector{
+						MatchLabels: targetNodeSelector,
+					},
+				},
+			}
+			err := reconciler.Client.Create(ctx, pool)
+			Expect(err).To(BeNil())
+
+			canaryPool := &mcfgv1.MachineConfigPool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: canaryPoolName,
+				},
+				Spec: mcfgv1.MachineConfigPoolSpec{
+					NodeSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"canary": ""},
+					},
+					Configuration: mcfgv1.MachineConfigPoolStatusConfiguration{
+						ObjectReference: corev1.ObjectReference{Name: "rendered-canary-new"},
+					},
+				},
+				Status: mcfgv1.MachineConfigPoolStatus{
+					Configuration: mcfgv1.MachineConfigPoolStatusConfiguration{
+						ObjectReference: corev1.ObjectReference{Name: "rendered-canary-old"},
+					},
+				},
+			}
+			err = reconciler.Client.Create(ctx, canaryPool)
+			Expect(err).To(BeNil())
+
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "canary-node",
+					Labels: canaryLabels,
+				},
+				Status: corev1.NodeStatus{
+					Conditions: []corev1.NodeCondition{
+						{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+					},
+				},
+			}
+			err = reconciler.Client.Create(ctx, node)
+			Expect(err).To(BeNil())
+
+			remediation := &compv1alpha1.ComplianceRemediation{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      remediationName,
+					Namespace: namespace,
+					Labels: map[string]string{
+						compv1alpha1.SuiteLabel:          suiteName,
+						compv1alpha1.ComplianceScanLabel: "testScanNode",
+					},
+				},
+			}
+			mc := &mcfgv1.MachineConfig{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "MachineConfig",
+					APIVersion: mcfgapi.GroupName + "/v1",
+				},
+			}
+			unstructuredMC, err := runtime.DefaultUnstructuredConverter.ToUnstructured(mc)
+			Expect(err).ToNot(HaveOccurred())
+			remediation.Spec.Current.Object = &unstructured.Unstructured{
+				Object: unstructuredMC,
+			}
+			err = reconciler.Client.Create(ctx, remediation)
+			Expect(err).To(BeNil())
+
+			err = reconciler.Client.Create(ctx, newCheck("testScanNode", "audit-rules", compv1alpha1.CheckResultPass))
+			Expect(err).To(BeNil())
+			err = reconciler.Client.Create(ctx, newCheck("testScanNode", "coredumps", compv1alpha1.CheckResultFail))
+			Expect(err).To(BeNil())
+
+			suite.Spec.AutoApplyRemediations = true
+			suite.Spec.RemediationRollout = &compv1alpha1.RemediationRolloutSettings{
+				CanaryPools: []string{canaryPoolName},
+			}
+			err = reconciler.Client.Update(ctx, suite)
+			Expect(err).To(BeNil())
+			reconciler.Recorder = &common.SafeRecorder{}
+			suiteAndScansInDonePhase()
+		})
+
+		It("Should roll out to the other pools once the canary nodes pass", func() {
+			rollOutToCanary()
+			err := reconciler.Client.Create(ctx, newCheck("testScanNode-canary", "audit-rules", compv1alpha1.CheckResultPass))
+			Expect(err).To(BeNil())
+			err = reconciler.Client.Create(ctx, newCheck("testScanNode-canary", "coredumps", compv1alpha1.CheckResultPass))
+			Expect(err).To(BeNil())
+
+			By("Promoting the rollout")
+			_, err = reconciler.reconcileRemediations(suite, logger)
+			Expect(err).To(BeNil())
+			Expect(getRollout().Phase).To(Equal(compv1alpha1.RemediationRolloutDone))
+			Expect(getPool(poolName).Spec.Paused).To(BeFalse())
+			Expect(getPool(canaryPoolName).Spec.Paused).To(BeFalse())
+
+			canaryScan := &compv1alpha1.ComplianceScan{}
+			err = reconciler.Client.Get(ctx, types.NamespacedName{Name: "testScanNode-canary", Namespace: namespace}, canaryScan)
+			Expect(kerrors.IsNotFound(err)).To(BeTrue())
+
+			By("Resetting the rollout on the next run")
+			suite.Status.Phase = compv1alpha1.PhaseRunning
+			_, err = reconciler.reconcileRemediations(suite, logger)
+			Expect(err).To(BeNil())
+			Expect(getRollout()).To(BeNil())
+		})
+
+		It("Should halt the rollout on a new failure of the canary nodes", func() {
+			rollOutToCanary()
+			err := reconciler.Client.Create(ctx, newCheck("testScanNode-canary", "audit-rules", compv1alpha1.CheckResultFail))
+			Expect(err).To(BeNil())
+			err = reconciler.Client.Create(ctx, newCheck("testScanNode-canary", "coredumps", compv1alpha1.CheckResultFail))
+			Expect(err).To(BeNil())
+
+			_, err = reconciler.reconcileRemediations(suite, logger)
+			Expect(err).To(BeNil())
+			rollout := getRollout()
+			Expect(rollout.Phase).To(Equal(compv1alpha1.RemediationRolloutHalted))
+			Expect(rollout.Message).To(ContainSubstring("testScanNode-canary-audit-rules"))
+			Expect(rollout.Message).ToNot(ContainSubstring("coredumps"))
+
+			s := &compv1alpha1.ComplianceSuite{}
+			err = reconciler.Client.Get(ctx, suiteKey, s)
+			Expect(err).To(BeNil())
+			cond := s.Status.Conditions.GetCondition(compv1alpha1.RemediationRolloutHaltedCondition)
+			Expect(cond).ToNot(BeNil())
+			Expect(string(cond.Reason)).To(Equal("CanaryCheckFailed"))
+
+			By("Keeping the rest of the pools paused")
+			_, err = reconciler.reconcileRemediations(suite, logger)
+			Expect(err).To(BeNil())
+			Expect(getPool(poolName).Spec.Paused).To(BeTrue())
+		})
+
+		It("Should delete the canary scans once the suite stops rolling out in stages", func() {
+			rollOutToCanary()
+			// A canary scan that was launched before the status could be updated
+			stray := &compv1alpha1.ComplianceScan{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "testScanNode-other-canary",
+					Namespace: namespace,
+					Labels:    map[string]string{compv1alpha1.RemediationRolloutCanaryLabel: suiteName},
+				},
+			}
+			err := reconciler.Client.Create(ctx, stray)
+			Expect(err).To(BeNil())
+
+			suite.Spec.RemediationRollout = nil
+			_, err = reconciler.reconcileRemediations(suite, logger)
+			Expect(err).To(BeNil())
+			Expect(getRollout()).To(BeNil())
+
+			for _, name := range []string{"testScanNode-canary", "testScanNode-other-canary"} {
+				canaryScan := &compv1alpha1.ComplianceScan{}
+				err = reconciler.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, canaryScan)
+				Expect(kerrors.IsNotFound(err)).To(BeTrue())
+			}
+		})
+
+		It("Should halt the rollout when a canary node is not ready", func() {
+			rollOutToCanary()
+			node := &corev1.Node{}
+			err := reconciler.Client.Get(ctx, types.NamespacedName{Name: "canary-node"}, node)
+			Expect(err).To(BeNil())
+			node.Status.Conditions[0].Status = corev1.ConditionFalse
+			err = reconciler.Client.Status().Update(ctx, node)
+			Expect(err).To(BeNil())
+
+			_, err = reconciler.reconcileRemediations(suite, logger)
+			Expect(err).To(BeNil())
+			rollout := getRollout()
+			Expect(rollout.Phase).To(Equal(compv1alpha1.RemediationRolloutHalted))
+			Expect(rollout.Message).To(ContainSubstring("canary-node"))
+			Expect(getPool(poolName).Spec.Paused).To(BeTrue())
+		})
+	})
+
+	Context("When rolling back MachineConfig remediations", func() {
+		var (
+			poolName = "test-pool"
+			suiteKey = types.NamespacedName{Name: suiteName, Namespace: namespace}
+		)
+
+		getPool := func() *mcfgv1.MachineConfigPool {
+			p := &mcfgv1.MachineConfigPool{}
+			err := reconciler.Client.Get(ctx, types.NamespacedName{Name: poolName}, p)
+			Expect(err).To(BeNil())
+			return p
+		}
+
+		getBatch := func() *compv1alpha1.RemediationBatchStatus {
+			s := &compv1alpha1.ComplianceSuite{}
+			err := reconciler.Client.Get(ctx, suiteKey, s)
+			Expect(err).To(BeNil())
+			return s.Status.RemediationBatch
+		}
+
+		newCheck := func(rule string, status compv1alpha1.ComplianceCheckStatus) *compv1alpha1.ComplianceCheckResult {
+			return &compv1alpha1.ComplianceCheckResult{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "testScanNode-" + rule,
+					Namespace: namespace,
+					Labels: map[string]string{
+						compv1alpha1.SuiteLabel:                       suiteName,
+						compv1alpha1.ComplianceScanLabel:              "testScanNode",
+						compv1alpha1.ComplianceCheckResultStatusLabel: string(status),
+					},
+				},
+				Status: status,
+			}
+		}
+
+		rollOut := func() {
+			By("Applying the remediation as a new batch")
+			rem := reconcileAndGetRemediation()
+			Expect(rem.Spec.Apply).To(BeTrue())
+			batch := getBatch()
+			Expect(batch.Phase).To(Equal(compv1alpha1.RemediationBatchApplying))
+			Expect(batch.Remediations).To(Equal([]string{remediationName}))
+			Expect(batch.Pools).To(Equal([]string{poolName}))
+			Expect(batch.BaselineFailures).To(Equal([]string{"testScanNode-coredumps"}))
+
+			rem.Status.ApplicationState = compv1alpha1.RemediationApplied
+			err := reconciler.Client.Update(ctx, rem)
+			Expect(err).To(BeNil())
+
+			By("Watching the batch once the pool is un-paused")
+			res, err := reconciler.reconcileRemediations(suite, logger)
+			Expect(err).To(BeNil())
+			Expect(res.RequeueAfter).To(Equal(rollbackCheckInterval))
+			Expect(getPool().Spec.Paused).To(BeFalse())
+			batch = getBatch()
+			Expect(batch.Phase).To(Equal(compv1alpha1.RemediationBatchWatching))
+			Expect(batch.RolledOutAt).ToNot(BeNil())
+		}
+
+		markPoolUpdated := func() {
+			p := getPool()
+			p.Status.Configuration.Name = p.Spec.Configuration.Name
+			p.Status.MachineCount = 1
+			p.Status.UpdatedMachineCount = 1
+			p.Status.Conditions = []mcfgv1.MachineConfigPoolCondition{
+				{Type: mcfgv1.MachineConfigPoolUpdated, Status: corev1.ConditionTrue},
+			}
+			err := reconciler.Client.Update(ctx, p)
+			Expect(err).To(BeNil())
+		}
+
+		finishRescan := func() {
+			By("Rescanning the suite once the pool is updated")
+			markPoolUpdated()
+			_, err := reconciler.reconcileRemediations(suite, logger)
+			Expect(err).To(BeNil())
+			Expect(getBatch().Phase).To(Equal(compv1alpha1.RemediationBatchVerifying))
+
+			scan := &compv1alpha1.ComplianceScan{}
+			err = reconciler.Client.Get(ctx, types.NamespacedName{Name: "testScanNode", Namespace: namespace}, scan)
+			Expect(err).To(BeNil())
+			Expect(scan.NeedsRescan()).To(BeTrue())
+
+			delete(scan.Annotations, compv1alpha1.ComplianceScanRescanAnnotation)
+			err = reconciler.Client.Update(ctx, scan)
+			Expect(err).To(BeNil())
+			end := metav1.NewTime(time.Now().Add(time.Minute))
+			scan.Status.Phase = compv1alpha1.PhaseDone
+			scan.Status.EndTimestamp = &end
+			err = reconciler.Client.Status().Update(ctx, scan)
+			Expect(err).To(BeNil())
+		}
+
+		BeforeEach(func() {
+			pool := &mcfgv1.MachineConfigPool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: poolName,
+				},
+				Spec: mcfgv1.MachineConfigPoolSpec{
+					NodeSelector: &metav1.LabelSelector{
+						MatchLabels: targetNodeSelector,
+					},
+					Configuration: mcfgv1.MachineConfigPoolStatusConfiguration{
+						ObjectReference: corev1.ObjectReference{Name: "rendered-new"},
+					},
+				},
+			}
+			err := reconciler.Client.Create(ctx, pool)
+			Expect(err).To(BeNil())
+
+			remediation := &compv1alpha1.ComplianceRemediation{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      remediationName,
+					Namespace: namespace,
+					Labels: map[string]string{
+						compv1alpha1.SuiteLabel:          suiteName,
+						compv1alpha1.ComplianceScanLabel: "testScanNode",
+					},
+				},
+			}
+			mc := &mcfgv1.MachineConfig{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "MachineConfig",
+					APIVersion: mcfgapi.GroupName + "/v1",
+				},
+			}
+			unstructuredMC, err := runtime.DefaultUnstructuredConverter.ToUnstructured(mc)
+			Expect(err).ToNot(HaveOccurred())
+			remediation.Spec.Current.Object = &unstructured.Unstructured{
+				Object: unstructuredMC,
+			}
+			err = reconciler.Client.Create(ctx, remediation)
+			Expect(err).To(BeNil())
+
+			err = reconciler.Client.Create(ctx, newCheck("audit-rules", compv1alpha1.CheckResultPass))
+			Expect(err).To(BeNil())
+			err = reconciler.Client.Create(ctx, newCheck("coredumps", compv1alpha1.CheckResultFail))
+			Expect(err).To(BeNil())
+
+			suite.Spec.AutoApplyRemediations = true
+			suite.Spec.RemediationRollback = &compv1alpha1.RemediationRollbackSettings{
+				Window: metav1.Duration{Duration: time.Hour},
+			}
+			err = reconciler.Client.Update(ctx, suite)
+			Expect(err).To(BeNil())
+			suite.Status.ScanStatuses = []compv1alpha1.ComplianceScanStatusWrapper{{Name: "testScanNode"}}
+			reconciler.Recorder = &common.SafeRecorder{}
+			suiteAndScansInDonePhase()
+		})
+
+		It("Should roll back the batch when the pool degrades", func() {
+			rollOut()
+			p := getPool()
+			p.Status.Conditions = []mcfgv1.MachineConfigPoolCondition{
+				{Type: mcfgv1.MachineConfigPoolDegraded, Status: corev1.ConditionTrue},
+			}
+			err := reconciler.Client.Update(ctx, p)
+			Expect(err).To(BeNil())
+
+			By("Un-applying the remediation")
+			rem := reconcileAndGetRemediation()
+			Expect(rem.Spec.Apply).To(BeFalse())
+			Expect(rem.Annotations).To(HaveKeyWithValue(compv1alpha1.RemediationRolledBackAnnotation,
+				"The MachineConfigPool test-pool is degraded"))
+			batch := getBatch()
+			Expect(batch.Phase).To(Equal(compv1alpha1.RemediationBatchRolledBack))
+			Expect(batch.Reason).To(ContainSubstring("degraded"))
+
+			By("Not applying the rolled back remediation again")
+			rem.Status.ApplicationState = compv1alpha1.RemediationRolledBack
+			err = reconciler.Client.Status().Update(ctx, rem)
+			Expect(err).To(BeNil())
+			rem = reconcileAndGetRemediation()
+			Expect(rem.Spec.Apply).To(BeFalse())
+		})
+
+		It("Should roll back the batch when the rescan has new failures", func() {
+			rollOut()
+			finishRescan()
+			check := &compv1alpha1.ComplianceCheckResult{}
+			err := reconciler.Client.Get(ctx, types.NamespacedName{Name: "testScanNode-audit-rules", Namespace: namespace}, check)
+			Expect(err).To(BeNil())
+			check.Status = compv1alpha1.CheckResultFail
+			check.Labels[compv1alpha1.ComplianceCheckResultStatusLabel] = string(compv1alpha1.CheckResultFail)
+			err = reconciler.Client.Update(ctx, check)
+			Expect(err).To(BeNil())
+
+			rem := reconcileAndGetRemediation()
+			Expect(rem.Spec.Apply).To(BeFalse())
+			batch := getBatch()
+			Expect(batch.Phase).To(Equal(compv1alpha1.RemediationBatchRolledBack))
+			Expect(batch.Reason).To(ContainSubstring("testScanNode-audit-rules"))
+			Expect(batch.Reason).ToNot(ContainSubstring("coredumps"))
+		})
+
+		It("Should keep the batch when the rescan has no new failures", func() {
+			rollOut()
+			finishRescan()
+
+			rem := reconcileAndGetRemediation()
+			Expect(rem.Spec.Apply).To(BeTrue())
+			Expect(getBatch().Phase).To(Equal(compv1alpha1.RemediationBatchSucceeded))
+		})
+
+		It("Should restore the KubeletConfig the batch was merged into", func() {
+			kc := &mcfgv1.KubeletConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "custom-kubelet",
+				},
+				Spec: mcfgv1.KubeletConfigSpec{
+					KubeletConfig: &runtime.RawExtension{
+						Raw: []byte(`{"maxPods":100}`),
+					},
+				},
+			}
+			err := reconciler.Client.Create(ctx, kc)
+			Expect(err).To(BeNil())
+			kcMC := &mcfgv1.MachineConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "99-test-pool-generated-kubelet",
+					OwnerReferences: []metav1.OwnerReference{{
+						APIVersion: "machineconfiguration.openshift.io/v1",
+						Kind:       "KubeletConfig",
+						Name:       "custom-kubelet",
+						UID:        "12345",
+					}},
+				},
+			}
+			err = reconciler.Client.Create(ctx, kcMC)
+			Expect(err).To(BeNil())
+
+			By("Snapshotting the custom KubeletConfig of the pool")
+			p := getPool()
+			p.Spec.Configuration.Source = []corev1.ObjectReference{{Name: "99-test-pool-generated-kubelet"}}
+			snapshot, err := reconciler.snapshotKubeletConfig(p)
+			Expect(err).To(BeNil())
+			Expect(snapshot.Name).To(Equal("custom-kubelet"))
+			Expect(snapshot.Spec).ToNot(BeEmpty())
+
+			kcKey := types.NamespacedName{Name: "custom-kubelet"}
+			err = reconciler.Client.Get(ctx, kcKey, kc)
+			Expect(err).To(BeNil())
+			kc.Spec.KubeletConfig.Raw = []byte(`{"maxPods":100,"streamingConnectionIdleTimeout":"5m"}`)
+			err = reconciler.Client.Update(ctx, kc)
+			Expect(err).To(BeNil())
+
+			By("Restoring the previous spec")
+			err = reconciler.restoreKubeletConfig(snapshot, logger)
+			Expect(err).To(BeNil())
+			err = reconciler.Client.Get(ctx, kcKey, kc)
+			Expect(err).To(BeNil())
+			Expect(string(kc.Spec.KubeletConfig.Raw)).To(Equal(`{"maxPods":100}`))
+
+			By("Deleting a KubeletConfig the batch created")
+			snapshot, err = reconciler.snapshotKubeletConfig(getPool())
+			Expect(err).To(BeNil())
+			Expect(snapshot.Name).To(Equal("compliance-operator-kubelet-test-pool"))
+			Expect(snapshot.Spec).To(BeEmpty())
+			created := &mcfgv1.KubeletConfig{ObjectMeta: metav1.ObjectMeta{Name: snapshot.Name}}
+			err = reconciler.Client.Create(ctx, created)
+			Expect(err).To(BeNil())
+			err = reconciler.restoreKubeletConfig(snapshot, logger)
+			Expect(err).To(BeNil())
+			err = reconciler.Client.Get(ctx, types.NamespacedName{Name: snapshot.Name}, created)
+			Expect(kerrors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+
+	Context("When generating OSCAL assessment results", func() {
+		var cmKey types.NamespacedName
+
+		BeforeEach(func() {
+			cmKey = types.NamespacedName{Name: getAssessmentResultsConfigMapName(suiteName), Namespace: namespace}
+
+			check := &compv1alpha1.ComplianceCheckResult{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "testScanNode-audit-rules",
+					Namespace: namespace,
+					Labels: map[string]string{
+						compv1alpha1.SuiteLabel:          suiteName,
+						compv1alpha1.ComplianceScanLabel: "testScanNode",
+					},
+					Annotations: map[string]string{
+						compv1alpha1.ComplianceCheckResultRuleAnnotation: "audit-rules",
+					},
+				},
+				ID:     "xccdf_org.ssgproject.content_rule_audit_rules",
+				Status: compv1alpha1.CheckResultFail,
+			}
+			rule := &compv1alpha1.Rule{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "rhcos4-audit-rules",
+					Namespace: namespace,
+					Annotations: map[string]string{
+						compv1alpha1.RuleIDAnnotationKey:                         "audit-rules",
+						compv1alpha1.RuleControlAnnotationPrefix + "NIST-800-53": "AU-2",
+					},
+				},
+			}
+			Expect(reconciler.Client.Create(ctx, check)).To(Succeed())
+			Expect(reconciler.Client.Create(ctx, rule)).To(Succeed())
+
+			scan := &compv1alpha1.ComplianceScan{}
+			Expect(reconciler.Client.Get(ctx, types.NamespacedName{Name: "testScanNode", Namespace: namespace}, scan)).To(Succeed())
+			scan.Labels = map[string]string{compv1alpha1.SuiteLabel: suiteName}
+			Expect(reconciler.Client.Update(ctx, scan)).To(Succeed())
+
+			suite.Spec.OSCALAssessmentResults = true
+			suite.Status.Phase = compv1alpha1.PhaseDone
+			suite.Status.ScanStatuses = []compv1alpha1.ComplianceScanStatusWrapper{
+				{
+					Name: "testScanNode",
+					ComplianceScanStatus: compv1alpha1.ComplianceScanStatus{
+						Phase:        compv1alpha1.PhaseDone,
+						EndTimestamp: &metav1.Time{Time: time.Date(2026, 10, 19, 1, 0, 0, 0, time.UTC)},
+					},
+				},
+			}
+		})
+
+		It("stores the assessment results once per run of the suite", func() {
+			err := reconciler.reconcileAssessmentResults(suite, logger)
+			Expect(err).To(BeNil())
+
+			cm := &corev1.ConfigMap{}
+			Expect(reconciler.Client.Get(ctx, cmKey, cm)).To(Succeed())
+			Expect(cm.Labels).To(HaveKeyWithValue(compv1alpha1.SuiteLabel, suiteName))
+			Expect(cm.Annotations).To(HaveKeyWithValue(assessmentResultsEndAnnotation, "2026-10-19T01:00:00Z"))
+			Expect(cm.OwnerReferences).To(HaveLen(1))
+			var doc map[string]interface{}
+			Expect(json.Unmarshal([]byte(cm.Data[assessmentResultsKey]), &doc)).To(Succeed())
+			Expect(doc).To(HaveKey("assessment-results"))
+			Expect(cm.Data[assessmentResultsKey]).To(ContainSubstring(`"target-id": "au-2_smt"`))
+
+			By("Keeping the document of the same run")
+			cm.Data[assessmentResultsKey] = "{}"
+			Expect(reconciler.Client.Update(ctx, cm)).To(Succeed())
+			err = reconciler.reconcileAssessmentResults(suite, logger)
+			Expect(err).To(BeNil())
+			Expect(reconciler.Client.Get(ctx, cmKey, cm)).To(Succeed())
+			Expect(cm.Data[assessmentResultsKey]).To(Equal("{}"))
+
+			By("Generating the document of the next run")
+			suite.Status.ScanStatuses[0].EndTimestamp = &metav1.Time{Time: time.Date(2026, 10, 20, 1, 0, 0, 0, time.UTC)}
+			err = reconciler.reconcileAssessmentResults(suite, logger)
+			Expect(err).To(BeNil())
+			Expect(reconciler.Client.Get(ctx, cmKey, cm)).To(Succeed())
+			Expect(cm.Annotations).To(HaveKeyWithValue(assessmentResultsEndAnnotation, "2026-10-20T01:00:00Z"))
+			Expect(cm.Data[assessmentResultsKey]).To(ContainSubstring("assessment-results"))
+		})
+
+		It("doesn't store the assessment results unless the suite asks for them", func() {
+			suite.Spec.OSCALAssessmentResults = false
+			err := reconciler.reconcileAssessmentResults(suite, logger)
+			Expect(err).To(BeNil())
+			err = reconciler.Client.Get(ctx, cmKey, &corev1.ConfigMap{})
+			Expect(kerrors.IsNotFound(err)).To(BeTrue())
+		})
+	})
 })
@@ -0,0 +1,333 @@
+package compliancesuite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	mcfgv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/utils"
+)
+
+// How often the pools of a batch of remediations are checked while it's
+// being watched
+const rollbackCheckInterval = 30 * time.Second
+
+// reconcileRemediationBatch watches the last batch of remediations the suite
+// applied and rolls it back if it degraded a pool or made other checks fail.
+// It returns true once the suite can go on applying remediations. Note that
+// the suite that this takes is already a copy, so it's safe to modify.
+func (r *ReconcileComplianceSuite) reconcileRemediationBatch(suite *compv1alpha1.ComplianceSuite, logger logr.Logger) (reconcile.Result, bool, error) {
+	batch := suite.Status.RemediationBatch
+	if batch == nil || batch.RolledOutAt == nil ||
+		(batch.Phase != compv1alpha1.RemediationBatchWatching && batch.Phase != compv1alpha1.RemediationBatchVerifying) {
+		return reconcile.Result{}, true, nil
+	}
+
+	remaining := suite.Spec.RemediationRollback.Window.Duration - time.Since(batch.RolledOutAt.Time)
+	if remaining <= 0 {
+		logger.Info("The remediation batch passed the rollback window")
+		batch.Phase = compv1alpha1.RemediationBatchSucceeded
+		return reconcile.Result{}, true, r.Client.Status().Update(context.TODO(), suite)
+	}
+	requeue := reconcile.Result{RequeueAfter: rollbackCheckInterval}
+	if remaining < rollbackCheckInterval {
+		requeue.RequeueAfter = remaining
+	}
+
+	allUpdated := true
+	for _, poolName := range batch.Pools {
+		pool := &mcfgv1.MachineConfigPool{}
+		if err := r.Client.Get(context.TODO(), types.NamespacedName{Name: poolName}, pool); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return reconcile.Result{}, false, err
+		}
+		if mcfgv1.IsMachineConfigPoolConditionTrue(pool.Status.Conditions, mcfgv1.MachineConfigPoolDegraded) {
+			return reconcile.Result{}, false, r.rollbackRemediationBatch(suite,
+				fmt.Sprintf("The MachineConfigPool %s is degraded", poolName), logger)
+		}
+		if pool.Spec.Paused || !isPoolUpdated(pool) {
+			allUpdated = false
+		}
+	}
+
+	switch batch.Phase {
+	case compv1alpha1.RemediationBatchWatching:
+		if !allUpdated {
+			logger.Info("Waiting for the pools of the remediation batch to update")
+			return requeue, false, nil
+		}
+		if err := r.rescanSuite(suite, logger); err != nil {
+			return reconcile.Result{}, false, err
+		}
+		now := metav1.Now()
+		batch.Phase = compv1alpha1.RemediationBatchVerifying
+		batch.RescannedAt = &now
+		return requeue, false, r.Client.Status().Update(context.TODO(), suite)
+	case compv1alpha1.RemediationBatchVerifying:
+		rescanned, err := r.isSuiteRescanned(suite, batch.RescannedAt)
+		if err != nil {
+			return reconcile.Result{}, false, err
+		}
+		if !rescanned {
+			logger.Info("Waiting for the rescan of the remediation batch")
+			return requeue, false, nil
+		}
+		failures, err := r.getFailedChecks(suite)
+		if err != nil {
+			return reconcile.Result{}, false, err
+		}
+		newFailures := subtractSorted(failures, batch.BaselineFailures)
+		if len(newFailures) > 0 {
+			return reconcile.Result{}, false, r.rollbackRemediationBatch(suite,
+				fmt.Sprintf("The checks %v fail since the remediations were applied", newFailures), logger)
+		}
+		logger.Info("The remediation batch passed the rescan")
+		batch.Phase = compv1alpha1.RemediationBatchSucceeded
+		return reconcile.Result{}, true, r.Client.Status().Update(context.TODO(), suite)
+	}
+	return reconcile.Result{}, true, nil
+}
+
+// addToRemediationBatch records a MachineConfig or KubeletConfig remediation
+// that is about to be applied in the batch of the suite. The first
+// remediation of a batch records the checks that fail before the batch is
+// applied, and the first KubeletConfig remediation for a pool records the
+// KubeletConfig it's merged into, so both can be compared and restored later.
+func (r *ReconcileComplianceSuite) addToRemediationBatch(suite *compv1alpha1.ComplianceSuite,
+	rem *compv1alpha1.ComplianceRemediation, pool *mcfgv1.MachineConfigPool, logger logr.Logger) error {
+	batch := suite.Status.RemediationBatch
+	if batch == nil || batch.Phase != compv1alpha1.RemediationBatchApplying {
+		baseline, err := r.getFailedChecks(suite)
+		if err != nil {
+			return err
+		}
+		logger.Info("Starting a new remediation batch")
+		batch = &compv1alpha1.RemediationBatchStatus{
+			Phase:            compv1alpha1.RemediationBatchApplying,
+			BaselineFailures: baseline,
+		}
+		suite.Status.RemediationBatch = batch
+	}
+
+	batch.Remediations = appendUnique(batch.Remediations, rem.Name)
+	batch.Pools = appendUnique(batch.Pools, pool.Name)
+
+	if utils.IsKubeletConfig(rem.Spec.Current.Object) {
+		snapshot, err := r.snapshotKubeletConfig(pool)
+		if err != nil {
+			return err
+		}
+		found := false
+		for i := range batch.KubeletConfigs {
+			if batch.KubeletConfigs[i].Name == snapshot.Name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			batch.KubeletConfigs = append(batch.KubeletConfigs, *snapshot)
+		}
+	}
+
+	return r.Client.Status().Update(context.TODO(), suite)
+}
+
+// startWatchingRemediationBatch starts the rollback window of a batch once
+// its pools were un-paused
+func (r *ReconcileComplianceSuite) startWatchingRemediationBatch(suite *compv1alpha1.ComplianceSuite, logger logr.Logger) error {
+	logger.Info("Watching the rolled out remediation batch")
+	now := metav1.Now()
+	suite.Status.RemediationBatch.Phase = compv1alpha1.RemediationBatchWatching
+	suite.Status.RemediationBatch.RolledOutAt = &now
+	return r.Client.Status().Update(context.TODO(), suite)
+}
+
+// rollbackRemediationBatch un-applies the remediations of the batch, marking
+// them as rolled back so the suite doesn't apply them again, and restores
+// the KubeletConfigs they were merged into
+func (r *ReconcileComplianceSuite) rollbackRemediationBatch(suite *compv1alpha1.ComplianceSuite, reason string, logger logr.Logger) error {
+	logger.Info("Rolling back the remediation batch", "Reason", reason)
+	batch := suite.Status.RemediationBatch
+
+	for _, remName := range batch.Remediations {
+		rem := &compv1alpha1.ComplianceRemediation{}
+		remKey := types.NamespacedName{Name: remName, Namespace: suite.Namespace}
+		if err := r.Client.Get(context.TODO(), remKey, rem); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		remCopy := rem.DeepCopy()
+		remCopy.Spec.Apply = false
+		if remCopy.Annotations == nil {
+			remCopy.Annotations = make(map[string]string)
+		}
+		remCopy.Annotations[compv1alpha1.RemediationRolledBackAnnotation] = reason
+		logger.Info("Rolling back remediation", "ComplianceRemediation.Name", remName)
+		if err := r.Client.Update(context.TODO(), remCopy); err != nil {
+			return err
+		}
+	}
+
+	for i := range batch.KubeletConfigs {
+		if err := r.restoreKubeletConfig(&batch.KubeletConfigs[i], logger); err != nil {
+			return err
+		}
+	}
+
+	r.Recorder.Event(suite, corev1.EventTypeWarning, "RemediationsRolledBack", reason)
+	batch.Phase = compv1alpha1.RemediationBatchRolledBack
+	batch.Reason = reason
+	return r.Client.Status().Update(context.TODO(), suite)
+}
+
+// snapshotKubeletConfig records the spec of the KubeletConfig that the
+// KubeletConfig remediations for the pool are merged into
+func (r *ReconcileComplianceSuite) snapshotKubeletConfig(pool *mcfgv1.MachineConfigPool) (*compv1alpha1.KubeletConfigSnapshot, error) {
+	kcName, err := utils.GetKCNameForPool(pool, r.Client)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &compv1alpha1.KubeletConfigSnapshot{Name: kcName}
+	kc := &mcfgv1.KubeletConfig{}
+	if err := r.Client.Get(context.TODO(), types.NamespacedName{Name: kcName}, kc); err != nil {
+		if errors.IsNotFound(err) {
+			return snapshot, nil
+		}
+		return nil, err
+	}
+	spec, err := json.Marshal(kc.Spec)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't encode the spec of KubeletConfig %s: %w", kcName, err)
+	}
+	snapshot.Spec = string(spec)
+	return snapshot, nil
+}
+
+// restoreKubeletConfig puts the spec of a KubeletConfig back, or deletes the
+// KubeletConfig if it was created by the batch
+func (r *ReconcileComplianceSuite) restoreKubeletConfig(snapshot *compv1alpha1.KubeletConfigSnapshot, logger logr.Logger) error {
+	kc := &mcfgv1.KubeletConfig{}
+	if err := r.Client.Get(context.TODO(), types.NamespacedName{Name: snapshot.Name}, kc); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if snapshot.Spec == "" {
+		logger.Info("Deleting KubeletConfig created by the remediation batch", "KubeletConfig.Name", kc.Name)
+		if err := r.Client.Delete(context.TODO(), kc); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	spec := mcfgv1.KubeletConfigSpec{}
+	if err := json.Unmarshal([]byte(snapshot.Spec), &spec); err != nil {
+		return fmt.Errorf("couldn't decode the previous spec of KubeletConfig %s: %w", kc.Name, err)
+	}
+	logger.Info("Restoring the previous spec of KubeletConfig", "KubeletConfig.Name", kc.Name)
+	kc.Spec = spec
+	return r.Client.Update(context.TODO(), kc)
+}
+
+// rescanSuite reruns the scans of the suite to look for the checks the
+// remediations made fail
+func (r *ReconcileComplianceSuite) rescanSuite(suite *compv1alpha1.ComplianceSuite, logger logr.Logger) error {
+	for _, scanStatus := range suite.Status.ScanStatuses {
+		scan := &compv1alpha1.ComplianceScan{}
+		if err := r.Client.Get(context.TODO(), types.NamespacedName{Name: scanStatus.Name, Namespace: suite.Namespace}, scan); err != nil {
+			return err
+		}
+		scanCopy := scan.DeepCopy()
+		if scanCopy.Annotations == nil {
+			scanCopy.Annotations = make(map[string]string)
+		}
+		scanCopy.Annotations[compv1alpha1.ComplianceScanRescanAnnotation] = ""
+		logger.Info("Rescanning to verify the remediation batch", "ComplianceScan.Name", scan.Name)
+		if err := r.Client.Update(context.TODO(), scanCopy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isSuiteRescanned returns whether all the scans of the suite finished after
+// the given time
+func (r *ReconcileComplianceSuite) isSuiteRescanned(suite *compv1alpha1.ComplianceSuite, since *metav1.Time) (bool, error) {
+	if suite.Status.Phase != compv1alpha1.PhaseDone {
+		return false, nil
+	}
+	for _, scanStatus := range suite.Status.ScanStatuses {
+		scan := &compv1alpha1.ComplianceScan{}
+		if err := r.Client.Get(context.TODO(), types.NamespacedName{Name: scanStatus.Name, Namespace: suite.Namespace}, scan); err != nil {
+			return false, err
+		}
+		if scan.NeedsRescan() || scan.Status.Phase != compv1alpha1.PhaseDone ||
+			scan.Status.EndTimestamp == nil || scan.Status.EndTimestamp.Before(since) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// getFailedChecks returns the sorted names of the failed checks of the suite
+func (r *ReconcileComplianceSuite) getFailedChecks(suite *compv1alpha1.ComplianceSuite) ([]string, error) {
+	checks := &compv1alpha1.ComplianceCheckResultList{}
+	if err := r.Client.List(context.TODO(), checks, client.InNamespace(suite.Namespace), client.MatchingLabels{
+		compv1alpha1.SuiteLabel:                       suite.Name,
+		compv1alpha1.ComplianceCheckResultStatusLabel: string(compv1alpha1.CheckResultFail),
+	}); err != nil {
+		return nil, err
+	}
+	failures := make([]string, 0, len(checks.Items))
+	for i := range checks.Items {
+		failures = append(failures, checks.Items[i].Name)
+	}
+	sort.Strings(failures)
+	return failures, nil
+}
+
+// isRolledBack returns whether the remediation was rolled back and not
+// applied again since
+func isRolledBack(rem *compv1alpha1.ComplianceRemediation) bool {
+	return !rem.Spec.Apply && rem.HasAnnotation(compv1alpha1.RemediationRolledBackAnnotation)
+}
+
+func appendUnique(list []string, item string) []string {
+	for _, existing := range list {
+		if existing == item {
+			return list
+		}
+	}
+	return append(list, item)
+}
+
+// subtractSorted returns the items of the sorted list that aren't in the
+// other sorted list
+func subtractSorted(list, other []string) []string {
+	var diff []string
+	for _, item := range list {
+		idx := sort.SearchStrings(other, item)
+		if idx == len(other) || other[idx] != item {
+			diff = append(diff, item)
+		}
+	}
+	return diff
+}
@@ -0,0 +1,345 @@
+package compliancesuite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	mcfgv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+)
+
+const (
+	canaryScanSuffix = "-canary"
+	// Names the scan of the suite that a canary scan rescans
+	canaryOriginAnnotation = "compliance.openshift.io/canary-of-scan"
+)
+
+// pauseCanaryPools pauses the canary pools of a staged rollout along with the
+// pools the remediations are applied to, so that the canary nodes don't start
+// rebooting before all the remediations are in place
+func (r *ReconcileComplianceSuite) pauseCanaryPools(suite *compv1alpha1.ComplianceSuite,
+	mcfgpools *mcfgv1.MachineConfigPoolList,
+	affectedMcfgPools map[string]*mcfgv1.MachineConfigPool,
+	logger logr.Logger) error {
+	for _, poolName := range suite.Spec.RemediationRollout.CanaryPools {
+		if _, poolIsTracked := affectedMcfgPools[poolName]; poolIsTracked {
+			continue
+		}
+		for i := range mcfgpools.Items {
+			if mcfgpools.Items[i].Name != poolName {
+				continue
+			}
+			pool := mcfgpools.Items[i].DeepCopy()
+			affectedMcfgPools[poolName] = pool
+			if !pool.Spec.Paused && !isUpdatingCanaryPool(suite, pool.Name) {
+				logger.Info("Pausing canary pool", "MachineConfigPool.Name", pool.Name)
+				pool.Spec.Paused = true
+				if err := r.Client.Update(context.TODO(), pool); err != nil {
+					logger.Error(err, "Could not pause canary pool", "MachineConfigPool.Name", pool.Name)
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// reconcileStagedRollout rolls the applied remediations out to the canary
+// pools and verifies the canary nodes with a rescan. It returns true once the
+// rest of the affected pools can be un-paused. Note that the suite that this
+// takes is already a copy, so it's safe to modify.
+func (r *ReconcileComplianceSuite) reconcileStagedRollout(suite *compv1alpha1.ComplianceSuite, logger logr.Logger) (reconcile.Result, bool, error) {
+	canaryPools := make([]*mcfgv1.MachineConfigPool, 0, len(suite.Spec.RemediationRollout.CanaryPools))
+	for _, poolName := range suite.Spec.RemediationRollout.CanaryPools {
+		pool := &mcfgv1.MachineConfigPool{}
+		if err := r.Reader.Get(context.TODO(), types.NamespacedName{Name: poolName}, pool); err != nil {
+			if errors.IsNotFound(err) {
+				return reconcile.Result{}, false, r.haltRollout(suite, "CanaryPoolNotFound",
+					fmt.Sprintf("The canary MachineConfigPool %s doesn't exist", poolName), logger)
+			}
+			return reconcile.Result{}, false, err
+		}
+		canaryPools = append(canaryPools, pool)
+	}
+
+	if suite.Status.RemediationRollout == nil {
+		for _, pool := range canaryPools {
+			if pool.Spec.Paused {
+				logger.Info("Unpausing canary pool", "MachineConfigPool.Name", pool.Name)
+				poolCopy := pool.DeepCopy()
+				poolCopy.Spec.Paused = false
+				if err := r.Client.Update(context.TODO(), poolCopy); err != nil {
+					logger.Error(err, "Could not unpause canary pool", "MachineConfigPool.Name", pool.Name)
+					return reconcile.Result{}, false, err
+				}
+			}
+		}
+		r.Recorder.Event(suite, corev1.EventTypeNormal, "RemediationRolloutStarted",
+			"Rolling out the remediations to the canary pools")
+		err := r.setRolloutStatus(suite, &compv1alpha1.RemediationRolloutStatus{
+			Phase: compv1alpha1.RemediationRolloutCanaryUpdating,
+		})
+		return reconcile.Result{Requeue: true, RequeueAfter: requeueAfterDefault}, false, err
+	}
+
+	if halted, reason, message, err := r.canaryPoolsBroken(canaryPools); err != nil {
+		return reconcile.Result{}, false, err
+	} else if halted {
+		return reconcile.Result{}, false, r.haltRollout(suite, reason, message, logger)
+	}
+
+	switch suite.Status.RemediationRollout.Phase {
+	case compv1alpha1.RemediationRolloutCanaryUpdating:
+		for _, pool := range canaryPools {
+			if !isPoolUpdated(pool) {
+				logger.Info("Waiting until the canary pool is updated", "MachineConfigPool.Name", pool.Name)
+				return reconcile.Result{Requeue: true, RequeueAfter: requeueAfterDefault}, false, nil
+			}
+		}
+		canaryScans, err := r.launchCanaryScans(suite, canaryPools, logger)
+		if err != nil {
+			return reconcile.Result{}, false, err
+		}
+		err = r.setRolloutStatus(suite, &compv1alpha1.RemediationRolloutStatus{
+			Phase:       compv1alpha1.RemediationRolloutCanaryVerifying,
+			CanaryScans: canaryScans,
+		})
+		return reconcile.Result{Requeue: true, RequeueAfter: requeueAfterDefault}, false, err
+	case compv1alpha1.RemediationRolloutCanaryVerifying:
+		for _, scanName := range suite.Status.RemediationRollout.CanaryScans {
+			scan := &compv1alpha1.ComplianceScan{}
+			if err := r.Client.Get(context.TODO(), types.NamespacedName{Name: scanName, Namespace: suite.Namespace}, scan); err != nil {
+				return reconcile.Result{}, false, err
+			}
+			if scan.Status.Phase != compv1alpha1.PhaseDone {
+				logger.Info("Waiting until the canary scan is done", "ComplianceScan.Name", scanName)
+				return reconcile.Result{}, false, nil
+			}
+			if scan.Status.Result == compv1alpha1.ResultError {
+				return reconcile.Result{}, false, r.haltRollout(suite, "CanaryScanError",
+					fmt.Sprintf("The canary scan %s ended with an error: %s", scanName, scan.Status.ErrorMessage), logger)
+			}
+			newFailures, err := r.getNewCanaryFailures(scan)
+			if err != nil {
+				return reconcile.Result{}, false, err
+			}
+			if len(newFailures) > 0 {
+				return reconcile.Result{}, false, r.haltRollout(suite, "CanaryCheckFailed",
+					fmt.Sprintf("The canary scan %s has new failures: %v", scanName, newFailures), logger)
+			}
+		}
+
+		if err := r.deleteCanaryScans(suite); err != nil {
+			return reconcile.Result{}, false, err
+		}
+		r.Recorder.Event(suite, corev1.EventTypeNormal, "RemediationRolloutPromoted",
+			"The canary nodes passed the rescan, rolling out the remediations to the rest of the pools")
+		return reconcile.Result{}, true, r.setRolloutStatus(suite, &compv1alpha1.RemediationRolloutStatus{
+			Phase: compv1alpha1.RemediationRolloutDone,
+		})
+	}
+
+	return reconcile.Result{}, true, nil
+}
+
+// resetStagedRollout clears the rollout of a previous run of the suite, so the
+// remediations of the next run are rolled out in stages again
+func (r *ReconcileComplianceSuite) resetStagedRollout(suite *compv1alpha1.ComplianceSuite) error {
+	if err := r.deleteCanaryScans(suite); err != nil {
+		return err
+	}
+	suite.Status.Conditions.RemoveCondition(compv1alpha1.RemediationRolloutHaltedCondition)
+	return r.setRolloutStatus(suite, nil)
+}
+
+func (r *ReconcileComplianceSuite) setRolloutStatus(suite *compv1alpha1.ComplianceSuite, rollout *compv1alpha1.RemediationRolloutStatus) error {
+	suite.Status.RemediationRollout = rollout
+	return r.Client.Status().Update(context.TODO(), suite)
+}
+
+func (r *ReconcileComplianceSuite) haltRollout(suite *compv1alpha1.ComplianceSuite, reason compv1alpha1.ConditionReason,
+	message string, logger logr.Logger) error {
+	logger.Info("Halting the remediation rollout", "Reason", reason, "Message", message)
+	r.Recorder.Event(suite, corev1.EventTypeWarning, "RemediationRolloutHalted", message)
+	suite.Status.SetConditionRolloutHalted(reason, message)
+	rollout := &compv1alpha1.RemediationRolloutStatus{
+		Phase:   compv1alpha1.RemediationRolloutHalted,
+		Message: message,
+	}
+	if suite.Status.RemediationRollout != nil {
+		rollout.CanaryScans = suite.Status.RemediationRollout.CanaryScans
+	}
+	return r.setRolloutStatus(suite, rollout)
+}
+
+// canaryPoolsBroken returns whether any of the canary pools is degraded or
+// has a node that isn't ready once the pool is updated. Nodes are expected
+// to be unavailable while they reboot into the new configuration.
+func (r *ReconcileComplianceSuite) canaryPoolsBroken(canaryPools []*mcfgv1.MachineConfigPool) (bool, compv1alpha1.ConditionReason, string, error) {
+	for _, pool := range canaryPools {
+		if mcfgv1.IsMachineConfigPoolConditionTrue(pool.Status.Conditions, mcfgv1.MachineConfigPoolDegraded) {
+			return true, "CanaryPoolDegraded", fmt.Sprintf("The canary MachineConfigPool %s is degraded", pool.Name), nil
+		}
+		if pool.Spec.NodeSelector == nil || !isPoolUpdated(pool) {
+			continue
+		}
+		nodes := &corev1.NodeList{}
+		if err := r.Client.List(context.TODO(), nodes, client.MatchingLabels(pool.Spec.NodeSelector.MatchLabels)); err != nil {
+			return false, "", "", err
+		}
+		for i := range nodes.Items {
+			if !isNodeReady(&nodes.Items[i]) {
+				return true, "CanaryNodeNotReady", fmt.Sprintf("The canary node %s is not ready", nodes.Items[i].Name), nil
+			}
+		}
+	}
+	return false, "", "", nil
+}
+
+// launchCanaryScans rescans the canary nodes with each node scan of the
+// suite that has nodes in a canary pool
+func (r *ReconcileComplianceSuite) launchCanaryScans(suite *compv1alpha1.ComplianceSuite,
+	canaryPools []*mcfgv1.MachineConfigPool, logger logr.Logger) ([]string, error) {
+	var canaryScans []string
+	for idx := range suite.Spec.Scans {
+		scanWrap := &suite.Spec.Scans[idx]
+		if scanWrap.ScanType == compv1alpha1.ScanTypePlatform {
+			continue
+		}
+		for _, pool := range canaryPools {
+			if pool.Spec.NodeSelector == nil {
+				continue
+			}
+			nodeSelector := map[string]string{}
+			for k, v := range scanWrap.NodeSelector {
+				nodeSelector[k] = v
+			}
+			for k, v := range pool.Spec.NodeSelector.MatchLabels {
+				nodeSelector[k] = v
+			}
+			nodes := &corev1.NodeList{}
+			if err := r.Client.List(context.TODO(), nodes, client.MatchingLabels(nodeSelector)); err != nil {
+				return nil, err
+			}
+			if len(nodes.Items) == 0 {
+				continue
+			}
+
+			scan := compv1alpha1.ComplianceScanFromWrapper(scanWrap)
+			scan.SetName(getCanaryScanName(scanWrap.Name, pool.Name, len(canaryPools)))
+			scan.SetNamespace(suite.Namespace)
+			scan.SetLabels(map[string]string{
+				compv1alpha1.RemediationRolloutCanaryLabel: suite.Name,
+			})
+			scan.SetAnnotations(map[string]string{
+				canaryOriginAnnotation: scanWrap.Name,
+			})
+			scan.Spec.NodeSelector = nodeSelector
+			if err := controllerutil.SetControllerReference(suite, scan, r.Scheme); err != nil {
+				return nil, err
+			}
+			logger.Info("Launching canary scan", "ComplianceScan.Name", scan.Name)
+			if err := r.Client.Create(context.TODO(), scan); err != nil && !errors.IsAlreadyExists(err) {
+				return nil, err
+			}
+			canaryScans = append(canaryScans, scan.Name)
+		}
+	}
+	return canaryScans, nil
+}
+
+// getNewCanaryFailures returns the checks that fail in the canary scan, but
+// didn't in the scan of the suite it rescans
+func (r *ReconcileComplianceSuite) getNewCanaryFailures(canaryScan *compv1alpha1.ComplianceScan) ([]string, error) {
+	previous := map[string]compv1alpha1.ComplianceCheckStatus{}
+	previousChecks := &compv1alpha1.ComplianceCheckResultList{}
+	if err := r.Client.List(context.TODO(), previousChecks, client.InNamespace(canaryScan.Namespace),
+		client.MatchingLabels{compv1alpha1.ComplianceScanLabel: canaryScan.Annotations[canaryOriginAnnotation]}); err != nil {
+		return nil, err
+	}
+	for i := range previousChecks.Items {
+		previous[previousChecks.Items[i].ID] = previousChecks.Items[i].Status
+	}
+
+	canaryChecks := &compv1alpha1.ComplianceCheckResultList{}
+	if err := r.Client.List(context.TODO(), canaryChecks, client.InNamespace(canaryScan.Namespace),
+		client.MatchingLabels{compv1alpha1.ComplianceScanLabel: canaryScan.Name}); err != nil {
+		return nil, err
+	}
+	var newFailures []string
+	for i := range canaryChecks.Items {
+		check := &canaryChecks.Items[i]
+		if check.Status != compv1alpha1.CheckResultFail {
+			continue
+		}
+		switch previous[check.ID] {
+		case compv1alpha1.CheckResultFail, compv1alpha1.CheckResultExcepted:
+			continue
+		}
+		newFailures = append(newFailures, check.Name)
+	}
+	return newFailures, nil
+}
+
+// deleteCanaryScans deletes the canary scans of the suite along with their
+// results. The scans are found by their label rather than by the status of the
+// rollout, so that the scans that were launched before the status could be
+// updated are deleted as well.
+func (r *ReconcileComplianceSuite) deleteCanaryScans(suite *compv1alpha1.ComplianceSuite) error {
+	scans := &compv1alpha1.ComplianceScanList{}
+	if err := r.Client.List(context.TODO(), scans, client.InNamespace(suite.Namespace),
+		client.MatchingLabels{compv1alpha1.RemediationRolloutCanaryLabel: suite.Name}); err != nil {
+		return err
+	}
+	for i := range scans.Items {
+		if err := r.Client.Delete(context.TODO(), &scans.Items[i]); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// isUpdatingCanaryPool returns whether the pool is a canary pool that the
+// staged rollout already un-paused, and thus mustn't be paused again
+func isUpdatingCanaryPool(suite *compv1alpha1.ComplianceSuite, poolName string) bool {
+	if !suite.HasStagedRemediationRollout() || suite.Status.RemediationRollout == nil {
+		return false
+	}
+	for _, canaryPool := range suite.Spec.RemediationRollout.CanaryPools {
+		if canaryPool == poolName {
+			return true
+		}
+	}
+	return false
+}
+
+func getCanaryScanName(scanName, poolName string, numPools int) string {
+	if numPools == 1 {
+		return scanName + canaryScanSuffix
+	}
+	return scanName + "-" + poolName + canaryScanSuffix
+}
+
+func isPoolUpdated(pool *mcfgv1.MachineConfigPool) bool {
+	return pool.Status.ObservedGeneration == pool.Generation &&
+		pool.Status.Configuration.Name == pool.Spec.Configuration.Name &&
+		pool.Status.UpdatedMachineCount == pool.Status.MachineCount &&
+		mcfgv1.IsMachineConfigPoolConditionTrue(pool.Status.Conditions, mcfgv1.MachineConfigPoolUpdated)
+}
+
+func isNodeReady(node *corev1.Node) bool {
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == corev1.NodeReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
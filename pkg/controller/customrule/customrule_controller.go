@@ -0,0 +1,95 @@
+package customrule
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/metrics"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/utils"
+)
+
+var log = logf.Log.WithName("customrulectrl")
+
+func (r *ReconcileCustomRule) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&compv1alpha1.CustomRule{}).
+		Complete(r)
+}
+
+// Add creates a new CustomRule Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, met *metrics.Metrics, _ utils.CtlplaneSchedulingInfo, _ *kubernetes.Clientset) error {
+	return add(mgr, newReconciler(mgr, met))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, met *metrics.Metrics) reconcile.Reconciler {
+	return &ReconcileCustomRule{Client: mgr.GetClient(), Scheme: mgr.GetScheme(), Metrics: met}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("customrule-controller").
+		For(&compv1alpha1.CustomRule{}).
+		Complete(r)
+}
+
+// blank assignment to verify that ReconcileCustomRule implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileCustomRule{}
+
+// ReconcileCustomRule reconciles a CustomRule object
+type ReconcileCustomRule struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	Client  client.Client
+	Scheme  *runtime.Scheme
+	Metrics *metrics.Metrics
+}
+
+// Reconcile validates the CustomRule, compiling its expression if it has
+// one, and reports in the status whether the rule can be checked, so that a
+// broken rule is noticed before a scan uses it.
+func (r *ReconcileCustomRule) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling CustomRule")
+
+	instance := &compv1alpha1.CustomRule{}
+	err := r.Client.Get(ctx, request.NamespacedName, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Return and don't requeue
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	status := compv1alpha1.CustomRuleStatus{Phase: compv1alpha1.CustomRulePhaseReady}
+	if err := utils.ValidateCustomRule(instance); err != nil {
+		reqLogger.Info("The CustomRule is invalid", "error", err.Error())
+		status.Phase = compv1alpha1.CustomRulePhaseError
+		status.ErrorMessage = err.Error()
+	}
+	if instance.Status == status {
+		return reconcile.Result{}, nil
+	}
+
+	ruleCopy := instance.DeepCopy()
+	ruleCopy.Status = status
+	if err := r.Client.Status().Update(ctx, ruleCopy); err != nil {
+		return reconcile.Result{}, fmt.Errorf("couldn't update CustomRule status: %w", err)
+	}
+	return reconcile.Result{}, nil
+}
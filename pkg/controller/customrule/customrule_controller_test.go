@@ -0,0 +1,166 @@
+package customrule
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/ComplianceAsCode/compliance-operator/pkg/apis"
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+)
+
+const namespace = "test-ns"
+
+var _ = Describe("CustomRule controller", func() {
+	var (
+		ctx        = context.Background()
+		ruleKey    = types.NamespacedName{Name: "oauth-token-max-age", Namespace: namespace}
+		reconciler *ReconcileCustomRule
+		rule       *compv1alpha1.CustomRule
+	)
+
+	reconcileRule := func() *compv1alpha1.CustomRule {
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: ruleKey})
+		Expect(err).To(BeNil())
+
+		found := &compv1alpha1.CustomRule{}
+		Expect(reconciler.Client.Get(ctx, ruleKey, found)).To(BeNil())
+		return found
+	}
+
+	BeforeEach(func() {
+		cscheme := scheme.Scheme
+		err := apis.AddToScheme(cscheme)
+		Expect(err).To(BeNil())
+
+		rule = &compv1alpha1.CustomRule{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      ruleKey.Name,
+				Namespace: namespace,
+			},
+			Spec: compv1alpha1.CustomRuleSpec{
+				Title:    "OAuth access tokens expire within a day",
+				Severity: compv1alpha1.CheckResultSeverityMedium,
+				Inputs: []compv1alpha1.CustomRuleInput{
+					{Name: "oauth", Path: "/apis/config.openshift.io/v1/oauths/cluster"},
+				},
+				Expression: "oauth.spec.tokenConfig.accessTokenMaxAgeSeconds <= 86400",
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		client := fake.NewClientBuilder().
+			WithScheme(scheme.Scheme).
+			WithStatusSubresource(&compv1alpha1.CustomRule{}).
+			WithObjects(rule).
+			Build()
+		reconciler = &ReconcileCustomRule{Client: client, Scheme: scheme.Scheme}
+	})
+
+	Context("with an expression that compiles", func() {
+		It("marks the rule as ready", func() {
+			found := reconcileRule()
+			Expect(found.Status.Phase).To(Equal(compv1alpha1.CustomRulePhaseReady))
+			Expect(found.Status.ErrorMessage).To(BeEmpty())
+		})
+	})
+
+	Context("with an expression that doesn't compile", func() {
+		BeforeEach(func() {
+			rule.Spec.Expression = "oauth.spec.tokenConfig.accessTokenMaxAgeSeconds <="
+		})
+
+		It("marks the rule as failed with the compilation error", func() {
+			found := reconcileRule()
+			Expect(found.Status.Phase).To(Equal(compv1alpha1.CustomRulePhaseError))
+			Expect(found.Status.ErrorMessage).To(ContainSubstring("Syntax error"))
+		})
+	})
+
+	Context("with an expression that doesn't return a bool", func() {
+		BeforeEach(func() {
+			rule.Spec.Expression = "oauth.spec.tokenConfig.accessTokenMaxAgeSeconds + 1 == 2 ? 'yes' : 'no'"
+		})
+
+		It("marks the rule as failed", func() {
+			found := reconcileRule()
+			Expect(found.Status.Phase).To(Equal(compv1alpha1.CustomRulePhaseError))
+			Expect(found.Status.ErrorMessage).To(ContainSubstring("must return a bool"))
+		})
+	})
+
+	Context("with an expression referencing an unknown input", func() {
+		BeforeEach(func() {
+			rule.Spec.Expression = "apiserver.spec.audit.profile == 'Default'"
+		})
+
+		It("marks the rule as failed", func() {
+			found := reconcileRule()
+			Expect(found.Status.Phase).To(Equal(compv1alpha1.CustomRulePhaseError))
+			Expect(found.Status.ErrorMessage).To(ContainSubstring("undeclared reference"))
+		})
+
+		It("marks the rule as ready once the expression is fixed", func() {
+			Expect(reconcileRule().Status.Phase).To(Equal(compv1alpha1.CustomRulePhaseError))
+
+			fixed := &compv1alpha1.CustomRule{}
+			Expect(reconciler.Client.Get(ctx, ruleKey, fixed)).To(BeNil())
+			fixed.Spec.Expression = "oauth != null"
+			Expect(reconciler.Client.Update(ctx, fixed)).To(BeNil())
+
+			found := reconcileRule()
+			Expect(found.Status.Phase).To(Equal(compv1alpha1.CustomRulePhaseReady))
+			Expect(found.Status.ErrorMessage).To(BeEmpty())
+		})
+	})
+
+	Context("with a probe", func() {
+		BeforeEach(func() {
+			rule.Spec.Inputs = nil
+			rule.Spec.Expression = ""
+			rule.Spec.Probe = &compv1alpha1.CustomRuleProbe{
+				Script: `test -f "$HOST_ROOT/etc/kubernetes/kubelet.conf"`,
+			}
+		})
+
+		It("marks the rule as ready", func() {
+			found := reconcileRule()
+			Expect(found.Status.Phase).To(Equal(compv1alpha1.CustomRulePhaseReady))
+			Expect(found.CheckType()).To(Equal(compv1alpha1.CheckTypeNode))
+		})
+	})
+
+	Context("with both a probe and an expression", func() {
+		BeforeEach(func() {
+			rule.Spec.Probe = &compv1alpha1.CustomRuleProbe{
+				Script: `test -f "$HOST_ROOT/etc/kubernetes/kubelet.conf"`,
+			}
+		})
+
+		It("marks the rule as failed", func() {
+			found := reconcileRule()
+			Expect(found.Status.Phase).To(Equal(compv1alpha1.CustomRulePhaseError))
+			Expect(found.Status.ErrorMessage).To(ContainSubstring("can't have inputs or an expression"))
+		})
+	})
+
+	Context("with neither a probe nor an expression", func() {
+		BeforeEach(func() {
+			rule.Spec.Inputs = nil
+			rule.Spec.Expression = ""
+		})
+
+		It("marks the rule as failed", func() {
+			found := reconcileRule()
+			Expect(found.Status.Phase).To(Equal(compv1alpha1.CustomRulePhaseError))
+			Expect(found.Status.ErrorMessage).To(ContainSubstring("either inputs and an expression, or a probe"))
+		})
+	})
+})
@@ -0,0 +1,13 @@
+package customrule
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestCustomrule(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Customrule Suite")
+}
@@ -2,6 +2,8 @@ package profilebundle
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"time"
 
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -12,9 +14,11 @@ import (
 
 	"fmt"
 	"path"
+	"reflect"
 
 	compliancev1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
 	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/common"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/profileparser"
 	"github.com/ComplianceAsCode/compliance-operator/pkg/utils"
 	"github.com/go-logr/logr"
 	ocpimg "github.com/openshift/api/image/v1"
@@ -30,6 +34,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -39,6 +44,11 @@ var log = logf.Log.WithName("profilebundlectrl")
 
 var oneReplica int32 = 1
 
+// standardsHashAnnotation holds the hash of the standards configuration the
+// profile parser runs with, so that the bundle is parsed again when the
+// configuration changes
+const standardsHashAnnotation = "compliance.openshift.io/standards-hash"
+
 func (r *ReconcileProfileBundle) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&compliancev1alpha1.ProfileBundle{}).
@@ -64,9 +74,12 @@ func newReconciler(mgr manager.Manager, met *metrics.Metrics, si utils.CtlplaneS
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
 func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	standardsMapper := &standardsConfigMapMapper{mgr.GetClient()}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		Named("profilebundle-controller").
 		For(&compliancev1alpha1.ProfileBundle{}).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(standardsMapper.Map)).
 		Complete(r)
 }
 
@@ -174,8 +187,16 @@ func (r *ReconcileProfileBundle) Reconcile(ctx context.Context, request reconcil
 		effectiveImage = isTagImageRef
 	}
 
+	standardsHash, err := r.getStandardsHash(instance)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
 	// Define a new Pod object
 	depl := r.newWorkloadForBundle(instance, effectiveImage)
+	if standardsHash != "" {
+		depl.Spec.Template.Annotations[standardsHashAnnotation] = standardsHash
+	}
 
 	found := &appsv1.Deployment{}
 	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: depl.Name, Namespace: depl.Namespace}, found)
@@ -192,7 +213,7 @@ func (r *ReconcileProfileBundle) Reconcile(ctx context.Context, request reconcil
 		return reconcile.Result{}, err
 	}
 
-	if workloadNeedsUpdate(effectiveImage, found) {
+	if workloadNeedsUpdate(effectiveImage, found) || parserCommandChanged(depl, found) || standardsHashChanged(depl, found) {
 		pbCopy := instance.DeepCopy()
 		pbCopy.Status.DataStreamStatus = compliancev1alpha1.DataStreamPending
 		pbCopy.Status.ErrorMessage = ""
@@ -480,12 +501,7 @@ func (r *ReconcileProfileBundle) newWorkloadForBundle(pb *compliancev1alpha1.Pro
 									corev1.ResourceCPU:    resource.MustParse("100m"),
 								},
 							},
-							Command: []string{
-								"compliance-operator", "profileparser",
-								"--name", pb.Name,
-								"--namespace", pb.Namespace,
-								"--ds-path", path.Join("/content", pb.Spec.ContentFile),
-							},
+							Command: getProfileParserCommand(pb),
 							VolumeMounts: []corev1.VolumeMount{
 								{
 									Name:      "content-dir",
@@ -541,6 +557,19 @@ func (r *ReconcileProfileBundle) newWorkloadForBundle(pb *compliancev1alpha1.Pro
 	}
 }
 
+func getProfileParserCommand(pb *compliancev1alpha1.ProfileBundle) []string {
+	cmd := []string{
+		"compliance-operator", "profileparser",
+		"--name", pb.Name,
+		"--namespace", pb.Namespace,
+		"--ds-path", path.Join("/content", pb.Spec.ContentFile),
+	}
+	if pb.Spec.StandardsConfigMap != nil && pb.Spec.StandardsConfigMap.Name != "" {
+		cmd = append(cmd, "--standards-configmap", pb.Spec.StandardsConfigMap.Name)
+	}
+	return cmd
+}
+
 // podStartupError returns false if for some reason the pod couldn't even
 // run. If there's more conditions in the function in the future, let's
 // split it
@@ -585,3 +614,43 @@ func workloadNeedsUpdate(image string, depl *appsv1.Deployment) bool {
 	// and we should try to update anyway
 	return true
 }
+
+// parserCommandChanged returns true if the profileparser init container
+// of the found Deployment was started with different arguments than the
+// desired one, e.g. because the standards ConfigMap reference changed.
+func parserCommandChanged(desired, found *appsv1.Deployment) bool {
+	getCommand := func(depl *appsv1.Deployment) []string {
+		for _, container := range depl.Spec.Template.Spec.InitContainers {
+			if container.Name == "profileparser" {
+				return container.Command
+			}
+		}
+		return nil
+	}
+
+	return !reflect.DeepEqual(getCommand(desired), getCommand(found))
+}
+
+// getStandardsHash returns the hash of the standards configuration of the
+// bundle, or an empty string if the bundle has none or its ConfigMap doesn't
+// exist (yet)
+func (r *ReconcileProfileBundle) getStandardsHash(pb *compliancev1alpha1.ProfileBundle) (string, error) {
+	if pb.Spec.StandardsConfigMap == nil || pb.Spec.StandardsConfigMap.Name == "" {
+		return "", nil
+	}
+	cm := &corev1.ConfigMap{}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: pb.Spec.StandardsConfigMap.Name, Namespace: pb.Namespace}, cm)
+	if errors.IsNotFound(err) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(cm.Data[profileparser.StandardsConfigMapKey]))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// standardsHashChanged returns true if the found Deployment runs the profile
+// parser with a different standards configuration than the desired one
+func standardsHashChanged(desired, found *appsv1.Deployment) bool {
+	return desired.Spec.Template.Annotations[standardsHashAnnotation] != found.Spec.Template.Annotations[standardsHashAnnotation]
+}
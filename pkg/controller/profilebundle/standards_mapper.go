@@ -0,0 +1,39 @@
+package profilebundle
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	compliancev1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+)
+
+// standardsConfigMapMapper enqueues the ProfileBundles that reference the
+// standards ConfigMap that changed, so that their content is parsed again
+// with the new configuration.
+type standardsConfigMapMapper struct {
+	client.Client
+}
+
+func (m *standardsConfigMapMapper) Map(ctx context.Context, obj client.Object) []reconcile.Request {
+	var requests []reconcile.Request
+
+	pbList := compliancev1alpha1.ProfileBundleList{}
+	if err := m.List(ctx, &pbList, client.InNamespace(obj.GetNamespace())); err != nil {
+		return requests
+	}
+
+	for i := range pbList.Items {
+		pb := &pbList.Items[i]
+		if pb.Spec.StandardsConfigMap == nil || pb.Spec.StandardsConfigMap.Name != obj.GetName() {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+			Name:      pb.GetName(),
+			Namespace: pb.GetNamespace(),
+		}})
+	}
+	return requests
+}
@@ -0,0 +1,59 @@
+package remediationapproval
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+)
+
+type suiteMapper struct {
+	client.Client
+}
+
+// Map enqueues the approvals that review the suite, so that they hold its
+// remediations once the suite is done
+func (s *suiteMapper) Map(ctx context.Context, obj client.Object) []reconcile.Request {
+	var requests []reconcile.Request
+
+	approvalList := compv1alpha1.RemediationApprovalList{}
+	err := s.List(ctx, &approvalList, client.InNamespace(obj.GetNamespace()))
+	if err != nil {
+		return requests
+	}
+
+	for i := range approvalList.Items {
+		approval := &approvalList.Items[i]
+		if approval.Spec.Suite != obj.GetName() {
+			continue
+		}
+
+		objKey := types.NamespacedName{
+			Name:      approval.GetName(),
+			Namespace: approval.GetNamespace(),
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: objKey})
+	}
+
+	return requests
+}
+
+type remediationMapper struct{}
+
+// Map enqueues the approval that holds the remediation, so that its preview
+// is kept up to date
+func (r *remediationMapper) Map(ctx context.Context, obj client.Object) []reconcile.Request {
+	name, ok := obj.GetAnnotations()[compv1alpha1.RemediationApprovalAnnotation]
+	if !ok {
+		return nil
+	}
+
+	objKey := types.NamespacedName{
+		Name:      name,
+		Namespace: obj.GetNamespace(),
+	}
+	return []reconcile.Request{{NamespacedName: objKey}}
+}
@@ -0,0 +1,386 @@
+package remediationapproval
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/common"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/metrics"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/utils"
+)
+
+var log = logf.Log.WithName("remediationapprovalctrl")
+
+// Add creates a new RemediationApproval Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, met *metrics.Metrics, _ utils.CtlplaneSchedulingInfo, _ *kubernetes.Clientset) error {
+	return add(mgr, newReconciler(mgr, met))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, met *metrics.Metrics) reconcile.Reconciler {
+	return &ReconcileRemediationApproval{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: common.NewSafeRecorder("remediationapprovalctrl", mgr),
+		Metrics:  met,
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	suiteMapper := &suiteMapper{mgr.GetClient()}
+	remMapper := &remediationMapper{}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("remediationapproval-controller").
+		For(&compv1alpha1.RemediationApproval{}).
+		Watches(&compv1alpha1.ComplianceSuite{}, handler.EnqueueRequestsFromMapFunc(suiteMapper.Map)).
+		Watches(&compv1alpha1.ComplianceRemediation{}, handler.EnqueueRequestsFromMapFunc(remMapper.Map)).
+		Complete(r)
+}
+
+// blank assignment to verify that ReconcileRemediationApproval implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileRemediationApproval{}
+
+// ReconcileRemediationApproval reconciles a RemediationApproval object
+type ReconcileRemediationApproval struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	Client   client.Client
+	Scheme   *runtime.Scheme
+	Recorder *common.SafeRecorder
+	Metrics  *metrics.Metrics
+}
+
+// Reconcile holds the pending remediations of the suite for review once the
+// suite is done, previews them, and applies or rejects each of them as the
+// decisions come in.
+func (r *ReconcileRemediationApproval) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling RemediationApproval")
+
+	instance := &compv1alpha1.RemediationApproval{}
+	err := r.Client.Get(ctx, request.NamespacedName, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Return and don't requeue
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	if instance.ObjectMeta.DeletionTimestamp.IsZero() {
+		if !common.ContainsFinalizer(instance.ObjectMeta.Finalizers, compv1alpha1.RemediationApprovalFinalizer) {
+			approvalCopy := instance.DeepCopy()
+			approvalCopy.ObjectMeta.Finalizers = append(approvalCopy.ObjectMeta.Finalizers, compv1alpha1.RemediationApprovalFinalizer)
+			return reconcile.Result{}, r.Client.Update(ctx, approvalCopy)
+		}
+	} else {
+		// The object is being deleted
+		return reconcile.Result{}, r.approvalDeleteHandler(ctx, instance, reqLogger)
+	}
+
+	suite := &compv1alpha1.ComplianceSuite{}
+	err = r.Client.Get(ctx, types.NamespacedName{Name: instance.Spec.Suite, Namespace: instance.Namespace}, suite)
+	if errors.IsNotFound(err) {
+		return r.updateStatus(ctx, instance, func(s *compv1alpha1.RemediationApprovalStatus) {
+			s.Phase = compv1alpha1.RemediationApprovalPhaseError
+			s.ErrorMessage = fmt.Sprintf("ComplianceSuite '%s' not found", instance.Spec.Suite)
+		})
+	} else if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	remList := &compv1alpha1.ComplianceRemediationList{}
+	if err := r.Client.List(ctx, remList, client.InNamespace(instance.Namespace),
+		client.MatchingLabels{compv1alpha1.SuiteLabel: suite.Name}); err != nil {
+		return reconcile.Result{}, err
+	}
+	remediations := make(map[string]*compv1alpha1.ComplianceRemediation, len(remList.Items))
+	for i := range remList.Items {
+		remediations[remList.Items[i].Name] = &remList.Items[i]
+	}
+
+	reviews, err := r.reconcileReviews(ctx, instance, remediations, reqLogger)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// The remediations are only held once the suite is done, so that they
+	// are all there
+	if suite.Status.Phase == compv1alpha1.PhaseDone {
+		held, err := r.holdRemediations(ctx, instance, remList.Items, reviews, reqLogger)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		reviews = append(reviews, held...)
+	}
+
+	sort.Slice(reviews, func(i, j int) bool {
+		return reviews[i].Name < reviews[j].Name
+	})
+
+	return r.updateStatus(ctx, instance, func(s *compv1alpha1.RemediationApprovalStatus) {
+		s.Phase = getPhase(suite, reviews)
+		s.ErrorMessage = ""
+		s.Remediations = reviews
+	})
+}
+
+// reconcileReviews refreshes the previews of the remediations pending review
+// and applies the decisions made on them. Pending reviews whose remediation
+// is gone are dropped.
+func (r *ReconcileRemediationApproval) reconcileReviews(ctx context.Context, approval *compv1alpha1.RemediationApproval, remediations map[string]*compv1alpha1.ComplianceRemediation, logger logr.Logger) ([]compv1alpha1.RemediationReview, error) {
+	reviews := make([]compv1alpha1.RemediationReview, 0, len(approval.Status.Remediations))
+
+	for _, review := range approval.Status.Remediations {
+		if review.Decision != compv1alpha1.RemediationDecisionPending {
+			reviews = append(reviews, review)
+			continue
+		}
+
+		rem, ok := remediations[review.Name]
+		if !ok || !isHeldBy(rem, approval) {
+			logger.Info("Remediation is no longer held for review", "ComplianceRemediation.Name", review.Name)
+			continue
+		}
+
+		review.Changes = nil
+		review.PreviewError = ""
+		if rem.Status.Preview != nil {
+			review.Changes = rem.Status.Preview.Changes
+			review.PreviewError = rem.Status.Preview.ErrorMessage
+		}
+
+		decision := approval.GetDecision(review.Name)
+		if decision != nil {
+			logger.Info("Recording decision", "ComplianceRemediation.Name", review.Name,
+				"Decision", decision.Decision, "Reviewer", decision.Reviewer)
+			if err := r.Client.Update(ctx, decideRemediation(rem, approval, decision.Decision)); err != nil {
+				return nil, err
+			}
+
+			now := metav1.Now()
+			review.Decision = decision.Decision
+			review.Reviewer = decision.Reviewer
+			review.Comment = decision.Comment
+			review.DecidedAt = &now
+			r.Recorder.Eventf(approval, corev1.EventTypeNormal, "Remediation"+string(decision.Decision),
+				"Remediation %s was %s by %s", review.Name, decisionVerb(decision.Decision), decision.Reviewer)
+		}
+
+		reviews = append(reviews, review)
+	}
+
+	return reviews, nil
+}
+
+// holdRemediations holds the remediations of the suite that aren't applied
+// and aren't reviewed yet. The held remediations get previewed, and aren't
+// applied by the suite until they are approved.
+func (r *ReconcileRemediationApproval) holdRemediations(ctx context.Context, approval *compv1alpha1.RemediationApproval, remediations []compv1alpha1.ComplianceRemediation, reviews []compv1alpha1.RemediationReview, logger logr.Logger) ([]compv1alpha1.RemediationReview, error) {
+	reviewed := make(map[string]bool, len(reviews))
+	for _, review := range reviews {
+		reviewed[review.Name] = true
+	}
+
+	var held []compv1alpha1.RemediationReview
+	var rules *compv1alpha1.RuleList
+	for i := range remediations {
+		rem := &remediations[i]
+		if reviewed[rem.Name] || rem.Spec.Apply || rem.Spec.Current.Object == nil {
+			continue
+		}
+		// Rolled back and rejected remediations are left for the admin to
+		// apply manually, and another approval might hold the remediation
+		// already
+		if rem.HasAnnotation(compv1alpha1.RemediationRolledBackAnnotation) ||
+			rem.HasAnnotation(compv1alpha1.RemediationRejectedAnnotation) ||
+			(rem.HasAnnotation(compv1alpha1.RemediationApprovalAnnotation) && !isHeldBy(rem, approval)) {
+			continue
+		}
+
+		if rules == nil {
+			rules = &compv1alpha1.RuleList{}
+			if err := r.Client.List(ctx, rules, client.InNamespace(approval.Namespace)); err != nil {
+				return nil, err
+			}
+		}
+		disruption, err := r.getDisruption(ctx, rem, rules)
+		if err != nil {
+			return nil, err
+		}
+
+		logger.Info("Holding remediation for review", "ComplianceRemediation.Name", rem.Name)
+		if err := r.Client.Update(ctx, holdRemediation(rem, approval)); err != nil {
+			return nil, err
+		}
+
+		held = append(held, compv1alpha1.RemediationReview{
+			Name:       rem.Name,
+			Kind:       rem.Spec.Current.Object.GetKind(),
+			ObjectName: rem.Spec.Current.Object.GetName(),
+			Disruption: disruption,
+			Decision:   compv1alpha1.RemediationDecisionPending,
+		})
+	}
+
+	if len(held) > 0 {
+		r.Recorder.Eventf(approval, corev1.EventTypeNormal, "RemediationsHeld",
+			"%d remediations of suite %s are held for review", len(held), approval.Spec.Suite)
+	}
+	return held, nil
+}
+
+// getDisruption returns the disruption of the fix of the rule that the
+// remediation comes from, if it's known
+func (r *ReconcileRemediationApproval) getDisruption(ctx context.Context, rem *compv1alpha1.ComplianceRemediation, rules *compv1alpha1.RuleList) (string, error) {
+	// Remediations are owned by the check result they remediate
+	checkName := rem.Name
+	if owner := metav1.GetControllerOf(rem); owner != nil && owner.Kind == "ComplianceCheckResult" {
+		checkName = owner.Name
+	}
+
+	check := &compv1alpha1.ComplianceCheckResult{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: checkName, Namespace: rem.Namespace}, check)
+	if errors.IsNotFound(err) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+
+	ruleID := check.Annotations[compv1alpha1.ComplianceCheckResultRuleAnnotation]
+	if ruleID == "" {
+		return "", nil
+	}
+	for i := range rules.Items {
+		rule := &rules.Items[i]
+		if rule.Annotations[compv1alpha1.RuleIDAnnotationKey] != ruleID {
+			continue
+		}
+		for _, fix := range rule.AvailableFixes {
+			if fix.Disruption != "" {
+				return fix.Disruption, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+func (r *ReconcileRemediationApproval) approvalDeleteHandler(ctx context.Context, approval *compv1alpha1.RemediationApproval, logger logr.Logger) error {
+	remList := &compv1alpha1.ComplianceRemediationList{}
+	if err := r.Client.List(ctx, remList, client.InNamespace(approval.Namespace)); err != nil {
+		return err
+	}
+
+	// Release the remediations that weren't decided on, so that the suite
+	// handles them as usual again. The rejected ones keep being marked as
+	// such, so that the suite still doesn't apply them.
+	for i := range remList.Items {
+		rem := &remList.Items[i]
+		if !isHeldBy(rem, approval) {
+			continue
+		}
+		logger.Info("Releasing remediation", "ComplianceRemediation.Name", rem.Name,
+			"Rejected", rem.HasAnnotation(compv1alpha1.RemediationRejectedAnnotation))
+		if err := r.Client.Update(ctx, releaseRemediation(rem)); err != nil {
+			return err
+		}
+	}
+
+	approvalCopy := approval.DeepCopy()
+	// remove our finalizer from the list and update it.
+	approvalCopy.ObjectMeta.Finalizers = common.RemoveFinalizer(approvalCopy.ObjectMeta.Finalizers, compv1alpha1.RemediationApprovalFinalizer)
+	return r.Client.Update(ctx, approvalCopy)
+}
+
+// updateStatus applies the mutation to a copy of the approval's status and
+// only updates the object if that changed anything
+func (r *ReconcileRemediationApproval) updateStatus(ctx context.Context, approval *compv1alpha1.RemediationApproval, mutate func(s *compv1alpha1.RemediationApprovalStatus)) (reconcile.Result, error) {
+	approvalCopy := approval.DeepCopy()
+	mutate(&approvalCopy.Status)
+	if reflect.DeepEqual(approval.Status, approvalCopy.Status) {
+		return reconcile.Result{}, nil
+	}
+
+	if err := r.Client.Status().Update(ctx, approvalCopy); err != nil {
+		return reconcile.Result{}, fmt.Errorf("couldn't update RemediationApproval status: %w", err)
+	}
+	return reconcile.Result{}, nil
+}
+
+func getPhase(suite *compv1alpha1.ComplianceSuite, reviews []compv1alpha1.RemediationReview) compv1alpha1.RemediationApprovalPhase {
+	if len(reviews) == 0 && suite.Status.Phase != compv1alpha1.PhaseDone {
+		return compv1alpha1.RemediationApprovalPhasePending
+	}
+	for _, review := range reviews {
+		if review.Decision == compv1alpha1.RemediationDecisionPending {
+			return compv1alpha1.RemediationApprovalPhaseReviewing
+		}
+	}
+	return compv1alpha1.RemediationApprovalPhaseDone
+}
+
+func decisionVerb(decision compv1alpha1.RemediationDecisionType) string {
+	if decision == compv1alpha1.RemediationDecisionApproved {
+		return "approved"
+	}
+	return "rejected"
+}
+
+func isHeldBy(rem *compv1alpha1.ComplianceRemediation, approval *compv1alpha1.RemediationApproval) bool {
+	return rem.Annotations[compv1alpha1.RemediationApprovalAnnotation] == approval.Name
+}
+
+func holdRemediation(rem *compv1alpha1.ComplianceRemediation, approval *compv1alpha1.RemediationApproval) *compv1alpha1.ComplianceRemediation {
+	remCopy := rem.DeepCopy()
+	if remCopy.Annotations == nil {
+		remCopy.Annotations = make(map[string]string)
+	}
+	remCopy.Annotations[compv1alpha1.RemediationApprovalAnnotation] = approval.Name
+	remCopy.Annotations[compv1alpha1.RemediationPreviewAnnotation] = ""
+	return remCopy
+}
+
+// decideRemediation stops previewing the remediation and, if it's approved,
+// releases it and sets it to apply. Rejected remediations stay held and are
+// marked as rejected, so that the suite doesn't apply them even once the
+// approval is gone.
+func decideRemediation(rem *compv1alpha1.ComplianceRemediation, approval *compv1alpha1.RemediationApproval, decision compv1alpha1.RemediationDecisionType) *compv1alpha1.ComplianceRemediation {
+	if decision == compv1alpha1.RemediationDecisionApproved {
+		remCopy := releaseRemediation(rem)
+		remCopy.Spec.Apply = true
+		return remCopy
+	}
+	remCopy := rem.DeepCopy()
+	delete(remCopy.Annotations, compv1alpha1.RemediationPreviewAnnotation)
+	remCopy.Annotations[compv1alpha1.RemediationRejectedAnnotation] = approval.Name
+	return remCopy
+}
+
+func releaseRemediation(rem *compv1alpha1.ComplianceRemediation) *compv1alpha1.ComplianceRemediation {
+	remCopy := rem.DeepCopy()
+	delete(remCopy.Annotations, compv1alpha1.RemediationApprovalAnnotation)
+	delete(remCopy.Annotations, compv1alpha1.RemediationPreviewAnnotation)
+	return remCopy
+}
@@ -0,0 +1,362 @@
+package remediationapproval
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/ComplianceAsCode/compliance-operator/pkg/apis"
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/common"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/metrics"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/metrics/metricsfakes"
+)
+
+const namespace = "test-ns"
+
+func newRule(name, ruleID, disruption string) *compv1alpha1.Rule {
+	return &compv1alpha1.Rule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Annotations: map[string]string{
+				compv1alpha1.RuleIDAnnotationKey: ruleID,
+			},
+		},
+		RulePayload: compv1alpha1.RulePayload{
+			ID: ruleID,
+			AvailableFixes: []compv1alpha1.FixDefinition{
+				{Disruption: disruption},
+			},
+		},
+	}
+}
+
+func newCheck(name, ruleID string) *compv1alpha1.ComplianceCheckResult {
+	return &compv1alpha1.ComplianceCheckResult{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Annotations: map[string]string{
+				compv1alpha1.ComplianceCheckResultRuleAnnotation: ruleID,
+			},
+		},
+		Status: compv1alpha1.CheckResultFail,
+	}
+}
+
+func newRemediation(name, kind string, apply bool) *compv1alpha1.ComplianceRemediation {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind(kind)
+	obj.SetName(name + "-obj")
+
+	return &compv1alpha1.ComplianceRemediation{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				compv1alpha1.SuiteLabel: "e8",
+			},
+		},
+		Spec: compv1alpha1.ComplianceRemediationSpec{
+			ComplianceRemediationSpecMeta: compv1alpha1.ComplianceRemediationSpecMeta{
+				Apply: apply,
+			},
+			Current: compv1alpha1.ComplianceRemediationPayload{
+				Object: obj,
+			},
+		},
+	}
+}
+
+var _ = Describe("RemediationApproval controller", func() {
+	var (
+		ctx         = context.Background()
+		approvalKey = types.NamespacedName{Name: "e8-review", Namespace: namespace}
+		reconciler  *ReconcileRemediationApproval
+		suite       *compv1alpha1.ComplianceSuite
+		approval    *compv1alpha1.RemediationApproval
+	)
+
+	reconcileApproval := func() {
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: approvalKey})
+		Expect(err).To(BeNil())
+	}
+
+	getApproval := func() *compv1alpha1.RemediationApproval {
+		found := &compv1alpha1.RemediationApproval{}
+		Expect(reconciler.Client.Get(ctx, approvalKey, found)).To(BeNil())
+		return found
+	}
+
+	getRemediation := func(name string) *compv1alpha1.ComplianceRemediation {
+		rem := &compv1alpha1.ComplianceRemediation{}
+		Expect(reconciler.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, rem)).To(BeNil())
+		return rem
+	}
+
+	decide := func(decisions ...compv1alpha1.RemediationDecision) {
+		found := getApproval()
+		found.Spec.Decisions = decisions
+		Expect(reconciler.Client.Update(ctx, found)).To(BeNil())
+	}
+
+	BeforeEach(func() {
+		cscheme := scheme.Scheme
+		err := apis.AddToScheme(cscheme)
+		Expect(err).To(BeNil())
+
+		suite = &compv1alpha1.ComplianceSuite{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "e8",
+				Namespace: namespace,
+			},
+			Status: compv1alpha1.ComplianceSuiteStatus{
+				Phase: compv1alpha1.PhaseDone,
+			},
+		}
+
+		approval = &compv1alpha1.RemediationApproval{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      approvalKey.Name,
+				Namespace: namespace,
+			},
+			Spec: compv1alpha1.RemediationApprovalSpec{
+				Suite: "e8",
+			},
+		}
+
+		objs := []runtime.Object{
+			newRule("ocp4-api-server-audit-log-path", "api-server-audit-log-path", "low"),
+			newRule("rhcos4-sysctl-kernel-kptr-restrict", "sysctl-kernel-kptr-restrict", "medium"),
+			newCheck("e8-api-server-audit-log-path", "api-server-audit-log-path"),
+			newCheck("e8-sysctl-kernel-kptr-restrict", "sysctl-kernel-kptr-restrict"),
+			newRemediation("e8-api-server-audit-log-path", "APIServer", false),
+			newRemediation("e8-sysctl-kernel-kptr-restrict", "MachineConfig", false),
+			newRemediation("e8-already-applied", "ConfigMap", true),
+		}
+
+		client := fake.NewClientBuilder().
+			WithScheme(cscheme).
+			WithStatusSubresource(&compv1alpha1.RemediationApproval{}, &compv1alpha1.ComplianceRemediation{}).
+			WithRuntimeObjects(objs...).
+			Build()
+
+		mockMetrics := metrics.NewMetrics(&metricsfakes.FakeImpl{})
+		err = mockMetrics.Register()
+		Expect(err).To(BeNil())
+
+		reconciler = &ReconcileRemediationApproval{
+			Client:   client,
+			Scheme:   cscheme,
+			Recorder: &common.SafeRecorder{},
+			Metrics:  mockMetrics,
+		}
+	})
+
+	JustBeforeEach(func() {
+		Expect(reconciler.Client.Create(ctx, suite)).To(BeNil())
+		Expect(reconciler.Client.Create(ctx, approval)).To(BeNil())
+	})
+
+	Context("with a done suite", func() {
+		It("holds the pending remediations for review", func() {
+			By("Adding the finalizer")
+			reconcileApproval()
+			Expect(getApproval().Finalizers).To(ContainElement(compv1alpha1.RemediationApprovalFinalizer))
+
+			By("Holding the remediations that aren't applied")
+			reconcileApproval()
+			found := getApproval()
+			Expect(found.Status.Phase).To(Equal(compv1alpha1.RemediationApprovalPhaseReviewing))
+			Expect(found.Status.Remediations).To(HaveLen(2))
+
+			review := found.Status.Remediations[0]
+			Expect(review.Name).To(Equal("e8-api-server-audit-log-path"))
+			Expect(review.Kind).To(Equal("APIServer"))
+			Expect(review.ObjectName).To(Equal("e8-api-server-audit-log-path-obj"))
+			Expect(review.Disruption).To(Equal("low"))
+			Expect(review.Decision).To(Equal(compv1alpha1.RemediationDecisionPending))
+			Expect(found.Status.Remediations[1].Disruption).To(Equal("medium"))
+
+			rem := getRemediation("e8-api-server-audit-log-path")
+			Expect(rem.Annotations).To(HaveKeyWithValue(compv1alpha1.RemediationApprovalAnnotation, "e8-review"))
+			Expect(rem.Annotations).To(HaveKey(compv1alpha1.RemediationPreviewAnnotation))
+			Expect(getRemediation("e8-already-applied").Annotations).ToNot(HaveKey(compv1alpha1.RemediationApprovalAnnotation))
+		})
+
+		It("shows the previewed changes", func() {
+			reconcileApproval()
+			reconcileApproval()
+
+			rem := getRemediation("e8-api-server-audit-log-path")
+			rem.Status.Preview = &compv1alpha1.RemediationPreview{
+				Changes: []compv1alpha1.RemediationFieldChange{
+					{Path: ".spec.audit.profile", Current: `"Default"`, Desired: `"WriteRequestBodies"`},
+				},
+			}
+			Expect(reconciler.Client.Status().Update(ctx, rem)).To(BeNil())
+			reconcileApproval()
+
+			review := getApproval().Status.Remediations[0]
+			Expect(review.Changes).To(Equal(rem.Status.Preview.Changes))
+		})
+
+		It("applies the approved remediations only", func() {
+			reconcileApproval()
+			reconcileApproval()
+
+			decide(compv1alpha1.RemediationDecision{
+				Remediation: "e8-api-server-audit-log-path",
+				Decision:    compv1alpha1.RemediationDecisionApproved,
+				Reviewer:    "alice",
+			}, compv1alpha1.RemediationDecision{
+				Remediation: "e8-sysctl-kernel-kptr-restrict",
+				Decision:    compv1alpha1.RemediationDecisionRejected,
+				Reviewer:    "bob",
+				Comment:     "Needs a maintenance window",
+			})
+			reconcileApproval()
+
+			found := getApproval()
+			Expect(found.Status.Phase).To(Equal(compv1alpha1.RemediationApprovalPhaseDone))
+			approved := found.Status.Remediations[0]
+			Expect(approved.Decision).To(Equal(compv1alpha1.RemediationDecisionApproved))
+			Expect(approved.Reviewer).To(Equal("alice"))
+			Expect(approved.DecidedAt).ToNot(BeNil())
+			rejected := found.Status.Remediations[1]
+			Expect(rejected.Decision).To(Equal(compv1alpha1.RemediationDecisionRejected))
+			Expect(rejected.Comment).To(Equal("Needs a maintenance window"))
+
+			rem := getRemediation("e8-api-server-audit-log-path")
+			Expect(rem.Spec.Apply).To(BeTrue())
+			Expect(rem.Annotations).ToNot(HaveKey(compv1alpha1.RemediationApprovalAnnotation))
+			Expect(rem.Annotations).ToNot(HaveKey(compv1alpha1.RemediationPreviewAnnotation))
+
+			rem = getRemediation("e8-sysctl-kernel-kptr-restrict")
+			Expect(rem.Spec.Apply).To(BeFalse())
+			Expect(rem.Annotations).To(HaveKeyWithValue(compv1alpha1.RemediationApprovalAnnotation, "e8-review"))
+			Expect(rem.Annotations).To(HaveKeyWithValue(compv1alpha1.RemediationRejectedAnnotation, "e8-review"))
+			Expect(rem.Annotations).ToNot(HaveKey(compv1alpha1.RemediationPreviewAnnotation))
+
+			By("Keeping the decisions once recorded")
+			decide()
+			reconcileApproval()
+			Expect(getApproval().Status.Remediations[1].Decision).To(Equal(compv1alpha1.RemediationDecisionRejected))
+		})
+
+		It("releases the held remediations when deleted", func() {
+			reconcileApproval()
+			reconcileApproval()
+
+			Expect(reconciler.Client.Delete(ctx, getApproval())).To(BeNil())
+			reconcileApproval()
+
+			rem := getRemediation("e8-sysctl-kernel-kptr-restrict")
+			Expect(rem.Annotations).ToNot(HaveKey(compv1alpha1.RemediationApprovalAnnotation))
+			Expect(rem.Annotations).ToNot(HaveKey(compv1alpha1.RemediationPreviewAnnotation))
+			Expect(rem.Annotations).ToNot(HaveKey(compv1alpha1.RemediationRejectedAnnotation))
+
+			found := &compv1alpha1.RemediationApproval{}
+			err := reconciler.Client.Get(ctx, approvalKey, found)
+			Expect(err).ToNot(BeNil())
+		})
+
+		It("keeps the rejected remediations rejected when deleted", func() {
+			reconcileApproval()
+			reconcileApproval()
+
+			decide(compv1alpha1.RemediationDecision{
+				Remediation: "e8-sysctl-kernel-kptr-restrict",
+				Decision:    compv1alpha1.RemediationDecisionRejected,
+				Reviewer:    "bob",
+			})
+			reconcileApproval()
+
+			Expect(reconciler.Client.Delete(ctx, getApproval())).To(BeNil())
+			reconcileApproval()
+
+			rem := getRemediation("e8-sysctl-kernel-kptr-restrict")
+			Expect(rem.Spec.Apply).To(BeFalse())
+			Expect(rem.Annotations).ToNot(HaveKey(compv1alpha1.RemediationApprovalAnnotation))
+			Expect(rem.Annotations).To(HaveKeyWithValue(compv1alpha1.RemediationRejectedAnnotation, "e8-review"))
+
+			By("Not holding the rejected remediations for review again")
+			approval = &compv1alpha1.RemediationApproval{
+				ObjectMeta: metav1.ObjectMeta{Name: approvalKey.Name, Namespace: namespace},
+				Spec:       compv1alpha1.RemediationApprovalSpec{Suite: "e8"},
+			}
+			Expect(reconciler.Client.Create(ctx, approval)).To(BeNil())
+			reconcileApproval()
+			reconcileApproval()
+
+			found := getApproval()
+			Expect(found.Status.Remediations).To(HaveLen(1))
+			Expect(found.Status.Remediations[0].Name).To(Equal("e8-api-server-audit-log-path"))
+		})
+	})
+
+	Context("with a running suite", func() {
+		BeforeEach(func() {
+			suite.Status.Phase = compv1alpha1.PhaseRunning
+		})
+
+		It("waits for the suite to be done", func() {
+			reconcileApproval()
+			reconcileApproval()
+
+			found := getApproval()
+			Expect(found.Status.Phase).To(Equal(compv1alpha1.RemediationApprovalPhasePending))
+			Expect(found.Status.Remediations).To(BeEmpty())
+			Expect(getRemediation("e8-api-server-audit-log-path").Annotations).ToNot(HaveKey(compv1alpha1.RemediationApprovalAnnotation))
+		})
+	})
+
+	Context("with an unknown suite", func() {
+		BeforeEach(func() {
+			approval.Spec.Suite = "unexistent"
+		})
+
+		It("reports an error", func() {
+			reconcileApproval()
+			reconcileApproval()
+
+			found := getApproval()
+			Expect(found.Status.Phase).To(Equal(compv1alpha1.RemediationApprovalPhaseError))
+			Expect(found.Status.ErrorMessage).To(ContainSubstring("not found"))
+		})
+	})
+
+	Context("mapping objects", func() {
+		It("enqueues the approvals of the suite", func() {
+			mapper := &suiteMapper{reconciler.Client}
+			requests := mapper.Map(ctx, suite)
+			Expect(requests).To(HaveLen(1))
+			Expect(requests[0].NamespacedName).To(Equal(approvalKey))
+
+			other := suite.DeepCopy()
+			other.Name = "cis"
+			Expect(mapper.Map(ctx, other)).To(BeEmpty())
+		})
+
+		It("enqueues the approval that holds the remediation", func() {
+			mapper := &remediationMapper{}
+			rem := newRemediation("e8-api-server-audit-log-path", "APIServer", false)
+			Expect(mapper.Map(ctx, rem)).To(BeEmpty())
+
+			rem.Annotations = map[string]string{compv1alpha1.RemediationApprovalAnnotation: "e8-review"}
+			requests := mapper.Map(ctx, rem)
+			Expect(requests).To(HaveLen(1))
+			Expect(requests[0].NamespacedName).To(Equal(approvalKey))
+		})
+	})
+})
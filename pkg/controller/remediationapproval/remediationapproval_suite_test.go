@@ -0,0 +1,13 @@
+package remediationapproval
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestRemediationapproval(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Remediationapproval Suite")
+}
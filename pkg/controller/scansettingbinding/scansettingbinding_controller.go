@@ -181,6 +181,23 @@ func (r *ReconcileScanSettingBinding) Reconcile(ctx context.Context, request rec
 					"TailoredProfile", profileObj.GetName())
 				return reconcile.Result{Requeue: true, RequeueAfter: requeueAfterDefault}, nil
 			}
+
+			// A composed TailoredProfile is scanned once per tailoring
+			// it generated
+			composedScans, products, composeErr := newCompScansFromComposedProfile(r, instance, profileObj, reqLogger)
+			if composeErr != nil {
+				return common.ReturnWithRetriableError(reqLogger, composeErr)
+			}
+			if composedScans != nil {
+				for _, product := range products {
+					nodeProduct = getRelevantProduct(nodeProduct, product)
+					if isDifferentProduct(nodeProduct, product) {
+						return r.setMultipleProductsInvalid(instance, product, nodeProduct)
+					}
+				}
+				suite.Spec.Scans = append(suite.Spec.Scans, composedScans...)
+				continue
+			}
 		}
 
 		scan, product, err := newCompScanFromBindingProfile(r, instance, profileObj, log)
@@ -191,17 +208,7 @@ func (r *ReconcileScanSettingBinding) Reconcile(ctx context.Context, request rec
 		nodeProduct = getRelevantProduct(nodeProduct, product)
 
 		if isDifferentProduct(nodeProduct, product) {
-			msg := fmt.Sprintf("ScanSettingBinding defines multiple products: %s and %s", product, nodeProduct)
-			r.Eventf(instance, corev1.EventTypeWarning, "MultipleProducts", msg)
-
-			ssb := instance.DeepCopy()
-			ssb.Status.SetConditionInvalid(msg)
-			ssb.Status.Phase = compliancev1alpha1.ScanSettingBindingPhaseInvalid
-			if updateErr := r.Client.Status().Update(context.TODO(), ssb); updateErr != nil {
-				return reconcile.Result{}, fmt.Errorf("couldn't update ScanSettingBinding condition: %w", updateErr)
-			}
-			// Don't requeue in this case, nothing we can do
-			return reconcile.Result{}, nil
+			return r.setMultipleProductsInvalid(instance, product, nodeProduct)
 		}
 
 		suite.Spec.Scans = append(suite.Spec.Scans, *scan)
@@ -329,6 +336,22 @@ func (r *ReconcileScanSettingBinding) Reconcile(ctx context.Context, request rec
 	return reconcile.Result{}, nil
 }
 
+// setMultipleProductsInvalid marks the binding as invalid because its
+// profiles scan the nodes for different products
+func (r *ReconcileScanSettingBinding) setMultipleProductsInvalid(instance *compliancev1alpha1.ScanSettingBinding, product, nodeProduct string) (reconcile.Result, error) {
+	msg := fmt.Sprintf("ScanSettingBinding defines multiple products: %s and %s", product, nodeProduct)
+	r.Eventf(instance, corev1.EventTypeWarning, "MultipleProducts", msg)
+
+	ssb := instance.DeepCopy()
+	ssb.Status.SetConditionInvalid(msg)
+	ssb.Status.Phase = compliancev1alpha1.ScanSettingBindingPhaseInvalid
+	if updateErr := r.Client.Status().Update(context.TODO(), ssb); updateErr != nil {
+		return reconcile.Result{}, fmt.Errorf("couldn't update ScanSettingBinding condition: %w", updateErr)
+	}
+	// Don't requeue in this case, nothing we can do
+	return reconcile.Result{}, nil
+}
+
 func getRelevantProduct(nodeProduct, incomingProduct string) string {
 	// Initialize
 	if nodeProduct == "" && incomingProduct != "" {
@@ -462,6 +485,58 @@ func newCompScanFromBindingProfile(r *ReconcileScanSettingBinding, instance *com
 	return scan, platform, nil
 }
 
+// newCompScansFromComposedProfile returns a scan for each of the tailorings
+// generated from a composed TailoredProfile, or nil if the TailoredProfile
+// isn't a composed one
+func newCompScansFromComposedProfile(r *ReconcileScanSettingBinding, instance *compliancev1alpha1.ScanSettingBinding, tp *unstructured.Unstructured, logger logr.Logger) ([]compliancev1alpha1.ComplianceScanSpecWrapper, []string, error) {
+	if err := isCmpv1Alpha1Gvk(tp, "TailoredProfile"); err != nil {
+		return nil, nil, common.WrapNonRetriableCtrlError(err)
+	}
+
+	v1alphaTp := compliancev1alpha1.TailoredProfile{}
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(tp.Object, &v1alphaTp)
+	if err != nil {
+		return nil, nil, common.WrapNonRetriableCtrlError(err)
+	}
+
+	if v1alphaTp.Spec.Compose == nil {
+		return nil, nil, nil
+	}
+	// A ready composed TailoredProfile without tailorings has nothing to
+	// scan, and won't get any until it's changed
+	if len(v1alphaTp.Status.Outputs) == 0 {
+		return nil, nil, common.NewNonRetriableCtrlError("composed TailoredProfile %s has no outputs to scan", v1alphaTp.Name)
+	}
+
+	scans := []compliancev1alpha1.ComplianceScanSpecWrapper{}
+	products := []string{}
+	for _, output := range v1alphaTp.Status.Outputs {
+		key := types.NamespacedName{Namespace: instance.Namespace, Name: output.ProfileBundle}
+		bundle, err := getUnstructured(r, instance, key, "ProfileBundle", compliancev1alpha1.SchemeGroupVersion.String(), logger)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		scan := compliancev1alpha1.ComplianceScanSpecWrapper{
+			ComplianceScanSpec: compliancev1alpha1.ComplianceScanSpec{
+				ScanType:           output.ScanType,
+				Profile:            output.ID,
+				TailoringConfigMap: &compliancev1alpha1.TailoringConfigMapRef{Name: output.OutputRef.Name},
+			},
+			Name: output.Name,
+		}
+		if err := fillContentData(bundle, &scan); err != nil {
+			return nil, nil, err
+		}
+		scans = append(scans, scan)
+		if output.ScanType == compliancev1alpha1.ScanTypeNode {
+			products = append(products, output.Product)
+		}
+	}
+
+	return scans, products, nil
+}
+
 type profileReference struct {
 	name string
 
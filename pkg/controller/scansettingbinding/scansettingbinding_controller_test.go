@@ -12,6 +12,7 @@ import (
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -535,6 +536,170 @@ var _ = Describe("Testing scansettingbinding controller", func() {
 		})
 	})
 
+	Context("Creates a suite from a composed TailoredProfile", func() {
+		var composedTP *compv1alpha1.TailoredProfile
+
+		JustBeforeEach(func() {
+			composedTP = &compv1alpha1.TailoredProfile{
+				TypeMeta: v1.TypeMeta{
+					Kind:       "TailoredProfile",
+					APIVersion: compv1alpha1.SchemeGroupVersion.String(),
+				},
+				ObjectMeta: v1.ObjectMeta{
+					Name:      "composed-tp",
+					Namespace: common.GetComplianceOperatorNamespace(),
+				},
+				Spec: compv1alpha1.TailoredProfileSpec{
+					Title:       "composed TP",
+					Description: "some desc",
+					Compose: &compv1alpha1.ProfileComposition{
+						Profiles:  []string{profRhcosE8.Name, "rhcos4-platform"},
+						Operation: compv1alpha1.ProfileSetOperationUnion,
+					},
+				},
+			}
+			err := reconciler.Client.Create(context.TODO(), composedTP)
+			Expect(err).To(BeNil())
+
+			composedTP.Status = compv1alpha1.TailoredProfileStatus{
+				State: compv1alpha1.TailoredProfileStateReady,
+				Outputs: []compv1alpha1.TailoringOutput{
+					{
+						Name:          "composed-tp-rhcos4-platform",
+						ID:            "xccdf_compliance.openshift.io_profile_composed-tp-rhcos4-platform",
+						ProfileBundle: pBundleRhcos.Name,
+						ScanType:      compv1alpha1.ScanTypePlatform,
+						OutputRef: compv1alpha1.OutputRef{
+							Name:      "composed-tp-rhcos4-platform-tp",
+							Namespace: common.GetComplianceOperatorNamespace(),
+						},
+					},
+					{
+						Name:          "composed-tp-rhcos4-node",
+						ID:            "xccdf_compliance.openshift.io_profile_composed-tp-rhcos4-node",
+						ProfileBundle: pBundleRhcos.Name,
+						ScanType:      compv1alpha1.ScanTypeNode,
+						Product:       "rhcos4",
+						OutputRef: compv1alpha1.OutputRef{
+							Name:      "composed-tp-rhcos4-node-tp",
+							Namespace: common.GetComplianceOperatorNamespace(),
+						},
+					},
+				},
+			}
+			err = reconciler.Client.Status().Update(context.TODO(), composedTP)
+			Expect(err).To(BeNil())
+
+			ssb = &compv1alpha1.ScanSettingBinding{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      "composed-tp",
+					Namespace: common.GetComplianceOperatorNamespace(),
+				},
+				Profiles: []compv1alpha1.NamedObjectReference{
+					{
+						Name:     composedTP.Name,
+						Kind:     composedTP.Kind,
+						APIGroup: composedTP.APIVersion,
+					},
+				},
+				SettingsRef: &compv1alpha1.NamedObjectReference{
+					Name:     setting.Name,
+					Kind:     setting.Kind,
+					APIGroup: setting.APIVersion,
+				},
+			}
+			ssb.Status.SetConditionPending()
+
+			err = reconciler.Client.Create(context.TODO(), ssb)
+			Expect(err).To(BeNil())
+		})
+
+		It("Should create a scan per tailoring of the TailoredProfile", func() {
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: ssb.Namespace,
+					Name:      ssb.Name,
+				},
+			})
+			Expect(err).To(BeNil())
+
+			err = reconciler.Client.Get(context.TODO(), types.NamespacedName{Name: ssb.Name, Namespace: ssb.Namespace}, suite)
+			Expect(err).To(BeNil())
+
+			newScan := func(output compv1alpha1.TailoringOutput, name string, selector map[string]string) compv1alpha1.ComplianceScanSpecWrapper {
+				return compv1alpha1.ComplianceScanSpecWrapper{
+					ComplianceScanSpec: compv1alpha1.ComplianceScanSpec{
+						ScanType:     output.ScanType,
+						ContentImage: pBundleRhcos.Spec.ContentImage,
+						Profile:      output.ID,
+						Content:      pBundleRhcos.Spec.ContentFile,
+						NodeSelector: selector,
+						TailoringConfigMap: &compv1alpha1.TailoringConfigMapRef{
+							Name: output.OutputRef.Name,
+						},
+						ComplianceScanSettings: compv1alpha1.ComplianceScanSettings{
+							Debug: true,
+						},
+					},
+					Name: name,
+				}
+			}
+			platform := composedTP.Status.Outputs[0]
+			node := composedTP.Status.Outputs[1]
+			Expect(suite.Spec.Scans).To(ConsistOf(
+				newScan(platform, "composed-tp-rhcos4-platform", nil),
+				newScan(node, "composed-tp-rhcos4-node-master", masterSelector),
+				newScan(node, "composed-tp-rhcos4-node-worker", workerSelector),
+			))
+		})
+
+		It("Should not create a suite if the tailorings scan another product", func() {
+			composedTP.Status.Outputs[1].Product = "somethingelse"
+			err := reconciler.Client.Status().Update(context.TODO(), composedTP)
+			Expect(err).To(BeNil())
+			ssb.Profiles = append(ssb.Profiles, compv1alpha1.NamedObjectReference{
+				Name:     profRhcosE8.Name,
+				Kind:     profRhcosE8.Kind,
+				APIGroup: profRhcosE8.APIVersion,
+			})
+			err = reconciler.Client.Update(context.TODO(), ssb)
+			Expect(err).To(BeNil())
+
+			res, err := reconciler.Reconcile(context.TODO(), reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: ssb.Namespace,
+					Name:      ssb.Name,
+				},
+			})
+			Expect(err).To(BeNil())
+			Expect(res.Requeue).To(BeFalse())
+
+			err = reconciler.Client.Get(context.TODO(), types.NamespacedName{Name: ssb.Name, Namespace: ssb.Namespace}, ssb)
+			Expect(err).To(BeNil())
+			Expect(ssb.Status.Conditions.GetCondition("Ready").Reason).To(Equal(compv1alpha1.ConditionReason("Invalid")))
+			err = reconciler.Client.Get(context.TODO(), types.NamespacedName{Name: ssb.Name, Namespace: ssb.Namespace}, suite)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("Should not create a suite if the TailoredProfile has no tailorings", func() {
+			composedTP.Status.Outputs = nil
+			err := reconciler.Client.Status().Update(context.TODO(), composedTP)
+			Expect(err).To(BeNil())
+
+			res, err := reconciler.Reconcile(context.TODO(), reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: ssb.Namespace,
+					Name:      ssb.Name,
+				},
+			})
+			Expect(err).To(BeNil())
+			Expect(res.Requeue).To(BeFalse())
+
+			err = reconciler.Client.Get(context.TODO(), types.NamespacedName{Name: ssb.Name, Namespace: ssb.Namespace}, suite)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+
 	Context("Detects error if unexistent profile", func() {
 		JustBeforeEach(func() {
 			ssb = &compv1alpha1.ScanSettingBinding{
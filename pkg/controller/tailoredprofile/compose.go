@@ -0,0 +1,515 @@
+package tailoredprofile
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	cmpv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/common"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/utils"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/xccdf"
+)
+
+// composedSource is a Profile being composed together with the
+// ProfileBundle it comes from
+type composedSource struct {
+	profile  *cmpv1alpha1.Profile
+	bundle   *cmpv1alpha1.ProfileBundle
+	scanType cmpv1alpha1.ComplianceScanType
+}
+
+// tailoringGroup holds the composed rules that are scanned together, that
+// is, the ones coming from the same ProfileBundle with the same scan type
+type tailoringGroup struct {
+	bundle      *cmpv1alpha1.ProfileBundle
+	scanType    cmpv1alpha1.ComplianceScanType
+	rules       []*cmpv1alpha1.Rule
+	manualRules map[string]bool
+	variables   []cmpv1alpha1.VariableValueSpec
+}
+
+func (g *tailoringGroup) outputName(tp *cmpv1alpha1.TailoredProfile) string {
+	return tp.Name + "-" + g.bundle.Name + "-" + strings.ToLower(string(g.scanType))
+}
+
+// reconcileComposedProfile renders a TailoredProfile that composes several
+// profiles. The composed rules are split in a tailoring per ProfileBundle and
+// scan type, each of them stored in its own ConfigMap and listed in the
+// status so that the ScanSettingBinding can launch a scan for each.
+func (r *ReconcileTailoredProfile) reconcileComposedProfile(instance *cmpv1alpha1.TailoredProfile, logger logr.Logger) (reconcile.Result, error) {
+	if instance.Spec.Extends != "" || instance.Spec.ExtendsTailoredProfile != "" {
+		err := common.NewNonRetriableCtrlError("TailoredProfile '%s' can't set compose together with extends or extendsTailoredProfile", instance.Name)
+		return reconcile.Result{}, r.handleComposedProfileStatusError(instance, err)
+	}
+
+	sources, err := r.getComposedSources(instance)
+	if err != nil && !common.IsRetriable(err) {
+		return reconcile.Result{}, r.handleComposedProfileStatusError(instance, err)
+	} else if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Make the TailoredProfile be owned by all the ProfileBundles it
+	// composes. This way it's garbage collected once they're all gone.
+	// This update will trigger a requeue with the new object.
+	tpCopy := instance.DeepCopy()
+	for _, src := range sources {
+		if err := controllerutil.SetOwnerReference(src.bundle, tpCopy, r.Scheme); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+	if len(tpCopy.GetOwnerReferences()) != len(instance.GetOwnerReferences()) {
+		return reconcile.Result{}, r.Client.Update(context.TODO(), tpCopy)
+	}
+
+	ruleNames, manualRules, err := composeSelections(instance, sources)
+	if err != nil {
+		return reconcile.Result{}, r.handleComposedProfileStatusError(instance, err)
+	}
+
+	groups, err := r.groupComposedRules(instance, ruleNames, manualRules)
+	if err != nil && !common.IsRetriable(err) {
+		return reconcile.Result{}, r.handleComposedProfileStatusError(instance, err)
+	} else if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	customRules, err := r.getCustomRules(instance)
+	if err != nil && !common.IsRetriable(err) {
+		return reconcile.Result{}, r.handleComposedProfileStatusError(instance, err)
+	} else if err != nil {
+		return reconcile.Result{}, err
+	}
+	// The CustomRules are checked once, by the first scan of their type
+	scanTypes := []cmpv1alpha1.ComplianceScanType{cmpv1alpha1.ScanTypePlatform, cmpv1alpha1.ScanTypeNode}
+	customRulesData := map[cmpv1alpha1.ComplianceScanType]string{}
+	for _, scanType := range scanTypes {
+		ofType := customRulesOfType(customRules, string(scanType))
+		if len(ofType) == 0 {
+			continue
+		}
+		customRulesData[scanType], err = customRulesToJSON(ofType)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	outputs := []cmpv1alpha1.TailoringOutput{}
+	cms := []*corev1.ConfigMap{}
+	for _, group := range groups {
+		cm, output, err := r.renderTailoringGroup(instance, group, sources)
+		if err != nil && !common.IsRetriable(err) {
+			return reconcile.Result{}, r.handleComposedProfileStatusError(instance, err)
+		} else if err != nil {
+			return reconcile.Result{}, err
+		}
+		if data, ok := customRulesData[group.scanType]; ok {
+			cm.Data[utils.CustomRulesFile] = data
+			delete(customRulesData, group.scanType)
+		}
+		cms = append(cms, cm)
+		outputs = append(outputs, *output)
+	}
+	for _, scanType := range scanTypes {
+		if _, ok := customRulesData[scanType]; ok {
+			err := common.NewNonRetriableCtrlError("TailoredProfile '%s' composes no rules of type %s, its custom rules of that type can't be checked", instance.Name, scanType)
+			return reconcile.Result{}, r.handleComposedProfileStatusError(instance, err)
+		}
+	}
+
+	keep := map[string]bool{}
+	for _, cm := range cms {
+		keep[cm.Name] = true
+		if err := r.ensureComposedOutputObject(instance, cm, logger); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+	if err := r.deleteComposedOutputObjects(instance, keep); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	tpCopy = instance.DeepCopy()
+	tpCopy.Status.State = cmpv1alpha1.TailoredProfileStateReady
+	tpCopy.Status.ErrorMessage = ""
+	tpCopy.Status.ID = ""
+	tpCopy.Status.OutputRef = cmpv1alpha1.OutputRef{}
+	tpCopy.Status.Outputs = outputs
+	return reconcile.Result{}, r.Client.Status().Update(context.TODO(), tpCopy)
+}
+
+// getComposedSources fetches the Profiles to compose and their ProfileBundles
+func (r *ReconcileTailoredProfile) getComposedSources(tp *cmpv1alpha1.TailoredProfile) ([]composedSource, error) {
+	sources := []composedSource{}
+	for _, name := range tp.Spec.Compose.Profiles {
+		p := &cmpv1alpha1.Profile{}
+		err := r.Client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: tp.Namespace}, p)
+		if kerrors.IsNotFound(err) {
+			return nil, common.NewNonRetriableCtrlError("fetching profile to be composed: %w", err)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		pb, err := r.getProfileBundleFrom("Profile", p)
+		if err != nil {
+			return nil, err
+		}
+
+		scanType := cmpv1alpha1.ScanTypePlatform
+		if strings.EqualFold(p.GetAnnotations()[cmpv1alpha1.ProductTypeAnnotation], string(cmpv1alpha1.ScanTypeNode)) {
+			scanType = cmpv1alpha1.ScanTypeNode
+		}
+		sources = append(sources, composedSource{profile: p, bundle: pb, scanType: scanType})
+	}
+	return sources, nil
+}
+
+// composeSelections combines the rules of the composed profiles with the
+// requested set operation and applies the selections of the TailoredProfile
+// on top. It returns the resulting rule names, in the order they were first
+// seen, and the rules that were selected for manual checking.
+func composeSelections(tp *cmpv1alpha1.TailoredProfile, sources []composedSource) ([]string, map[string]bool, error) {
+	profiles := make([]*cmpv1alpha1.Profile, 0, len(sources))
+	for _, src := range sources {
+		profiles = append(profiles, src.profile)
+	}
+	ruleNames := composeProfileRules(profiles, tp.Spec.Compose.Operation)
+
+	selected := map[string]bool{}
+	for _, name := range ruleNames {
+		selected[name] = true
+	}
+
+	seen := map[string]bool{}
+	for _, selection := range append(tp.Spec.EnableRules, append(tp.Spec.DisableRules, tp.Spec.ManualRules...)...) {
+		if seen[selection.Name] {
+			return nil, nil, common.NewNonRetriableCtrlError("Rule '%s' appears twice in selections (enableRules or disableRules or manualRules)", selection.Name)
+		}
+		seen[selection.Name] = true
+	}
+
+	manualRules := map[string]bool{}
+	for _, selection := range append(tp.Spec.EnableRules, tp.Spec.ManualRules...) {
+		if !selected[selection.Name] {
+			selected[selection.Name] = true
+			ruleNames = append(ruleNames, selection.Name)
+		}
+	}
+	for _, selection := range tp.Spec.ManualRules {
+		manualRules[selection.Name] = true
+	}
+	for _, selection := range tp.Spec.DisableRules {
+		delete(selected, selection.Name)
+	}
+
+	result := []string{}
+	for _, name := range ruleNames {
+		if selected[name] {
+			result = append(result, name)
+		}
+	}
+	return result, manualRules, nil
+}
+
+// composeProfileRules returns the union or the intersection of the rules of
+// the given profiles, in the order they were first seen
+func composeProfileRules(profiles []*cmpv1alpha1.Profile, op cmpv1alpha1.ProfileSetOperation) []string {
+	ruleCount := map[string]int{}
+	ruleOrder := []string{}
+	for _, p := range profiles {
+		inProfile := map[string]bool{}
+		for _, rule := range p.Rules {
+			name := string(rule)
+			if inProfile[name] {
+				continue
+			}
+			inProfile[name] = true
+			if ruleCount[name] == 0 {
+				ruleOrder = append(ruleOrder, name)
+			}
+			ruleCount[name]++
+		}
+	}
+
+	rules := []string{}
+	for _, name := range ruleOrder {
+		if op == cmpv1alpha1.ProfileSetOperationIntersection && ruleCount[name] != len(profiles) {
+			continue
+		}
+		rules = append(rules, name)
+	}
+	return rules
+}
+
+// groupComposedRules splits the composed rules per ProfileBundle and scan
+// type. Rules without a check type are merely informational, so they're
+// added to the platform group of their bundle or to its node group if the
+// bundle has no platform rules.
+func (r *ReconcileTailoredProfile) groupComposedRules(tp *cmpv1alpha1.TailoredProfile, ruleNames []string, manualRules map[string]bool) ([]*tailoringGroup, error) {
+	groups := []*tailoringGroup{}
+	findGroup := func(pb string, scanType cmpv1alpha1.ComplianceScanType) *tailoringGroup {
+		for _, g := range groups {
+			if g.bundle.Name == pb && (scanType == "" || g.scanType == scanType) {
+				return g
+			}
+		}
+		return nil
+	}
+	bundles := map[string]*cmpv1alpha1.ProfileBundle{}
+	getBundle := func(objtype string, o *cmpv1alpha1.Rule) (*cmpv1alpha1.ProfileBundle, error) {
+		ref, err := getProfileBundleReference(objtype, o)
+		if err != nil {
+			return nil, common.WrapNonRetriableCtrlError(err)
+		}
+		if pb, ok := bundles[ref.Name]; ok {
+			return pb, nil
+		}
+		pb, err := r.getProfileBundleFrom(objtype, o)
+		if err != nil {
+			return nil, err
+		}
+		bundles[ref.Name] = pb
+		return pb, nil
+	}
+
+	informational := []*cmpv1alpha1.Rule{}
+	for _, name := range ruleNames {
+		rule := &cmpv1alpha1.Rule{}
+		err := r.Client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: tp.Namespace}, rule)
+		if kerrors.IsNotFound(err) {
+			return nil, common.NewNonRetriableCtrlError("Fetching rule: %w", err)
+		} else if err != nil {
+			return nil, err
+		}
+
+		if rule.CheckType == cmpv1alpha1.CheckTypeNone {
+			informational = append(informational, rule)
+			continue
+		}
+
+		pb, err := getBundle("Rule", rule)
+		if err != nil {
+			return nil, err
+		}
+		scanType := cmpv1alpha1.ScanTypePlatform
+		if rule.CheckType == cmpv1alpha1.CheckTypeNode {
+			scanType = cmpv1alpha1.ScanTypeNode
+		}
+
+		group := findGroup(pb.Name, scanType)
+		if group == nil {
+			group = &tailoringGroup{bundle: pb, scanType: scanType, manualRules: map[string]bool{}}
+			groups = append(groups, group)
+		}
+		group.rules = append(group.rules, rule)
+		group.manualRules[rule.Name] = manualRules[rule.Name]
+	}
+
+	for _, rule := range informational {
+		pb, err := getBundle("Rule", rule)
+		if err != nil {
+			return nil, err
+		}
+		group := findGroup(pb.Name, cmpv1alpha1.ScanTypePlatform)
+		if group == nil {
+			group = findGroup(pb.Name, "")
+		}
+		if group == nil {
+			group = &tailoringGroup{bundle: pb, scanType: cmpv1alpha1.ScanTypePlatform, manualRules: map[string]bool{}}
+			groups = append(groups, group)
+		}
+		group.rules = append(group.rules, rule)
+		group.manualRules[rule.Name] = manualRules[rule.Name]
+	}
+
+	// Values are set in all the tailorings of the bundle they belong to
+	for _, setValue := range tp.Spec.SetValues {
+		variable := &cmpv1alpha1.Variable{}
+		err := r.Client.Get(context.TODO(), types.NamespacedName{Name: setValue.Name, Namespace: tp.Namespace}, variable)
+		if kerrors.IsNotFound(err) {
+			return nil, common.NewNonRetriableCtrlError("fetching variable: %w", err)
+		} else if err != nil {
+			return nil, err
+		}
+		ref, err := getProfileBundleReference("Variable", variable)
+		if err != nil {
+			return nil, common.WrapNonRetriableCtrlError(err)
+		}
+		found := false
+		for _, g := range groups {
+			if g.bundle.Name == ref.Name {
+				g.variables = append(g.variables, setValue)
+				found = true
+			}
+		}
+		if !found {
+			return nil, common.NewNonRetriableCtrlError("variable %s belongs to ProfileBundle %s, which has no composed rules",
+				variable.GetName(), ref.Name)
+		}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].bundle.Name != groups[j].bundle.Name {
+			return groups[i].bundle.Name < groups[j].bundle.Name
+		}
+		return groups[i].scanType > groups[j].scanType
+	})
+	return groups, nil
+}
+
+// renderTailoringGroup renders the tailoring of a group of composed rules.
+// The tailoring extends the first composed profile of the same bundle and
+// scan type, if any, so the values set by that profile are kept. The rules
+// of that profile that aren't part of the composition are deselected.
+func (r *ReconcileTailoredProfile) renderTailoringGroup(tp *cmpv1alpha1.TailoredProfile, group *tailoringGroup, sources []composedSource) (*corev1.ConfigMap, *cmpv1alpha1.TailoringOutput, error) {
+	var base *cmpv1alpha1.Profile
+	for _, src := range sources {
+		if src.bundle.Name == group.bundle.Name && src.scanType == group.scanType {
+			base = src.profile
+			break
+		}
+	}
+
+	view := tp.DeepCopy()
+	view.Name = group.outputName(tp)
+	view.Spec.Compose = nil
+	view.Spec.EnableRules = nil
+	view.Spec.DisableRules = nil
+	view.Spec.ManualRules = nil
+	view.Spec.SetValues = group.variables
+
+	inGroup := map[string]bool{}
+	for _, rule := range group.rules {
+		inGroup[rule.Name] = true
+		ref := cmpv1alpha1.RuleReferenceSpec{Name: rule.Name, Rationale: "Composed"}
+		if group.manualRules[rule.Name] {
+			view.Spec.ManualRules = append(view.Spec.ManualRules, ref)
+		} else {
+			view.Spec.EnableRules = append(view.Spec.EnableRules, ref)
+		}
+	}
+	if base != nil {
+		view.Spec.Extends = base.Name
+		for _, rule := range base.Rules {
+			if !inGroup[string(rule)] {
+				view.Spec.DisableRules = append(view.Spec.DisableRules, cmpv1alpha1.RuleReferenceSpec{
+					Name:      string(rule),
+					Rationale: "Not part of the composition",
+				})
+			}
+		}
+	}
+
+	rules, err := r.getRulesFromSelections(view, group.bundle)
+	if err != nil {
+		return nil, nil, err
+	}
+	variables, err := r.getVariablesFromSelections(view, group.bundle)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cm := newTailoredProfileCM(view)
+	cm.Labels["tailored-profile"] = tp.Name
+	cm.Data[tailoringFile], err = xccdf.TailoredProfileToXML(view, base, group.bundle, rules, variables)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	output := &cmpv1alpha1.TailoringOutput{
+		Name:          view.Name,
+		ID:            xccdf.GetXCCDFProfileID(view),
+		ProfileBundle: group.bundle.Name,
+		ScanType:      group.scanType,
+		Product:       getGroupProduct(group, sources),
+		OutputRef: cmpv1alpha1.OutputRef{
+			Name:      cm.Name,
+			Namespace: cm.Namespace,
+		},
+	}
+	return cm, output, nil
+}
+
+// getGroupProduct returns the product of the composed profiles of the bundle
+// of a node group, preferring the node profiles. Platform groups have no
+// product, the same as the platform profiles a ScanSettingBinding scans.
+func getGroupProduct(group *tailoringGroup, sources []composedSource) string {
+	if group.scanType != cmpv1alpha1.ScanTypeNode {
+		return ""
+	}
+	product := ""
+	for _, src := range sources {
+		if src.bundle.Name != group.bundle.Name {
+			continue
+		}
+		srcProduct := src.profile.GetAnnotations()[cmpv1alpha1.ProductAnnotation]
+		if src.scanType == cmpv1alpha1.ScanTypeNode && srcProduct != "" {
+			return srcProduct
+		}
+		if product == "" {
+			product = srcProduct
+		}
+	}
+	return product
+}
+
+func (r *ReconcileTailoredProfile) ensureComposedOutputObject(tp *cmpv1alpha1.TailoredProfile, tpcm *corev1.ConfigMap, logger logr.Logger) error {
+	if err := controllerutil.SetControllerReference(tp, tpcm, r.Scheme); err != nil {
+		return err
+	}
+
+	found := &corev1.ConfigMap{}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: tpcm.Name, Namespace: tpcm.Namespace}, found)
+	if kerrors.IsNotFound(err) {
+		logger.Info("Creating a new ConfigMap", "ConfigMap.Namespace", tpcm.Namespace, "ConfigMap.Name", tpcm.Name)
+		return r.Client.Create(context.TODO(), tpcm)
+	} else if err != nil {
+		return err
+	}
+
+	update := found.DeepCopy()
+	update.Data = tpcm.Data
+	return r.Client.Update(context.TODO(), update)
+}
+
+// deleteComposedOutputObjects removes the ConfigMaps generated for the
+// TailoredProfile that aren't in the keep set, e.g. because the composed
+// rules of a bundle were disabled
+func (r *ReconcileTailoredProfile) deleteComposedOutputObjects(tp *cmpv1alpha1.TailoredProfile, keep map[string]bool) error {
+	cmList := &corev1.ConfigMapList{}
+	err := r.Client.List(context.TODO(), cmList,
+		client.InNamespace(tp.Namespace), client.MatchingLabels{"tailored-profile": tp.Name})
+	if err != nil {
+		return err
+	}
+
+	for i := range cmList.Items {
+		cm := &cmList.Items[i]
+		if keep[cm.Name] || !isOwnedBy(cm, tp) {
+			continue
+		}
+		if err := r.Client.Delete(context.TODO(), cm); err != nil && !kerrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *ReconcileTailoredProfile) handleComposedProfileStatusError(tp *cmpv1alpha1.TailoredProfile, err error) error {
+	if delErr := r.deleteComposedOutputObjects(tp, nil); delErr != nil {
+		return delErr
+	}
+
+	tpCopy := tp.DeepCopy()
+	tpCopy.Status.Outputs = nil
+	return r.updateTailoredProfileStatusError(tpCopy, err)
+}
@@ -0,0 +1,63 @@
+package tailoredprofile
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	cmpv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+)
+
+// customRuleMapper enqueues the TailoredProfiles that reference the
+// CustomRule that changed, along with the ones extending them, so that
+// their tailorings pick up the new version of the rule.
+type customRuleMapper struct {
+	client.Client
+}
+
+func (s *customRuleMapper) Map(ctx context.Context, obj client.Object) []reconcile.Request {
+	var requests []reconcile.Request
+
+	tpList := cmpv1alpha1.TailoredProfileList{}
+	err := s.List(ctx, &tpList, client.InNamespace(obj.GetNamespace()))
+	if err != nil {
+		return requests
+	}
+
+	extendingMapper := &extendingTailoredProfileMapper{s.Client}
+	enqueued := map[string]bool{}
+	enqueue := func(req reconcile.Request) {
+		if enqueued[req.Name] {
+			return
+		}
+		enqueued[req.Name] = true
+		requests = append(requests, req)
+	}
+	for i := range tpList.Items {
+		tp := &tpList.Items[i]
+		if !referencesCustomRule(tp, obj.GetName()) {
+			continue
+		}
+
+		enqueue(reconcile.Request{NamespacedName: types.NamespacedName{
+			Name:      tp.GetName(),
+			Namespace: tp.GetNamespace(),
+		}})
+		for _, req := range extendingMapper.Map(ctx, tp) {
+			enqueue(req)
+		}
+	}
+
+	return requests
+}
+
+func referencesCustomRule(tp *cmpv1alpha1.TailoredProfile, name string) bool {
+	for _, ref := range tp.Spec.CustomRules {
+		if ref.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package tailoredprofile
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	cmpv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+)
+
+// extendingTailoredProfileMapper enqueues all the TailoredProfiles that
+// directly or transitively extend the TailoredProfile that changed, so
+// that their tailorings get re-rendered.
+type extendingTailoredProfileMapper struct {
+	client.Client
+}
+
+func (s *extendingTailoredProfileMapper) Map(ctx context.Context, obj client.Object) []reconcile.Request {
+	var requests []reconcile.Request
+
+	tpList := cmpv1alpha1.TailoredProfileList{}
+	err := s.List(ctx, &tpList, client.InNamespace(obj.GetNamespace()))
+	if err != nil {
+		return requests
+	}
+
+	children := make(map[string][]string)
+	for _, tp := range tpList.Items {
+		if tp.Spec.ExtendsTailoredProfile == "" {
+			continue
+		}
+		children[tp.Spec.ExtendsTailoredProfile] = append(children[tp.Spec.ExtendsTailoredProfile], tp.GetName())
+	}
+
+	// The visited set makes sure we terminate on inheritance cycles
+	visited := map[string]bool{obj.GetName(): true}
+	queue := []string{obj.GetName()}
+	for len(queue) > 0 {
+		parent := queue[0]
+		queue = queue[1:]
+		for _, child := range children[parent] {
+			if visited[child] {
+				continue
+			}
+			visited[child] = true
+			queue = append(queue, child)
+
+			objKey := types.NamespacedName{
+				Name:      child,
+				Namespace: obj.GetNamespace(),
+			}
+			requests = append(requests, reconcile.Request{NamespacedName: objKey})
+		}
+	}
+
+	return requests
+}
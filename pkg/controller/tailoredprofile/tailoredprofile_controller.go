@@ -2,6 +2,7 @@ package tailoredprofile
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -23,6 +24,7 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -53,10 +55,15 @@ func newReconciler(mgr manager.Manager, met *metrics.Metrics) reconcile.Reconcil
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
 func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	tpMapper := &extendingTailoredProfileMapper{mgr.GetClient()}
+	crMapper := &customRuleMapper{mgr.GetClient()}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		Named("tailoredprofile-controller").
 		For(&cmpv1alpha1.TailoredProfile{}).
 		Owns(&corev1.ConfigMap{}).
+		Watches(&cmpv1alpha1.TailoredProfile{}, handler.EnqueueRequestsFromMapFunc(tpMapper.Map)).
+		Watches(&cmpv1alpha1.CustomRule{}, handler.EnqueueRequestsFromMapFunc(crMapper.Map)).
 		Complete(r)
 }
 
@@ -95,12 +102,28 @@ func (r *ReconcileTailoredProfile) Reconcile(ctx context.Context, request reconc
 		return reconcile.Result{}, err
 	}
 
+	if instance.Spec.Compose != nil {
+		return r.reconcileComposedProfile(instance, reqLogger)
+	}
+
+	// Resolve the TailoredProfiles this one extends, if any, and merge
+	// them into a single TailoredProfile to work with.
+	chain, chainErr := r.getTailoredProfileChain(instance)
+	if chainErr != nil && !common.IsRetriable(chainErr) {
+		// Surface the error.
+		err = r.handleTailoredProfileStatusError(instance, chainErr)
+		return reconcile.Result{}, err
+	} else if chainErr != nil {
+		return reconcile.Result{}, chainErr
+	}
+	tp := xccdf.FlattenTailoredProfiles(chain)
+
 	var pb *cmpv1alpha1.ProfileBundle
 	var p *cmpv1alpha1.Profile
 
-	if instance.Spec.Extends != "" {
+	if tp.Spec.Extends != "" {
 		var pbgetErr error
-		p, pb, pbgetErr = r.getProfileInfoFromExtends(instance)
+		p, pb, pbgetErr = r.getProfileInfoFromExtends(tp)
 		if pbgetErr != nil && !common.IsRetriable(pbgetErr) {
 			// the Profile or ProfileBundle objects didn't exist. Surface the error.
 			err = r.handleTailoredProfileStatusError(instance, pbgetErr)
@@ -114,11 +137,17 @@ func (r *ReconcileTailoredProfile) Reconcile(ctx context.Context, request reconc
 		// This update will trigger a requeue with the new object.
 		if needsControllerRef(instance) {
 			tpCopy := instance.DeepCopy()
+			// A TailoredProfile extending another one has no extends
+			// attribute to get the product type from, so copy it
+			// from the Profile at the root of the chain.
+			if instance.Spec.ExtendsTailoredProfile != "" {
+				inheritProductType(tpCopy, p)
+			}
 			return r.setOwnership(tpCopy, p)
 		}
 	} else {
 		var pbgetErr error
-		pb, pbgetErr = r.getProfileBundleFromRulesOrVars(instance)
+		pb, pbgetErr = r.getProfileBundleFromRulesOrVars(tp)
 		if pbgetErr != nil && !common.IsRetriable(pbgetErr) {
 			// the Profile or ProfileBundle objects didn't exist. Surface the error.
 			err = r.handleTailoredProfileStatusError(instance, pbgetErr)
@@ -136,6 +165,10 @@ func (r *ReconcileTailoredProfile) Reconcile(ctx context.Context, request reconc
 			if anns == nil {
 				anns = make(map[string]string)
 			}
+			if len(chain) > 1 {
+				inheritProductType(tpCopy, chain[0])
+				anns = tpCopy.GetAnnotations()
+			}
 			// If the user already provided the product type, we
 			// don't need to set it
 			_, ok := anns[cmpv1alpha1.ProductTypeAnnotation]
@@ -152,7 +185,7 @@ func (r *ReconcileTailoredProfile) Reconcile(ctx context.Context, request reconc
 		}
 	}
 
-	rules, ruleErr := r.getRulesFromSelections(instance, pb)
+	rules, ruleErr := r.getRulesFromSelections(tp, pb)
 	if ruleErr != nil && !common.IsRetriable(ruleErr) {
 		// Surface the error.
 		suerr := r.handleTailoredProfileStatusError(instance, ruleErr)
@@ -167,7 +200,7 @@ func (r *ReconcileTailoredProfile) Reconcile(ctx context.Context, request reconc
 		return reconcile.Result{}, suerr
 	}
 
-	variables, varErr := r.getVariablesFromSelections(instance, pb)
+	variables, varErr := r.getVariablesFromSelections(tp, pb)
 	if varErr != nil && !common.IsRetriable(varErr) {
 		// Surface the error.
 		suerr := r.handleTailoredProfileStatusError(instance, varErr)
@@ -176,17 +209,82 @@ func (r *ReconcileTailoredProfile) Reconcile(ctx context.Context, request reconc
 		return reconcile.Result{}, varErr
 	}
 
+	customRules, customRuleErr := r.getCustomRules(tp)
+	if customRuleErr != nil && !common.IsRetriable(customRuleErr) {
+		// Surface the error.
+		suerr := r.handleTailoredProfileStatusError(instance, customRuleErr)
+		return reconcile.Result{}, suerr
+	} else if customRuleErr != nil {
+		return reconcile.Result{}, customRuleErr
+	}
+
+	checkType := cmpv1alpha1.CheckTypePlatform
+	if isNodeProfile(instance, rules) {
+		checkType = cmpv1alpha1.CheckTypeNode
+	}
+	if customRuleValidErr := assertValidCustomRuleTypes(customRules, checkType); customRuleValidErr != nil {
+		// Surface the error.
+		suerr := r.handleTailoredProfileStatusError(instance, customRuleValidErr)
+		return reconcile.Result{}, suerr
+	}
+
 	// Get tailored profile config map
 	tpcm := newTailoredProfileCM(instance)
 
-	tpcm.Data[tailoringFile], err = xccdf.TailoredProfileToXML(instance, p, pb, rules, variables)
+	tpcm.Data[tailoringFile], err = xccdf.TailoredProfileToXML(tp, p, pb, rules, variables)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
+	if len(customRules) > 0 {
+		tpcm.Data[utils.CustomRulesFile], err = customRulesToJSON(customRules)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
 
 	return r.ensureOutputObject(instance, tpcm, reqLogger)
 }
 
+// getTailoredProfileChain returns the TailoredProfiles extended by the given
+// one, ordered from the root of the chain to the given TailoredProfile itself.
+func (r *ReconcileTailoredProfile) getTailoredProfileChain(tp *cmpv1alpha1.TailoredProfile) ([]*cmpv1alpha1.TailoredProfile, error) {
+	chain := []*cmpv1alpha1.TailoredProfile{tp}
+	visited := map[string]bool{tp.Name: true}
+	path := []string{tp.Name}
+
+	cur := tp
+	for cur.Spec.ExtendsTailoredProfile != "" {
+		if cur.Spec.Extends != "" {
+			return nil, common.NewNonRetriableCtrlError("TailoredProfile '%s' can't set both extends and extendsTailoredProfile", cur.Name)
+		}
+
+		parentName := cur.Spec.ExtendsTailoredProfile
+		path = append(path, parentName)
+		if visited[parentName] {
+			return nil, common.NewNonRetriableCtrlError("TailoredProfile inheritance cycle detected: %s", strings.Join(path, " -> "))
+		}
+		visited[parentName] = true
+
+		parent := &cmpv1alpha1.TailoredProfile{}
+		err := r.Client.Get(context.TODO(), types.NamespacedName{Name: parentName, Namespace: tp.Namespace}, parent)
+		if kerrors.IsNotFound(err) {
+			return nil, common.NewNonRetriableCtrlError("fetching TailoredProfile to be extended: %w", err)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if parent.Spec.Compose != nil {
+			return nil, common.NewNonRetriableCtrlError("TailoredProfile '%s' composes several profiles and can't be extended", parent.Name)
+		}
+
+		chain = append([]*cmpv1alpha1.TailoredProfile{parent}, chain...)
+		cur = parent
+	}
+
+	return chain, nil
+}
+
 // getProfileInfoFromExtends gets the Profile and ProfileBundle where the rules come from
 // out of the profile that's being extended
 func (r *ReconcileTailoredProfile) getProfileInfoFromExtends(tp *cmpv1alpha1.TailoredProfile) (*cmpv1alpha1.Profile, *cmpv1alpha1.ProfileBundle, error) {
@@ -322,6 +420,30 @@ func (r *ReconcileTailoredProfile) getVariablesFromSelections(tp *cmpv1alpha1.Ta
 	return variableList, nil
 }
 
+// getCustomRules fetches the CustomRules the TailoredProfile references. A
+// CustomRule that doesn't exist or is invalid can't be checked, so it makes
+// the TailoredProfile fail.
+func (r *ReconcileTailoredProfile) getCustomRules(tp *cmpv1alpha1.TailoredProfile) ([]*cmpv1alpha1.CustomRule, error) {
+	customRules := make([]*cmpv1alpha1.CustomRule, 0, len(tp.Spec.CustomRules))
+	for _, ref := range tp.Spec.CustomRules {
+		rule := &cmpv1alpha1.CustomRule{}
+		ruleKey := types.NamespacedName{Name: ref.Name, Namespace: tp.Namespace}
+		err := r.Client.Get(context.TODO(), ruleKey, rule)
+		if err != nil {
+			if kerrors.IsNotFound(err) {
+				return nil, common.NewNonRetriableCtrlError("fetching custom rule: %w", err)
+			}
+			return nil, err
+		}
+
+		if err := utils.ValidateCustomRule(rule); err != nil {
+			return nil, common.NewNonRetriableCtrlError("custom rule %s is invalid: %s", rule.GetName(), err)
+		}
+		customRules = append(customRules, rule)
+	}
+	return customRules, nil
+}
+
 func (r *ReconcileTailoredProfile) updateTailoredProfileStatusReady(tp *cmpv1alpha1.TailoredProfile, out metav1.Object) error {
 	// Never update the original (update the copy)
 	tpCopy := tp.DeepCopy()
@@ -464,6 +586,25 @@ func newTailoredProfileCM(tp *cmpv1alpha1.TailoredProfile) *corev1.ConfigMap {
 	}
 }
 
+// inheritProductType copies the product type annotation from the object
+// a TailoredProfile extends, unless the TailoredProfile already has one
+func inheritProductType(tp *cmpv1alpha1.TailoredProfile, from metav1.Object) {
+	productType, ok := from.GetAnnotations()[cmpv1alpha1.ProductTypeAnnotation]
+	if !ok {
+		return
+	}
+
+	anns := tp.GetAnnotations()
+	if anns == nil {
+		anns = make(map[string]string)
+	}
+	if _, ok := anns[cmpv1alpha1.ProductTypeAnnotation]; ok {
+		return
+	}
+	anns[cmpv1alpha1.ProductTypeAnnotation] = productType
+	tp.SetAnnotations(anns)
+}
+
 func needsControllerRef(obj metav1.Object) bool {
 	refs := obj.GetOwnerReferences()
 	for _, ref := range refs {
@@ -486,6 +627,61 @@ func isOwnedBy(obj, owner metav1.Object) bool {
 	return false
 }
 
+// isNodeProfile returns whether the TailoredProfile is scanned on the nodes,
+// either because of its product type or because of the rules it selects
+func isNodeProfile(tp *cmpv1alpha1.TailoredProfile, rules map[string]*cmpv1alpha1.Rule) bool {
+	if strings.EqualFold(tp.GetAnnotations()[cmpv1alpha1.ProductTypeAnnotation], string(cmpv1alpha1.ScanTypeNode)) {
+		return true
+	}
+	for _, rule := range rules {
+		if rule.CheckType == cmpv1alpha1.CheckTypeNode {
+			return true
+		}
+	}
+	return false
+}
+
+// assertValidCustomRuleTypes checks that the CustomRules are checked by the
+// same type of scan as the TailoredProfile: the ones with an expression by a
+// platform scan, and the ones with a probe by a node scan
+func assertValidCustomRuleTypes(customRules []*cmpv1alpha1.CustomRule, expectedCheckType string) error {
+	for _, rule := range customRules {
+		if rule.CheckType() != expectedCheckType {
+			return common.NewNonRetriableCtrlError("Custom rule '%s' with type '%s' didn't match expected type: %s",
+				rule.GetName(), rule.CheckType(), expectedCheckType)
+		}
+	}
+	return nil
+}
+
+// customRulesOfType returns the CustomRules checked by the given type of scan
+func customRulesOfType(customRules []*cmpv1alpha1.CustomRule, checkType string) []*cmpv1alpha1.CustomRule {
+	var filtered []*cmpv1alpha1.CustomRule
+	for _, rule := range customRules {
+		if rule.CheckType() == checkType {
+			filtered = append(filtered, rule)
+		}
+	}
+	return filtered
+}
+
+// customRulesToJSON renders the CustomRules for the scan to check. Only
+// their names and specs are needed.
+func customRulesToJSON(customRules []*cmpv1alpha1.CustomRule) (string, error) {
+	out := make([]cmpv1alpha1.CustomRule, 0, len(customRules))
+	for _, rule := range customRules {
+		out = append(out, cmpv1alpha1.CustomRule{
+			ObjectMeta: metav1.ObjectMeta{Name: rule.GetName()},
+			Spec:       rule.Spec,
+		})
+	}
+	data, err := json.Marshal(out)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
 func assertValidRuleTypes(rules map[string]*cmpv1alpha1.Rule) error {
 	// Figure out
 	var expectedCheckType string
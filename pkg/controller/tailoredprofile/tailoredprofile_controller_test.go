@@ -2,12 +2,14 @@ package tailoredprofile
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 
 	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/metrics"
 	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/metrics/metricsfakes"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/utils"
 
 	"github.com/ComplianceAsCode/compliance-operator/pkg/apis"
 	. "github.com/onsi/ginkgo"
@@ -25,6 +27,37 @@ import (
 	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
 )
 
+func newCustomRule(name, namespace string) *compv1alpha1.CustomRule {
+	return &compv1alpha1.CustomRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: compv1alpha1.CustomRuleSpec{
+			Title: "OAuth access tokens expire within a day",
+			Inputs: []compv1alpha1.CustomRuleInput{
+				{Name: "oauth", Path: "/apis/config.openshift.io/v1/oauths/cluster"},
+			},
+			Expression: "oauth.spec.tokenConfig.accessTokenMaxAgeSeconds <= 86400",
+		},
+	}
+}
+
+func newProbeCustomRule(name, namespace string) *compv1alpha1.CustomRule {
+	return &compv1alpha1.CustomRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: compv1alpha1.CustomRuleSpec{
+			Title: "The kubelet service is enabled",
+			Probe: &compv1alpha1.CustomRuleProbe{
+				Script: `chroot "$HOST_ROOT" systemctl is-enabled kubelet`,
+			},
+		},
+	}
+}
+
 var _ = Describe("TailoredprofileController", func() {
 
 	var (
@@ -342,6 +375,506 @@ var _ = Describe("TailoredprofileController", func() {
 		})
 	})
 
+	When("extending another tailored profile", func() {
+		var (
+			baseName = "base-tailoring"
+			teamName = "team-tailoring"
+		)
+
+		reconcileTP := func(name string) {
+			tpReq := reconcile.Request{}
+			tpReq.Name = name
+			tpReq.Namespace = namespace
+			_, err := r.Reconcile(context.TODO(), tpReq)
+			Expect(err).To(BeNil())
+		}
+
+		getTP := func(name string) *compv1alpha1.TailoredProfile {
+			tp := &compv1alpha1.TailoredProfile{}
+			geterr := r.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, tp)
+			Expect(geterr).To(BeNil())
+			return tp
+		}
+
+		BeforeEach(func() {
+			base := &compv1alpha1.TailoredProfile{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      baseName,
+					Namespace: namespace,
+				},
+				Spec: compv1alpha1.TailoredProfileSpec{
+					Extends: profileName,
+					EnableRules: []compv1alpha1.RuleReferenceSpec{
+						{Name: "rule-3", Rationale: "Company-wide"},
+						{Name: "rule-4", Rationale: "Company-wide"},
+					},
+					SetValues: []compv1alpha1.VariableValueSpec{
+						{Name: "var-1", Value: "base", Rationale: "Company-wide"},
+					},
+				},
+			}
+			team := &compv1alpha1.TailoredProfile{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      teamName,
+					Namespace: namespace,
+				},
+				Spec: compv1alpha1.TailoredProfileSpec{
+					ExtendsTailoredProfile: baseName,
+					DisableRules: []compv1alpha1.RuleReferenceSpec{
+						{Name: "rule-4", Rationale: "Not for this team"},
+					},
+					SetValues: []compv1alpha1.VariableValueSpec{
+						{Name: "var-1", Value: "team", Rationale: "Team value"},
+					},
+				},
+			}
+
+			Expect(r.Client.Create(ctx, base)).To(BeNil())
+			Expect(r.Client.Create(ctx, team)).To(BeNil())
+		})
+
+		It("renders the flattened selections", func() {
+			By("Reconciling the first time")
+			reconcileTP(teamName)
+
+			By("Sets the Profile at the root of the chain as the owner")
+			tp := getTP(teamName)
+			ownerRefs := tp.GetOwnerReferences()
+			Expect(ownerRefs).To(HaveLen(1))
+			Expect(ownerRefs[0].Name).To(Equal(profileName))
+			Expect(ownerRefs[0].Kind).To(Equal("Profile"))
+
+			By("Reconciling a second time")
+			reconcileTP(teamName)
+
+			tp = getTP(teamName)
+			Expect(tp.Status.State).To(Equal(compv1alpha1.TailoredProfileStateReady))
+
+			cm := &corev1.ConfigMap{}
+			cmKey := types.NamespacedName{
+				Name:      tp.Status.OutputRef.Name,
+				Namespace: tp.Status.OutputRef.Namespace,
+			}
+			Expect(r.Client.Get(ctx, cmKey, cm)).To(BeNil())
+			data := cm.Data["tailoring.xml"]
+			Expect(data).To(ContainSubstring(`extends="profile_1"`))
+			Expect(data).To(ContainSubstring(`select idref="rule_3" selected="true"`))
+			Expect(data).To(ContainSubstring(`select idref="rule_4" selected="false"`))
+			Expect(data).ToNot(ContainSubstring(`select idref="rule_4" selected="true"`))
+			Expect(data).To(ContainSubstring(`<xccdf-1.2:set-value idref="var_1">team</xccdf-1.2:set-value>`))
+			Expect(data).ToNot(ContainSubstring(`>base<`))
+		})
+
+		It("fails on an inheritance cycle", func() {
+			base := getTP(baseName)
+			base.Spec.Extends = ""
+			base.Spec.ExtendsTailoredProfile = teamName
+			Expect(r.Client.Update(ctx, base)).To(BeNil())
+
+			reconcileTP(teamName)
+
+			tp := getTP(teamName)
+			Expect(tp.Status.State).To(Equal(compv1alpha1.TailoredProfileStateError))
+			Expect(tp.Status.ErrorMessage).To(ContainSubstring("cycle detected"))
+		})
+
+		It("fails if the extended tailored profile doesn't exist", func() {
+			team := getTP(teamName)
+			team.Spec.ExtendsTailoredProfile = "unexistent"
+			Expect(r.Client.Update(ctx, team)).To(BeNil())
+
+			reconcileTP(teamName)
+
+			tp := getTP(teamName)
+			Expect(tp.Status.State).To(Equal(compv1alpha1.TailoredProfileStateError))
+		})
+
+		It("enqueues the tailored profiles extending a changed one", func() {
+			mapper := &extendingTailoredProfileMapper{r.Client}
+			requests := mapper.Map(ctx, getTP(baseName))
+			Expect(requests).To(HaveLen(1))
+			Expect(requests[0].Name).To(Equal(teamName))
+		})
+	})
+
+	When("composing several profiles", func() {
+		var tpName = "composed"
+
+		reconcileTP := func() {
+			tpReq := reconcile.Request{}
+			tpReq.Name = tpName
+			tpReq.Namespace = namespace
+			_, err := r.Reconcile(context.TODO(), tpReq)
+			Expect(err).To(BeNil())
+		}
+
+		getTP := func() *compv1alpha1.TailoredProfile {
+			tp := &compv1alpha1.TailoredProfile{}
+			geterr := r.Client.Get(ctx, types.NamespacedName{Name: tpName, Namespace: namespace}, tp)
+			Expect(geterr).To(BeNil())
+			return tp
+		}
+
+		getTailoring := func(output compv1alpha1.TailoringOutput) string {
+			cm := &corev1.ConfigMap{}
+			cmKey := types.NamespacedName{Name: output.OutputRef.Name, Namespace: output.OutputRef.Namespace}
+			Expect(r.Client.Get(ctx, cmKey, cm)).To(BeNil())
+			return cm.Data["tailoring.xml"]
+		}
+
+		createComposedTP := func(op compv1alpha1.ProfileSetOperation, profiles ...string) {
+			tp := &compv1alpha1.TailoredProfile{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      tpName,
+					Namespace: namespace,
+				},
+				Spec: compv1alpha1.TailoredProfileSpec{
+					Title:       "Composed",
+					Description: "Composed",
+					Compose: &compv1alpha1.ProfileComposition{
+						Profiles:  profiles,
+						Operation: op,
+					},
+				},
+			}
+			Expect(r.Client.Create(ctx, tp)).To(BeNil())
+		}
+
+		BeforeEach(func() {
+			pb1 := &compv1alpha1.ProfileBundle{}
+			Expect(r.Client.Get(ctx, types.NamespacedName{Name: "pb-1", Namespace: namespace}, pb1)).To(BeNil())
+			pb2 := &compv1alpha1.ProfileBundle{}
+			Expect(r.Client.Get(ctx, types.NamespacedName{Name: "pb-2", Namespace: namespace}, pb2)).To(BeNil())
+
+			newProfile := func(name, id, productType string, pb *compv1alpha1.ProfileBundle, rules ...compv1alpha1.ProfileRule) {
+				p := &compv1alpha1.Profile{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        name,
+						Namespace:   namespace,
+						Annotations: map[string]string{compv1alpha1.ProductTypeAnnotation: productType},
+					},
+					ProfilePayload: compv1alpha1.ProfilePayload{
+						ID:    id,
+						Rules: rules,
+					},
+				}
+				Expect(controllerutil.SetControllerReference(pb, p, r.Scheme)).To(BeNil())
+				Expect(r.Client.Create(ctx, p)).To(BeNil())
+			}
+
+			newProfile("other-profile", "profile_other", "Platform", pb1, "rule-2", "rule-3")
+			newProfile("pb2-platform", "profile_pb2_platform", "Platform", pb2, "rule-5", "rule-6", "rule-7")
+			newProfile("pb2-node", "profile_pb2_node", "Node", pb2, "rule-8", "rule-9")
+			pb2Node := &compv1alpha1.Profile{}
+			Expect(r.Client.Get(ctx, types.NamespacedName{Name: "pb2-node", Namespace: namespace}, pb2Node)).To(BeNil())
+			pb2Node.Annotations[compv1alpha1.ProductAnnotation] = "rhcos4"
+			Expect(r.Client.Update(ctx, pb2Node)).To(BeNil())
+		})
+
+		It("splits the union of the rules per bundle and scan type", func() {
+			createComposedTP(compv1alpha1.ProfileSetOperationUnion, profileName, "pb2-platform", "pb2-node")
+			tp := getTP()
+			tp.Spec.DisableRules = []compv1alpha1.RuleReferenceSpec{{Name: "rule-6", Rationale: "Not needed"}}
+			tp.Spec.EnableRules = []compv1alpha1.RuleReferenceSpec{{Name: "rule-3", Rationale: "Needed"}}
+			tp.Spec.SetValues = []compv1alpha1.VariableValueSpec{{Name: "var-5", Value: "1234", Rationale: "Needed"}}
+			Expect(r.Client.Update(ctx, tp)).To(BeNil())
+
+			By("Reconciling the first time")
+			reconcileTP()
+
+			By("Sets all the composed bundles as owners")
+			tp = getTP()
+			ownerRefs := tp.GetOwnerReferences()
+			Expect(ownerRefs).To(HaveLen(2))
+			Expect(ownerRefs[0].Name).To(Equal("pb-1"))
+			Expect(ownerRefs[1].Name).To(Equal("pb-2"))
+
+			By("Reconciling a second time")
+			reconcileTP()
+
+			tp = getTP()
+			Expect(tp.Status.State).To(Equal(compv1alpha1.TailoredProfileStateReady))
+			Expect(tp.Status.Outputs).To(HaveLen(3))
+
+			pb1Platform := tp.Status.Outputs[0]
+			Expect(pb1Platform.Name).To(Equal("composed-pb-1-platform"))
+			Expect(pb1Platform.ProfileBundle).To(Equal("pb-1"))
+			Expect(pb1Platform.ScanType).To(Equal(compv1alpha1.ScanTypePlatform))
+			Expect(pb1Platform.ID).To(Equal("xccdf_compliance.openshift.io_profile_composed-pb-1-platform"))
+			data := getTailoring(pb1Platform)
+			Expect(data).To(ContainSubstring(`extends="profile_1"`))
+			Expect(data).To(ContainSubstring(`select idref="rule_1" selected="true"`))
+			Expect(data).To(ContainSubstring(`select idref="rule_3" selected="true"`))
+
+			pb2Platform := tp.Status.Outputs[1]
+			Expect(pb2Platform.Name).To(Equal("composed-pb-2-platform"))
+			Expect(pb2Platform.ScanType).To(Equal(compv1alpha1.ScanTypePlatform))
+			data = getTailoring(pb2Platform)
+			Expect(data).To(ContainSubstring(`extends="profile_pb2_platform"`))
+			Expect(data).To(ContainSubstring(`select idref="rule_5" selected="true"`))
+			Expect(data).To(ContainSubstring(`select idref="rule_6" selected="false"`))
+			Expect(data).To(ContainSubstring(`select idref="rule_7" selected="true"`))
+			Expect(data).To(ContainSubstring(`<xccdf-1.2:set-value idref="var_5">1234</xccdf-1.2:set-value>`))
+
+			pb2Node := tp.Status.Outputs[2]
+			Expect(pb2Node.Name).To(Equal("composed-pb-2-node"))
+			Expect(pb2Node.ScanType).To(Equal(compv1alpha1.ScanTypeNode))
+			Expect(pb2Node.Product).To(Equal("rhcos4"))
+			Expect(pb2Platform.Product).To(BeEmpty())
+			data = getTailoring(pb2Node)
+			Expect(data).To(ContainSubstring(`extends="profile_pb2_node"`))
+			Expect(data).To(ContainSubstring(`select idref="rule_8" selected="true"`))
+			Expect(data).To(ContainSubstring(`<xccdf-1.2:set-value idref="var_5">1234</xccdf-1.2:set-value>`))
+
+			By("Removing the stale tailorings when a bundle is no longer composed")
+			tp.Spec.Compose.Profiles = []string{profileName}
+			tp.Spec.DisableRules = nil
+			tp.Spec.SetValues = nil
+			Expect(r.Client.Update(ctx, tp)).To(BeNil())
+			reconcileTP()
+
+			tp = getTP()
+			Expect(tp.Status.Outputs).To(HaveLen(1))
+			cmList := &corev1.ConfigMapList{}
+			Expect(r.Client.List(ctx, cmList)).To(BeNil())
+			Expect(cmList.Items).To(HaveLen(1))
+			Expect(cmList.Items[0].Name).To(Equal("composed-pb-1-platform-tp"))
+		})
+
+		It("keeps only the common rules on an intersection", func() {
+			createComposedTP(compv1alpha1.ProfileSetOperationIntersection, profileName, "other-profile")
+			reconcileTP()
+			reconcileTP()
+
+			tp := getTP()
+			Expect(tp.Status.State).To(Equal(compv1alpha1.TailoredProfileStateReady))
+			Expect(tp.Status.Outputs).To(HaveLen(1))
+			data := getTailoring(tp.Status.Outputs[0])
+			Expect(data).To(ContainSubstring(`select idref="rule_2" selected="true"`))
+			Expect(data).To(ContainSubstring(`select idref="rule_1" selected="false"`))
+			Expect(data).ToNot(ContainSubstring(`rule_3`))
+		})
+
+		It("fails if a composed profile doesn't exist", func() {
+			createComposedTP(compv1alpha1.ProfileSetOperationUnion, profileName, "unexistent")
+			reconcileTP()
+
+			tp := getTP()
+			Expect(tp.Status.State).To(Equal(compv1alpha1.TailoredProfileStateError))
+			Expect(tp.Status.ErrorMessage).To(ContainSubstring("fetching profile to be composed"))
+		})
+
+		It("fails if it also extends a profile", func() {
+			createComposedTP(compv1alpha1.ProfileSetOperationUnion, profileName)
+			tp := getTP()
+			tp.Spec.Extends = profileName
+			Expect(r.Client.Update(ctx, tp)).To(BeNil())
+			reconcileTP()
+
+			tp = getTP()
+			Expect(tp.Status.State).To(Equal(compv1alpha1.TailoredProfileStateError))
+		})
+		It("checks the custom rules in the first tailoring of their type only", func() {
+			Expect(r.Client.Create(ctx, newCustomRule("oauth-token-max-age", namespace))).To(BeNil())
+			Expect(r.Client.Create(ctx, newProbeCustomRule("kubelet-enabled", namespace))).To(BeNil())
+			createComposedTP(compv1alpha1.ProfileSetOperationUnion, "pb2-node", "pb2-platform")
+			tp := getTP()
+			tp.Spec.CustomRules = []compv1alpha1.RuleReferenceSpec{
+				{Name: "oauth-token-max-age", Rationale: "Needed"},
+				{Name: "kubelet-enabled", Rationale: "Needed"},
+			}
+			Expect(r.Client.Update(ctx, tp)).To(BeNil())
+			reconcileTP()
+			reconcileTP()
+
+			tp = getTP()
+			Expect(tp.Status.State).To(Equal(compv1alpha1.TailoredProfileStateReady))
+			Expect(tp.Status.Outputs).To(HaveLen(2))
+			for _, output := range tp.Status.Outputs {
+				cm := &corev1.ConfigMap{}
+				cmKey := types.NamespacedName{Name: output.OutputRef.Name, Namespace: output.OutputRef.Namespace}
+				Expect(r.Client.Get(ctx, cmKey, cm)).To(BeNil())
+				if output.ScanType == compv1alpha1.ScanTypePlatform {
+					Expect(cm.Data[utils.CustomRulesFile]).To(ContainSubstring(`"name":"oauth-token-max-age"`))
+					Expect(cm.Data[utils.CustomRulesFile]).ToNot(ContainSubstring(`"name":"kubelet-enabled"`))
+				} else {
+					Expect(cm.Data[utils.CustomRulesFile]).To(ContainSubstring(`"name":"kubelet-enabled"`))
+					Expect(cm.Data[utils.CustomRulesFile]).ToNot(ContainSubstring(`"name":"oauth-token-max-age"`))
+				}
+			}
+		})
+
+		It("fails to check custom rules without platform rules", func() {
+			Expect(r.Client.Create(ctx, newCustomRule("oauth-token-max-age", namespace))).To(BeNil())
+			createComposedTP(compv1alpha1.ProfileSetOperationUnion, "pb2-node")
+			tp := getTP()
+			tp.Spec.CustomRules = []compv1alpha1.RuleReferenceSpec{{Name: "oauth-token-max-age", Rationale: "Needed"}}
+			Expect(r.Client.Update(ctx, tp)).To(BeNil())
+			reconcileTP()
+			reconcileTP()
+
+			tp = getTP()
+			Expect(tp.Status.State).To(Equal(compv1alpha1.TailoredProfileStateError))
+			Expect(tp.Status.ErrorMessage).To(ContainSubstring("composes no rules of type Platform"))
+		})
+	})
+
+	When("referencing custom rules", func() {
+		var (
+			tpName = "custom"
+			tpKey  = types.NamespacedName{Name: tpName, Namespace: namespace}
+		)
+
+		reconcileTP := func() *compv1alpha1.TailoredProfile {
+			tpReq := reconcile.Request{NamespacedName: tpKey}
+			_, err := r.Reconcile(context.TODO(), tpReq)
+			Expect(err).To(BeNil())
+
+			tp := &compv1alpha1.TailoredProfile{}
+			Expect(r.Client.Get(ctx, tpKey, tp)).To(BeNil())
+			return tp
+		}
+
+		createTP := func(ruleName string, customRules ...string) {
+			tp := &compv1alpha1.TailoredProfile{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      tpName,
+					Namespace: namespace,
+				},
+				Spec: compv1alpha1.TailoredProfileSpec{
+					EnableRules: []compv1alpha1.RuleReferenceSpec{
+						{
+							Name:      ruleName,
+							Rationale: "Needed",
+						},
+					},
+				},
+			}
+			for _, name := range customRules {
+				tp.Spec.CustomRules = append(tp.Spec.CustomRules, compv1alpha1.RuleReferenceSpec{
+					Name:      name,
+					Rationale: "Needed",
+				})
+			}
+			Expect(r.Client.Create(ctx, tp)).To(BeNil())
+		}
+
+		BeforeEach(func() {
+			Expect(r.Client.Create(ctx, newCustomRule("oauth-token-max-age", namespace))).To(BeNil())
+		})
+
+		It("stores the custom rules next to the tailoring", func() {
+			createTP("rule-5", "oauth-token-max-age")
+			reconcileTP()
+			tp := reconcileTP()
+			Expect(tp.Status.State).To(Equal(compv1alpha1.TailoredProfileStateReady))
+
+			cm := &corev1.ConfigMap{}
+			cmKey := types.NamespacedName{Name: tp.Status.OutputRef.Name, Namespace: tp.Status.OutputRef.Namespace}
+			Expect(r.Client.Get(ctx, cmKey, cm)).To(BeNil())
+			Expect(cm.Data["tailoring.xml"]).To(ContainSubstring(`select idref="rule_5" selected="true"`))
+
+			var customRules []compv1alpha1.CustomRule
+			Expect(json.Unmarshal([]byte(cm.Data[utils.CustomRulesFile]), &customRules)).To(BeNil())
+			Expect(customRules).To(HaveLen(1))
+			Expect(customRules[0].Name).To(Equal("oauth-token-max-age"))
+			Expect(customRules[0].Spec.Expression).To(Equal("oauth.spec.tokenConfig.accessTokenMaxAgeSeconds <= 86400"))
+		})
+
+		It("doesn't store custom rules when there are none", func() {
+			createTP("rule-5")
+			reconcileTP()
+			tp := reconcileTP()
+			Expect(tp.Status.State).To(Equal(compv1alpha1.TailoredProfileStateReady))
+
+			cm := &corev1.ConfigMap{}
+			cmKey := types.NamespacedName{Name: tp.Status.OutputRef.Name, Namespace: tp.Status.OutputRef.Namespace}
+			Expect(r.Client.Get(ctx, cmKey, cm)).To(BeNil())
+			Expect(cm.Data).ToNot(HaveKey(utils.CustomRulesFile))
+		})
+
+		It("fails if a custom rule doesn't exist", func() {
+			createTP("rule-5", "unexistent")
+			reconcileTP()
+			tp := reconcileTP()
+			Expect(tp.Status.State).To(Equal(compv1alpha1.TailoredProfileStateError))
+			Expect(tp.Status.ErrorMessage).To(ContainSubstring("fetching custom rule"))
+		})
+
+		It("fails if the expression of a custom rule doesn't compile", func() {
+			broken := newCustomRule("broken", namespace)
+			broken.Spec.Expression = "oauth.spec.tokenConfig.accessTokenMaxAgeSeconds <="
+			Expect(r.Client.Create(ctx, broken)).To(BeNil())
+
+			createTP("rule-5", "broken")
+			reconcileTP()
+			tp := reconcileTP()
+			Expect(tp.Status.State).To(Equal(compv1alpha1.TailoredProfileStateError))
+			Expect(tp.Status.ErrorMessage).To(ContainSubstring("custom rule broken is invalid"))
+		})
+
+		It("fails on a custom rule with an expression in a node profile", func() {
+			createTP("rule-8", "oauth-token-max-age")
+			reconcileTP()
+			tp := reconcileTP()
+			Expect(tp.Status.State).To(Equal(compv1alpha1.TailoredProfileStateError))
+			Expect(tp.Status.ErrorMessage).To(ContainSubstring("didn't match expected type"))
+		})
+
+		It("stores the custom rules with a probe next to a node tailoring", func() {
+			Expect(r.Client.Create(ctx, newProbeCustomRule("kubelet-enabled", namespace))).To(BeNil())
+			createTP("rule-8", "kubelet-enabled")
+			reconcileTP()
+			tp := reconcileTP()
+			Expect(tp.Status.State).To(Equal(compv1alpha1.TailoredProfileStateReady))
+
+			cm := &corev1.ConfigMap{}
+			cmKey := types.NamespacedName{Name: tp.Status.OutputRef.Name, Namespace: tp.Status.OutputRef.Namespace}
+			Expect(r.Client.Get(ctx, cmKey, cm)).To(BeNil())
+
+			var customRules []compv1alpha1.CustomRule
+			Expect(json.Unmarshal([]byte(cm.Data[utils.CustomRulesFile]), &customRules)).To(BeNil())
+			Expect(customRules).To(HaveLen(1))
+			Expect(customRules[0].Spec.Probe).ToNot(BeNil())
+			Expect(customRules[0].Spec.Probe.Script).To(Equal(`chroot "$HOST_ROOT" systemctl is-enabled kubelet`))
+		})
+
+		It("fails on a custom rule with a probe in a platform profile", func() {
+			Expect(r.Client.Create(ctx, newProbeCustomRule("kubelet-enabled", namespace))).To(BeNil())
+			createTP("rule-5", "kubelet-enabled")
+			reconcileTP()
+			tp := reconcileTP()
+			Expect(tp.Status.State).To(Equal(compv1alpha1.TailoredProfileStateError))
+			Expect(tp.Status.ErrorMessage).To(ContainSubstring("didn't match expected type"))
+		})
+
+		It("enqueues the tailored profiles referencing a changed custom rule", func() {
+			createTP("rule-5", "oauth-token-max-age")
+			other := &compv1alpha1.TailoredProfile{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "unrelated",
+					Namespace: namespace,
+				},
+				Spec: compv1alpha1.TailoredProfileSpec{Extends: profileName},
+			}
+			Expect(r.Client.Create(ctx, other)).To(BeNil())
+			extending := &compv1alpha1.TailoredProfile{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "extending",
+					Namespace: namespace,
+				},
+				Spec: compv1alpha1.TailoredProfileSpec{ExtendsTailoredProfile: tpName},
+			}
+			Expect(r.Client.Create(ctx, extending)).To(BeNil())
+
+			mapper := &customRuleMapper{r.Client}
+			requests := mapper.Map(ctx, newCustomRule("oauth-token-max-age", namespace))
+			Expect(requests).To(HaveLen(2))
+			Expect(requests[0].Name).To(Equal(tpName))
+			Expect(requests[1].Name).To(Equal("extending"))
+		})
+	})
+
 	When("extending a profile with reference to another bundle", func() {
 		var tpName = "tailoring"
 		Context("with a rule from another bundle", func() {
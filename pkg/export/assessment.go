@@ -0,0 +1,140 @@
+package export
+
+import (
+	"context"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cmpv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+)
+
+// GetAssessment reads the scans of the suite, their results and
+// remediations, the exceptions of the results, the profiles they checked and
+// the rules of the namespace of the suite
+func GetAssessment(ctx context.Context, c client.Reader, suite *cmpv1alpha1.ComplianceSuite) (*Assessment, error) {
+	scanList := &cmpv1alpha1.ComplianceScanList{}
+	err := c.List(ctx, scanList, client.InNamespace(suite.Namespace), client.MatchingLabels{cmpv1alpha1.SuiteLabel: suite.Name})
+	if err != nil {
+		return nil, err
+	}
+	a, err := getAssessment(ctx, c, suite.Namespace, scanList.Items, cmpv1alpha1.SuiteLabel, suite.Name)
+	if err != nil {
+		return nil, err
+	}
+	a.Suite = suite
+	return a, nil
+}
+
+// GetScanAssessment reads the same as GetAssessment, but only for the scan.
+// The suite of the assessment is unset.
+func GetScanAssessment(ctx context.Context, c client.Reader, scan *cmpv1alpha1.ComplianceScan) (*Assessment, error) {
+	return getAssessment(ctx, c, scan.Namespace, []cmpv1alpha1.ComplianceScan{*scan}, cmpv1alpha1.ComplianceScanLabel, scan.Name)
+}
+
+// getAssessment reads the results and the remediations with the label, and
+// what they refer to
+func getAssessment(ctx context.Context, c client.Reader, namespace string, scans []cmpv1alpha1.ComplianceScan, label, value string) (*Assessment, error) {
+	withLabel := []client.ListOption{
+		client.InNamespace(namespace),
+		client.MatchingLabels{label: value},
+	}
+
+	checkList := &cmpv1alpha1.ComplianceCheckResultList{}
+	if err := c.List(ctx, checkList, withLabel...); err != nil {
+		return nil, err
+	}
+	remediationList := &cmpv1alpha1.ComplianceRemediationList{}
+	if err := c.List(ctx, remediationList, withLabel...); err != nil {
+		return nil, err
+	}
+	ruleList := &cmpv1alpha1.RuleList{}
+	if err := c.List(ctx, ruleList, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	profiles, err := getScannedProfiles(ctx, c, namespace, scans)
+	if err != nil {
+		return nil, err
+	}
+	exceptions, err := getCheckExceptions(ctx, c, checkList.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Assessment{
+		Scans:        scans,
+		Profiles:     profiles,
+		Rules:        ruleList.Items,
+		Checks:       checkList.Items,
+		Remediations: remediationList.Items,
+		Exceptions:   exceptions,
+	}, nil
+}
+
+// getCheckExceptions returns the exceptions the checks are excepted by. An
+// exception might be in another namespace than the checks, the exceptions
+// that can't be read are left out.
+func getCheckExceptions(ctx context.Context, c client.Reader, checks []cmpv1alpha1.ComplianceCheckResult) ([]cmpv1alpha1.ComplianceException, error) {
+	var exceptions []cmpv1alpha1.ComplianceException
+	seen := make(map[string]bool)
+	for i := range checks {
+		ref, ok := checks[i].Annotations[cmpv1alpha1.ComplianceCheckResultExceptionAnnotation]
+		if !ok || seen[ref] {
+			continue
+		}
+		seen[ref] = true
+		namespace, name, ok := strings.Cut(ref, "/")
+		if !ok {
+			continue
+		}
+		exception := cmpv1alpha1.ComplianceException{}
+		err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &exception)
+		if errors.IsNotFound(err) || errors.IsForbidden(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		exceptions = append(exceptions, exception)
+	}
+	return exceptions, nil
+}
+
+// getScannedProfiles returns the profiles the scans checked. The ID of a
+// profile is only unique within its bundle, so the bundle must ship the
+// content of the scan.
+func getScannedProfiles(ctx context.Context, c client.Reader, namespace string, scans []cmpv1alpha1.ComplianceScan) ([]cmpv1alpha1.Profile, error) {
+	bundleList := &cmpv1alpha1.ProfileBundleList{}
+	if err := c.List(ctx, bundleList, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	profileList := &cmpv1alpha1.ProfileList{}
+	if err := c.List(ctx, profileList, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+
+	var profiles []cmpv1alpha1.Profile
+	for i := range profileList.Items {
+		profile := &profileList.Items[i]
+		bundleName := profile.Labels[cmpv1alpha1.ProfileBundleOwnerLabel]
+		for j := range scans {
+			scan := &scans[j]
+			if scan.Spec.Profile == profile.ID && bundleShipsContent(bundleList.Items, bundleName, scan) {
+				profiles = append(profiles, *profile)
+				break
+			}
+		}
+	}
+	return profiles, nil
+}
+
+func bundleShipsContent(bundles []cmpv1alpha1.ProfileBundle, name string, scan *cmpv1alpha1.ComplianceScan) bool {
+	for i := range bundles {
+		bundle := &bundles[i]
+		if bundle.Name == name {
+			return bundle.Spec.ContentFile == scan.Spec.Content && bundle.Spec.ContentImage == scan.Spec.ContentImage
+		}
+	}
+	return false
+}
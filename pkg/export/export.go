@@ -0,0 +1,153 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cmpv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/utils"
+)
+
+// The name of the tool the results are exported from
+const toolName = "compliance-operator"
+
+// Results are the check results exported together, typically those of a
+// scan
+type Results struct {
+	// The name the results are known by, e.g. the name of the scan
+	Name string
+	// The results of the checks
+	Checks []cmpv1alpha1.ComplianceCheckResult
+	// The controls each rule maps to, by standard. The rules are keyed by
+	// their DNS-friendly name, as in the rule annotation of the checks.
+	Controls map[string]map[string][]string
+}
+
+// GetScanResults reads the ComplianceCheckResults of the scan, along with
+// the controls of their rules
+func GetScanResults(ctx context.Context, c client.Reader, scanName, namespace string) (*Results, error) {
+	checkList := &cmpv1alpha1.ComplianceCheckResultList{}
+	if err := c.List(ctx, checkList, client.InNamespace(namespace),
+		client.MatchingLabels{cmpv1alpha1.ComplianceScanLabel: scanName}); err != nil {
+		return nil, err
+	}
+	ruleList := &cmpv1alpha1.RuleList{}
+	if err := c.List(ctx, ruleList, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	return &Results{
+		Name:     scanName,
+		Checks:   checkList.Items,
+		Controls: utils.GetRuleControls(ruleList.Items),
+	}, nil
+}
+
+// Render writes the results in the format
+func Render(format cmpv1alpha1.ExportFormat, results *Results, w io.Writer) error {
+	switch format {
+	case cmpv1alpha1.ExportFormatSARIF:
+		return renderSARIF(results, w)
+	case cmpv1alpha1.ExportFormatJUnit:
+		return renderJUnit(results, w)
+	default:
+		return fmt.Errorf("unknown export format %s", format)
+	}
+}
+
+// FileName returns the name of the file the results are written to in the
+// format
+func FileName(format cmpv1alpha1.ExportFormat) string {
+	switch format {
+	case cmpv1alpha1.ExportFormatSARIF:
+		return "results.sarif"
+	case cmpv1alpha1.ExportFormatJUnit:
+		return "results.junit.xml"
+	default:
+		return "results." + string(format)
+	}
+}
+
+// ParseFormat returns the export format with the given name
+func ParseFormat(name string) (cmpv1alpha1.ExportFormat, error) {
+	format := cmpv1alpha1.ExportFormat(strings.ToLower(name))
+	switch format {
+	case cmpv1alpha1.ExportFormatSARIF, cmpv1alpha1.ExportFormatJUnit:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unknown export format %s, expected %s or %s",
+			name, cmpv1alpha1.ExportFormatSARIF, cmpv1alpha1.ExportFormatJUnit)
+	}
+}
+
+// sortedChecks returns the checks ordered by ID and name, so the exports of
+// the same results are the same
+func (r *Results) sortedChecks() []*cmpv1alpha1.ComplianceCheckResult {
+	checks := make([]*cmpv1alpha1.ComplianceCheckResult, 0, len(r.Checks))
+	for i := range r.Checks {
+		checks = append(checks, &r.Checks[i])
+	}
+	sort.SliceStable(checks, func(i, j int) bool {
+		if checks[i].ID != checks[j].ID {
+			return checks[i].ID < checks[j].ID
+		}
+		return checks[i].Name < checks[j].Name
+	})
+	return checks
+}
+
+// controlsOf returns the controls of the rule of the check, by standard
+func (r *Results) controlsOf(check *cmpv1alpha1.ComplianceCheckResult) map[string][]string {
+	return r.Controls[ruleName(check)]
+}
+
+// controlTags returns the controls as standard:control tags, sorted
+func controlTags(controls map[string][]string) []string {
+	var tags []string
+	for standard, ids := range controls {
+		for _, id := range ids {
+			tags = append(tags, standard+":"+id)
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// ruleName returns the DNS-friendly name of the rule of the check
+func ruleName(check *cmpv1alpha1.ComplianceCheckResult) string {
+	if name, ok := check.Annotations[cmpv1alpha1.ComplianceCheckResultRuleAnnotation]; ok {
+		return name
+	}
+	return utils.IDToDNSFriendlyName(check.ID)
+}
+
+// title returns the title of the check, the first line of its description
+func title(check *cmpv1alpha1.ComplianceCheckResult) string {
+	title, _, _ := strings.Cut(strings.TrimSpace(check.Description), "\n")
+	if title == "" {
+		return check.ID
+	}
+	return title
+}
+
+// scanName returns the name of the scan of the check, or the name of the
+// results if the check doesn't tell
+func (r *Results) scanName(check *cmpv1alpha1.ComplianceCheckResult) string {
+	if name, ok := check.Labels[cmpv1alpha1.ComplianceScanLabel]; ok {
+		return name
+	}
+	return r.Name
+}
+
+// statusText returns the status of a check as text, as a check might have
+// no result
+func statusText(status cmpv1alpha1.ComplianceCheckStatus) string {
+	if status == cmpv1alpha1.CheckResultNoResult {
+		return "NO-RESULT"
+	}
+	return string(status)
+}
@@ -0,0 +1,201 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	cmpv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+)
+
+func newCheck(name, id string, status cmpv1alpha1.ComplianceCheckStatus, severity cmpv1alpha1.ComplianceCheckResultSeverity) cmpv1alpha1.ComplianceCheckResult {
+	return cmpv1alpha1.ComplianceCheckResult{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "openshift-compliance",
+			Labels:    map[string]string{cmpv1alpha1.ComplianceScanLabel: "ocp4-cis"},
+		},
+		ID:           id,
+		Status:       status,
+		Severity:     severity,
+		Description:  "Title of " + id + "\nDescription of " + id,
+		Rationale:    "Rationale of " + id,
+		Instructions: "Instructions of " + id,
+	}
+}
+
+var _ = Describe("Exporting results", func() {
+	var results *Results
+
+	BeforeEach(func() {
+		fail := newCheck("ocp4-cis-audit-log-forwarding", "xccdf_org.ssgproject.content_rule_audit_log_forwarding",
+			cmpv1alpha1.CheckResultFail, cmpv1alpha1.CheckResultSeverityHigh)
+		fail.Warnings = []string{"No forwarding is configured"}
+		results = &Results{
+			Name: "ocp4-cis",
+			Checks: []cmpv1alpha1.ComplianceCheckResult{
+				newCheck("ocp4-cis-scc-limit", "xccdf_org.ssgproject.content_rule_scc_limit",
+					cmpv1alpha1.CheckResultPass, cmpv1alpha1.CheckResultSeverityMedium),
+				fail,
+				newCheck("ocp4-cis-etcd-encryption", "xccdf_org.ssgproject.content_rule_etcd_encryption",
+					cmpv1alpha1.CheckResultError, cmpv1alpha1.CheckResultSeverityMedium),
+				newCheck("ocp4-cis-idp-is-configured", "xccdf_org.ssgproject.content_rule_idp_is_configured",
+					cmpv1alpha1.CheckResultManual, cmpv1alpha1.CheckResultSeverityLow),
+				newCheck("ocp4-cis-api-server-tls", "xccdf_org.ssgproject.content_rule_api_server_tls",
+					cmpv1alpha1.CheckResultExcepted, cmpv1alpha1.CheckResultSeverityMedium),
+			},
+			Controls: map[string]map[string][]string{
+				"audit-log-forwarding": {
+					"NIST-800-53": {"AU-9(2)", "AU-4(1)"},
+					"CIS-OCP":     {"1.2.22"},
+				},
+			},
+		}
+	})
+
+	Context("to SARIF", func() {
+		var log sarifLog
+
+		BeforeEach(func() {
+			var buf bytes.Buffer
+			Expect(Render(cmpv1alpha1.ExportFormatSARIF, results, &buf)).To(Succeed())
+			Expect(json.Unmarshal(buf.Bytes(), &log)).To(Succeed())
+		})
+
+		It("describes each rule once with its severity and controls", func() {
+			Expect(log.Version).To(Equal("2.1.0"))
+			Expect(log.Runs).To(HaveLen(1))
+			run := log.Runs[0]
+			Expect(run.Tool.Driver.Name).To(Equal("compliance-operator"))
+			Expect(run.AutomationDetails.ID).To(Equal("ocp4-cis/"))
+			Expect(run.Tool.Driver.Rules).To(HaveLen(5))
+
+			rule := run.Tool.Driver.Rules[run.Results[1].RuleIndex]
+			Expect(rule.ID).To(Equal("xccdf_org.ssgproject.content_rule_audit_log_forwarding"))
+			Expect(rule.Name).To(Equal("audit-log-forwarding"))
+			Expect(rule.ShortDescription.Text).To(Equal("Title of xccdf_org.ssgproject.content_rule_audit_log_forwarding"))
+			Expect(rule.Help.Text).To(Equal("Instructions of xccdf_org.ssgproject.content_rule_audit_log_forwarding"))
+			Expect(rule.DefaultConfiguration.Level).To(Equal("error"))
+			Expect(rule.Properties.Severity).To(Equal("high"))
+			Expect(rule.Properties.SecuritySeverity).To(Equal("8.0"))
+			Expect(rule.Properties.Tags).To(Equal([]string{"CIS-OCP:1.2.22", "NIST-800-53:AU-4(1)", "NIST-800-53:AU-9(2)"}))
+		})
+
+		It("maps the status of the checks to the kind and level of the results", func() {
+			byRule := map[string]sarifResult{}
+			for _, result := range log.Runs[0].Results {
+				byRule[result.RuleID] = result
+			}
+
+			pass := byRule["xccdf_org.ssgproject.content_rule_scc_limit"]
+			Expect(pass.Kind).To(Equal("pass"))
+			Expect(pass.Level).To(Equal("none"))
+
+			fail := byRule["xccdf_org.ssgproject.content_rule_audit_log_forwarding"]
+			Expect(fail.Kind).To(Equal("fail"))
+			Expect(fail.Level).To(Equal("error"))
+			Expect(fail.Message.Text).To(ContainSubstring("No forwarding is configured"))
+			Expect(fail.Locations[0].LogicalLocations[0].FullyQualifiedName).To(Equal("openshift-compliance/ocp4-cis-audit-log-forwarding"))
+			Expect(fail.Properties.Scan).To(Equal("ocp4-cis"))
+
+			Expect(byRule["xccdf_org.ssgproject.content_rule_etcd_encryption"].Kind).To(Equal("open"))
+			Expect(byRule["xccdf_org.ssgproject.content_rule_idp_is_configured"].Kind).To(Equal("review"))
+
+			excepted := byRule["xccdf_org.ssgproject.content_rule_api_server_tls"]
+			Expect(excepted.Kind).To(Equal("fail"))
+			Expect(excepted.Suppressions).To(HaveLen(1))
+			Expect(excepted.Suppressions[0].Kind).To(Equal("external"))
+		})
+
+		It("orders the results the same way whatever the order of the checks", func() {
+			var first, second bytes.Buffer
+			Expect(Render(cmpv1alpha1.ExportFormatSARIF, results, &first)).To(Succeed())
+			results.Checks[0], results.Checks[4] = results.Checks[4], results.Checks[0]
+			Expect(Render(cmpv1alpha1.ExportFormatSARIF, results, &second)).To(Succeed())
+			Expect(second.String()).To(Equal(first.String()))
+		})
+	})
+
+	Context("to JUnit", func() {
+		var report junitTestSuites
+
+		BeforeEach(func() {
+			var buf bytes.Buffer
+			Expect(Render(cmpv1alpha1.ExportFormatJUnit, results, &buf)).To(Succeed())
+			Expect(buf.String()).To(HavePrefix(xml.Header))
+			Expect(xml.Unmarshal(buf.Bytes(), &report)).To(Succeed())
+		})
+
+		It("counts the failures, errors and skipped checks", func() {
+			Expect(report.Tests).To(Equal(5))
+			Expect(report.Failures).To(Equal(1))
+			Expect(report.Errors).To(Equal(1))
+			Expect(report.Skipped).To(Equal(2))
+			Expect(report.Suites).To(HaveLen(1))
+			Expect(report.Suites[0].Name).To(Equal("ocp4-cis"))
+			Expect(report.Suites[0].Tests).To(Equal(5))
+		})
+
+		It("maps the severity, instructions and controls into the test cases", func() {
+			var failed *junitTestCase
+			for i := range report.Suites[0].TestCases {
+				if report.Suites[0].TestCases[i].Failure != nil {
+					failed = &report.Suites[0].TestCases[i]
+				}
+			}
+			Expect(failed).ToNot(BeNil())
+			Expect(failed.Name).To(Equal("audit-log-forwarding"))
+			Expect(failed.ClassName).To(Equal("ocp4-cis"))
+			Expect(failed.Failure.Type).To(Equal("high"))
+			Expect(failed.Failure.Message).To(ContainSubstring("No forwarding is configured"))
+			Expect(failed.Failure.Text).To(Equal("Instructions of xccdf_org.ssgproject.content_rule_audit_log_forwarding"))
+			Expect(failed.Properties).To(ContainElement(junitProperty{Name: "severity", Value: "high"}))
+			Expect(failed.Properties).To(ContainElement(junitProperty{Name: "control:NIST-800-53", Value: "AU-9(2);AU-4(1)"}))
+			Expect(failed.SystemOut).To(ContainSubstring("Rationale of xccdf_org.ssgproject.content_rule_audit_log_forwarding"))
+		})
+	})
+
+	It("reads the results of the scan with the controls of their rules", func() {
+		rule := &cmpv1alpha1.Rule{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "ocp4-audit-log-forwarding",
+				Namespace: "openshift-compliance",
+				Annotations: map[string]string{
+					cmpv1alpha1.RuleIDAnnotationKey:                         "audit-log-forwarding",
+					cmpv1alpha1.RuleControlAnnotationPrefix + "NIST-800-53": "AU-9(2)",
+				},
+			},
+		}
+		check := newCheck("ocp4-cis-audit-log-forwarding", "xccdf_org.ssgproject.content_rule_audit_log_forwarding",
+			cmpv1alpha1.CheckResultFail, cmpv1alpha1.CheckResultSeverityHigh)
+		other := newCheck("other-audit-log-forwarding", "xccdf_org.ssgproject.content_rule_audit_log_forwarding",
+			cmpv1alpha1.CheckResultPass, cmpv1alpha1.CheckResultSeverityHigh)
+		other.Labels[cmpv1alpha1.ComplianceScanLabel] = "other"
+
+		scheme := runtime.NewScheme()
+		Expect(cmpv1alpha1.SchemeBuilder.AddToScheme(scheme)).To(Succeed())
+		client := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(rule, &check, &other).Build()
+
+		results, err := GetScanResults(context.TODO(), client, "ocp4-cis", "openshift-compliance")
+		Expect(err).To(BeNil())
+		Expect(results.Name).To(Equal("ocp4-cis"))
+		Expect(results.Checks).To(HaveLen(1))
+		Expect(results.Checks[0].Name).To(Equal("ocp4-cis-audit-log-forwarding"))
+		Expect(results.Controls["audit-log-forwarding"]).To(HaveKeyWithValue("NIST-800-53", []string{"AU-9(2)"}))
+	})
+
+	It("rejects unknown formats", func() {
+		format, err := ParseFormat("JUnit")
+		Expect(err).To(BeNil())
+		Expect(format).To(Equal(cmpv1alpha1.ExportFormatJUnit))
+		_, err = ParseFormat("csv")
+		Expect(err).ToNot(BeNil())
+	})
+})
@@ -0,0 +1,178 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	cmpv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+)
+
+// The JUnit XML report the results are exported to, as understood by most
+// CI systems
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name       string          `xml:"name,attr"`
+	ClassName  string          `xml:"classname,attr"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	Failure    *junitProblem   `xml:"failure,omitempty"`
+	Error      *junitProblem   `xml:"error,omitempty"`
+	Skipped    *junitSkipped   `xml:"skipped,omitempty"`
+	SystemOut  string          `xml:"system-out,omitempty"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// renderJUnit writes the results as a JUnit XML report with a test suite per
+// scan and a test case per check. Failed checks are failures, checks that
+// couldn't be evaluated are errors, and the checks that don't apply, need a
+// manual review or are covered by an exception are skipped.
+func renderJUnit(results *Results, w io.Writer) error {
+	report := junitTestSuites{Name: results.Name}
+
+	suiteIndex := make(map[string]int)
+	for _, check := range results.sortedChecks() {
+		scan := results.scanName(check)
+		idx, ok := suiteIndex[scan]
+		if !ok {
+			idx = len(report.Suites)
+			suiteIndex[scan] = idx
+			report.Suites = append(report.Suites, junitTestSuite{Name: scan})
+		}
+		suite := &report.Suites[idx]
+
+		testCase := newJUnitTestCase(check, scan, results.controlsOf(check))
+		suite.Tests++
+		switch {
+		case testCase.Failure != nil:
+			suite.Failures++
+		case testCase.Error != nil:
+			suite.Errors++
+		case testCase.Skipped != nil:
+			suite.Skipped++
+		}
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+	sort.SliceStable(report.Suites, func(i, j int) bool {
+		return report.Suites[i].Name < report.Suites[j].Name
+	})
+	for _, suite := range report.Suites {
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Errors += suite.Errors
+		report.Skipped += suite.Skipped
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func newJUnitTestCase(check *cmpv1alpha1.ComplianceCheckResult, scan string, controls map[string][]string) junitTestCase {
+	testCase := junitTestCase{
+		Name:      ruleName(check),
+		ClassName: scan,
+		Properties: []junitProperty{
+			{Name: "id", Value: check.ID},
+			{Name: "status", Value: statusText(check.Status)},
+			{Name: "severity", Value: string(check.Severity)},
+		},
+		SystemOut: junitSystemOut(check),
+	}
+	standards := make([]string, 0, len(controls))
+	for standard := range controls {
+		standards = append(standards, standard)
+	}
+	sort.Strings(standards)
+	for _, standard := range standards {
+		testCase.Properties = append(testCase.Properties, junitProperty{
+			Name:  "control:" + standard,
+			Value: strings.Join(controls[standard], cmpv1alpha1.RuleControlSeparator),
+		})
+	}
+
+	switch check.Status {
+	case cmpv1alpha1.CheckResultPass, cmpv1alpha1.CheckResultInfo:
+	case cmpv1alpha1.CheckResultFail:
+		testCase.Failure = &junitProblem{
+			Message: junitMessage(check),
+			Type:    string(check.Severity),
+			Text:    check.Instructions,
+		}
+	case cmpv1alpha1.CheckResultNotApplicable:
+		testCase.Skipped = &junitSkipped{Message: "The check doesn't apply"}
+	case cmpv1alpha1.CheckResultManual:
+		testCase.Skipped = &junitSkipped{Message: "The check needs a manual review"}
+	case cmpv1alpha1.CheckResultExcepted:
+		testCase.Skipped = &junitSkipped{Message: "The failure is covered by an active ComplianceException"}
+	default:
+		testCase.Error = &junitProblem{
+			Message: junitMessage(check),
+			Type:    statusText(check.Status),
+			Text:    strings.Join(check.Warnings, "\n"),
+		}
+	}
+	return testCase
+}
+
+// junitMessage sums up why the check didn't pass
+func junitMessage(check *cmpv1alpha1.ComplianceCheckResult) string {
+	message := fmt.Sprintf("%s: %s", statusText(check.Status), title(check))
+	if len(check.Warnings) > 0 {
+		message = message + ": " + check.Warnings[0]
+	}
+	return message
+}
+
+// junitSystemOut describes the rule of the check
+func junitSystemOut(check *cmpv1alpha1.ComplianceCheckResult) string {
+	var out []string
+	if check.Description != "" {
+		out = append(out, check.Description)
+	}
+	if check.Rationale != "" {
+		out = append(out, "Rationale: "+check.Rationale)
+	}
+	return strings.Join(out, "\n\n")
+}
@@ -0,0 +1,536 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pborman/uuid"
+
+	cmpv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/utils"
+)
+
+const (
+	oscalVersion = "1.1.2"
+	// The namespace of the properties that aren't defined by OSCAL
+	oscalPropNamespace = toolInfoURI
+	// The standard the OSCAL catalogs define the controls of. The findings
+	// only target the controls of this standard.
+	oscalStandard = "NIST-800-53"
+)
+
+// Assessment is what the OSCAL assessment-results documents and the HTML
+// reports are generated from: the results of the scans of a suite, the
+// profiles they checked and the rules, whose annotations map them to the
+// controls
+type Assessment struct {
+	// The suite of the scans. It's only unset for the report of a single
+	// scan.
+	Suite *cmpv1alpha1.ComplianceSuite
+	// The scans of the suite
+	Scans []cmpv1alpha1.ComplianceScan
+	// The profiles the scans checked. Scans of TailoredProfiles have none.
+	Profiles []cmpv1alpha1.Profile
+	// The rules of the namespace of the suite
+	Rules []cmpv1alpha1.Rule
+	// The results of the checks of the scans
+	Checks []cmpv1alpha1.ComplianceCheckResult
+	// The remediations of the checks
+	Remediations []cmpv1alpha1.ComplianceRemediation
+	// The exceptions that except some of the checks
+	Exceptions []cmpv1alpha1.ComplianceException
+}
+
+// The subset of the OSCAL assessment-results model the results are exported to
+
+type oscalDocument struct {
+	AssessmentResults oscalAssessmentResults `json:"assessment-results"`
+}
+
+type oscalAssessmentResults struct {
+	UUID       string           `json:"uuid"`
+	Metadata   oscalMetadata    `json:"metadata"`
+	ImportAP   oscalImportAP    `json:"import-ap"`
+	Results    []oscalResult    `json:"results"`
+	BackMatter *oscalBackMatter `json:"back-matter,omitempty"`
+}
+
+type oscalMetadata struct {
+	Title        string      `json:"title"`
+	LastModified string      `json:"last-modified"`
+	Version      string      `json:"version"`
+	OSCALVersion string      `json:"oscal-version"`
+	Props        []oscalProp `json:"props,omitempty"`
+}
+
+type oscalImportAP struct {
+	Href    string `json:"href"`
+	Remarks string `json:"remarks,omitempty"`
+}
+
+type oscalProp struct {
+	Name    string `json:"name"`
+	NS      string `json:"ns,omitempty"`
+	Value   string `json:"value"`
+	Remarks string `json:"remarks,omitempty"`
+}
+
+type oscalResult struct {
+	UUID             string               `json:"uuid"`
+	Title            string               `json:"title"`
+	Description      string               `json:"description"`
+	Start            string               `json:"start"`
+	End              string               `json:"end,omitempty"`
+	Props            []oscalProp          `json:"props,omitempty"`
+	ReviewedControls oscalReviewedControl `json:"reviewed-controls"`
+	Observations     []oscalObservation   `json:"observations,omitempty"`
+	Findings         []oscalFinding       `json:"findings,omitempty"`
+}
+
+type oscalReviewedControl struct {
+	ControlSelections []oscalControlSelection `json:"control-selections"`
+}
+
+type oscalControlSelection struct {
+	Description     string              `json:"description,omitempty"`
+	IncludeControls []oscalControlIDRef `json:"include-controls,omitempty"`
+}
+
+type oscalControlIDRef struct {
+	ControlID string `json:"control-id"`
+}
+
+type oscalObservation struct {
+	UUID             string                  `json:"uuid"`
+	Title            string                  `json:"title"`
+	Description      string                  `json:"description"`
+	Props            []oscalProp             `json:"props"`
+	Methods          []string                `json:"methods"`
+	RelevantEvidence []oscalRelevantEvidence `json:"relevant-evidence"`
+	Collected        string                  `json:"collected"`
+	Remarks          string                  `json:"remarks,omitempty"`
+}
+
+type oscalRelevantEvidence struct {
+	Href        string `json:"href"`
+	Description string `json:"description"`
+}
+
+type oscalFinding struct {
+	UUID                string                    `json:"uuid"`
+	Title               string                    `json:"title"`
+	Description         string                    `json:"description"`
+	Target              oscalFindingTarget        `json:"target"`
+	RelatedObservations []oscalRelatedObservation `json:"related-observations"`
+}
+
+type oscalFindingTarget struct {
+	Type     string               `json:"type"`
+	TargetID string               `json:"target-id"`
+	Status   oscalObjectiveStatus `json:"status"`
+}
+
+type oscalObjectiveStatus struct {
+	State   string `json:"state"`
+	Reason  string `json:"reason,omitempty"`
+	Remarks string `json:"remarks,omitempty"`
+}
+
+type oscalRelatedObservation struct {
+	ObservationUUID string `json:"observation-uuid"`
+}
+
+type oscalBackMatter struct {
+	Resources []oscalResource `json:"resources"`
+}
+
+type oscalResource struct {
+	UUID        string       `json:"uuid"`
+	Title       string       `json:"title"`
+	Description string       `json:"description"`
+	Props       []oscalProp  `json:"props,omitempty"`
+	RLinks      []oscalRLink `json:"rlinks"`
+}
+
+type oscalRLink struct {
+	Href string `json:"href"`
+}
+
+// RenderOSCAL writes an OSCAL assessment-results document with a single
+// result for the assessment. Each check is an observation that links to the
+// raw results of its scan, and each NIST-800-53 control or statement the
+// checks map to is a finding that refers to the observations of its checks.
+func RenderOSCAL(a *Assessment, w io.Writer) error {
+	start, end := a.period()
+	// The UUIDs are derived from the suite and its run, so the documents of
+	// the same run are the same
+	space := uuid.NewSHA1(uuid.NameSpace_URL,
+		[]byte(fmt.Sprintf("%s/%s/%s", toolInfoURI, a.Suite.UID, end.UTC().Format(time.RFC3339))))
+	newUUID := func(name string) string {
+		return uuid.NewSHA1(space, []byte(name)).String()
+	}
+
+	checks := (&Results{Checks: a.Checks}).sortedChecks()
+	controls := utils.GetRuleControls(a.Rules)
+	scans := make(map[string]*cmpv1alpha1.ComplianceScan, len(a.Scans))
+	for i := range a.Scans {
+		scans[a.Scans[i].Name] = &a.Scans[i]
+	}
+
+	result := oscalResult{
+		UUID:        newUUID("result"),
+		Title:       fmt.Sprintf("Results of the %s ComplianceSuite", a.Suite.Name),
+		Description: fmt.Sprintf("The results of the %s scans of the %s ComplianceSuite", strings.Join(a.scanNames(), ", "), a.Suite.Name),
+		Start:       oscalTime(start),
+		End:         oscalTime(end),
+		Props:       a.resultProps(),
+		ReviewedControls: oscalReviewedControl{
+			ControlSelections: []oscalControlSelection{
+				{
+					Description:     "The " + oscalStandard + " controls the rules of the scanned profiles map to",
+					IncludeControls: a.reviewedControls(controls, checks),
+				},
+			},
+		},
+	}
+
+	var backMatter oscalBackMatter
+	for _, name := range a.scanNames() {
+		backMatter.Resources = append(backMatter.Resources, newOSCALScanResource(scans[name], newUUID("scan/"+name)))
+	}
+
+	targetControls := make(map[string]string)
+	targetObservations := make(map[string][]string)
+	targetStatuses := make(map[string][]cmpv1alpha1.ComplianceCheckStatus)
+	for _, check := range checks {
+		scan := check.Labels[cmpv1alpha1.ComplianceScanLabel]
+		collected := end
+		if s, ok := scans[scan]; ok && s.Status.EndTimestamp != nil {
+			collected = s.Status.EndTimestamp.Time
+		}
+		observation := newOSCALObservation(check, newUUID("observation/"+check.Name), collected)
+		if _, ok := scans[scan]; ok {
+			observation.RelevantEvidence = append(observation.RelevantEvidence, oscalRelevantEvidence{
+				Href:        "#" + newUUID("scan/"+scan),
+				Description: fmt.Sprintf("The raw results of the %s scan", scan),
+			})
+		}
+		result.Observations = append(result.Observations, observation)
+
+		for _, ctrlID := range controls[ruleName(check)][oscalStandard] {
+			target := oscalStatementID(ctrlID)
+			if target == "" {
+				continue
+			}
+			targetControls[target] = ctrlID
+			targetObservations[target] = append(targetObservations[target], observation.UUID)
+			targetStatuses[target] = append(targetStatuses[target], check.Status)
+		}
+	}
+
+	targets := make([]string, 0, len(targetObservations))
+	for target := range targetObservations {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+	for _, target := range targets {
+		status, ok := oscalTargetStatus(targetStatuses[target])
+		if !ok {
+			// None of the checks of the target apply
+			continue
+		}
+		finding := oscalFinding{
+			UUID:        newUUID("finding/" + target),
+			Title:       fmt.Sprintf("%s %s", oscalStandard, targetControls[target]),
+			Description: fmt.Sprintf("The result of the %d checks that map to %s", len(targetObservations[target]), targetControls[target]),
+			Target: oscalFindingTarget{
+				Type:     "statement-id",
+				TargetID: target,
+				Status:   status,
+			},
+		}
+		for _, observation := range targetObservations[target] {
+			finding.RelatedObservations = append(finding.RelatedObservations, oscalRelatedObservation{ObservationUUID: observation})
+		}
+		result.Findings = append(result.Findings, finding)
+	}
+
+	doc := oscalDocument{
+		AssessmentResults: oscalAssessmentResults{
+			UUID: newUUID("assessment-results"),
+			Metadata: oscalMetadata{
+				Title:        fmt.Sprintf("Assessment results of the %s ComplianceSuite", a.Suite.Name),
+				LastModified: oscalTime(end),
+				Version:      oscalTime(end),
+				OSCALVersion: oscalVersion,
+				Props: []oscalProp{
+					{Name: "suite", NS: oscalPropNamespace, Value: a.Suite.Name},
+				},
+			},
+			// The ComplianceSuite plays the part of the assessment plan
+			ImportAP: oscalImportAP{
+				Href:    apiPath("compliance.openshift.io/v1alpha1", a.Suite.Namespace, "compliancesuites", a.Suite.Name),
+				Remarks: "The ComplianceSuite the scans were run from",
+			},
+			Results: []oscalResult{result},
+		},
+	}
+	if len(backMatter.Resources) > 0 {
+		doc.AssessmentResults.BackMatter = &backMatter
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// period returns when the scans of the assessment started and ended
+func (a *Assessment) period() (time.Time, time.Time) {
+	var start, end time.Time
+	for i := range a.Scans {
+		status := &a.Scans[i].Status
+		if status.StartTimestamp != nil && (start.IsZero() || status.StartTimestamp.Time.Before(start)) {
+			start = status.StartTimestamp.Time
+		}
+		if status.EndTimestamp != nil && status.EndTimestamp.Time.After(end) {
+			end = status.EndTimestamp.Time
+		}
+	}
+	if start.IsZero() && a.Suite != nil {
+		start = a.Suite.CreationTimestamp.Time
+	}
+	if end.IsZero() {
+		end = start
+	}
+	return start, end
+}
+
+func (a *Assessment) scanNames() []string {
+	names := make([]string, 0, len(a.Scans))
+	for i := range a.Scans {
+		names = append(names, a.Scans[i].Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (a *Assessment) resultProps() []oscalProp {
+	props := []oscalProp{}
+	if a.Suite.Status.Result != "" {
+		props = append(props, oscalProp{Name: "suite-result", NS: oscalPropNamespace, Value: string(a.Suite.Status.Result)})
+	}
+	for i := range a.Profiles {
+		props = append(props, oscalProp{
+			Name:    "profile",
+			NS:      oscalPropNamespace,
+			Value:   a.Profiles[i].ID,
+			Remarks: a.Profiles[i].Title,
+		})
+	}
+	return props
+}
+
+// reviewedControls returns the controls of the rules of the profiles and of
+// the checks, as the checks of TailoredProfiles and CustomRules have no
+// profile
+func (a *Assessment) reviewedControls(controls map[string]map[string][]string, checks []*cmpv1alpha1.ComplianceCheckResult) []oscalControlIDRef {
+	ruleIDs := make(map[string]string, len(a.Rules))
+	for i := range a.Rules {
+		ruleIDs[a.Rules[i].Name] = a.Rules[i].Annotations[cmpv1alpha1.RuleIDAnnotationKey]
+	}
+
+	var rules []string
+	for i := range a.Profiles {
+		for _, rule := range a.Profiles[i].Rules {
+			rules = append(rules, ruleIDs[string(rule)])
+		}
+	}
+	for _, check := range checks {
+		rules = append(rules, ruleName(check))
+	}
+
+	seen := make(map[string]bool)
+	refs := []oscalControlIDRef{}
+	for _, rule := range rules {
+		for _, ctrlID := range controls[rule][oscalStandard] {
+			id := oscalControlID(ctrlID)
+			if id == "" || seen[id] {
+				continue
+			}
+			seen[id] = true
+			refs = append(refs, oscalControlIDRef{ControlID: id})
+		}
+	}
+	sort.Slice(refs, func(i, j int) bool { return refs[i].ControlID < refs[j].ControlID })
+	return refs
+}
+
+func newOSCALObservation(check *cmpv1alpha1.ComplianceCheckResult, id string, collected time.Time) oscalObservation {
+	observation := oscalObservation{
+		UUID:        id,
+		Title:       title(check),
+		Description: fmt.Sprintf("The %s check of the %s rule is %s", check.Name, ruleName(check), statusText(check.Status)),
+		Props: []oscalProp{
+			{Name: "check", NS: oscalPropNamespace, Value: check.Name},
+			{Name: "rule", NS: oscalPropNamespace, Value: ruleName(check)},
+			{Name: "check-status", NS: oscalPropNamespace, Value: statusText(check.Status)},
+		},
+		Methods: []string{"TEST"},
+		RelevantEvidence: []oscalRelevantEvidence{
+			{
+				Href:        apiPath("compliance.openshift.io/v1alpha1", check.Namespace, "compliancecheckresults", check.Name),
+				Description: "The ComplianceCheckResult of the check",
+			},
+		},
+		Collected: oscalTime(collected),
+		Remarks:   strings.Join(check.Warnings, "\n"),
+	}
+	if check.Severity != "" {
+		observation.Props = append(observation.Props, oscalProp{Name: "severity", NS: oscalPropNamespace, Value: string(check.Severity)})
+	}
+	if check.Status == cmpv1alpha1.CheckResultManual {
+		observation.Methods = []string{"EXAMINE"}
+	}
+	return observation
+}
+
+// newOSCALScanResource describes where the raw results of the scan are:
+// the ARF reports in the volume of the scan, and the XCCDF results in
+// ConfigMaps labeled with the name of the scan
+func newOSCALScanResource(scan *cmpv1alpha1.ComplianceScan, id string) oscalResource {
+	resource := oscalResource{
+		UUID:  id,
+		Title: fmt.Sprintf("Raw results of the %s scan", scan.Name),
+		Description: fmt.Sprintf("The XCCDF results of the %s scan are in the ConfigMaps labeled with %s=%s.",
+			scan.Name, cmpv1alpha1.ComplianceScanLabel, scan.Name),
+		Props: []oscalProp{
+			{Name: "scan", NS: oscalPropNamespace, Value: scan.Name},
+		},
+	}
+	if scan.Status.ResultsStorage.Name != "" {
+		resource.Description = fmt.Sprintf("The ARF reports of the %s scan are in the %d directory of the %s PersistentVolumeClaim. ",
+			scan.Name, scan.Status.CurrentIndex, scan.Status.ResultsStorage.Name) + resource.Description
+		resource.Props = append(resource.Props, oscalProp{
+			Name:  "results-directory",
+			NS:    oscalPropNamespace,
+			Value: fmt.Sprintf("%d", scan.Status.CurrentIndex),
+		})
+		resource.RLinks = append(resource.RLinks, oscalRLink{
+			Href: apiPath("v1", scan.Status.ResultsStorage.Namespace, "persistentvolumeclaims", scan.Status.ResultsStorage.Name),
+		})
+	}
+	selector := url.Values{"labelSelector": {cmpv1alpha1.ComplianceScanLabel + "=" + scan.Name}}
+	resource.RLinks = append(resource.RLinks, oscalRLink{
+		Href: apiPath("v1", scan.Namespace, "configmaps", "") + "?" + selector.Encode(),
+	})
+	return resource
+}
+
+// oscalTargetStatus returns the status of a control statement given the
+// status of the checks that map to it, and false if none of them apply. A
+// statement is only satisfied if none of its checks failed or need
+// attention.
+func oscalTargetStatus(statuses []cmpv1alpha1.ComplianceCheckStatus) (oscalObjectiveStatus, bool) {
+	statusCount := make(map[cmpv1alpha1.ComplianceCheckStatus]int)
+	for _, status := range statuses {
+		statusCount[status]++
+	}
+
+	notSatisfied := func(reason, remarks string) (oscalObjectiveStatus, bool) {
+		return oscalObjectiveStatus{State: "not-satisfied", Reason: reason, Remarks: remarks}, true
+	}
+	switch {
+	case statusCount[cmpv1alpha1.CheckResultFail] > 0:
+		return notSatisfied("fail", fmt.Sprintf("%d of the checks failed", statusCount[cmpv1alpha1.CheckResultFail]))
+	case statusCount[cmpv1alpha1.CheckResultError] > 0 || statusCount[cmpv1alpha1.CheckResultNoResult] > 0:
+		return notSatisfied("other", "Some of the checks couldn't be evaluated")
+	case statusCount[cmpv1alpha1.CheckResultInconsistent] > 0:
+		return notSatisfied("other", "Some of the checks had different results on different nodes")
+	case statusCount[cmpv1alpha1.CheckResultManual] > 0:
+		return notSatisfied("other", "Some of the checks need a manual review")
+	case statusCount[cmpv1alpha1.CheckResultExcepted] > 0:
+		return notSatisfied("other", "The failures are covered by an active ComplianceException")
+	case statusCount[cmpv1alpha1.CheckResultPass] > 0:
+		return oscalObjectiveStatus{State: "satisfied", Reason: "pass"}, true
+	default:
+		return oscalObjectiveStatus{}, false
+	}
+}
+
+// oscalControlID returns the OSCAL identifier of a NIST-800-53 control as
+// annotated on the rules, e.g. ac-2.1 for AC-2(1). The statements of the
+// control, e.g. (a) in CM-6(a), are not part of its identifier.
+func oscalControlID(ctrlID string) string {
+	id, _ := splitNISTControl(ctrlID)
+	return id
+}
+
+// oscalStatementID returns the OSCAL identifier of the statement of a
+// NIST-800-53 control as annotated on the rules, e.g. cm-6_smt.a for
+// CM-6(a), or the whole statement of the control if it has no item
+func oscalStatementID(ctrlID string) string {
+	id, items := splitNISTControl(ctrlID)
+	if id == "" {
+		return ""
+	}
+	return strings.Join(append([]string{id + "_smt"}, items...), ".")
+}
+
+// splitNISTControl splits a NIST-800-53 control, e.g. AC-2(1)(a), into its
+// OSCAL identifier and the items of its statement. The parenthesized
+// numbers that directly follow the control are enhancements.
+func splitNISTControl(ctrlID string) (string, []string) {
+	ctrlID = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(ctrlID), " ", ""))
+	base, rest, _ := strings.Cut(ctrlID, "(")
+	if base == "" || !strings.Contains(base, "-") {
+		return "", nil
+	}
+
+	id := base
+	var items []string
+	if rest != "" {
+		for _, part := range strings.Split(strings.TrimSuffix(rest, ")"), ")(") {
+			if part == "" {
+				continue
+			}
+			if items == nil && isNumber(part) {
+				id = id + "." + part
+				continue
+			}
+			items = append(items, part)
+		}
+	}
+	return id, items
+}
+
+func isNumber(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// apiPath returns the path of the object in the API server, or of the
+// resource if the name is empty
+func apiPath(groupVersion, namespace, resource, name string) string {
+	prefix := "/apis/"
+	if !strings.Contains(groupVersion, "/") {
+		prefix = "/api/"
+	}
+	path := fmt.Sprintf("%s%s/namespaces/%s/%s", prefix, groupVersion, namespace, resource)
+	if name != "" {
+		path = path + "/" + name
+	}
+	return path
+}
+
+func oscalTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
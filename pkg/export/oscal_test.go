@@ -0,0 +1,232 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	cmpv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+)
+
+func newRule(name, ruleID, nistControls string) cmpv1alpha1.Rule {
+	return cmpv1alpha1.Rule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "openshift-compliance",
+			Annotations: map[string]string{
+				cmpv1alpha1.RuleIDAnnotationKey:                         ruleID,
+				cmpv1alpha1.RuleControlAnnotationPrefix + "NIST-800-53": nistControls,
+			},
+		},
+	}
+}
+
+var _ = Describe("Generating OSCAL assessment results", func() {
+	var assessment *Assessment
+	var end time.Time
+
+	BeforeEach(func() {
+		start := time.Date(2026, 10, 19, 1, 0, 0, 0, time.UTC)
+		end = start.Add(5 * time.Minute)
+		scan := cmpv1alpha1.ComplianceScan{
+			ObjectMeta: metav1.ObjectMeta{Name: "ocp4-cis", Namespace: "openshift-compliance"},
+			Status: cmpv1alpha1.ComplianceScanStatus{
+				CurrentIndex:   2,
+				StartTimestamp: &metav1.Time{Time: start},
+				EndTimestamp:   &metav1.Time{Time: end},
+				ResultsStorage: cmpv1alpha1.StorageReference{Name: "ocp4-cis", Namespace: "openshift-compliance"},
+			},
+		}
+		checks := []cmpv1alpha1.ComplianceCheckResult{
+			newCheck("ocp4-cis-audit-log-forwarding", "xccdf_org.ssgproject.content_rule_audit_log_forwarding",
+				cmpv1alpha1.CheckResultFail, cmpv1alpha1.CheckResultSeverityHigh),
+			newCheck("ocp4-cis-scc-limit", "xccdf_org.ssgproject.content_rule_scc_limit",
+				cmpv1alpha1.CheckResultPass, cmpv1alpha1.CheckResultSeverityMedium),
+			newCheck("ocp4-cis-idp-is-configured", "xccdf_org.ssgproject.content_rule_idp_is_configured",
+				cmpv1alpha1.CheckResultManual, cmpv1alpha1.CheckResultSeverityLow),
+		}
+		for i := range checks {
+			checks[i].Annotations = map[string]string{
+				cmpv1alpha1.ComplianceCheckResultRuleAnnotation: ruleName(&checks[i]),
+			}
+		}
+		profile := cmpv1alpha1.Profile{
+			ObjectMeta: metav1.ObjectMeta{Name: "ocp4-cis", Namespace: "openshift-compliance"},
+			ProfilePayload: cmpv1alpha1.ProfilePayload{
+				ID:    "xccdf_org.ssgproject.content_profile_cis",
+				Title: "CIS Red Hat OpenShift Container Platform Benchmark",
+				Rules: []cmpv1alpha1.ProfileRule{"ocp4-audit-log-forwarding", "ocp4-scc-limit", "ocp4-idp-is-configured", "ocp4-etcd-encryption"},
+			},
+		}
+		assessment = &Assessment{
+			Suite: &cmpv1alpha1.ComplianceSuite{
+				ObjectMeta: metav1.ObjectMeta{Name: "cis", Namespace: "openshift-compliance", UID: "1234"},
+				Status:     cmpv1alpha1.ComplianceSuiteStatus{Result: cmpv1alpha1.ResultNonCompliant},
+			},
+			Scans:    []cmpv1alpha1.ComplianceScan{scan},
+			Profiles: []cmpv1alpha1.Profile{profile},
+			Rules: []cmpv1alpha1.Rule{
+				newRule("ocp4-audit-log-forwarding", "audit-log-forwarding", "AU-4(1);AU-9(2)"),
+				newRule("ocp4-scc-limit", "scc-limit", "AC-6;AU-4(1)"),
+				newRule("ocp4-idp-is-configured", "idp-is-configured", "AC-2(a)"),
+				newRule("ocp4-etcd-encryption", "etcd-encryption", "SC-28"),
+			},
+			Checks: checks,
+		}
+	})
+
+	render := func() oscalAssessmentResults {
+		var buf bytes.Buffer
+		Expect(RenderOSCAL(assessment, &buf)).To(Succeed())
+		var doc oscalDocument
+		Expect(json.Unmarshal(buf.Bytes(), &doc)).To(Succeed())
+		return doc.AssessmentResults
+	}
+
+	It("describes the run of the suite", func() {
+		doc := render()
+		Expect(doc.Metadata.OSCALVersion).To(Equal("1.1.2"))
+		Expect(doc.Metadata.LastModified).To(Equal("2026-10-19T01:05:00Z"))
+		Expect(doc.ImportAP.Href).To(Equal("/apis/compliance.openshift.io/v1alpha1/namespaces/openshift-compliance/compliancesuites/cis"))
+		Expect(doc.Results).To(HaveLen(1))
+		result := doc.Results[0]
+		Expect(result.Start).To(Equal("2026-10-19T01:00:00Z"))
+		Expect(result.End).To(Equal("2026-10-19T01:05:00Z"))
+		Expect(result.Props).To(ContainElement(oscalProp{
+			Name:    "profile",
+			NS:      oscalPropNamespace,
+			Value:   "xccdf_org.ssgproject.content_profile_cis",
+			Remarks: "CIS Red Hat OpenShift Container Platform Benchmark",
+		}))
+	})
+
+	It("reviews the controls of the rules of the profiles", func() {
+		result := render().Results[0]
+		Expect(result.ReviewedControls.ControlSelections).To(HaveLen(1))
+		Expect(result.ReviewedControls.ControlSelections[0].IncludeControls).To(Equal([]oscalControlIDRef{
+			{ControlID: "ac-2"},
+			{ControlID: "ac-6"},
+			{ControlID: "au-4.1"},
+			{ControlID: "au-9.2"},
+			{ControlID: "sc-28"},
+		}))
+	})
+
+	It("links the observations to the raw results of their scan", func() {
+		doc := render()
+		result := doc.Results[0]
+		Expect(result.Observations).To(HaveLen(3))
+		Expect(doc.BackMatter.Resources).To(HaveLen(1))
+		resource := doc.BackMatter.Resources[0]
+		Expect(resource.RLinks).To(ConsistOf(
+			oscalRLink{Href: "/api/v1/namespaces/openshift-compliance/persistentvolumeclaims/ocp4-cis"},
+			oscalRLink{Href: "/api/v1/namespaces/openshift-compliance/configmaps?labelSelector=compliance.openshift.io%2Fscan-name%3Docp4-cis"},
+		))
+		Expect(resource.Description).To(ContainSubstring("in the 2 directory of the ocp4-cis PersistentVolumeClaim"))
+
+		observation := result.Observations[0]
+		Expect(observation.Title).To(Equal("Title of xccdf_org.ssgproject.content_rule_audit_log_forwarding"))
+		Expect(observation.Props).To(ContainElement(oscalProp{Name: "check-status", NS: oscalPropNamespace, Value: "FAIL"}))
+		Expect(observation.Collected).To(Equal("2026-10-19T01:05:00Z"))
+		Expect(observation.RelevantEvidence).To(ContainElement(oscalRelevantEvidence{
+			Href:        "#" + resource.UUID,
+			Description: "The raw results of the ocp4-cis scan",
+		}))
+		Expect(observation.RelevantEvidence).To(ContainElement(HaveField("Href",
+			"/apis/compliance.openshift.io/v1alpha1/namespaces/openshift-compliance/compliancecheckresults/ocp4-cis-audit-log-forwarding")))
+	})
+
+	It("rolls the checks up to findings on the control statements", func() {
+		result := render().Results[0]
+		observations := make(map[string]string)
+		for _, observation := range result.Observations {
+			observations[observation.UUID] = observation.Props[0].Value
+		}
+
+		findings := make(map[string]oscalFinding)
+		for _, finding := range result.Findings {
+			findings[finding.Target.TargetID] = finding
+		}
+		Expect(findings).To(HaveLen(4))
+
+		// Failed by audit-log-forwarding, passed by scc-limit
+		Expect(findings["au-4.1_smt"].Target.Status.State).To(Equal("not-satisfied"))
+		Expect(findings["au-4.1_smt"].Target.Status.Reason).To(Equal("fail"))
+		Expect(findings["au-4.1_smt"].RelatedObservations).To(HaveLen(2))
+		Expect(findings["au-9.2_smt"].Target.Status.State).To(Equal("not-satisfied"))
+		Expect(findings["ac-6_smt"].Target.Status.State).To(Equal("satisfied"))
+		Expect(observations[findings["ac-6_smt"].RelatedObservations[0].ObservationUUID]).To(Equal("ocp4-cis-scc-limit"))
+		Expect(findings["ac-2_smt.a"].Target.Status.State).To(Equal("not-satisfied"))
+		Expect(findings["ac-2_smt.a"].Target.Status.Remarks).To(Equal("Some of the checks need a manual review"))
+	})
+
+	It("generates the same document for the same run", func() {
+		Expect(render()).To(Equal(render()))
+
+		first := render()
+		assessment.Scans[0].Status.EndTimestamp = &metav1.Time{Time: end.Add(24 * time.Hour)}
+		Expect(render().UUID).ToNot(Equal(first.UUID))
+	})
+
+	It("maps the NIST-800-53 controls to OSCAL identifiers", func() {
+		Expect(oscalControlID("AC-2")).To(Equal("ac-2"))
+		Expect(oscalControlID("AC-2(1)")).To(Equal("ac-2.1"))
+		Expect(oscalControlID("CM-6(a)")).To(Equal("cm-6"))
+		Expect(oscalStatementID("CM-6(a)")).To(Equal("cm-6_smt.a"))
+		Expect(oscalStatementID("AC-17(1)(a)")).To(Equal("ac-17.1_smt.a"))
+		Expect(oscalStatementID("Req-1.2")).To(Equal("req-1.2_smt"))
+		Expect(oscalStatementID("")).To(BeEmpty())
+	})
+
+	It("reads the profiles the scans of the suite checked", func() {
+		suite := assessment.Suite
+		scan := assessment.Scans[0].DeepCopy()
+		scan.Labels = map[string]string{cmpv1alpha1.SuiteLabel: "cis"}
+		scan.Spec.Profile = "xccdf_org.ssgproject.content_profile_cis"
+		scan.Spec.Content = "ssg-ocp4-ds.xml"
+		scan.Spec.ContentImage = "quay.io/complianceascode/ocp4:latest"
+
+		newBundle := func(name, contentFile string) *cmpv1alpha1.ProfileBundle {
+			return &cmpv1alpha1.ProfileBundle{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "openshift-compliance"},
+				Spec: cmpv1alpha1.ProfileBundleSpec{
+					ContentImage: "quay.io/complianceascode/ocp4:latest",
+					ContentFile:  contentFile,
+				},
+			}
+		}
+		newProfile := func(name, bundle string) *cmpv1alpha1.Profile {
+			profile := assessment.Profiles[0].DeepCopy()
+			profile.Name = name
+			profile.Labels = map[string]string{cmpv1alpha1.ProfileBundleOwnerLabel: bundle}
+			return profile
+		}
+		check := assessment.Checks[0].DeepCopy()
+		check.Labels[cmpv1alpha1.SuiteLabel] = "cis"
+		otherCheck := assessment.Checks[1].DeepCopy()
+		otherCheck.Labels[cmpv1alpha1.SuiteLabel] = "other"
+
+		scheme := runtime.NewScheme()
+		Expect(cmpv1alpha1.SchemeBuilder.AddToScheme(scheme)).To(Succeed())
+		client := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(
+			scan, check, otherCheck,
+			newBundle("ocp4", "ssg-ocp4-ds.xml"), newBundle("rhcos4", "ssg-rhcos4-ds.xml"),
+			newProfile("ocp4-cis", "ocp4"), newProfile("rhcos4-cis", "rhcos4"),
+		).Build()
+
+		got, err := GetAssessment(context.TODO(), client, suite)
+		Expect(err).To(BeNil())
+		Expect(got.Scans).To(HaveLen(1))
+		Expect(got.Checks).To(HaveLen(1))
+		Expect(got.Checks[0].Name).To(Equal(check.Name))
+		Expect(got.Profiles).To(HaveLen(1))
+		Expect(got.Profiles[0].Name).To(Equal("ocp4-cis"))
+	})
+})
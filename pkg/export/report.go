@@ -0,0 +1,546 @@
+package export
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmpv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/utils"
+)
+
+// The order the statuses of the checks are summarized and detailed in, the
+// ones that need attention first
+var reportStatusOrder = []cmpv1alpha1.ComplianceCheckStatus{
+	cmpv1alpha1.CheckResultFail,
+	cmpv1alpha1.CheckResultError,
+	cmpv1alpha1.CheckResultNoResult,
+	cmpv1alpha1.CheckResultInconsistent,
+	cmpv1alpha1.CheckResultManual,
+	cmpv1alpha1.CheckResultExcepted,
+	cmpv1alpha1.CheckResultInfo,
+	cmpv1alpha1.CheckResultPass,
+	cmpv1alpha1.CheckResultNotApplicable,
+}
+
+// The order the severities of the checks are summarized and detailed in
+var reportSeverityOrder = []cmpv1alpha1.ComplianceCheckResultSeverity{
+	cmpv1alpha1.CheckResultSeverityHigh,
+	cmpv1alpha1.CheckResultSeverityMedium,
+	cmpv1alpha1.CheckResultSeverityLow,
+	cmpv1alpha1.CheckResultSeverityInfo,
+	cmpv1alpha1.CheckResultSeverityUnknown,
+}
+
+// htmlReport is what the HTML report is rendered from
+type htmlReport struct {
+	Title      string
+	Generated  string
+	Scans      []htmlReportScan
+	Statuses   []string
+	Severities []htmlReportCount
+	Total      htmlReportCount
+	Controls   []htmlReportControl
+	Checks     []htmlReportCheck
+	// The remediations of all the checks
+	Remediations []htmlReportRemediation
+	Exceptions   []htmlReportException
+}
+
+type htmlReportScan struct {
+	Name    string
+	Type    string
+	Profile string
+	Result  string
+	Ended   string
+}
+
+// htmlReportCount counts the checks of each status of the report, in the
+// order of the statuses of the report
+type htmlReportCount struct {
+	Name   string
+	Counts []int
+	Total  int
+}
+
+type htmlReportControl struct {
+	Standard string
+	ID       string
+	Status   string
+	Remarks  string
+	Class    string
+	Passed   int
+	Checks   int
+}
+
+type htmlReportCheck struct {
+	Name         string
+	Title        string
+	ID           string
+	Scan         string
+	Severity     string
+	Status       string
+	Class        string
+	Description  string
+	Rationale    string
+	Instructions string
+	Controls     []string
+	Warnings     []string
+	Answer       string
+	Exception    string
+	// The status the check had before it was excepted
+	ExceptedStatus string
+	Remediations   []htmlReportRemediation
+}
+
+type htmlReportRemediation struct {
+	Name  string
+	Check string
+	State string
+	// Why the remediation is in its state, if it tells
+	Message string
+}
+
+type htmlReportException struct {
+	Name          string
+	Rule          string
+	Justification string
+	Approver      string
+	Expires       string
+	State         string
+	Checks        []string
+}
+
+// RenderHTML writes a human-readable HTML report of the assessment: the
+// results of the checks summarized by severity and by control, the details
+// of each check along with the status of its remediations, and the
+// exceptions of the checks. The report is styled to be printed, e.g. to PDF.
+func RenderHTML(a *Assessment, w io.Writer) error {
+	report := htmlReport{
+		Title: a.reportTitle(),
+		Scans: a.reportScans(),
+	}
+	if _, end := a.period(); !end.IsZero() {
+		report.Generated = end.UTC().Format(time.RFC1123)
+	}
+
+	checks := (&Results{Checks: a.Checks}).sortedChecks()
+	sort.SliceStable(checks, func(i, j int) bool {
+		si, sj := statusRank(checks[i].Status), statusRank(checks[j].Status)
+		if si != sj {
+			return si < sj
+		}
+		return severityRank(checks[i].Severity) < severityRank(checks[j].Severity)
+	})
+
+	// Only the statuses the checks have are summarized
+	statusCount := make(map[cmpv1alpha1.ComplianceCheckStatus]int)
+	for _, check := range checks {
+		statusCount[check.Status]++
+	}
+	var statuses []cmpv1alpha1.ComplianceCheckStatus
+	for _, status := range reportStatusOrder {
+		if statusCount[status] > 0 {
+			statuses = append(statuses, status)
+			report.Statuses = append(report.Statuses, statusText(status))
+		}
+	}
+	countStatuses := func(name string, include func(*cmpv1alpha1.ComplianceCheckResult) bool) htmlReportCount {
+		count := htmlReportCount{Name: name, Counts: make([]int, len(statuses))}
+		for _, check := range checks {
+			if !include(check) {
+				continue
+			}
+			for i, status := range statuses {
+				if check.Status == status {
+					count.Counts[i]++
+				}
+			}
+			count.Total++
+		}
+		return count
+	}
+	for _, severity := range reportSeverityOrder {
+		severity := severity
+		count := countStatuses(string(severity), func(check *cmpv1alpha1.ComplianceCheckResult) bool {
+			return severityRank(check.Severity) == severityRank(severity)
+		})
+		if count.Total > 0 {
+			report.Severities = append(report.Severities, count)
+		}
+	}
+	report.Total = countStatuses("total", func(*cmpv1alpha1.ComplianceCheckResult) bool { return true })
+
+	controls := utils.GetRuleControls(a.Rules)
+	report.Controls = reportControls(controls, checks)
+
+	remediations := a.checkRemediations()
+	exceptions := make(map[string]*htmlReportException, len(a.Exceptions))
+	for i := range a.Exceptions {
+		exception := &a.Exceptions[i]
+		exceptions[exception.Namespace+"/"+exception.Name] = &htmlReportException{
+			Name:          exception.Name,
+			Rule:          exception.Spec.Rule,
+			Justification: exception.Spec.Justification,
+			Approver:      exception.Spec.Approver,
+			Expires:       exception.Spec.ExpiresAt.UTC().Format(time.RFC1123),
+			State:         string(exception.Status.State),
+		}
+	}
+
+	for _, check := range checks {
+		item := htmlReportCheck{
+			Name:           check.Name,
+			Title:          title(check),
+			ID:             check.ID,
+			Scan:           check.Labels[cmpv1alpha1.ComplianceScanLabel],
+			Severity:       string(check.Severity),
+			Status:         statusText(check.Status),
+			Class:          statusClass(check.Status),
+			Description:    description(check),
+			Rationale:      strings.TrimSpace(check.Rationale),
+			Instructions:   strings.TrimSpace(check.Instructions),
+			Controls:       controlTags(controls[ruleName(check)]),
+			Warnings:       check.Warnings,
+			Answer:         check.Annotations[cmpv1alpha1.ComplianceCheckResultAnswerAnnotation],
+			Exception:      check.Annotations[cmpv1alpha1.ComplianceCheckResultExceptionAnnotation],
+			ExceptedStatus: check.Annotations[cmpv1alpha1.ComplianceCheckResultExceptedStatusAnnotation],
+			Remediations:   remediations[check.Name],
+		}
+		report.Checks = append(report.Checks, item)
+		report.Remediations = append(report.Remediations, item.Remediations...)
+		if exception, ok := exceptions[item.Exception]; ok {
+			exception.Checks = append(exception.Checks, check.Name)
+		}
+	}
+
+	refs := make([]string, 0, len(exceptions))
+	for ref := range exceptions {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+	for _, ref := range refs {
+		report.Exceptions = append(report.Exceptions, *exceptions[ref])
+	}
+
+	return htmlReportTemplate.Execute(w, report)
+}
+
+// reportTitle returns the title of the report, after the suite or the scan
+// it's the report of
+func (a *Assessment) reportTitle() string {
+	if a.Suite != nil {
+		return fmt.Sprintf("Compliance report of the %s ComplianceSuite", a.Suite.Name)
+	}
+	return fmt.Sprintf("Compliance report of the %s ComplianceScan", strings.Join(a.scanNames(), ", "))
+}
+
+func (a *Assessment) reportScans() []htmlReportScan {
+	profileTitles := make(map[string]string, len(a.Profiles))
+	for i := range a.Profiles {
+		profileTitles[a.Profiles[i].ID] = a.Profiles[i].Title
+	}
+
+	scans := make([]htmlReportScan, 0, len(a.Scans))
+	for i := range a.Scans {
+		scan := &a.Scans[i]
+		item := htmlReportScan{
+			Name:    scan.Name,
+			Type:    string(scan.Spec.ScanType),
+			Profile: scan.Spec.Profile,
+			Result:  string(scan.Status.Result),
+		}
+		if profileTitle, ok := profileTitles[scan.Spec.Profile]; ok {
+			item.Profile = profileTitle
+		}
+		if scan.Status.EndTimestamp != nil {
+			item.Ended = scan.Status.EndTimestamp.UTC().Format(time.RFC1123)
+		}
+		scans = append(scans, item)
+	}
+	sort.Slice(scans, func(i, j int) bool { return scans[i].Name < scans[j].Name })
+	return scans
+}
+
+// checkRemediations returns the remediations of each check, by the name of
+// the check that owns them
+func (a *Assessment) checkRemediations() map[string][]htmlReportRemediation {
+	remediations := make(map[string][]htmlReportRemediation)
+	for i := range a.Remediations {
+		rem := &a.Remediations[i]
+		owner := metav1.GetControllerOf(rem)
+		if owner == nil || owner.Kind != "ComplianceCheckResult" {
+			continue
+		}
+		state := rem.Status.ApplicationState
+		if state == "" {
+			state = cmpv1alpha1.RemediationNotApplied
+		}
+		message := rem.Status.ErrorMessage
+		if message == "" {
+			message = rem.Status.RollbackReason
+		}
+		remediations[owner.Name] = append(remediations[owner.Name], htmlReportRemediation{
+			Name:    rem.Name,
+			Check:   owner.Name,
+			State:   string(state),
+			Message: message,
+		})
+	}
+	for _, rems := range remediations {
+		sort.Slice(rems, func(i, j int) bool { return rems[i].Name < rems[j].Name })
+	}
+	return remediations
+}
+
+// reportControls returns the status of each control the checks map to, of
+// any standard. The status of a control is rolled up from its checks as for
+// the findings of the OSCAL assessment results.
+func reportControls(controls map[string]map[string][]string, checks []*cmpv1alpha1.ComplianceCheckResult) []htmlReportControl {
+	type controlKey struct{ standard, id string }
+	statuses := make(map[controlKey][]cmpv1alpha1.ComplianceCheckStatus)
+	for _, check := range checks {
+		for standard, ids := range controls[ruleName(check)] {
+			for _, id := range ids {
+				key := controlKey{standard, id}
+				statuses[key] = append(statuses[key], check.Status)
+			}
+		}
+	}
+
+	var report []htmlReportControl
+	for key, checkStatuses := range statuses {
+		control := htmlReportControl{
+			Standard: key.standard,
+			ID:       key.id,
+			Checks:   len(checkStatuses),
+		}
+		for _, status := range checkStatuses {
+			if status == cmpv1alpha1.CheckResultPass {
+				control.Passed++
+			}
+		}
+		status, ok := oscalTargetStatus(checkStatuses)
+		switch {
+		case !ok:
+			control.Status = "Not applicable"
+			control.Class = statusClass(cmpv1alpha1.CheckResultNotApplicable)
+		case status.State == "satisfied":
+			control.Status = "Satisfied"
+			control.Class = statusClass(cmpv1alpha1.CheckResultPass)
+		case status.Reason == "fail":
+			control.Status = "Not satisfied"
+			control.Remarks = status.Remarks
+			control.Class = statusClass(cmpv1alpha1.CheckResultFail)
+		default:
+			control.Status = "Not satisfied"
+			control.Remarks = status.Remarks
+			control.Class = statusClass(cmpv1alpha1.CheckResultManual)
+		}
+		report = append(report, control)
+	}
+	sort.Slice(report, func(i, j int) bool {
+		if report[i].Standard != report[j].Standard {
+			return report[i].Standard < report[j].Standard
+		}
+		return report[i].ID < report[j].ID
+	})
+	return report
+}
+
+// description returns the description of the check without its title
+func description(check *cmpv1alpha1.ComplianceCheckResult) string {
+	_, rest, _ := strings.Cut(strings.TrimSpace(check.Description), "\n")
+	return strings.TrimSpace(rest)
+}
+
+func statusRank(status cmpv1alpha1.ComplianceCheckStatus) int {
+	for i, s := range reportStatusOrder {
+		if s == status {
+			return i
+		}
+	}
+	return len(reportStatusOrder)
+}
+
+// severityRank returns the rank of the severity, unset severities rank as
+// unknown ones
+func severityRank(severity cmpv1alpha1.ComplianceCheckResultSeverity) int {
+	for i, s := range reportSeverityOrder {
+		if s == severity {
+			return i
+		}
+	}
+	return len(reportSeverityOrder) - 1
+}
+
+// statusClass returns the CSS class the status is styled with
+func statusClass(status cmpv1alpha1.ComplianceCheckStatus) string {
+	switch status {
+	case cmpv1alpha1.CheckResultPass:
+		return "pass"
+	case cmpv1alpha1.CheckResultFail:
+		return "fail"
+	case cmpv1alpha1.CheckResultError, cmpv1alpha1.CheckResultNoResult, cmpv1alpha1.CheckResultInconsistent:
+		return "error"
+	case cmpv1alpha1.CheckResultManual, cmpv1alpha1.CheckResultExcepted:
+		return "review"
+	default:
+		return "neutral"
+	}
+}
+
+var htmlReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{ .Title }}</title>
+<style>
+body { font-family: "Red Hat Text", "Helvetica Neue", Arial, sans-serif; font-size: 11pt; color: #151515; margin: 2em auto; max-width: 60em; padding: 0 1em; }
+h1 { font-size: 1.8em; margin-bottom: 0.2em; }
+h2 { font-size: 1.4em; border-bottom: 1px solid #d2d2d2; padding-bottom: 0.2em; margin-top: 2em; }
+h3 { font-size: 1.1em; margin: 0 0 0.5em 0; }
+table { border-collapse: collapse; width: 100%; margin: 0.5em 0 1em 0; }
+th, td { border: 1px solid #d2d2d2; padding: 0.3em 0.5em; text-align: left; vertical-align: top; }
+th { background: #f0f0f0; }
+td.count { text-align: right; }
+.subtitle { color: #6a6e73; }
+.status { font-weight: bold; white-space: nowrap; }
+.pass { color: #3e8635; }
+.fail { color: #c9190b; }
+.error { color: #7d1007; }
+.review { color: #8f4700; }
+.neutral { color: #6a6e73; }
+.check { border: 1px solid #d2d2d2; border-left-width: 0.4em; padding: 0.5em 1em; margin: 1em 0; }
+.check-pass { border-left-color: #3e8635; }
+.check-fail { border-left-color: #c9190b; }
+.check-error { border-left-color: #7d1007; }
+.check-review { border-left-color: #8f4700; }
+.check-neutral { border-left-color: #d2d2d2; }
+.check dl { margin: 0; }
+.check dt { font-weight: bold; margin-top: 0.5em; }
+.check dd { margin: 0.2em 0 0 0; white-space: pre-line; }
+@media print {
+	@page { size: A4; margin: 15mm; }
+	body { margin: 0; max-width: none; font-size: 10pt; }
+	h2 { break-after: avoid; }
+	tr, .check { break-inside: avoid; }
+	thead { display: table-header-group; }
+}
+</style>
+</head>
+<body>
+<h1>{{ .Title }}</h1>
+{{- if .Generated }}
+<p class="subtitle">Results as of {{ .Generated }}</p>
+{{- end }}
+
+<h2>Scans</h2>
+<table>
+<thead><tr><th>Scan</th><th>Type</th><th>Profile</th><th>Result</th><th>Ended</th></tr></thead>
+<tbody>
+{{- range .Scans }}
+<tr><td>{{ .Name }}</td><td>{{ .Type }}</td><td>{{ .Profile }}</td><td>{{ .Result }}</td><td>{{ .Ended }}</td></tr>
+{{- end }}
+</tbody>
+</table>
+
+<h2>Summary by severity</h2>
+{{- if .Checks }}
+<table>
+<thead><tr><th>Severity</th>{{ range .Statuses }}<th>{{ . }}</th>{{ end }}<th>Total</th></tr></thead>
+<tbody>
+{{- range .Severities }}
+<tr><td>{{ .Name }}</td>{{ range .Counts }}<td class="count">{{ . }}</td>{{ end }}<td class="count">{{ .Total }}</td></tr>
+{{- end }}
+<tr><th>{{ .Total.Name }}</th>{{ range .Total.Counts }}<th class="count">{{ . }}</th>{{ end }}<th class="count">{{ .Total.Total }}</th></tr>
+</tbody>
+</table>
+{{- else }}
+<p>The scans have no results.</p>
+{{- end }}
+
+<h2>Summary by control</h2>
+{{- if .Controls }}
+<table>
+<thead><tr><th>Standard</th><th>Control</th><th>Status</th><th>Passed checks</th><th>Remarks</th></tr></thead>
+<tbody>
+{{- range .Controls }}
+<tr><td>{{ .Standard }}</td><td>{{ .ID }}</td><td class="status {{ .Class }}">{{ .Status }}</td><td class="count">{{ .Passed }} of {{ .Checks }}</td><td>{{ .Remarks }}</td></tr>
+{{- end }}
+</tbody>
+</table>
+{{- else }}
+<p>None of the rules of the checks map to controls.</p>
+{{- end }}
+
+<h2>Remediations</h2>
+{{- if .Remediations }}
+<table>
+<thead><tr><th>Remediation</th><th>Check</th><th>State</th><th>Details</th></tr></thead>
+<tbody>
+{{- range .Remediations }}
+<tr><td>{{ .Name }}</td><td>{{ .Check }}</td><td>{{ .State }}</td><td>{{ .Message }}</td></tr>
+{{- end }}
+</tbody>
+</table>
+{{- else }}
+<p>None of the checks have remediations.</p>
+{{- end }}
+
+<h2>Exceptions</h2>
+{{- if .Exceptions }}
+<table>
+<thead><tr><th>Exception</th><th>Rule</th><th>Justification</th><th>Approver</th><th>Expires</th><th>State</th><th>Excepted checks</th></tr></thead>
+<tbody>
+{{- range .Exceptions }}
+<tr><td>{{ .Name }}</td><td>{{ .Rule }}</td><td>{{ .Justification }}</td><td>{{ .Approver }}</td><td>{{ .Expires }}</td><td>{{ .State }}</td><td>{{ range $i, $c := .Checks }}{{ if $i }}, {{ end }}{{ $c }}{{ end }}</td></tr>
+{{- end }}
+</tbody>
+</table>
+{{- else }}
+<p>None of the checks are excepted.</p>
+{{- end }}
+
+<h2>Results of the checks</h2>
+{{- range .Checks }}
+<div class="check check-{{ .Class }}" id="{{ .Name }}">
+<h3>{{ .Title }}</h3>
+<p><span class="status {{ .Class }}">{{ .Status }}</span> &middot; {{ .Severity }} severity &middot; {{ .Name }}{{ if .Scan }} &middot; {{ .Scan }} scan{{ end }}</p>
+<dl>
+<dt>Rule</dt><dd>{{ .ID }}</dd>
+{{- if .Description }}
+<dt>Description</dt><dd>{{ .Description }}</dd>
+{{- end }}
+{{- if .Rationale }}
+<dt>Rationale</dt><dd>{{ .Rationale }}</dd>
+{{- end }}
+{{- if .Instructions }}
+<dt>Instructions</dt><dd>{{ .Instructions }}</dd>
+{{- end }}
+{{- if .Controls }}
+<dt>Controls</dt><dd>{{ range $i, $c := .Controls }}{{ if $i }}, {{ end }}{{ $c }}{{ end }}</dd>
+{{- end }}
+{{- if .Warnings }}
+<dt>Warnings</dt>{{ range .Warnings }}<dd>{{ . }}</dd>{{ end }}
+{{- end }}
+{{- if .Answer }}
+<dt>Answer</dt><dd>Answered by the {{ .Answer }} ComplianceCheckAnswer</dd>
+{{- end }}
+{{- if .Exception }}
+<dt>Exception</dt><dd>Excepted by the {{ .Exception }} ComplianceException{{ if .ExceptedStatus }}, the result was {{ .ExceptedStatus }}{{ end }}</dd>
+{{- end }}
+{{- if .Remediations }}
+<dt>Remediations</dt>{{ range .Remediations }}<dd>{{ .Name }}: {{ .State }}{{ if .Message }} ({{ .Message }}){{ end }}</dd>{{ end }}
+{{- end }}
+</dl>
+</div>
+{{- end }}
+</body>
+</html>
+`))
@@ -0,0 +1,214 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	cmpv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/utils"
+)
+
+func newRemediation(name string, check *cmpv1alpha1.ComplianceCheckResult, state cmpv1alpha1.RemediationApplicationState) cmpv1alpha1.ComplianceRemediation {
+	trueP := true
+	return cmpv1alpha1.ComplianceRemediation{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "openshift-compliance",
+			Labels:    map[string]string{cmpv1alpha1.ComplianceScanLabel: "ocp4-cis"},
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: "compliance.openshift.io/v1alpha1",
+					Kind:       "ComplianceCheckResult",
+					Name:       check.Name,
+					Controller: &trueP,
+				},
+			},
+		},
+		Status: cmpv1alpha1.ComplianceRemediationStatus{ApplicationState: state},
+	}
+}
+
+var _ = Describe("Generating HTML reports", func() {
+	var assessment *Assessment
+
+	BeforeEach(func() {
+		end := time.Date(2026, 10, 19, 1, 5, 0, 0, time.UTC)
+		checks := []cmpv1alpha1.ComplianceCheckResult{
+			newCheck("ocp4-cis-scc-limit", "xccdf_org.ssgproject.content_rule_scc_limit",
+				cmpv1alpha1.CheckResultPass, cmpv1alpha1.CheckResultSeverityMedium),
+			newCheck("ocp4-cis-audit-log-forwarding", "xccdf_org.ssgproject.content_rule_audit_log_forwarding",
+				cmpv1alpha1.CheckResultFail, cmpv1alpha1.CheckResultSeverityHigh),
+			newCheck("ocp4-cis-idp-is-configured", "xccdf_org.ssgproject.content_rule_idp_is_configured",
+				cmpv1alpha1.CheckResultManual, cmpv1alpha1.CheckResultSeverityMedium),
+			newCheck("ocp4-cis-kubeadmin-removed", "xccdf_org.ssgproject.content_rule_kubeadmin_removed",
+				cmpv1alpha1.CheckResultExcepted, cmpv1alpha1.CheckResultSeverityMedium),
+		}
+		for i := range checks {
+			checks[i].Annotations = map[string]string{
+				cmpv1alpha1.ComplianceCheckResultRuleAnnotation: ruleName(&checks[i]),
+			}
+		}
+		checks[3].Annotations[cmpv1alpha1.ComplianceCheckResultExceptionAnnotation] = "openshift-compliance/kubeadmin"
+		checks[3].Annotations[cmpv1alpha1.ComplianceCheckResultExceptedStatusAnnotation] = "FAIL"
+
+		assessment = &Assessment{
+			Suite: &cmpv1alpha1.ComplianceSuite{
+				ObjectMeta: metav1.ObjectMeta{Name: "cis", Namespace: "openshift-compliance"},
+			},
+			Scans: []cmpv1alpha1.ComplianceScan{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "ocp4-cis", Namespace: "openshift-compliance"},
+					Spec: cmpv1alpha1.ComplianceScanSpec{
+						ScanType: cmpv1alpha1.ScanTypePlatform,
+						Profile:  "xccdf_org.ssgproject.content_profile_cis",
+					},
+					Status: cmpv1alpha1.ComplianceScanStatus{
+						Result:       cmpv1alpha1.ResultNonCompliant,
+						EndTimestamp: &metav1.Time{Time: end},
+					},
+				},
+			},
+			Profiles: []cmpv1alpha1.Profile{
+				{
+					ProfilePayload: cmpv1alpha1.ProfilePayload{
+						ID:    "xccdf_org.ssgproject.content_profile_cis",
+						Title: "CIS Red Hat OpenShift Container Platform Benchmark",
+					},
+				},
+			},
+			Rules: []cmpv1alpha1.Rule{
+				newRule("ocp4-audit-log-forwarding", "audit-log-forwarding", "AU-4(1);AU-9(2)"),
+				newRule("ocp4-scc-limit", "scc-limit", "AC-6;AU-4(1)"),
+				newRule("ocp4-idp-is-configured", "idp-is-configured", "AC-2(a)"),
+			},
+			Checks: checks,
+			Remediations: []cmpv1alpha1.ComplianceRemediation{
+				newRemediation("ocp4-cis-audit-log-forwarding", &checks[1], cmpv1alpha1.RemediationError),
+			},
+			Exceptions: []cmpv1alpha1.ComplianceException{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "kubeadmin", Namespace: "openshift-compliance"},
+					Spec: cmpv1alpha1.ComplianceExceptionSpec{
+						Rule:          "ocp4-kubeadmin-removed",
+						Justification: "The kubeadmin user is removed after the installation",
+						Approver:      "security-team",
+						ExpiresAt:     metav1.NewTime(end.Add(30 * 24 * time.Hour)),
+					},
+					Status: cmpv1alpha1.ComplianceExceptionStatus{State: cmpv1alpha1.ComplianceExceptionStateActive},
+				},
+			},
+		}
+		assessment.Remediations[0].Status.ErrorMessage = "cannot apply the ClusterLogForwarder"
+	})
+
+	render := func() string {
+		var buf bytes.Buffer
+		Expect(RenderHTML(assessment, &buf)).To(Succeed())
+		return buf.String()
+	}
+
+	It("describes the scans of the suite", func() {
+		report := render()
+		Expect(report).To(ContainSubstring("<title>Compliance report of the cis ComplianceSuite</title>"))
+		Expect(report).To(ContainSubstring("Results as of Mon, 19 Oct 2026 01:05:00 UTC"))
+		Expect(report).To(ContainSubstring("<td>ocp4-cis</td><td>Platform</td><td>CIS Red Hat OpenShift Container Platform Benchmark</td><td>NON-COMPLIANT</td>"))
+	})
+
+	It("summarizes the checks by severity", func() {
+		report := render()
+		Expect(report).To(ContainSubstring("<th>Severity</th><th>FAIL</th><th>MANUAL</th><th>EXCEPTED</th><th>PASS</th><th>Total</th>"))
+		Expect(report).To(ContainSubstring(`<tr><td>high</td><td class="count">1</td><td class="count">0</td><td class="count">0</td><td class="count">0</td><td class="count">1</td></tr>`))
+		Expect(report).To(ContainSubstring(`<tr><td>medium</td><td class="count">0</td><td class="count">1</td><td class="count">1</td><td class="count">1</td><td class="count">3</td></tr>`))
+		Expect(report).ToNot(ContainSubstring("<td>low</td>"))
+	})
+
+	It("summarizes the checks by control", func() {
+		controls := reportControls(utils.GetRuleControls(assessment.Rules), (&Results{Checks: assessment.Checks}).sortedChecks())
+		Expect(controls).To(HaveLen(4))
+		Expect(controls[0]).To(Equal(htmlReportControl{
+			Standard: "NIST-800-53", ID: "AC-2(a)", Status: "Not satisfied",
+			Remarks: "Some of the checks need a manual review", Class: "review", Checks: 1,
+		}))
+		Expect(controls[1]).To(Equal(htmlReportControl{
+			Standard: "NIST-800-53", ID: "AC-6", Status: "Satisfied", Class: "pass", Passed: 1, Checks: 1,
+		}))
+		Expect(controls[2]).To(Equal(htmlReportControl{
+			Standard: "NIST-800-53", ID: "AU-4(1)", Status: "Not satisfied",
+			Remarks: "1 of the checks failed", Class: "fail", Passed: 1, Checks: 2,
+		}))
+		Expect(render()).To(ContainSubstring(`<td>AU-4(1)</td><td class="status fail">Not satisfied</td><td class="count">1 of 2</td>`))
+	})
+
+	It("details the checks, the ones that need attention first", func() {
+		report := render()
+		Expect(report).To(ContainSubstring("<h3>Title of xccdf_org.ssgproject.content_rule_audit_log_forwarding</h3>"))
+		Expect(report).To(ContainSubstring("<dt>Description</dt><dd>Description of xccdf_org.ssgproject.content_rule_audit_log_forwarding</dd>"))
+		Expect(report).To(ContainSubstring("<dt>Rationale</dt><dd>Rationale of xccdf_org.ssgproject.content_rule_audit_log_forwarding</dd>"))
+		Expect(report).To(ContainSubstring("<dt>Instructions</dt><dd>Instructions of xccdf_org.ssgproject.content_rule_audit_log_forwarding</dd>"))
+		Expect(report).To(ContainSubstring("<dt>Controls</dt><dd>NIST-800-53:AU-4(1), NIST-800-53:AU-9(2)</dd>"))
+
+		failed := strings.Index(report, `id="ocp4-cis-audit-log-forwarding"`)
+		manual := strings.Index(report, `id="ocp4-cis-idp-is-configured"`)
+		passed := strings.Index(report, `id="ocp4-cis-scc-limit"`)
+		Expect(failed).To(BeNumerically(">", 0))
+		Expect(failed).To(BeNumerically("<", manual))
+		Expect(manual).To(BeNumerically("<", passed))
+	})
+
+	It("reports the status of the remediations", func() {
+		report := render()
+		Expect(report).To(ContainSubstring("<tr><td>ocp4-cis-audit-log-forwarding</td><td>ocp4-cis-audit-log-forwarding</td><td>Error</td><td>cannot apply the ClusterLogForwarder</td></tr>"))
+		Expect(report).To(ContainSubstring("<dd>ocp4-cis-audit-log-forwarding: Error (cannot apply the ClusterLogForwarder)</dd>"))
+	})
+
+	It("reports the exceptions of the checks", func() {
+		report := render()
+		Expect(report).To(ContainSubstring("<td>kubeadmin</td><td>ocp4-kubeadmin-removed</td><td>The kubeadmin user is removed after the installation</td><td>security-team</td>"))
+		Expect(report).To(ContainSubstring("<td>ACTIVE</td><td>ocp4-cis-kubeadmin-removed</td>"))
+		Expect(report).To(ContainSubstring("Excepted by the openshift-compliance/kubeadmin ComplianceException, the result was FAIL"))
+	})
+
+	It("escapes the content of the checks", func() {
+		assessment.Checks[0].Rationale = "<script>alert(1)</script>"
+		report := render()
+		Expect(report).ToNot(ContainSubstring("<script>"))
+		Expect(report).To(ContainSubstring("&lt;script&gt;alert(1)&lt;/script&gt;"))
+	})
+
+	It("reads the remediations and exceptions of the checks of a scan", func() {
+		scan := assessment.Scans[0].DeepCopy()
+		excepted := assessment.Checks[3].DeepCopy()
+		missing := assessment.Checks[0].DeepCopy()
+		missing.Annotations[cmpv1alpha1.ComplianceCheckResultExceptionAnnotation] = "openshift-compliance/deleted"
+		otherScan := assessment.Checks[1].DeepCopy()
+		otherScan.Labels[cmpv1alpha1.ComplianceScanLabel] = "rhcos4-cis"
+		remediation := assessment.Remediations[0].DeepCopy()
+
+		scheme := runtime.NewScheme()
+		Expect(cmpv1alpha1.SchemeBuilder.AddToScheme(scheme)).To(Succeed())
+		client := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(
+			excepted, missing, otherScan, remediation, assessment.Exceptions[0].DeepCopy(),
+		).Build()
+
+		got, err := GetScanAssessment(context.TODO(), client, scan)
+		Expect(err).To(BeNil())
+		Expect(got.Suite).To(BeNil())
+		Expect(got.Scans).To(HaveLen(1))
+		Expect(got.Checks).To(HaveLen(2))
+		Expect(got.Remediations).To(HaveLen(1))
+		Expect(got.Exceptions).To(HaveLen(1))
+		Expect(got.Exceptions[0].Name).To(Equal("kubeadmin"))
+
+		var buf bytes.Buffer
+		Expect(RenderHTML(got, &buf)).To(Succeed())
+		Expect(buf.String()).To(ContainSubstring("<title>Compliance report of the ocp4-cis ComplianceScan</title>"))
+	})
+})
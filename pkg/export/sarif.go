@@ -0,0 +1,259 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	cmpv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+)
+
+const (
+	sarifVersion   = "2.1.0"
+	sarifSchemaURI = "https://json.schemastore.org/sarif-2.1.0.json"
+	toolInfoURI    = "https://github.com/ComplianceAsCode/compliance-operator"
+)
+
+// The subset of SARIF 2.1.0 the results are exported to
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool              sarifTool              `json:"tool"`
+	AutomationDetails sarifAutomationDetails `json:"automationDetails"`
+	Results           []sarifResult          `json:"results"`
+}
+
+type sarifAutomationDetails struct {
+	ID string `json:"id"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string              `json:"id"`
+	Name                 string              `json:"name"`
+	ShortDescription     sarifMessage        `json:"shortDescription"`
+	FullDescription      *sarifMessage       `json:"fullDescription,omitempty"`
+	Help                 *sarifMessage       `json:"help,omitempty"`
+	DefaultConfiguration sarifConfiguration  `json:"defaultConfiguration"`
+	Properties           sarifRuleProperties `json:"properties"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifRuleProperties struct {
+	Severity string `json:"severity"`
+	// The score code scanning tools rank the rules by
+	SecuritySeverity string              `json:"security-severity,omitempty"`
+	Rationale        string              `json:"rationale,omitempty"`
+	Controls         map[string][]string `json:"controls,omitempty"`
+	Tags             []string            `json:"tags,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID       string                `json:"ruleId"`
+	RuleIndex    int                   `json:"ruleIndex"`
+	Kind         string                `json:"kind"`
+	Level        string                `json:"level"`
+	Message      sarifMessage          `json:"message"`
+	Locations    []sarifLocation       `json:"locations"`
+	Suppressions []sarifSuppression    `json:"suppressions,omitempty"`
+	Properties   sarifResultProperties `json:"properties"`
+}
+
+type sarifLocation struct {
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Justification string `json:"justification"`
+}
+
+type sarifResultProperties struct {
+	Status      string                                  `json:"status"`
+	Scan        string                                  `json:"scan,omitempty"`
+	NodeResults []cmpv1alpha1.ComplianceCheckNodeResult `json:"nodeResults,omitempty"`
+}
+
+// renderSARIF writes the results as a SARIF log with a single run. Each
+// rule is described once, with its severity, instructions and controls, and
+// each check is a result of its rule.
+func renderSARIF(results *Results, w io.Writer) error {
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:           toolName,
+				InformationURI: toolInfoURI,
+				Rules:          []sarifRule{},
+			},
+		},
+		AutomationDetails: sarifAutomationDetails{ID: results.Name + "/"},
+		Results:           []sarifResult{},
+	}
+
+	ruleIndex := make(map[string]int)
+	for _, check := range results.sortedChecks() {
+		idx, ok := ruleIndex[check.ID]
+		if !ok {
+			idx = len(run.Tool.Driver.Rules)
+			ruleIndex[check.ID] = idx
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, newSARIFRule(check, results.controlsOf(check)))
+		}
+		run.Results = append(run.Results, newSARIFResult(check, idx, results.scanName(check)))
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{
+		Schema:  sarifSchemaURI,
+		Version: sarifVersion,
+		Runs:    []sarifRun{run},
+	})
+}
+
+func newSARIFRule(check *cmpv1alpha1.ComplianceCheckResult, controls map[string][]string) sarifRule {
+	rule := sarifRule{
+		ID:               check.ID,
+		Name:             ruleName(check),
+		ShortDescription: sarifMessage{Text: title(check)},
+		DefaultConfiguration: sarifConfiguration{
+			Level: sarifLevel(check.Severity),
+		},
+		Properties: sarifRuleProperties{
+			Severity:         string(check.Severity),
+			SecuritySeverity: sarifSecuritySeverity(check.Severity),
+			Rationale:        check.Rationale,
+			Controls:         controls,
+			Tags:             controlTags(controls),
+		},
+	}
+	if check.Description != "" {
+		rule.FullDescription = &sarifMessage{Text: check.Description}
+	}
+	if check.Instructions != "" {
+		rule.Help = &sarifMessage{Text: check.Instructions}
+	}
+	return rule
+}
+
+func newSARIFResult(check *cmpv1alpha1.ComplianceCheckResult, ruleIndex int, scan string) sarifResult {
+	kind := sarifKind(check.Status)
+	level := "none"
+	if kind == "fail" {
+		level = sarifLevel(check.Severity)
+	}
+
+	message := fmt.Sprintf("%s: %s", statusText(check.Status), title(check))
+	if len(check.Warnings) > 0 {
+		message = message + "\n" + strings.Join(check.Warnings, "\n")
+	}
+
+	result := sarifResult{
+		RuleID:    check.ID,
+		RuleIndex: ruleIndex,
+		Kind:      kind,
+		Level:     level,
+		Message:   sarifMessage{Text: message},
+		Locations: []sarifLocation{
+			{
+				LogicalLocations: []sarifLogicalLocation{
+					{
+						Name:               check.Name,
+						FullyQualifiedName: check.Namespace + "/" + check.Name,
+						Kind:               "resource",
+					},
+				},
+			},
+		},
+		Properties: sarifResultProperties{
+			Status:      statusText(check.Status),
+			Scan:        scan,
+			NodeResults: check.NodeResults,
+		},
+	}
+	if check.Status == cmpv1alpha1.CheckResultExcepted {
+		result.Suppressions = []sarifSuppression{
+			{
+				Kind:          "external",
+				Justification: "The failure is covered by an active ComplianceException",
+			},
+		}
+	}
+	return result
+}
+
+// sarifKind returns the kind of the SARIF result of a check
+func sarifKind(status cmpv1alpha1.ComplianceCheckStatus) string {
+	switch status {
+	case cmpv1alpha1.CheckResultPass:
+		return "pass"
+	case cmpv1alpha1.CheckResultFail, cmpv1alpha1.CheckResultExcepted:
+		return "fail"
+	case cmpv1alpha1.CheckResultInfo:
+		return "informational"
+	case cmpv1alpha1.CheckResultManual:
+		return "review"
+	case cmpv1alpha1.CheckResultNotApplicable:
+		return "notApplicable"
+	default:
+		// ERROR, INCONSISTENT or no result: whether the rule is met
+		// couldn't be determined
+		return "open"
+	}
+}
+
+// sarifLevel returns the level of a failed check of the severity
+func sarifLevel(severity cmpv1alpha1.ComplianceCheckResultSeverity) string {
+	switch severity {
+	case cmpv1alpha1.CheckResultSeverityHigh:
+		return "error"
+	case cmpv1alpha1.CheckResultSeverityMedium:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// sarifSecuritySeverity returns the score of the severity, as understood by
+// code scanning tools
+func sarifSecuritySeverity(severity cmpv1alpha1.ComplianceCheckResultSeverity) string {
+	switch severity {
+	case cmpv1alpha1.CheckResultSeverityHigh:
+		return "8.0"
+	case cmpv1alpha1.CheckResultSeverityMedium:
+		return "5.0"
+	case cmpv1alpha1.CheckResultSeverityLow:
+		return "2.0"
+	case cmpv1alpha1.CheckResultSeverityInfo:
+		return "0.0"
+	default:
+		return ""
+	}
+}
@@ -29,7 +29,7 @@ const (
 	machineConfigFixType  = "urn:xccdf:fix:script:ignition"
 	kubernetesFixType     = "urn:xccdf:fix:script:kubernetes"
 	valuePrefix           = "xccdf_org.ssgproject.content_value_"
-	controlAnnotationBase = "control.compliance.openshift.io/"
+	controlAnnotationBase = cmpv1alpha1.RuleControlAnnotationPrefix
 
 	rhacmStdsAnnotationKey   = "policies.open-cluster-management.io/standards"
 	rhacmCtrlsAnnotationsKey = "policies.open-cluster-management.io/controls"
@@ -42,6 +42,9 @@ type ParserConfig struct {
 	ProfileBundleKey types.NamespacedName
 	Client           runtimeclient.Client
 	Scheme           *k8sruntime.Scheme
+	// Standards optionally configures the compliance standards that
+	// are recognized in rule references
+	Standards *StandardsConfig
 }
 
 func LogAndReturnError(errormsg string) error {
@@ -54,12 +57,16 @@ func GetPrefixedName(pbName, objName string) string {
 }
 
 func ParseBundle(contentDom *xmlquery.Node, pb *cmpv1alpha1.ProfileBundle, pcfg *ParserConfig) error {
+	stdParser, err := newReferenceParser(pcfg.Standards)
+	if err != nil {
+		return err
+	}
+
 	// One go routine per type
 	errChan := make(chan error)
 	done := make(chan string)
 	var wg sync.WaitGroup
 	wg.Add(3)
-	stdParser := newStandardParser()
 	nonce := names.SimpleNameGenerator.GenerateName(fmt.Sprintf("pb-%s", pb.Name))
 	go func() {
 		profErr := ParseProfilesAndDo(contentDom, pb, nonce, func(p *cmpv1alpha1.Profile) error {
@@ -767,33 +774,11 @@ type referenceParser struct {
 }
 
 func newStandardParser() *referenceParser {
-	p := referenceParser{}
-	p.registeredStds = make([]*complianceStandard, 0)
-	p.annotationFormatters = make([]annotationsFormatterFn, 0)
-	err := p.registerStandard("NIST-800-53", `^http://nvlpubs\.nist\.gov/nistpubs/SpecialPublications/NIST\.SP\.800-53r4\.pdf$`)
+	p, err := newReferenceParser(nil)
 	if err != nil {
-		log.Error(err, "Could not register NIST-800-53 reference parser") // not much we can do here..
-	}
-	cocperr := p.registerStandard("CIS-OCP", `^https://www\.cisecurity\.org/benchmark/kubernetes/$`)
-	if cocperr != nil {
-		log.Error(cocperr, "Could not register CIS OpenShift reference parser") // not much we can do here..
+		log.Error(err, "Could not register the default reference parsers") // not much we can do here..
 	}
-	crherr := p.registerStandard("CIS-RHEL", `^https://www\.cisecurity\.org/benchmark/red_hat_linux/$`)
-	if crherr != nil {
-		log.Error(crherr, "Could not register CIS Red Hat Linux reference parser") // not much we can do here..
-	}
-	nciperr := p.registerStandard("NERC-CIP", `^https://www\.nerc\.com/pa/Stand/Standard%20Purpose%20Statement%20DL/US_Standard_One-Stop-Shop\.xlsx$`)
-	if nciperr != nil {
-		log.Error(nciperr, "Could not register NERC-CIP reference parser") // not much we can do here..
-	}
-	pcidssperr := p.registerStandard("PCI-DSS", `^https://www\.pcisecuritystandards\.org/documents/PCI_DSS_v3-2-1\.pdf$`)
-	if pcidssperr != nil {
-		log.Error(nciperr, "Could not register PCI-DSS reference parser") // not much we can do here..
-	}
-
-	p.registerFormatter(profileOperatorFormatter)
-	p.registerFormatter(rhacmFormatter)
-	return &p
+	return p
 }
 
 func (p *referenceParser) registerStandard(name, hrefRegexp string) error {
@@ -839,10 +824,9 @@ func (p *referenceParser) parseXmlNode(ruleObj *xmlquery.Node) (map[string]strin
 }
 
 func profileOperatorFormatter(annotations map[string]string, std, ctrl string) {
-	const poSep = ";"
 	key := controlAnnotationBase + std
 
-	appendKeyWithSep(annotations, key, ctrl, poSep)
+	appendKeyWithSep(annotations, key, ctrl, cmpv1alpha1.RuleControlSeparator)
 }
 
 func rhacmFormatter(annotations map[string]string, std, ctrl string) {
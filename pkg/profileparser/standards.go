@@ -0,0 +1,122 @@
+package profileparser
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// StandardsConfigMapKey is the key in the standards ConfigMap that holds
+	// the standards configuration
+	StandardsConfigMapKey = "standards.yaml"
+
+	// ProfileOperatorFormatterName is the name of the formatter that creates
+	// the control.compliance.openshift.io/<standard> annotations
+	ProfileOperatorFormatterName = "compliance-operator"
+	// RHACMFormatterName is the name of the formatter that creates the
+	// policies.open-cluster-management.io annotations
+	RHACMFormatterName = "rhacm"
+)
+
+// StandardDefinition maps the href of a rule reference to the name of
+// a compliance standard
+type StandardDefinition struct {
+	// Name of the standard as it will appear in the annotations
+	Name string `json:"name"`
+	// Regular expression matched against the href attribute of the
+	// XCCDF reference elements
+	HrefRegex string `json:"hrefRegex"`
+}
+
+// StandardsConfig configures which compliance standards are recognized
+// in rule references and how they are rendered into annotations.
+type StandardsConfig struct {
+	// If set, the built-in standards are not registered and only the
+	// standards listed in this configuration are used.
+	ReplaceDefaults bool `json:"replaceDefaults,omitempty"`
+	// Additional standards to register
+	Standards []StandardDefinition `json:"standards,omitempty"`
+	// Names of the annotation formatters to use. If empty, all known
+	// formatters are used.
+	Formatters []string `json:"formatters,omitempty"`
+}
+
+// defaultStandards are the standards that are always registered unless
+// the configuration replaces them
+var defaultStandards = []StandardDefinition{
+	{Name: "NIST-800-53", HrefRegex: `^http://nvlpubs\.nist\.gov/nistpubs/SpecialPublications/NIST\.SP\.800-53r4\.pdf$`},
+	{Name: "CIS-OCP", HrefRegex: `^https://www\.cisecurity\.org/benchmark/kubernetes/$`},
+	{Name: "CIS-RHEL", HrefRegex: `^https://www\.cisecurity\.org/benchmark/red_hat_linux/$`},
+	{Name: "NERC-CIP", HrefRegex: `^https://www\.nerc\.com/pa/Stand/Standard%20Purpose%20Statement%20DL/US_Standard_One-Stop-Shop\.xlsx$`},
+	{Name: "PCI-DSS", HrefRegex: `^https://www\.pcisecuritystandards\.org/documents/PCI_DSS_v3-2-1\.pdf$`},
+}
+
+var knownFormatters = map[string]annotationsFormatterFn{
+	ProfileOperatorFormatterName: profileOperatorFormatter,
+	RHACMFormatterName:           rhacmFormatter,
+}
+
+// ParseStandardsConfig parses the contents of the standards ConfigMap key
+func ParseStandardsConfig(data string) (*StandardsConfig, error) {
+	cfg := StandardsConfig{}
+	if err := yaml.UnmarshalStrict([]byte(data), &cfg); err != nil {
+		return nil, fmt.Errorf("couldn't parse standards configuration: %w", err)
+	}
+
+	for _, std := range cfg.Standards {
+		if std.Name == "" {
+			return nil, fmt.Errorf("standard with href regex '%s' has no name", std.HrefRegex)
+		}
+		if std.HrefRegex == "" {
+			return nil, fmt.Errorf("standard %s has no href regex", std.Name)
+		}
+	}
+
+	for _, name := range cfg.Formatters {
+		if _, ok := knownFormatters[name]; !ok {
+			return nil, fmt.Errorf("unknown annotation formatter: %s", name)
+		}
+	}
+
+	return &cfg, nil
+}
+
+// newReferenceParser creates a reference parser with the built-in standards
+// and formatters, amended by the given configuration. A nil configuration
+// yields the defaults.
+func newReferenceParser(cfg *StandardsConfig) (*referenceParser, error) {
+	if cfg == nil {
+		cfg = &StandardsConfig{}
+	}
+
+	p := referenceParser{}
+	p.registeredStds = make([]*complianceStandard, 0)
+	p.annotationFormatters = make([]annotationsFormatterFn, 0)
+
+	stds := cfg.Standards
+	if !cfg.ReplaceDefaults {
+		stds = append(append([]StandardDefinition{}, defaultStandards...), cfg.Standards...)
+	}
+
+	for _, std := range stds {
+		if err := p.registerStandard(std.Name, std.HrefRegex); err != nil {
+			return nil, fmt.Errorf("could not register %s reference parser: %w", std.Name, err)
+		}
+	}
+
+	formatters := cfg.Formatters
+	if len(formatters) == 0 {
+		formatters = []string{ProfileOperatorFormatterName, RHACMFormatterName}
+	}
+
+	for _, name := range formatters {
+		formatter, ok := knownFormatters[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown annotation formatter: %s", name)
+		}
+		p.registerFormatter(formatter)
+	}
+
+	return &p, nil
+}
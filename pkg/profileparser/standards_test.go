@@ -0,0 +1,111 @@
+package profileparser
+
+import (
+	"strings"
+
+	"github.com/antchfx/xmlquery"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const ruleWithReferences = `<xccdf-1.2:Benchmark xmlns:xccdf-1.2="http://checklists.nist.gov/xccdf/1.2">
+  <xccdf-1.2:Rule id="xccdf_org.ssgproject.content_rule_test">
+    <xccdf-1.2:reference href="http://nvlpubs.nist.gov/nistpubs/SpecialPublications/NIST.SP.800-53r4.pdf">AC-2</xccdf-1.2:reference>
+    <xccdf-1.2:reference href="https://www.iso.org/standard/54534.html">A.9.2.1</xccdf-1.2:reference>
+    <xccdf-1.2:reference href="https://www.iso.org/standard/54534.html">A.9.2.2</xccdf-1.2:reference>
+    <xccdf-1.2:reference href="https://public.cyber.mil/stigs/srg-stig-tools/">CNTR-OS-000010</xccdf-1.2:reference>
+  </xccdf-1.2:Rule>
+</xccdf-1.2:Benchmark>`
+
+var _ = Describe("Testing the standards configuration", func() {
+	var ruleNode *xmlquery.Node
+
+	BeforeEach(func() {
+		doc, err := xmlquery.Parse(strings.NewReader(ruleWithReferences))
+		Expect(err).To(BeNil())
+		ruleNode = xmlquery.FindOne(doc, "//xccdf-1.2:Rule")
+		Expect(ruleNode).ToNot(BeNil())
+	})
+
+	Context("Parsing the configuration", func() {
+		It("Parses a valid configuration", func() {
+			cfg, err := ParseStandardsConfig(`
+standards:
+  - name: ISO-27001
+    hrefRegex: '^https://www\.iso\.org/standard/54534\.html$'
+formatters:
+  - compliance-operator
+`)
+			Expect(err).To(BeNil())
+			Expect(cfg.ReplaceDefaults).To(BeFalse())
+			Expect(cfg.Standards).To(HaveLen(1))
+			Expect(cfg.Standards[0].Name).To(Equal("ISO-27001"))
+			Expect(cfg.Formatters).To(ConsistOf(ProfileOperatorFormatterName))
+		})
+
+		It("Rejects unknown formatters", func() {
+			_, err := ParseStandardsConfig(`formatters: ["nope"]`)
+			Expect(err).ToNot(BeNil())
+		})
+
+		It("Rejects standards without a name", func() {
+			_, err := ParseStandardsConfig(`standards: [{hrefRegex: "foo"}]`)
+			Expect(err).ToNot(BeNil())
+		})
+
+		It("Rejects unknown keys", func() {
+			_, err := ParseStandardsConfig(`standard: []`)
+			Expect(err).ToNot(BeNil())
+		})
+	})
+
+	Context("Creating the reference parser", func() {
+		It("Only annotates the default standards without a configuration", func() {
+			p, err := newReferenceParser(nil)
+			Expect(err).To(BeNil())
+			annotations, err := p.parseXmlNode(ruleNode)
+			Expect(err).To(BeNil())
+			Expect(annotations).To(HaveKeyWithValue(controlAnnotationBase+"NIST-800-53", "AC-2"))
+			Expect(annotations).To(HaveKeyWithValue(rhacmStdsAnnotationKey, "NIST-800-53"))
+			Expect(annotations).To(HaveLen(3))
+		})
+
+		It("Adds the configured standards to the defaults", func() {
+			p, err := newReferenceParser(&StandardsConfig{
+				Standards: []StandardDefinition{
+					{Name: "ISO-27001", HrefRegex: `^https://www\.iso\.org/standard/54534\.html$`},
+					{Name: "DISA-STIG", HrefRegex: `^https://public\.cyber\.mil/stigs/`},
+				},
+			})
+			Expect(err).To(BeNil())
+			annotations, err := p.parseXmlNode(ruleNode)
+			Expect(err).To(BeNil())
+			Expect(annotations).To(HaveKeyWithValue(controlAnnotationBase+"NIST-800-53", "AC-2"))
+			Expect(annotations).To(HaveKeyWithValue(controlAnnotationBase+"ISO-27001", "A.9.2.1;A.9.2.2"))
+			Expect(annotations).To(HaveKeyWithValue(controlAnnotationBase+"DISA-STIG", "CNTR-OS-000010"))
+			Expect(annotations).To(HaveKeyWithValue(rhacmStdsAnnotationKey, "NIST-800-53,ISO-27001,DISA-STIG"))
+		})
+
+		It("Replaces the defaults and uses only the selected formatters", func() {
+			p, err := newReferenceParser(&StandardsConfig{
+				ReplaceDefaults: true,
+				Standards: []StandardDefinition{
+					{Name: "ISO-27001", HrefRegex: `^https://www\.iso\.org/standard/54534\.html$`},
+				},
+				Formatters: []string{ProfileOperatorFormatterName},
+			})
+			Expect(err).To(BeNil())
+			annotations, err := p.parseXmlNode(ruleNode)
+			Expect(err).To(BeNil())
+			Expect(annotations).To(HaveLen(1))
+			Expect(annotations).To(HaveKeyWithValue(controlAnnotationBase+"ISO-27001", "A.9.2.1;A.9.2.2"))
+		})
+
+		It("Fails on an invalid regular expression", func() {
+			_, err := newReferenceParser(&StandardsConfig{
+				Standards: []StandardDefinition{{Name: "broken", HrefRegex: "("}},
+			})
+			Expect(err).ToNot(BeNil())
+		})
+	})
+})
@@ -0,0 +1,167 @@
+/*
+Copyright © 2026 Red Hat Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package requester records who approved, reviewed or answered something in
+// the compliance objects. The names given in those objects can't be trusted,
+// so a mutating webhook overwrites them with the authenticated user of the
+// request that created or changed the object.
+package requester
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+)
+
+// WebhookPath is the path the webhook is served on
+const WebhookPath = "/mutate-compliance-requester"
+
+// Resources are the resources whose requesters are recorded
+var Resources = []string{
+	"compliancecheckanswers",
+	"complianceexceptions",
+	"remediationapprovals",
+}
+
+// Handler is the mutating webhook that records the requesters
+type Handler struct {
+	decoder *admission.Decoder
+}
+
+// NewHandler returns a Handler that decodes the objects with the scheme
+func NewHandler(scheme *runtime.Scheme) *Handler {
+	return &Handler{decoder: admission.NewDecoder(scheme)}
+}
+
+// Handle records the user of the request in the objects it creates or changes
+func (h *Handler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return admission.Allowed("")
+	}
+	user := req.UserInfo.Username
+
+	var obj runtime.Object
+	switch req.Kind.Kind {
+	case "ComplianceException":
+		exc, old := &compv1alpha1.ComplianceException{}, &compv1alpha1.ComplianceException{}
+		updated, err := h.decode(req, exc, old)
+		if err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if !updated {
+			old = nil
+		}
+		setExceptionApprover(exc, old, user)
+		obj = exc
+	case "ComplianceCheckAnswer":
+		answer, old := &compv1alpha1.ComplianceCheckAnswer{}, &compv1alpha1.ComplianceCheckAnswer{}
+		updated, err := h.decode(req, answer, old)
+		if err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if !updated {
+			old = nil
+		}
+		setAnswerAuthor(answer, old, user)
+		obj = answer
+	case "RemediationApproval":
+		approval, old := &compv1alpha1.RemediationApproval{}, &compv1alpha1.RemediationApproval{}
+		updated, err := h.decode(req, approval, old)
+		if err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if !updated {
+			old = nil
+		}
+		setDecisionReviewers(approval, old, user)
+		obj = approval
+	default:
+		return admission.Allowed("")
+	}
+
+	marshaled, err := json.Marshal(obj)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaled)
+}
+
+// decode decodes the object of the request, and on updates the object it
+// replaces. It returns whether the request is an update.
+func (h *Handler) decode(req admission.Request, obj, old runtime.Object) (bool, error) {
+	if err := h.decoder.Decode(req, obj); err != nil {
+		return false, err
+	}
+	if req.Operation != admissionv1.Update {
+		return false, nil
+	}
+	return true, h.decoder.DecodeRaw(req.OldObject, old)
+}
+
+// setExceptionApprover makes the user the approver of the exception, unless
+// the update doesn't change anything but the approver, which then stays
+func setExceptionApprover(exc, old *compv1alpha1.ComplianceException, user string) {
+	if old != nil {
+		oldSpec := old.Spec.DeepCopy()
+		oldSpec.Approver = exc.Spec.Approver
+		if equality.Semantic.DeepEqual(*oldSpec, exc.Spec) {
+			exc.Spec.Approver = old.Spec.Approver
+			return
+		}
+	}
+	exc.Spec.Approver = user
+}
+
+// setAnswerAuthor makes the user the author of the answer, unless the
+// update doesn't change anything but the author, which then stays
+func setAnswerAuthor(answer, old *compv1alpha1.ComplianceCheckAnswer, user string) {
+	if old != nil {
+		oldSpec := old.Spec.DeepCopy()
+		oldSpec.Author = answer.Spec.Author
+		if equality.Semantic.DeepEqual(*oldSpec, answer.Spec) {
+			answer.Spec.Author = old.Spec.Author
+			return
+		}
+	}
+	answer.Spec.Author = user
+}
+
+// setDecisionReviewers makes the user the reviewer of the decisions that are
+// added or changed. The decisions that only have their reviewer changed keep
+// the previous one.
+func setDecisionReviewers(approval, old *compv1alpha1.RemediationApproval, user string) {
+	for i := range approval.Spec.Decisions {
+		decision := &approval.Spec.Decisions[i]
+		if old != nil {
+			if oldDecision := old.GetDecision(decision.Remediation); oldDecision != nil {
+				oldCopy := *oldDecision
+				oldCopy.Reviewer = decision.Reviewer
+				if oldCopy == *decision {
+					decision.Reviewer = oldDecision.Reviewer
+					continue
+				}
+			}
+		}
+		decision.Reviewer = user
+	}
+}
@@ -0,0 +1,185 @@
+package requester
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gomodules.xyz/jsonpatch/v2"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+)
+
+func newRequest(op admissionv1.Operation, kind string, obj, old runtime.Object) admission.Request {
+	req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Operation: op,
+		Kind:      metav1.GroupVersionKind{Group: "compliance.openshift.io", Version: "v1alpha1", Kind: kind},
+		UserInfo:  authenticationv1.UserInfo{Username: "alice"},
+	}}
+	raw, err := json.Marshal(obj)
+	Expect(err).To(BeNil())
+	req.Object.Raw = raw
+	if old != nil {
+		raw, err = json.Marshal(old)
+		Expect(err).To(BeNil())
+		req.OldObject.Raw = raw
+	}
+	return req
+}
+
+var _ = Describe("Recording the requesters", func() {
+	var handler *Handler
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(compv1alpha1.SchemeBuilder.AddToScheme(scheme)).To(Succeed())
+		handler = NewHandler(scheme)
+	})
+
+	Context("of ComplianceExceptions", func() {
+		var exc *compv1alpha1.ComplianceException
+
+		BeforeEach(func() {
+			exc = &compv1alpha1.ComplianceException{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "compliance.openshift.io/v1alpha1", Kind: "ComplianceException"},
+				ObjectMeta: metav1.ObjectMeta{Name: "waiver", Namespace: "openshift-compliance"},
+				Spec: compv1alpha1.ComplianceExceptionSpec{
+					Rule:          "ocp4-kubeadmin-removed",
+					Justification: "The kubeadmin user is removed after the installation",
+					Approver:      "security-team",
+				},
+			}
+		})
+
+		It("makes the creator the approver", func() {
+			resp := handler.Handle(context.TODO(), newRequest(admissionv1.Create, "ComplianceException", exc, nil))
+			Expect(resp.Allowed).To(BeTrue())
+			Expect(resp.Patches).To(ConsistOf(jsonpatch.Operation{Operation: "replace", Path: "/spec/approver", Value: "alice"}))
+		})
+
+		It("makes the user who changes the exception the approver", func() {
+			old := exc.DeepCopy()
+			old.Spec.Approver = "bob"
+			exc.Spec.Justification = "The kubeadmin user is removed by the installer"
+			resp := handler.Handle(context.TODO(), newRequest(admissionv1.Update, "ComplianceException", exc, old))
+			Expect(resp.Allowed).To(BeTrue())
+			Expect(resp.Patches).To(ConsistOf(jsonpatch.Operation{Operation: "replace", Path: "/spec/approver", Value: "alice"}))
+		})
+
+		It("keeps the approver if only the approver is changed", func() {
+			old := exc.DeepCopy()
+			old.Spec.Approver = "bob"
+			resp := handler.Handle(context.TODO(), newRequest(admissionv1.Update, "ComplianceException", exc, old))
+			Expect(resp.Allowed).To(BeTrue())
+			Expect(resp.Patches).To(ConsistOf(jsonpatch.Operation{Operation: "replace", Path: "/spec/approver", Value: "bob"}))
+		})
+
+		It("keeps the approver if the spec isn't changed", func() {
+			old := exc.DeepCopy()
+			exc.Finalizers = []string{compv1alpha1.ComplianceExceptionFinalizer}
+			resp := handler.Handle(context.TODO(), newRequest(admissionv1.Update, "ComplianceException", exc, old))
+			Expect(resp.Allowed).To(BeTrue())
+			Expect(resp.Patches).To(BeEmpty())
+		})
+	})
+
+	Context("of ComplianceCheckAnswers", func() {
+		var answer *compv1alpha1.ComplianceCheckAnswer
+
+		BeforeEach(func() {
+			answer = &compv1alpha1.ComplianceCheckAnswer{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "compliance.openshift.io/v1alpha1", Kind: "ComplianceCheckAnswer"},
+				ObjectMeta: metav1.ObjectMeta{Name: "idp-answer", Namespace: "openshift-compliance"},
+				Spec: compv1alpha1.ComplianceCheckAnswerSpec{
+					Rule:     "ocp4-idp-is-configured",
+					Answer:   compv1alpha1.CheckResultPass,
+					Evidence: "The cluster authenticates users against LDAP",
+					Author:   "security-team",
+				},
+			}
+		})
+
+		It("makes the creator the author", func() {
+			resp := handler.Handle(context.TODO(), newRequest(admissionv1.Create, "ComplianceCheckAnswer", answer, nil))
+			Expect(resp.Allowed).To(BeTrue())
+			Expect(resp.Patches).To(ConsistOf(jsonpatch.Operation{Operation: "replace", Path: "/spec/author", Value: "alice"}))
+		})
+
+		It("makes the user who changes the answer the author", func() {
+			old := answer.DeepCopy()
+			old.Spec.Author = "bob"
+			answer.Spec.Answer = compv1alpha1.CheckResultFail
+			resp := handler.Handle(context.TODO(), newRequest(admissionv1.Update, "ComplianceCheckAnswer", answer, old))
+			Expect(resp.Allowed).To(BeTrue())
+			Expect(resp.Patches).To(ConsistOf(jsonpatch.Operation{Operation: "replace", Path: "/spec/author", Value: "alice"}))
+		})
+
+		It("keeps the author if only the author is changed", func() {
+			old := answer.DeepCopy()
+			old.Spec.Author = "bob"
+			resp := handler.Handle(context.TODO(), newRequest(admissionv1.Update, "ComplianceCheckAnswer", answer, old))
+			Expect(resp.Allowed).To(BeTrue())
+			Expect(resp.Patches).To(ConsistOf(jsonpatch.Operation{Operation: "replace", Path: "/spec/author", Value: "bob"}))
+		})
+	})
+
+	Context("of RemediationApprovals", func() {
+		var approval *compv1alpha1.RemediationApproval
+
+		BeforeEach(func() {
+			approval = &compv1alpha1.RemediationApproval{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "compliance.openshift.io/v1alpha1", Kind: "RemediationApproval"},
+				ObjectMeta: metav1.ObjectMeta{Name: "e8-review", Namespace: "openshift-compliance"},
+				Spec: compv1alpha1.RemediationApprovalSpec{
+					Suite: "e8",
+					Decisions: []compv1alpha1.RemediationDecision{{
+						Remediation: "e8-api-server-audit-log-path",
+						Decision:    compv1alpha1.RemediationDecisionApproved,
+						Reviewer:    "bob",
+					}},
+				},
+			}
+		})
+
+		It("makes the creator the reviewer of the decisions", func() {
+			resp := handler.Handle(context.TODO(), newRequest(admissionv1.Create, "RemediationApproval", approval, nil))
+			Expect(resp.Allowed).To(BeTrue())
+			Expect(resp.Patches).To(ConsistOf(jsonpatch.Operation{Operation: "replace", Path: "/spec/decisions/0/reviewer", Value: "alice"}))
+		})
+
+		It("only makes the user the reviewer of the decisions it adds or changes", func() {
+			old := approval.DeepCopy()
+			approval.Spec.Decisions = append(approval.Spec.Decisions, compv1alpha1.RemediationDecision{
+				Remediation: "e8-sysctl-kernel-kptr-restrict",
+				Decision:    compv1alpha1.RemediationDecisionRejected,
+				Reviewer:    "bob",
+			})
+			resp := handler.Handle(context.TODO(), newRequest(admissionv1.Update, "RemediationApproval", approval, old))
+			Expect(resp.Allowed).To(BeTrue())
+			Expect(resp.Patches).To(ConsistOf(jsonpatch.Operation{Operation: "replace", Path: "/spec/decisions/1/reviewer", Value: "alice"}))
+		})
+
+		It("keeps the reviewer if only the reviewer is changed", func() {
+			old := approval.DeepCopy()
+			approval.Spec.Decisions[0].Reviewer = "carol"
+			resp := handler.Handle(context.TODO(), newRequest(admissionv1.Update, "RemediationApproval", approval, old))
+			Expect(resp.Allowed).To(BeTrue())
+			Expect(resp.Patches).To(ConsistOf(jsonpatch.Operation{Operation: "replace", Path: "/spec/decisions/0/reviewer", Value: "bob"}))
+		})
+	})
+
+	It("ignores the deletions", func() {
+		resp := handler.Handle(context.TODO(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: admissionv1.Delete,
+			Kind:      metav1.GroupVersionKind{Kind: "ComplianceException"},
+		}})
+		Expect(resp.Allowed).To(BeTrue())
+		Expect(resp.Patches).To(BeEmpty())
+	})
+})
@@ -0,0 +1,198 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+)
+
+// CustomRulesFile is the key of the tailoring ConfigMap that holds the
+// CustomRules of the tailored profile
+const CustomRulesFile = "custom-rules.json"
+
+// CustomRulesResultKey is the key of the result ConfigMap that holds the
+// results of the CustomRules checked by a platform scan
+const CustomRulesResultKey = "custom-rules"
+
+// The IDs of the CustomRules are prefixed, so their results can't be
+// mistaken for the results of the rules of a data stream
+const customRuleIDPrefix = "custom_rule_"
+
+// Bounds the cost of evaluating an expression, so that an expression that
+// iterates over big lists can't hang the scan
+const customRuleCostLimit = 1000000
+
+// The exit statuses of a probe that aren't an ERROR
+const (
+	customRuleProbePass          = 0
+	customRuleProbeFail          = 1
+	customRuleProbeNotApplicable = 2
+)
+
+// ValidateCustomRule checks that the rule can be checked: it either has a
+// probe, or inputs and an expression that compiles
+func ValidateCustomRule(rule *compv1alpha1.CustomRule) error {
+	if rule.Spec.Probe != nil {
+		if len(rule.Spec.Inputs) > 0 || rule.Spec.Expression != "" {
+			return errors.New("a rule with a probe can't have inputs or an expression")
+		}
+		if strings.TrimSpace(rule.Spec.Probe.Script) == "" {
+			return errors.New("the script of the probe is empty")
+		}
+		return nil
+	}
+
+	if len(rule.Spec.Inputs) == 0 || rule.Spec.Expression == "" {
+		return errors.New("the rule needs either inputs and an expression, or a probe")
+	}
+	_, err := CompileCustomRule(rule)
+	return err
+}
+
+// CustomRuleProbeStatus returns the status of a check whose probe exited
+// with the given status
+func CustomRuleProbeStatus(exitStatus int) compv1alpha1.ComplianceCheckStatus {
+	switch exitStatus {
+	case customRuleProbePass:
+		return compv1alpha1.CheckResultPass
+	case customRuleProbeFail:
+		return compv1alpha1.CheckResultFail
+	case customRuleProbeNotApplicable:
+		return compv1alpha1.CheckResultNotApplicable
+	default:
+		return compv1alpha1.CheckResultError
+	}
+}
+
+// CustomRuleProbesRunAsRoot returns whether any of the CustomRules a
+// tailoring ConfigMap holds has a probe that runs as root
+func CustomRuleProbesRunAsRoot(data string) (bool, error) {
+	if data == "" {
+		return false, nil
+	}
+	var rules []compv1alpha1.CustomRule
+	if err := json.Unmarshal([]byte(data), &rules); err != nil {
+		return false, fmt.Errorf("cannot parse the custom rules: %w", err)
+	}
+	for i := range rules {
+		if rules[i].Spec.Probe != nil && rules[i].Spec.Probe.RunAsRoot {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// CompileCustomRule checks the expression of the rule and returns the
+// program that evaluates it
+func CompileCustomRule(rule *compv1alpha1.CustomRule) (cel.Program, error) {
+	opts := []cel.EnvOption{ext.Strings()}
+	for _, input := range rule.Spec.Inputs {
+		opts = append(opts, cel.Variable(input.Name, cel.DynType))
+	}
+	env, err := cel.NewEnv(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	ast, issues := env.Compile(rule.Spec.Expression)
+	if issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return nil, fmt.Errorf("the expression must return a bool, not %s", ast.OutputType())
+	}
+	return env.Program(ast, cel.CostLimit(customRuleCostLimit))
+}
+
+// EvaluateCustomRule evaluates the expression of the rule against the
+// resources of its inputs, keyed by the name of the input. A resource that
+// doesn't exist is null.
+func EvaluateCustomRule(rule *compv1alpha1.CustomRule, resources map[string]interface{}) (compv1alpha1.ComplianceCheckStatus, error) {
+	prg, err := CompileCustomRule(rule)
+	if err != nil {
+		return compv1alpha1.CheckResultError, fmt.Errorf("cannot compile the expression: %w", err)
+	}
+
+	vars := make(map[string]interface{}, len(rule.Spec.Inputs))
+	for _, input := range rule.Spec.Inputs {
+		vars[input.Name] = resources[input.Name]
+	}
+	out, _, err := prg.Eval(vars)
+	if err != nil {
+		return compv1alpha1.CheckResultError, fmt.Errorf("cannot evaluate the expression: %w", err)
+	}
+	pass, ok := out.Value().(bool)
+	if !ok {
+		return compv1alpha1.CheckResultError, fmt.Errorf("the expression returned %v instead of a bool", out.Value())
+	}
+	if pass {
+		return compv1alpha1.CheckResultPass, nil
+	}
+	return compv1alpha1.CheckResultFail, nil
+}
+
+// NewCustomRuleCheckResult returns the result of checking a CustomRule. The
+// warning, if any, is added to the warnings of the result. The result is
+// named once the scan it belongs to is known.
+func NewCustomRuleCheckResult(rule *compv1alpha1.CustomRule, status compv1alpha1.ComplianceCheckStatus, warning string) *compv1alpha1.ComplianceCheckResult {
+	severity := rule.Spec.Severity
+	if severity == "" {
+		severity = compv1alpha1.CheckResultSeverityMedium
+	}
+	description := rule.Spec.Title
+	if rule.Spec.Description != "" {
+		description = description + "\n" + rule.Spec.Description
+	}
+
+	var warnings []string
+	if status == compv1alpha1.CheckResultFail && rule.Spec.FailureMessage != "" {
+		warnings = append(warnings, rule.Spec.FailureMessage)
+	}
+	if warning != "" {
+		warnings = append(warnings, warning)
+	}
+
+	return &compv1alpha1.ComplianceCheckResult{
+		ID:           customRuleIDPrefix + rule.Name,
+		Status:       status,
+		Severity:     severity,
+		Description:  description,
+		Rationale:    rule.Spec.Rationale,
+		Instructions: rule.Spec.Instructions,
+		Warnings:     warnings,
+	}
+}
+
+// ParseCustomRuleResults returns the results of the CustomRules a scan
+// stored in its result ConfigMap
+func ParseCustomRuleResults(scanName, namespace, data string) ([]*ParseResult, error) {
+	if data == "" {
+		return nil, nil
+	}
+
+	var checks []compv1alpha1.ComplianceCheckResult
+	if err := json.Unmarshal([]byte(data), &checks); err != nil {
+		return nil, fmt.Errorf("cannot parse the results of the custom rules: %w", err)
+	}
+
+	results := make([]*ParseResult, 0, len(checks))
+	for i := range checks {
+		check := &checks[i]
+		check.ObjectMeta = v1.ObjectMeta{
+			Name:      nameFromId(scanName, check.ID),
+			Namespace: namespace,
+		}
+		results = append(results, &ParseResult{
+			Id:          check.ID,
+			CheckResult: check,
+		})
+	}
+	return results, nil
+}
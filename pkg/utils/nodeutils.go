@@ -39,6 +39,9 @@ const (
 	generatedKubeletSuffix = "kubelet"
 	mcPayloadPrefix        = `data:text/plain,`
 	mcBase64PayloadPrefix  = `data:text/plain;charset=utf-8;base64,`
+	// OperatorKubeletConfigPrefix prefixes the name of the KubeletConfig the
+	// operator creates for a pool that doesn't use a custom KubeletConfig
+	OperatorKubeletConfigPrefix = "compliance-operator-kubelet-"
 )
 
 var nodeSizingEnvList = [2]string{"autoSizingReserved", "systemReserved"}
@@ -143,6 +146,27 @@ func IsMcfgPoolUsingKC(pool *mcfgv1.MachineConfigPool) (bool, string, error) {
 	return true, currentKCMC, nil
 }
 
+// GetKCNameForPool returns the name of the KubeletConfig that the KubeletConfig
+// remediations for a pool are merged into
+func GetKCNameForPool(pool *mcfgv1.MachineConfigPool, client runtimeclient.Client) (string, error) {
+	isUsingKC, currentKCMCName, err := IsMcfgPoolUsingKC(pool)
+	if err != nil {
+		return "", err
+	}
+	if !isUsingKC {
+		return OperatorKubeletConfigPrefix + pool.GetName(), nil
+	}
+	kcmcfg := &mcfgv1.MachineConfig{}
+	if err := client.Get(context.TODO(), types.NamespacedName{Name: currentKCMCName}, kcmcfg); err != nil {
+		return "", fmt.Errorf("couldn't get current generated KubeletConfig MC: %w", err)
+	}
+	kc, err := GetKCFromMC(kcmcfg, client)
+	if err != nil {
+		return "", fmt.Errorf("couldn't get kubelet config from machine config: %w", err)
+	}
+	return kc.GetName(), nil
+}
+
 func AreKubeletConfigsRendered(pool *mcfgv1.MachineConfigPool, client runtimeclient.Client) (bool, error, string) {
 	// find out if pool is using a custom kubelet config
 	isUsingKC, currentKCMCName, err := IsMcfgPoolUsingKC(pool)
@@ -2,17 +2,20 @@ package utils
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/url"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 	"text/template"
 	"text/template/parse"
 
 	"github.com/antchfx/xmlquery"
 	"github.com/pkg/errors"
+	"golang.org/x/net/html/charset"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -54,13 +57,25 @@ const (
 	ocpVersionAnnotationKey = "complianceascode.io/ocp-version"
 	// Establishes that a remediation is optional; thus errors applying won't be reflected
 	optionalAnnotationKey = "complianceascode.io/optional"
+	// Establishes that a remediation patches an existing object; could be json or strategic-merge
+	patchTypeAnnotationKey = "complianceascode.io/patch-type"
 	// Establishes the type of remediation; could be enforcement or configuration
 	remediationTypeAnnotationKey = "complianceascode.io/remediation-type"
+	// Establishes the order in which the objects of a fix are applied
+	remediationOrderAnnotationKey = "complianceascode.io/remediation-order"
 	// Establishes that a remediation needs a value to be defined
 	valueInputRequiredAnnotationKey = "complianceascode.io/value-input-required"
 )
 
 // Constants useful for parsing warnings
+// The values of the patch type annotation
+const (
+	jsonPatchType           = "json"
+	strategicMergePatchType = "strategic-merge"
+	// The top-level key of a json patch remediation that holds the operations
+	jsonPatchOperationsKey = "jsonPatch"
+)
+
 const (
 	endPointTag              = "ocp-api-endpoint"
 	hideTag                  = "ocp-hide-rule"
@@ -464,66 +479,126 @@ func ParseContent(dsReader io.Reader) (*xmlquery.Node, error) {
 	return dsDom, nil
 }
 
+// DataStreamTables are the lookup tables of a data stream that the results
+// of a scan are parsed with. They are built once and can be used to parse
+// the results of any number of scans of the data stream.
+type DataStreamTables struct {
+	rules        NodeByIdHashTable
+	questions    NodeByIdHashTable
+	defs         NodeByIdHashTable
+	ovalTestVars nodeByIdHashVariablesTable
+}
+
+// NewDataStreamTables builds the lookup tables of the data stream
+func NewDataStreamTables(dsDom *xmlquery.Node) *DataStreamTables {
+	statesTable := newStateHashTable(dsDom)
+	objsTable := newObjHashTable(dsDom)
+	return &DataStreamTables{
+		rules:        newRuleHashTable(dsDom),
+		questions:    NewOcilQuestionTable(dsDom),
+		defs:         NewDefHashTable(dsDom),
+		ovalTestVars: newValueListTable(dsDom, statesTable, objsTable),
+	}
+}
+
+// ParseResultsFromContentAndXccdf parses all the results of a scan and
+// returns them in a list. Use StreamResultsFromContentAndXccdf to avoid
+// holding all of them in memory.
 func ParseResultsFromContentAndXccdf(scheme *runtime.Scheme, scanName string, namespace string,
 	dsDom *xmlquery.Node, resultsReader io.Reader, manualRules []string) ([]*ParseResult, error) {
+	parsedResults := make([]*ParseResult, 0)
+	err := StreamResultsFromContentAndXccdf(scheme, scanName, namespace, NewDataStreamTables(dsDom), resultsReader, manualRules,
+		func(pr *ParseResult) error {
+			parsedResults = append(parsedResults, pr)
+			return nil
+		})
+	return parsedResults, err
+}
 
-	resultsDom, err := xmlquery.Parse(resultsReader)
+// StreamResultsFromContentAndXccdf parses the results of a scan one rule
+// result at a time and passes each parsed result to handle. Only the rule
+// result being parsed is kept in memory. The values the scan was run with
+// precede the rule results in the XCCDF results, so they are all known by
+// the time the first rule result is parsed.
+//
+// An error returned by handle stops the parsing. Errors rendering
+// remediations don't, and are returned once all the results were parsed.
+func StreamResultsFromContentAndXccdf(scheme *runtime.Scheme, scanName string, namespace string,
+	tables *DataStreamTables, resultsReader io.Reader, manualRules []string, handle func(*ParseResult) error) error {
+
+	// The stream parser forgets the namespaces declared on the elements it
+	// already returned, so the decoder isn't strict about them
+	sp, err := xmlquery.CreateStreamParserWithOptions(resultsReader, xmlquery.ParserOptions{
+		Decoder: &xmlquery.DecoderOptions{
+			Strict:        false,
+			CharsetReader: charset.NewReaderLabel,
+		},
+	}, "//set-value | //rule-result")
 	if err != nil {
-		return nil, err
-	}
-	allValues := xmlquery.Find(resultsDom, "//set-value")
-	valuesList := make(map[string]string)
-
-	for _, codeNode := range allValues {
-		valuesList[strings.TrimPrefix(codeNode.SelectAttr("idref"), valuePrefix)] = codeNode.InnerText()
+		return err
 	}
 
-	ruleTable := newRuleHashTable(dsDom)
-	questionsTable := NewOcilQuestionTable(dsDom)
-	statesTable := newStateHashTable(dsDom)
-	objsTable := newObjHashTable(dsDom)
-	defTable := NewDefHashTable(dsDom)
-	ovalTestVarTable := newValueListTable(dsDom, statesTable, objsTable)
-	results := resultsDom.SelectElements("//rule-result")
-	parsedResults := make([]*ParseResult, 0)
+	valuesList := make(map[string]string)
 	var remErrs string
 
-	for i := range results {
-		result := results[i]
-		ruleIDRef := result.SelectAttr("idref")
-		if ruleIDRef == "" {
-			continue
+	for {
+		node, err := sp.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
 		}
 
-		resultRule := ruleTable[ruleIDRef]
-		if resultRule == nil {
+		if node.Data == "set-value" {
+			valuesList[strings.TrimPrefix(node.SelectAttr("idref"), valuePrefix)] = node.InnerText()
 			continue
 		}
 
-		instructions, _ := GetInstructionsForRule(resultRule, questionsTable, valuesList)
-		ruleValues := getValueListUsedForRule(resultRule, ovalTestVarTable, defTable, questionsTable, valuesList)
-		resCheck, err := newComplianceCheckResult(result, resultRule, ruleIDRef, instructions, scanName, namespace, ruleValues, manualRules, valuesList)
+		pr, err := parseRuleResult(scheme, scanName, namespace, tables, node, manualRules, valuesList)
 		if err != nil {
+			remErrs = "CheckID." + pr.Id + err.Error() + "\n"
+		}
+		if pr == nil {
 			continue
 		}
-
-		if resCheck != nil {
-			pr := &ParseResult{
-				Id:          ruleIDRef,
-				CheckResult: resCheck,
-			}
-			pr.Remediations, err = newComplianceRemediation(scheme, scanName, namespace, resultRule, valuesList)
-			if err != nil {
-				remErrs = "CheckID." + ruleIDRef + err.Error() + "\n"
-			}
-			parsedResults = append(parsedResults, pr)
+		if err := handle(pr); err != nil {
+			return err
 		}
 	}
 	if remErrs != "" {
-		return parsedResults, errors.New(remErrs)
+		return errors.New(remErrs)
+	}
+	return nil
+}
+
+// parseRuleResult parses a single rule result. It returns nil if the rule
+// result isn't usable, and the parsed result along with an error if the
+// remediations of the rule couldn't be rendered.
+func parseRuleResult(scheme *runtime.Scheme, scanName, namespace string, tables *DataStreamTables,
+	result *xmlquery.Node, manualRules []string, valuesList map[string]string) (*ParseResult, error) {
+	ruleIDRef := result.SelectAttr("idref")
+	if ruleIDRef == "" {
+		return nil, nil
 	}
-	return parsedResults, nil
 
+	resultRule := tables.rules[ruleIDRef]
+	if resultRule == nil {
+		return nil, nil
+	}
+
+	instructions, _ := GetInstructionsForRule(resultRule, tables.questions, valuesList)
+	ruleValues := getValueListUsedForRule(resultRule, tables.ovalTestVars, tables.defs, tables.questions, valuesList)
+	resCheck, err := newComplianceCheckResult(result, resultRule, ruleIDRef, instructions, scanName, namespace, ruleValues, manualRules, valuesList)
+	if err != nil || resCheck == nil {
+		return nil, nil
+	}
+
+	pr := &ParseResult{
+		Id:          ruleIDRef,
+		CheckResult: resCheck,
+	}
+	pr.Remediations, err = newComplianceRemediation(scheme, scanName, namespace, resultRule, valuesList)
+	return pr, err
 }
 
 // Returns a new complianceCheckResult if the check data is usable
@@ -737,13 +812,37 @@ func mapComplianceCheckResultStatus(result *xmlquery.Node) (compv1alpha1.Complia
 func newComplianceRemediation(scheme *runtime.Scheme, scanName, namespace string, rule *xmlquery.Node, resultValues map[string]string) ([]*compv1alpha1.ComplianceRemediation, error) {
 	for _, fix := range rule.SelectElements("//xccdf-1.2:fix") {
 		if isRelevantFix(fix) {
-			return remediationFromFixElement(scheme, fix, scanName, namespace, resultValues)
+			rems, err := remediationFromFixElement(scheme, fix, scanName, namespace, resultValues)
+			if err != nil {
+				return nil, err
+			}
+			annotateDisruption(rems, fix, rule)
+			return rems, nil
 		}
 	}
 
 	return nil, nil
 }
 
+// annotateDisruption records the disruption of the fix and the severity of
+// the rule on the remediations, so that the suite can tell which ones it
+// applies automatically
+func annotateDisruption(rems []*compv1alpha1.ComplianceRemediation, fix, rule *xmlquery.Node) {
+	disruption := fix.SelectAttr("disruption")
+	severity, _ := mapComplianceCheckResultSeverity(rule)
+	for _, rem := range rems {
+		if rem.Annotations == nil {
+			rem.Annotations = make(map[string]string)
+		}
+		if disruption != "" {
+			rem.Annotations[compv1alpha1.RemediationDisruptionAnnotation] = disruption
+		}
+		if severity != "" {
+			rem.Annotations[compv1alpha1.RemediationSeverityAnnotation] = string(severity)
+		}
+	}
+}
+
 func isRelevantFix(fix *xmlquery.Node) bool {
 	if fix.SelectAttr("system") == machineConfigFixType {
 		return true
@@ -793,7 +892,8 @@ func remediationsFromString(scheme *runtime.Scheme, name string, namespace strin
 			annotations = handleValueUsed(valuesUsedList, annotations)
 		}
 
-		if hasValueRequiredAnnotation(obj) {
+		valueRequired := hasValueRequiredAnnotation(obj)
+		if valueRequired {
 			if (len(notFoundValueList) == 0) && (len(valuesUsedList) == 0) {
 				return nil, errors.New("do not have any parsed xccdf variable, shoudn't any required values")
 			} else {
@@ -817,6 +917,15 @@ func remediationsFromString(scheme *runtime.Scheme, name string, namespace strin
 			annotations = handleVersionDependencyAnnotation(obj, annotations)
 		}
 
+		// The objects of a fix are applied as a group
+		if len(objs) > 1 {
+			var orderErr error
+			annotations, orderErr = handleRemediationOrderAnnotation(obj, idx, annotations)
+			if orderErr != nil {
+				return nil, orderErr
+			}
+		}
+
 		remType := compv1alpha1.ConfigurationRemediation
 		if hasTypeAnnotation(obj) {
 			remType = handleRemediationTypeAnnotation(obj)
@@ -827,6 +936,20 @@ func remediationsFromString(scheme *runtime.Scheme, name string, namespace strin
 			annotations = handleEnforcementTypeAnnotation(obj, annotations)
 		}
 
+		payload := compv1alpha1.ComplianceRemediationPayload{
+			Object: obj,
+		}
+		if hasPatchTypeAnnotation(obj) {
+			if err := handlePatchTypeAnnotation(obj, &payload); err != nil {
+				return nil, err
+			}
+		}
+		// Keep the fix around so that the object can be rendered again once
+		// the values that are missing or need user input are provided
+		if len(notFoundValueList) > 0 || valueRequired {
+			payload.Template = newRemediationTemplate(fixContent, idx, valuesUsedList, resultValues)
+		}
+
 		var remName string
 		if idx == 0 {
 			// Use result's name
@@ -846,9 +969,7 @@ func remediationsFromString(scheme *runtime.Scheme, name string, namespace strin
 					Apply: false,
 					Type:  remType,
 				},
-				Current: compv1alpha1.ComplianceRemediationPayload{
-					Object: obj,
-				},
+				Current: payload,
 			},
 			Status: compv1alpha1.ComplianceRemediationStatus{
 				ApplicationState: compv1alpha1.RemediationPending,
@@ -1012,6 +1133,10 @@ func hasVersionDependencyAnnotation(u *unstructured.Unstructured) bool {
 	return hasAnnotation(u, ocpVersionAnnotationKey) || hasAnnotation(u, k8sVersionAnnotationKey)
 }
 
+func hasPatchTypeAnnotation(u *unstructured.Unstructured) bool {
+	return hasAnnotation(u, patchTypeAnnotationKey)
+}
+
 func hasAnnotation(u *unstructured.Unstructured, annotation string) bool {
 	annotations := u.GetAnnotations()
 	if annotations == nil {
@@ -1142,6 +1267,28 @@ func handleOptionalAnnotation(u *unstructured.Unstructured, annotations map[stri
 	return annotations
 }
 
+// handleRemediationOrderAnnotation sets the position of the remediation in
+// its group. Unless the object says otherwise, it's the position of the
+// object in the fix.
+func handleRemediationOrderAnnotation(u *unstructured.Unstructured, idx int, annotations map[string]string) (map[string]string, error) {
+	order := strconv.Itoa(idx)
+
+	inAnns := u.GetAnnotations()
+	if value, hasKey := inAnns[remediationOrderAnnotationKey]; hasKey {
+		if _, err := strconv.ParseInt(value, 10, 32); err != nil {
+			return nil, fmt.Errorf("invalid remediation order %q: %w", value, err)
+		}
+		order = value
+
+		// reset metadata of output object
+		delete(inAnns, remediationOrderAnnotationKey)
+		u.SetAnnotations(inAnns)
+	}
+
+	annotations[compv1alpha1.RemediationGroupOrderAnnotation] = order
+	return annotations, nil
+}
+
 func handleRemediationTypeAnnotation(u *unstructured.Unstructured) compv1alpha1.RemediationType {
 	// We already assume this has some annotation
 	inAnns := u.GetAnnotations()
@@ -1173,3 +1320,67 @@ func handleEnforcementTypeAnnotation(u *unstructured.Unstructured, annotations m
 
 	return annotations
 }
+
+// handlePatchTypeAnnotation turns the object into the target of a patch
+// remediation. The operations of a json patch are moved from the object to
+// the payload.
+func handlePatchTypeAnnotation(u *unstructured.Unstructured, payload *compv1alpha1.ComplianceRemediationPayload) error {
+	// We already assume this has some annotation
+	inAnns := u.GetAnnotations()
+
+	patchType := inAnns[patchTypeAnnotationKey]
+	switch patchType {
+	case strategicMergePatchType:
+		payload.PatchType = compv1alpha1.StrategicMergePatchRemediation
+	case jsonPatchType:
+		payload.PatchType = compv1alpha1.JSONPatchRemediation
+		ops, err := jsonPatchOperations(u)
+		if err != nil {
+			return err
+		}
+		payload.JSONPatch = ops
+	default:
+		return fmt.Errorf("unknown remediation patch type %q", patchType)
+	}
+
+	// reset metadata of output object
+	delete(inAnns, patchTypeAnnotationKey)
+	u.SetAnnotations(inAnns)
+	return nil
+}
+
+func jsonPatchOperations(u *unstructured.Unstructured) ([]compv1alpha1.JSONPatchOperation, error) {
+	rawOps, found, err := unstructured.NestedSlice(u.Object, jsonPatchOperationsKey)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read the json patch operations: %w", err)
+	} else if !found || len(rawOps) == 0 {
+		return nil, errors.New("json patch remediation without operations")
+	}
+	unstructured.RemoveNestedField(u.Object, jsonPatchOperationsKey)
+
+	ops := make([]compv1alpha1.JSONPatchOperation, 0, len(rawOps))
+	for _, rawOp := range rawOps {
+		opMap, ok := rawOp.(map[string]interface{})
+		if !ok {
+			return nil, errors.New("json patch operation is not an object")
+		}
+		op, _ := opMap["op"].(string)
+		path, _ := opMap["path"].(string)
+		if op == "" || path == "" {
+			return nil, errors.New("json patch operation without op or path")
+		}
+		patchOp := compv1alpha1.JSONPatchOperation{
+			Op:   op,
+			Path: path,
+		}
+		if value, hasValue := opMap["value"]; hasValue {
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				return nil, fmt.Errorf("couldn't encode the value of json patch operation on %s: %w", path, err)
+			}
+			patchOp.Value = string(encoded)
+		}
+		ops = append(ops, patchOp)
+	}
+	return ops, nil
+}
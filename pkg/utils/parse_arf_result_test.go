@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"net/url"
@@ -11,6 +12,7 @@ import (
 	igntypes "github.com/coreos/ignition/v2/config/v3_4/types"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
 
@@ -882,4 +884,155 @@ Server 3.fedora.pool.ntp.org`
 
 		})
 	})
+
+	Describe("Testing for patch remediations", func() {
+		It("Should turn a json patch fix into a patch remediation", func() {
+			fix := `apiVersion: config.openshift.io/v1
+kind: APIServer
+metadata:
+  name: cluster
+  annotations:
+    complianceascode.io/patch-type: json
+jsonPatch:
+- op: replace
+  path: /spec/audit/profile
+  value: WriteRequestBodies
+`
+			rems, err := remediationsFromString(nil, "test-rem", "test-ns", fix, map[string]string{})
+			Expect(err).To(BeNil())
+			Expect(rems).To(HaveLen(1))
+			payload := rems[0].Spec.Current
+			Expect(payload.PatchType).To(Equal(compv1alpha1.JSONPatchRemediation))
+			Expect(payload.JSONPatch).To(Equal([]compv1alpha1.JSONPatchOperation{
+				{Op: "replace", Path: "/spec/audit/profile", Value: `"WriteRequestBodies"`},
+			}))
+			Expect(payload.Object.Object).ToNot(HaveKey("jsonPatch"))
+			Expect(payload.Object.GetAnnotations()).ToNot(HaveKey("complianceascode.io/patch-type"))
+		})
+
+		It("Should keep the object of a strategic merge patch fix", func() {
+			fix := `apiVersion: config.openshift.io/v1
+kind: OAuth
+metadata:
+  name: cluster
+  annotations:
+    complianceascode.io/patch-type: strategic-merge
+spec:
+  tokenConfig:
+    accessTokenInactivityTimeout: 10m0s
+`
+			rems, err := remediationsFromString(nil, "test-rem", "test-ns", fix, map[string]string{})
+			Expect(err).To(BeNil())
+			Expect(rems).To(HaveLen(1))
+			payload := rems[0].Spec.Current
+			Expect(payload.PatchType).To(Equal(compv1alpha1.StrategicMergePatchRemediation))
+			Expect(payload.JSONPatch).To(BeNil())
+			Expect(payload.Object.Object).To(HaveKey("spec"))
+		})
+
+		It("Should order the objects of a fix with several objects", func() {
+			fix := `apiVersion: machineconfiguration.openshift.io/v1
+kind: KubeletConfig
+metadata:
+  name: kubelet-tls
+  annotations:
+    complianceascode.io/remediation-order: "5"
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: kubelet-tls-config
+`
+			rems, err := remediationsFromString(nil, "test-rem", "test-ns", fix, map[string]string{})
+			Expect(err).To(BeNil())
+			Expect(rems).To(HaveLen(2))
+			Expect(rems[0].Annotations).To(HaveKeyWithValue(compv1alpha1.RemediationGroupOrderAnnotation, "5"))
+			Expect(rems[0].Spec.Current.Object.GetAnnotations()).ToNot(HaveKey("complianceascode.io/remediation-order"))
+			Expect(rems[1].Annotations).To(HaveKeyWithValue(compv1alpha1.RemediationGroupOrderAnnotation, "1"))
+		})
+
+		It("Should reject an unknown patch type", func() {
+			fix := `apiVersion: config.openshift.io/v1
+kind: OAuth
+metadata:
+  name: cluster
+  annotations:
+    complianceascode.io/patch-type: merge
+`
+			_, err := remediationsFromString(nil, "test-rem", "test-ns", fix, map[string]string{})
+			Expect(err).ToNot(BeNil())
+		})
+	})
+
+	Describe("Testing for remediations rendered with provided values", func() {
+		fix := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: sshd-config
+data:
+  timeout: "{{.var_sshd_idle_timeout}}"
+  keepalive: "{{.var_sshd_keepalive}}"
+`
+
+		It("Should keep the fix of a remediation with unset values", func() {
+			rems, err := remediationsFromString(nil, "test-rem", "test-ns", fix, map[string]string{
+				"var_sshd_keepalive": "3",
+			})
+			Expect(err).To(BeNil())
+			Expect(rems).To(HaveLen(1))
+			Expect(rems[0].Spec.Current.Template).To(Equal(&compv1alpha1.RemediationTemplate{
+				Content: fix,
+				Values:  map[string]string{"var_sshd_keepalive": "3"},
+			}))
+		})
+
+		It("Should not keep the fix of a remediation with all values set", func() {
+			rems, err := remediationsFromString(nil, "test-rem", "test-ns", fix, map[string]string{
+				"var_sshd_keepalive":    "3",
+				"var_sshd_idle_timeout": "300",
+			})
+			Expect(err).To(BeNil())
+			Expect(rems[0].Spec.Current.Template).To(BeNil())
+		})
+
+		It("Should render the remediation with the provided values", func() {
+			rems, err := remediationsFromString(nil, "test-rem", "test-ns", fix, map[string]string{
+				"var_sshd_keepalive": "3",
+			})
+			Expect(err).To(BeNil())
+			rem := rems[0]
+
+			resolved, err := ResolveRemediationValues(rem, &RemediationValues{Values: map[string]string{"var_other": "1"}})
+			Expect(err).To(BeNil())
+			Expect(resolved).To(BeFalse())
+
+			resolved, err = ResolveRemediationValues(rem, &RemediationValues{Values: map[string]string{"var_sshd_idle_timeout": "300"}})
+			Expect(err).To(BeNil())
+			Expect(resolved).To(BeTrue())
+			data, _, err := unstructured.NestedStringMap(rem.Spec.Current.Object.Object, "data")
+			Expect(err).To(BeNil())
+			Expect(data).To(Equal(map[string]string{"timeout": "300", "keepalive": "3"}))
+			Expect(rem.Spec.Current.Template).ToNot(BeNil())
+			Expect(rem.Annotations).ToNot(HaveKey(compv1alpha1.RemediationUnsetValueAnnotation))
+			Expect(rem.Annotations).To(HaveKeyWithValue(compv1alpha1.RemediationSourcedValueAnnotation, "var-sshd-idle-timeout"))
+			Expect(rem.Annotations).To(HaveKeyWithValue(compv1alpha1.RemediationValueUsedAnnotation, "var-sshd-keepalive,var-sshd-idle-timeout"))
+		})
+
+		It("Should not render the remediation with rejected values", func() {
+			rems, err := remediationsFromString(nil, "test-rem", "test-ns", fix, map[string]string{
+				"var_sshd_keepalive": "3",
+			})
+			Expect(err).To(BeNil())
+			rem := rems[0]
+
+			resolved, err := ResolveRemediationValues(rem, &RemediationValues{
+				Invalid: map[string]string{"var_sshd_idle_timeout": "value can't be empty"},
+			})
+			Expect(resolved).To(BeFalse())
+			invalidErr := &InvalidRemediationValuesError{}
+			Expect(errors.As(err, &invalidErr)).To(BeTrue())
+			Expect(invalidErr.Reasons).To(Equal([]string{"var-sshd-idle-timeout: value can't be empty"}))
+			Expect(rem.Annotations).To(HaveKeyWithValue(compv1alpha1.RemediationUnsetValueAnnotation, "var-sshd-idle-timeout"))
+		})
+	})
 })
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"math"
+	"sort"
 
 	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
 	"github.com/google/go-cmp/cmp"
@@ -19,8 +20,42 @@ type ParseResultContextItem struct {
 	Annotations map[string]string
 	Labels      map[string]string
 
-	sources   []string
-	processed bool
+	sources     []string
+	processed   bool
+	nodeResults []compv1alpha1.ComplianceCheckNodeResult
+}
+
+// NodeResults returns the status of the check on each node the results
+// came from, ordered by node name
+func (item *ParseResultContextItem) NodeResults() []compv1alpha1.ComplianceCheckNodeResult {
+	return item.nodeResults
+}
+
+// Sources returns the sources the result comes from. The sources of a result
+// that was inconsistent are all the sources of the inconsistent results.
+func (item *ParseResultContextItem) Sources() []string {
+	return item.sources
+}
+
+// getNodeResults lists the status of the check on each source of the
+// items. Platform scans have no sources and thus no node results.
+func getNodeResults(items ...*ParseResultContextItem) []compv1alpha1.ComplianceCheckNodeResult {
+	var results []compv1alpha1.ComplianceCheckNodeResult
+	for _, item := range items {
+		for _, src := range item.sources {
+			if src == "" {
+				continue
+			}
+			results = append(results, compv1alpha1.ComplianceCheckNodeResult{
+				Node:   src,
+				Status: item.CheckResult.Status,
+			})
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Node < results[j].Node
+	})
+	return results
 }
 
 func newParseResultWithSources(pr *ParseResult, sources ...string) *ParseResultContextItem {
@@ -67,25 +102,59 @@ func NewParseResultContext() *ParseResultContext {
 // ParseResultContext.AddResults adds a batch of results coming from the parser and partitions them into
 // either the consistent or the inconsistent list
 func (prCtx *ParseResultContext) AddResults(source string, parsedResList []*ParseResult) {
+	batch := prCtx.NewBatch(source)
+	for _, pr := range parsedResList {
+		batch.Add(pr)
+	}
+	batch.Close()
+}
+
+// ParseResultBatch adds the results of a single source to a ParseResultContext
+// one at a time, so that the results of a source needn't be held in memory
+// together. The batch must be closed once all the results of the source were
+// added, and only one batch of a context may be open at a time.
+type ParseResultBatch struct {
+	prCtx  *ParseResultContext
+	source string
+	// The results of the first batch, or of a platform scan, are consistent
+	// by definition
+	consistent bool
+}
+
+// ParseResultContext.NewBatch opens a batch for the results of source
+func (prCtx *ParseResultContext) NewBatch(source string) *ParseResultBatch {
 	// If there is no source, the configMap is probably a platform scan map, in that case
 	// treat all the results as consistent.
-	if source == "" {
-		prCtx.addConsistentResults(source, parsedResList)
-		return
+	// Treat the first batch of results as consistent
+	consistent := source == "" || (len(prCtx.inconsistent) == 0 && len(prCtx.consistent) == 0)
+	if !consistent {
+		for _, consistentResult := range prCtx.consistent {
+			consistentResult.processed = false
+		}
 	}
+	return &ParseResultBatch{
+		prCtx:      prCtx,
+		source:     source,
+		consistent: consistent,
+	}
+}
 
-	// Treat the first batch of results as consistent
-	if len(prCtx.inconsistent) == 0 && len(prCtx.consistent) == 0 {
-		prCtx.addConsistentResults(source, parsedResList)
-	} else {
-		prCtx.addParsedResults(source, parsedResList)
+// ParseResultBatch.Add partitions a result of the source of the batch into either the consistent or
+// the inconsistent list
+func (batch *ParseResultBatch) Add(pr *ParseResult) {
+	if batch.consistent {
+		batch.prCtx.consistent[pr.Id] = newParseResultWithSources(pr, batch.source)
+		return
 	}
+	batch.prCtx.addParsedResult(batch.source, pr)
 }
 
-func (prCtx *ParseResultContext) addConsistentResults(source string, parsedResList []*ParseResult) {
-	for _, parsedRes := range parsedResList {
-		prCtx.consistent[parsedRes.Id] = newParseResultWithSources(parsedRes, source)
+// ParseResultBatch.Close marks the results the source of the batch didn't report as inconsistent
+func (batch *ParseResultBatch) Close() {
+	if batch.consistent {
+		return
 	}
+	batch.prCtx.markUnprocessedInconsistent()
 }
 
 func (prCtx *ParseResultContext) addInconsistentResult(id string, pr *ParseResult, sources ...string) {
@@ -100,39 +169,36 @@ func (prCtx *ParseResultContext) addInconsistentResult(id string, pr *ParseResul
 	prCtx.inconsistent[id] = append(prCtx.inconsistent[id], newParseResultWithSources(pr, sources...))
 }
 
-// ParseResultContext.addParsedResults add a subsequent batch of results that must be examined
+// ParseResultContext.addParsedResult adds a result of a subsequent batch that must be examined
 // for consistency
-func (prCtx *ParseResultContext) addParsedResults(source string, newResults []*ParseResult) {
-	for _, consistentResult := range prCtx.consistent {
-		consistentResult.processed = false
+func (prCtx *ParseResultContext) addParsedResult(source string, pr *ParseResult) {
+	consistentPr, ok := prCtx.consistent[pr.Id]
+	if !ok {
+		// This either already inconsistent result or an extra
+		// this batch has an extra item, save it as a diff with (only so far) this source
+		prCtx.addInconsistentResult(pr.Id, pr, source)
+		return
 	}
+	consistentPr.processed = true
 
-	for _, pr := range newResults {
-		consistentPr, ok := prCtx.consistent[pr.Id]
-		if !ok {
-			// This either already inconsistent result or an extra
-			// this batch has an extra item, save it as a diff with (only so far) this source
-			prCtx.addInconsistentResult(pr.Id, pr, source)
-			continue
-		}
-		consistentPr.processed = true
-
-		ok = diffChecks(consistentPr.CheckResult, pr.CheckResult) && diffRemediations(consistentPr.Remediations, pr.Remediations)
-		if !ok {
-			// remove the check from consistent, add it to diff, but TWICE
-			// once for the sources from the consistent list and once for the new source
-			prCtx.addInconsistentResult(pr.Id, &consistentPr.ParseResult, consistentPr.sources...)
-			delete(prCtx.consistent, pr.Id)
-			prCtx.addInconsistentResult(pr.Id, pr, source)
-			continue
-		}
-
-		// OK, same as a previous result in consistent, just append the source
-		consistentPr.sources = append(consistentPr.sources, source)
+	ok = DiffChecks(consistentPr.CheckResult, pr.CheckResult) && diffRemediations(consistentPr.Remediations, pr.Remediations)
+	if !ok {
+		// remove the check from consistent, add it to diff, but TWICE
+		// once for the sources from the consistent list and once for the new source
+		prCtx.addInconsistentResult(pr.Id, &consistentPr.ParseResult, consistentPr.sources...)
+		delete(prCtx.consistent, pr.Id)
+		prCtx.addInconsistentResult(pr.Id, pr, source)
+		return
 	}
 
-	// Make sure all previously consistent items were touched, IOW we didn't receive
-	// fewer items by moving all previously untouched items to the inconsistent list
+	// OK, same as a previous result in consistent, just append the source
+	consistentPr.sources = append(consistentPr.sources, source)
+}
+
+// ParseResultContext.markUnprocessedInconsistent makes sure all previously consistent items were touched
+// by the last batch, IOW we didn't receive fewer items by moving all previously untouched items to the
+// inconsistent list
+func (prCtx *ParseResultContext) markUnprocessedInconsistent() {
 	for _, consistentResult := range prCtx.consistent {
 		if consistentResult.processed == true {
 			continue
@@ -163,6 +229,9 @@ func (prCtx *ParseResultContext) reconcileInconsistentResults() {
 }
 
 func (prCtx *ParseResultContext) GetConsistentResults() []*ParseResultContextItem {
+	for _, item := range prCtx.consistent {
+		item.nodeResults = getNodeResults(item)
+	}
 	prCtx.reconcileInconsistentResults()
 
 	consistentList := make([]*ParseResultContextItem, 0)
@@ -204,6 +273,10 @@ func reconcileInconsistentResult(inconsistent []*ParseResultContextItem) *ParseR
 
 	pr.Labels = make(map[string]string)
 	pr.Labels[compv1alpha1.ComplianceCheckInconsistentLabel] = ""
+	pr.nodeResults = getNodeResults(inconsistent...)
+	for _, item := range inconsistent {
+		pr.sources = append(pr.sources, item.sources...)
+	}
 
 	return &pr
 }
@@ -296,8 +369,8 @@ func mostCommonState(inconsistent []*ParseResultContextItem) (compv1alpha1.Compl
 	return mostCommonState, hasCommonState
 }
 
-// returns true if the checks are the same, false if they differ
-func diffChecks(old, new *compv1alpha1.ComplianceCheckResult) bool {
+// DiffChecks returns true if the checks are the same, false if they differ
+func DiffChecks(old, new *compv1alpha1.ComplianceCheckResult) bool {
 	if old == nil {
 		return new == nil
 	}
@@ -329,6 +402,11 @@ func diffRemediations(old, new []*compv1alpha1.ComplianceRemediation) bool {
 		if !cmp.Equal(oldRem.Spec.Current.Object, newRem.Spec.Current.Object) {
 			return false
 		}
+
+		if oldRem.Spec.Current.PatchType != newRem.Spec.Current.PatchType ||
+			!cmp.Equal(oldRem.Spec.Current.JSONPatch, newRem.Spec.Current.JSONPatch) {
+			return false
+		}
 	}
 	return true
 }
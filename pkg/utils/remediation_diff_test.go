@@ -167,6 +167,27 @@ var _ = Describe("Testing reconciling differing parse results", func() {
 		})
 	})
 
+	Context("Adding results one at a time", func() {
+		It("Partitions the results like adding them in a batch", func() {
+			list2[0].CheckResult.Status = compv1alpha1.CheckResultFail
+			// The last source doesn't report the last check
+			list3 = list3[:2]
+
+			for i, list := range [][]*ParseResult{list1, list2, list3} {
+				batch := prCtx.NewBatch(fmt.Sprintf("source%d", i+1))
+				for _, pr := range list {
+					batch.Add(pr)
+				}
+				batch.Close()
+			}
+			Expect(prCtx.consistent).To(HaveLen(1))
+			Expect(prCtx.consistent["checkid_1"].sources).To(ConsistOf("source1", "source2", "source3"))
+			Expect(prCtx.inconsistent).To(HaveLen(2))
+			Expect(prCtx.inconsistent).To(HaveKey("checkid_0"))
+			Expect(prCtx.inconsistent).To(HaveKey("checkid_2"))
+		})
+	})
+
 	Context("Handling inconsistent results", func() {
 		JustBeforeEach(func() {
 			list2[0].CheckResult.Status = compv1alpha1.CheckResultFail
@@ -222,6 +243,37 @@ var _ = Describe("Testing reconciling differing parse results", func() {
 		})
 	})
 
+	Context("Listing the result of each node", func() {
+		It("Lists every node of a consistent result", func() {
+			ParseAndReconcile()
+			item := getItemById(consistent, "checkid_1")
+			Expect(item.NodeResults()).To(Equal([]compv1alpha1.ComplianceCheckNodeResult{
+				{Node: "source1", Status: compv1alpha1.CheckResultPass},
+				{Node: "source2", Status: compv1alpha1.CheckResultPass},
+				{Node: "source3", Status: compv1alpha1.CheckResultPass},
+			}))
+		})
+
+		It("Lists the status of every node of an inconsistent result", func() {
+			list2[0].CheckResult.Status = compv1alpha1.CheckResultFail
+			ParseAndReconcile()
+			item := getItemById(consistent, "checkid_0")
+			Expect(item.CheckResult.Status).To(BeEquivalentTo(compv1alpha1.CheckResultInconsistent))
+			Expect(item.NodeResults()).To(Equal([]compv1alpha1.ComplianceCheckNodeResult{
+				{Node: "source1", Status: compv1alpha1.CheckResultPass},
+				{Node: "source2", Status: compv1alpha1.CheckResultFail},
+				{Node: "source3", Status: compv1alpha1.CheckResultPass},
+			}))
+			Expect(item.Sources()).To(ConsistOf("source1", "source2", "source3"))
+		})
+
+		It("Lists no nodes for platform results", func() {
+			prCtx.AddResults("", list1)
+			consistent = prCtx.GetConsistentResults()
+			Expect(getItemById(consistent, "checkid_1").NodeResults()).To(BeEmpty())
+		})
+	})
+
 	Context("No common result", func() {
 		JustBeforeEach(func() {
 			list2[0].CheckResult.Status = compv1alpha1.CheckResultFail
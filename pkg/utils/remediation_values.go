@@ -0,0 +1,225 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+)
+
+func newRemediationTemplate(fixContent string, idx int, valuesUsedList []string, resultValues map[string]string) *compv1alpha1.RemediationTemplate {
+	tmpl := &compv1alpha1.RemediationTemplate{
+		Content: fixContent,
+		Index:   idx,
+	}
+	for _, name := range valuesUsedList {
+		key := valueKey(name)
+		if value, ok := resultValues[key]; ok {
+			if tmpl.Values == nil {
+				tmpl.Values = make(map[string]string)
+			}
+			tmpl.Values[key] = value
+		}
+	}
+	return tmpl
+}
+
+// valueKey returns the name a variable has in the fix templates. The
+// annotations of the remediations use DNS-friendly names instead.
+func valueKey(name string) string {
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+// RemediationValues are the values the remediation values ConfigMaps of a
+// namespace provide, keyed by the variable name as used in the fix templates
+type RemediationValues struct {
+	// The values that are valid for their variable
+	Values map[string]string
+	// Why the other values were rejected
+	Invalid map[string]string
+}
+
+// InvalidRemediationValuesError is returned when a remediation can't be
+// rendered because some of the values it needs were rejected
+type InvalidRemediationValuesError struct {
+	// Why each of the values was rejected, sorted by variable name
+	Reasons []string
+}
+
+func (e *InvalidRemediationValuesError) Error() string {
+	return "invalid remediation values: " + strings.Join(e.Reasons, "; ")
+}
+
+// GetRemediationValues returns the values of the remediation values
+// ConfigMaps of the namespace. If several ConfigMaps set a variable, the one
+// whose name sorts first wins. Each value is validated against the Variable
+// of the same name, as the values of a TailoredProfile are, and rejected if
+// there's no such Variable, it has another type, or it isn't one of the
+// selections of the Variable.
+func GetRemediationValues(ctx context.Context, client runtimeclient.Client, namespace string) (*RemediationValues, error) {
+	cmList := &corev1.ConfigMapList{}
+	if err := client.List(ctx, cmList, runtimeclient.InNamespace(namespace),
+		runtimeclient.HasLabels{compv1alpha1.RemediationValuesLabel}); err != nil {
+		return nil, fmt.Errorf("couldn't list remediation values ConfigMaps: %w", err)
+	}
+	sort.Slice(cmList.Items, func(i, j int) bool {
+		return cmList.Items[i].Name < cmList.Items[j].Name
+	})
+
+	provided := make(map[string]string)
+	for _, cm := range cmList.Items {
+		for name, value := range cm.Data {
+			key := valueKey(strings.TrimSpace(name))
+			if _, ok := provided[key]; !ok {
+				provided[key] = strings.TrimSpace(value)
+			}
+		}
+	}
+
+	values := &RemediationValues{
+		Values:  make(map[string]string, len(provided)),
+		Invalid: make(map[string]string),
+	}
+	if len(provided) == 0 {
+		return values, nil
+	}
+
+	varList := &compv1alpha1.VariableList{}
+	if err := client.List(ctx, varList, runtimeclient.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("couldn't list variables: %w", err)
+	}
+	for key, value := range provided {
+		if err := validateRemediationValue(varList.Items, key, value); err != nil {
+			values.Invalid[key] = err.Error()
+			continue
+		}
+		values.Values[key] = value
+	}
+	return values, nil
+}
+
+// validateRemediationValue validates the value against the Variables with
+// the ID of the key. Several profile bundles might define the variable, and
+// the value has to be valid for all of them.
+func validateRemediationValue(variables []compv1alpha1.Variable, key, value string) error {
+	found := false
+	for i := range variables {
+		if variables[i].ID != valuePrefix+key {
+			continue
+		}
+		found = true
+		if err := variables[i].ValidateValue(value); err != nil {
+			return fmt.Errorf("variable %s: %w", variables[i].Name, err)
+		}
+	}
+	if !found {
+		return fmt.Errorf("no variable with the ID %s%s found", valuePrefix, key)
+	}
+	return nil
+}
+
+// ResolveRemediationValues renders the remediation again from its template,
+// with the values it lacks taken from values. These are the values listed
+// in the unset-value annotation, and the values taken from values before.
+// It returns false if the remediation has no template or values doesn't
+// provide all of them, in which case the remediation is left as is. If some
+// of them were rejected, it returns an InvalidRemediationValuesError.
+func ResolveRemediationValues(rem *compv1alpha1.ComplianceRemediation, values *RemediationValues) (bool, error) {
+	tmpl := rem.Spec.Current.Template
+	if tmpl == nil {
+		return false, nil
+	}
+
+	annotations := rem.GetAnnotations()
+	names := appendUniqueValues(splitValues(annotations[compv1alpha1.RemediationUnsetValueAnnotation]),
+		splitValues(annotations[compv1alpha1.RemediationSourcedValueAnnotation])...)
+	if len(names) == 0 {
+		return false, nil
+	}
+
+	var reasons []string
+	for _, name := range names {
+		if reason, ok := values.Invalid[valueKey(name)]; ok {
+			reasons = append(reasons, fmt.Sprintf("%s: %s", name, reason))
+		}
+	}
+	if len(reasons) > 0 {
+		sort.Strings(reasons)
+		return false, &InvalidRemediationValuesError{Reasons: reasons}
+	}
+
+	renderValues := make(map[string]string, len(tmpl.Values)+len(names))
+	for key, value := range tmpl.Values {
+		renderValues[key] = value
+	}
+	for _, name := range names {
+		value, ok := values.Values[valueKey(name)]
+		if !ok {
+			return false, nil
+		}
+		renderValues[valueKey(name)] = value
+	}
+
+	rendered, err := remediationsFromString(nil, rem.Name, rem.Namespace, tmpl.Content, renderValues)
+	if err != nil {
+		return false, fmt.Errorf("couldn't render remediation %s: %w", rem.Name, err)
+	}
+	if tmpl.Index >= len(rendered) {
+		return false, fmt.Errorf("the fix of remediation %s has no object %d", rem.Name, tmpl.Index)
+	}
+	if rendered[tmpl.Index].HasAnnotation(compv1alpha1.RemediationUnsetValueAnnotation) {
+		return false, nil
+	}
+
+	rem.Spec.Current = rendered[tmpl.Index].Spec.Current
+	rem.Spec.Current.Template = tmpl
+
+	newAnnotations := make(map[string]string, len(annotations)+1)
+	for key, value := range annotations {
+		newAnnotations[key] = value
+	}
+	delete(newAnnotations, compv1alpha1.RemediationUnsetValueAnnotation)
+	// The values that needed user input are provided now
+	delete(newAnnotations, compv1alpha1.RemediationValueRequiredAnnotation)
+	newAnnotations[compv1alpha1.RemediationSourcedValueAnnotation] = strings.Join(names, ",")
+	newAnnotations[compv1alpha1.RemediationValueUsedAnnotation] = strings.Join(
+		appendUniqueValues(splitValues(annotations[compv1alpha1.RemediationValueUsedAnnotation]), names...), ",")
+	rem.SetAnnotations(newAnnotations)
+
+	if labels := rem.GetLabels(); labels != nil {
+		delete(labels, compv1alpha1.RemediationUnsetValueLabel)
+		rem.SetLabels(labels)
+	}
+	return true, nil
+}
+
+func splitValues(list string) []string {
+	var values []string
+	for _, value := range strings.Split(list, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func appendUniqueValues(values []string, more ...string) []string {
+	for _, value := range more {
+		found := false
+		for _, existing := range values {
+			if existing == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -1,9 +1,11 @@
 package utils
 
 import (
+	"bytes"
 	"encoding/base64"
 	"io"
 
+	"github.com/dsnet/compress/bzip2"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -66,3 +68,28 @@ func GetResultConfigMap(owner metav1.Object, configMapName, filename, nodeName s
 		},
 	}
 }
+
+// configMapDataLimit is the size of the data above which it's compressed
+// before being stored in a ConfigMap
+const configMapDataLimit = 1048570
+
+// GetConfigMapData returns the data to store in a ConfigMap, and whether it
+// was compressed. Data too large for a ConfigMap is compressed with bzip2
+// and base64 encoded, like the results of the scans.
+func GetConfigMapData(data []byte) (string, bool, error) {
+	if len(data) <= configMapDataLimit {
+		return string(data), false, nil
+	}
+	var buffer bytes.Buffer
+	w, err := bzip2.NewWriter(&buffer, &bzip2.WriterConfig{Level: bzip2.BestCompression})
+	if err != nil {
+		return "", false, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return "", false, err
+	}
+	if err := w.Close(); err != nil {
+		return "", false, err
+	}
+	return base64.StdEncoding.EncodeToString(buffer.Bytes()), true, nil
+}
@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"strings"
+
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+)
+
+// GetRuleControls returns the controls each rule maps to, by standard, from
+// the control annotations the profile parser sets on the rules. The rules are
+// keyed by their name as used in the rule annotation of the
+// ComplianceCheckResults.
+func GetRuleControls(rules []compv1alpha1.Rule) map[string]map[string][]string {
+	controls := make(map[string]map[string][]string)
+	for i := range rules {
+		rule := &rules[i]
+		ruleName, ok := rule.Annotations[compv1alpha1.RuleIDAnnotationKey]
+		if !ok {
+			continue
+		}
+		for key, value := range rule.Annotations {
+			if !strings.HasPrefix(key, compv1alpha1.RuleControlAnnotationPrefix) {
+				continue
+			}
+			standard := strings.TrimPrefix(key, compv1alpha1.RuleControlAnnotationPrefix)
+			// The same rule might be shipped by several bundles, merge the controls
+			for _, ctrlID := range strings.Split(value, compv1alpha1.RuleControlSeparator) {
+				ctrlID = strings.TrimSpace(ctrlID)
+				if ctrlID == "" || containsString(controls[ruleName][standard], ctrlID) {
+					continue
+				}
+				if controls[ruleName] == nil {
+					controls[ruleName] = make(map[string][]string)
+				}
+				controls[ruleName][standard] = append(controls[ruleName][standard], ctrlID)
+			}
+		}
+	}
+	return controls
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
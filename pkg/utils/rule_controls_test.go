@@ -0,0 +1,44 @@
+package utils_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/utils"
+)
+
+var _ = Describe("Rule controls", func() {
+	It("reads the controls of the rules from their annotations", func() {
+		rules := []compv1alpha1.Rule{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "ocp4-audit-log-forwarding",
+					Annotations: map[string]string{
+						compv1alpha1.RuleIDAnnotationKey:                         "audit-log-forwarding",
+						compv1alpha1.RuleControlAnnotationPrefix + "NIST-800-53": "AU-9(2);AU-4(1)",
+					},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "rhcos4-audit-log-forwarding",
+					Annotations: map[string]string{
+						compv1alpha1.RuleIDAnnotationKey:                         "audit-log-forwarding",
+						compv1alpha1.RuleControlAnnotationPrefix + "NIST-800-53": "AU-4(1);SI-4",
+					},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "ocp4-scc-limit",
+					Annotations: map[string]string{compv1alpha1.RuleIDAnnotationKey: "scc-limit"},
+				},
+			},
+		}
+		controls := utils.GetRuleControls(rules)
+		Expect(controls).To(HaveLen(1))
+		Expect(controls["audit-log-forwarding"]["NIST-800-53"]).To(Equal([]string{"AU-9(2)", "AU-4(1)", "SI-4"}))
+	})
+})
@@ -159,6 +159,99 @@ func getValuesFromVariables(variables []*cmpv1alpha1.Variable) []SetValueElement
 	return values
 }
 
+type ruleSelectionKind int
+
+const (
+	enabledSelection ruleSelectionKind = iota
+	disabledSelection
+	manualSelection
+)
+
+type flattenedRuleSelection struct {
+	ref  cmpv1alpha1.RuleReferenceSpec
+	kind ruleSelectionKind
+}
+
+// FlattenTailoredProfiles merges a chain of TailoredProfiles, ordered from the
+// root of the chain to the TailoredProfile being rendered, into a single
+// TailoredProfile. The selections and values are applied in order, so a
+// TailoredProfile overrides the selections and values of the ones it extends.
+// The CustomRules of the whole chain are kept.
+// The result keeps the metadata and title of the last TailoredProfile and
+// extends the Profile the root of the chain extends.
+func FlattenTailoredProfiles(chain []*cmpv1alpha1.TailoredProfile) *cmpv1alpha1.TailoredProfile {
+	if len(chain) == 0 {
+		return nil
+	}
+
+	flat := chain[len(chain)-1].DeepCopy()
+	flat.Spec.Extends = chain[0].Spec.Extends
+	flat.Spec.ExtendsTailoredProfile = ""
+
+	ruleOrder := []string{}
+	rules := map[string]*flattenedRuleSelection{}
+	valueOrder := []string{}
+	values := map[string]cmpv1alpha1.VariableValueSpec{}
+	customRuleOrder := []string{}
+	customRules := map[string]cmpv1alpha1.RuleReferenceSpec{}
+
+	selectRules := func(refs []cmpv1alpha1.RuleReferenceSpec, kind ruleSelectionKind) {
+		for _, ref := range refs {
+			if _, ok := rules[ref.Name]; !ok {
+				ruleOrder = append(ruleOrder, ref.Name)
+			}
+			rules[ref.Name] = &flattenedRuleSelection{ref: ref, kind: kind}
+		}
+	}
+
+	for _, tp := range chain {
+		selectRules(tp.Spec.EnableRules, enabledSelection)
+		selectRules(tp.Spec.DisableRules, disabledSelection)
+		selectRules(tp.Spec.ManualRules, manualSelection)
+
+		for _, val := range tp.Spec.SetValues {
+			if _, ok := values[val.Name]; !ok {
+				valueOrder = append(valueOrder, val.Name)
+			}
+			values[val.Name] = val
+		}
+
+		for _, ref := range tp.Spec.CustomRules {
+			if _, ok := customRules[ref.Name]; !ok {
+				customRuleOrder = append(customRuleOrder, ref.Name)
+			}
+			customRules[ref.Name] = ref
+		}
+	}
+
+	flat.Spec.EnableRules = nil
+	flat.Spec.DisableRules = nil
+	flat.Spec.ManualRules = nil
+	for _, name := range ruleOrder {
+		sel := rules[name]
+		switch sel.kind {
+		case enabledSelection:
+			flat.Spec.EnableRules = append(flat.Spec.EnableRules, sel.ref)
+		case disabledSelection:
+			flat.Spec.DisableRules = append(flat.Spec.DisableRules, sel.ref)
+		case manualSelection:
+			flat.Spec.ManualRules = append(flat.Spec.ManualRules, sel.ref)
+		}
+	}
+
+	flat.Spec.SetValues = nil
+	for _, name := range valueOrder {
+		flat.Spec.SetValues = append(flat.Spec.SetValues, values[name])
+	}
+
+	flat.Spec.CustomRules = nil
+	for _, name := range customRuleOrder {
+		flat.Spec.CustomRules = append(flat.Spec.CustomRules, customRules[name])
+	}
+
+	return flat
+}
+
 // TailoredProfileToXML gets an XML string from a TailoredProfile and the corresponding Profile
 func TailoredProfileToXML(tp *cmpv1alpha1.TailoredProfile, p *cmpv1alpha1.Profile, pb *cmpv1alpha1.ProfileBundle, rules map[string]*cmpv1alpha1.Rule, variables []*cmpv1alpha1.Variable) (string, error) {
 	tailoring := TailoringElement{
@@ -111,3 +111,97 @@ var _ = Describe("Testing parse variables", func() {
 		})
 	})
 })
+
+var _ = Describe("Testing flattening tailored profiles", func() {
+	var (
+		base *cmpv1alpha1.TailoredProfile
+		team *cmpv1alpha1.TailoredProfile
+	)
+
+	BeforeEach(func() {
+		base = &cmpv1alpha1.TailoredProfile{
+			ObjectMeta: v1.ObjectMeta{Name: "base"},
+			Spec: cmpv1alpha1.TailoredProfileSpec{
+				Extends: "ocp4-moderate",
+				Title:   "Base",
+				EnableRules: []cmpv1alpha1.RuleReferenceSpec{
+					{Name: "rule-a", Rationale: "base"},
+					{Name: "rule-b", Rationale: "base"},
+				},
+				DisableRules: []cmpv1alpha1.RuleReferenceSpec{
+					{Name: "rule-c", Rationale: "base"},
+				},
+				SetValues: []cmpv1alpha1.VariableValueSpec{
+					{Name: "var-a", Value: "1"},
+					{Name: "var-b", Value: "2"},
+				},
+			},
+		}
+		team = &cmpv1alpha1.TailoredProfile{
+			ObjectMeta: v1.ObjectMeta{Name: "team"},
+			Spec: cmpv1alpha1.TailoredProfileSpec{
+				ExtendsTailoredProfile: "base",
+				Title:                  "Team",
+				DisableRules: []cmpv1alpha1.RuleReferenceSpec{
+					{Name: "rule-b", Rationale: "team"},
+				},
+				ManualRules: []cmpv1alpha1.RuleReferenceSpec{
+					{Name: "rule-d", Rationale: "team"},
+				},
+				SetValues: []cmpv1alpha1.VariableValueSpec{
+					{Name: "var-b", Value: "3"},
+				},
+			},
+		}
+	})
+
+	It("keeps a single tailored profile as is", func() {
+		flat := FlattenTailoredProfiles([]*cmpv1alpha1.TailoredProfile{base})
+		Expect(flat.Spec).To(Equal(base.Spec))
+	})
+
+	It("applies the selections of the extending profile on top", func() {
+		flat := FlattenTailoredProfiles([]*cmpv1alpha1.TailoredProfile{base, team})
+		Expect(flat.Name).To(Equal("team"))
+		Expect(flat.Spec.Title).To(Equal("Team"))
+		Expect(flat.Spec.Extends).To(Equal("ocp4-moderate"))
+		Expect(flat.Spec.ExtendsTailoredProfile).To(BeEmpty())
+		Expect(flat.Spec.EnableRules).To(Equal([]cmpv1alpha1.RuleReferenceSpec{
+			{Name: "rule-a", Rationale: "base"},
+		}))
+		Expect(flat.Spec.DisableRules).To(Equal([]cmpv1alpha1.RuleReferenceSpec{
+			{Name: "rule-b", Rationale: "team"},
+			{Name: "rule-c", Rationale: "base"},
+		}))
+		Expect(flat.Spec.ManualRules).To(Equal([]cmpv1alpha1.RuleReferenceSpec{
+			{Name: "rule-d", Rationale: "team"},
+		}))
+		Expect(flat.Spec.SetValues).To(Equal([]cmpv1alpha1.VariableValueSpec{
+			{Name: "var-a", Value: "1"},
+			{Name: "var-b", Value: "3"},
+		}))
+	})
+
+	It("keeps the custom rules of the whole chain", func() {
+		base.Spec.CustomRules = []cmpv1alpha1.RuleReferenceSpec{
+			{Name: "custom-a", Rationale: "base"},
+			{Name: "custom-b", Rationale: "base"},
+		}
+		team.Spec.CustomRules = []cmpv1alpha1.RuleReferenceSpec{
+			{Name: "custom-b", Rationale: "team"},
+			{Name: "custom-c", Rationale: "team"},
+		}
+		flat := FlattenTailoredProfiles([]*cmpv1alpha1.TailoredProfile{base, team})
+		Expect(flat.Spec.CustomRules).To(Equal([]cmpv1alpha1.RuleReferenceSpec{
+			{Name: "custom-a", Rationale: "base"},
+			{Name: "custom-b", Rationale: "team"},
+			{Name: "custom-c", Rationale: "team"},
+		}))
+	})
+
+	It("doesn't modify the chain", func() {
+		FlattenTailoredProfiles([]*cmpv1alpha1.TailoredProfile{base, team})
+		Expect(team.Spec.ExtendsTailoredProfile).To(Equal("base"))
+		Expect(base.Spec.EnableRules).To(HaveLen(2))
+	})
+})